@@ -2,34 +2,88 @@ package main
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
-var days int
+var (
+	days                   int
+	fromDate               string
+	toDate                 string
+	leaderboardSort        string
+	leaderboardCompetitive bool
+	leaderboardAllTime     bool
+	spendFrom              string
+	spendTo                string
+	clearConfirm           bool
+	includeDeleted         bool
+)
 
 func addCommands(root *cobra.Command) {
 	root.AddCommand(healthCmd)
 
 	fetchCmd.Flags().IntVar(&days, "days", 0, "Number of past days to fetch matches from")
+	fetchCmd.Flags().StringVar(&fromDate, "from", "", "Fetch matches starting on or after this date (YYYY-MM-DD)")
+	fetchCmd.Flags().StringVar(&toDate, "to", "", "Fetch matches starting on or before this date (YYYY-MM-DD)")
 	root.AddCommand(fetchCmd)
 
 	root.AddCommand(processCmd)
 	root.AddCommand(membersCmd)
+	matchesCmd.Flags().BoolVar(&includeDeleted, "include-deleted", false, "Also list soft-deleted matches")
 	root.AddCommand(matchesCmd)
+	root.AddCommand(undeleteCmd)
+	root.AddCommand(weekMatchesCmd)
+	root.AddCommand(weeklyRecapCmd)
+	root.AddCommand(dailyDigestCmd)
+	root.AddCommand(processingStatsCmd)
+	root.AddCommand(migrationsCmd)
+	root.AddCommand(reencodeBlobsCmd)
+	leaderboardCmd.Flags().StringVar(&leaderboardSort, "sort", "", "Sort order: wins, winpct, confident, sets or games")
+	leaderboardCmd.Flags().BoolVar(&leaderboardCompetitive, "competitive", false, "Only count competitive matches")
+	leaderboardCmd.Flags().BoolVar(&leaderboardAllTime, "all-time", false, "Also count past seasons")
 	root.AddCommand(leaderboardCmd)
+	spendCmd.Flags().StringVar(&spendFrom, "from", "", "Only count matches starting on or after this date (YYYY-MM-DD)")
+	spendCmd.Flags().StringVar(&spendTo, "to", "", "Only count matches starting on or before this date (YYYY-MM-DD)")
+	root.AddCommand(spendCmd)
 	root.AddCommand(metricsCmd)
+	clearCmd.Flags().BoolVar(&clearConfirm, "confirm", false, "Confirm clearing the entire store")
 	root.AddCommand(clearCmd)
+	root.AddCommand(mergePlayersCmd)
+	root.AddCommand(slackUserCmd)
+	root.AddCommand(corePlayerCmd)
+	simulateCmd.Flags().StringVar(&simulateMatchType, "type", "competitive", "Match type: competitive or friendly")
+	simulateCmd.Flags().BoolVar(&simulateConfirmed, "confirmed", true, "Confirm the result once the match is played")
+	root.AddCommand(simulateCmd)
 
 	// Slack commands
+	commandCmd.AddCommand(commandHelpCmd)
 	commandCmd.AddCommand(commandLeaderboardCmd)
 	commandCmd.AddCommand(commandLevelLeaderboardCmd)
 	commandCmd.AddCommand(commandPlayerStatsCmd)
+	commandCmd.AddCommand(commandBallsCmd)
+	commandCmd.AddCommand(commandRosterCmd)
+	commandCmd.AddCommand(commandFormCmd)
+	commandCmd.AddCommand(commandLeaderboardDiffCmd)
+	commandCmd.AddCommand(commandReportResultCmd)
+	commandCmd.AddCommand(commandNextCmd)
+	commandCmd.AddCommand(commandMatchStatusCmd)
+	commandCmd.AddCommand(commandNotificationsCmd)
+	commandCmd.AddCommand(commandMapCmd)
+	commandCmd.AddCommand(commandMergeCmd)
+	commandCmd.AddCommand(commandUndoLastCmd)
+	commandCmd.AddCommand(commandRequeueCmd)
+	commandCmd.AddCommand(commandNewSeasonCmd)
+	commandCmd.AddCommand(commandResetBallsCmd)
 	root.AddCommand(commandCmd)
 }
 
@@ -45,18 +99,32 @@ var fetchCmd = &cobra.Command{
 	Use:   "fetch",
 	Short: "Trigger a fetch for new matches from Playtomic",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		path := "/fetch"
+		query := url.Values{}
 		if days > 0 {
-			path = fmt.Sprintf("/fetch?days=%d", days)
+			query.Set("days", fmt.Sprint(days))
+		}
+		if fromDate != "" {
+			query.Set("from", fromDate)
+		}
+		if toDate != "" {
+			query.Set("to", toDate)
+		}
+		path := "/fetch"
+		if len(query) > 0 {
+			path += "?" + query.Encode()
 		}
 		return performPostRequest(path, nil)
 	},
 }
 
 var processCmd = &cobra.Command{
-	Use:   "process",
-	Short: "Trigger the processing of fetched matches",
+	Use:   "process [matchID]",
+	Short: "Trigger the processing of fetched matches, or of a single match",
+	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 {
+			return performPostRequest("/process/"+url.PathEscape(args[0]), nil)
+		}
 		return performPostRequest("/process", nil)
 	},
 }
@@ -73,18 +141,114 @@ var matchesCmd = &cobra.Command{
 	Use:   "matches",
 	Short: "List all processed matches",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if includeDeleted {
+			return performGetRequest("/matches?include_deleted=true")
+		}
 		return performGetRequest("/matches")
 	},
 }
 
+var undeleteCmd = &cobra.Command{
+	Use:   "undelete [matchID]",
+	Short: "Restore a match that was cleared",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return performPostRequest("/matches/undelete?"+url.Values{"matchID": {args[0]}}.Encode(), nil)
+	},
+}
+
+var weekMatchesCmd = &cobra.Command{
+	Use:   "week-matches [YYYY-MM-DD]",
+	Short: "List the matches of the week (Monday to Sunday, UTC) containing the given date",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return performGetRequest("/matches/week?" + url.Values{"date": {args[0]}}.Encode())
+	},
+}
+
+var weeklyRecapCmd = &cobra.Command{
+	Use:   "weekly-recap [YYYY-MM-DD]",
+	Short: "Post the recap of the week containing the given date (default: the previous week)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 {
+			return performPostRequest("/recap/weekly?"+url.Values{"date": {args[0]}}.Encode(), nil)
+		}
+		return performPostRequest("/recap/weekly", nil)
+	},
+}
+
+var dailyDigestCmd = &cobra.Command{
+	Use:   "daily-digest",
+	Short: "Post the digest of new bookings (requires DAILY_BOOKING_DIGEST=true on the server)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return performPostRequest("/digest/daily", nil)
+	},
+}
+
+var processingStatsCmd = &cobra.Command{
+	Use:   "processing-stats",
+	Short: "Show how many matches are in each processing status",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return performGetRequest("/stats/processing")
+	},
+}
+
+var migrationsCmd = &cobra.Command{
+	Use:   "migrations",
+	Short: "Show the applied schema migrations and the current schema version",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return performGetRequest("/admin/migrations")
+	},
+}
+
+var reencodeBlobsCmd = &cobra.Command{
+	Use:   "reencode-blobs",
+	Short: "Re-encode match teams and results stored in an older blob version",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return performPostRequest("/admin/migrations/blobs", nil)
+	},
+}
+
 var leaderboardCmd = &cobra.Command{
 	Use:   "leaderboard",
 	Short: "Get the player statistics leaderboard",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		query := url.Values{}
+		if leaderboardSort != "" {
+			query.Set("sort", leaderboardSort)
+		}
+		if leaderboardCompetitive {
+			query.Set("competitive", "true")
+		}
+		if leaderboardAllTime {
+			query.Set("all_time", "true")
+		}
+		if len(query) > 0 {
+			return performGetRequest("/leaderboard?" + query.Encode())
+		}
 		return performGetRequest("/leaderboard")
 	},
 }
 
+var spendCmd = &cobra.Command{
+	Use:   "spend",
+	Short: "Get each player's share of match prices",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query := url.Values{}
+		if spendFrom != "" {
+			query.Set("from", spendFrom)
+		}
+		if spendTo != "" {
+			query.Set("to", spendTo)
+		}
+		if len(query) > 0 {
+			return performGetRequest("/reports/spend?" + query.Encode())
+		}
+		return performGetRequest("/reports/spend")
+	},
+}
+
 var metricsCmd = &cobra.Command{
 	Use:   "metrics",
 	Short: "Get application metrics",
@@ -95,17 +259,59 @@ var metricsCmd = &cobra.Command{
 
 var clearCmd = &cobra.Command{
 	Use:   "clear [matchID]",
-	Short: "Clear the internal store, or a specific match if matchID is provided",
+	Short: "Clear a specific match, or the entire internal store with --confirm",
 	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		path := "/clear"
 		if len(args) > 0 {
 			path = fmt.Sprintf("/clear?matchID=%s", args[0])
+		} else if clearConfirm {
+			path = "/clear?confirm=true"
 		}
 		return performPostRequest(path, nil)
 	},
 }
 
+var mergePlayersCmd = &cobra.Command{
+	Use:   "merge-players [keepID] [mergeID]",
+	Short: "Merge a duplicate player record into another, summing their stats",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query := url.Values{}
+		query.Set("keep", args[0])
+		query.Set("merge", args[1])
+		return performPostRequest("/players/merge?"+query.Encode(), nil)
+	},
+}
+
+var slackUserCmd = &cobra.Command{
+	Use:   "slack-user [playerID] [slackUserID]",
+	Short: "Map a player to their Slack user, or remove the mapping if no Slack user is given",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query := url.Values{}
+		query.Set("player", args[0])
+		if len(args) > 1 {
+			query.Set("slack_user", args[1])
+		}
+		return performPostRequest("/players/slack-user?"+query.Encode(), nil)
+	},
+}
+
+var corePlayerCmd = &cobra.Command{
+	Use:   "core-player [playerID] [true|false]",
+	Short: "Flag a player as a core member, whose bookings are always club matches",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query := url.Values{}
+		query.Set("player", args[0])
+		if len(args) > 1 {
+			query.Set("core", args[1])
+		}
+		return performPostRequest("/players/core?"+query.Encode(), nil)
+	},
+}
+
 var commandCmd = &cobra.Command{
 	Use:   "command",
 	Short: "Execute Slack commands",
@@ -119,6 +325,14 @@ var commandLeaderboardCmd = &cobra.Command{
 	},
 }
 
+var commandHelpCmd = &cobra.Command{
+	Use:   "help",
+	Short: "List the available Slack commands formatted for Slack",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return performPostRequest("/slack/command/help", nil)
+	},
+}
+
 var commandLevelLeaderboardCmd = &cobra.Command{
 	Use:   "level-leaderboard",
 	Short: "Get the level leaderboard formatted for Slack",
@@ -138,6 +352,181 @@ var commandPlayerStatsCmd = &cobra.Command{
 	},
 }
 
+var commandBallsCmd = &cobra.Command{
+	Use:   "balls",
+	Short: "Get the ball bringer standings formatted for Slack",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return performPostRequest("/slack/command/balls", nil)
+	},
+}
+
+var commandRosterCmd = &cobra.Command{
+	Use:   "roster [page]",
+	Short: "Get the club roster formatted for Slack",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		form := url.Values{}
+		if len(args) > 0 {
+			form.Set("text", args[0])
+		}
+		return performPostRequest("/slack/command/roster", strings.NewReader(form.Encode()))
+	},
+}
+
+var commandFormCmd = &cobra.Command{
+	Use:   "form [name]",
+	Short: "Get a player's recent results formatted for Slack",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		form := url.Values{}
+		form.Add("text", args[0])
+		return performPostRequest("/slack/command/form", strings.NewReader(form.Encode()))
+	},
+}
+
+var commandLeaderboardDiffCmd = &cobra.Command{
+	Use:   "leaderboard-diff [before] [after]",
+	Short: "Compare player stats between two periods (this-week, last-week or YYYY-MM-DD..YYYY-MM-DD)",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		form := url.Values{}
+		form.Add("text", strings.Join(args, " "))
+		return performPostRequest("/slack/command/leaderboard-diff", strings.NewReader(form.Encode()))
+	},
+}
+
+var commandReportResultCmd = &cobra.Command{
+	Use:   "report-result [slackUserID] [matchID] [set scores]",
+	Short: "Report a match result as the given Slack user",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		form := url.Values{}
+		form.Add("user_id", args[0])
+		form.Add("text", args[1]+" "+args[2])
+		return performPostRequest("/slack/command/report-result", strings.NewReader(form.Encode()))
+	},
+}
+
+var commandNextCmd = &cobra.Command{
+	Use:   "next [slackUserID]",
+	Short: "Get the next upcoming match for the given Slack user formatted for Slack",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		form := url.Values{}
+		form.Add("user_id", args[0])
+		return performPostRequest("/slack/command/next", strings.NewReader(form.Encode()))
+	},
+}
+
+var commandMatchStatusCmd = &cobra.Command{
+	Use:   "match-status [matchID]",
+	Short: "Explain where a match is in the processing state machine",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		form := url.Values{}
+		form.Add("text", args[0])
+		return performPostRequest("/slack/command/match-status", strings.NewReader(form.Encode()))
+	},
+}
+
+var commandNotificationsCmd = &cobra.Command{
+	Use:   "notifications [slackUserID] [on|off]",
+	Short: "Show or change whether the given Slack user receives direct messages",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		form := url.Values{}
+		form.Add("user_id", args[0])
+		if len(args) > 1 {
+			form.Add("text", args[1])
+		}
+		return performPostRequest("/slack/command/notifications", strings.NewReader(form.Encode()))
+	},
+}
+
+var commandMapCmd = &cobra.Command{
+	Use:   "map [adminSlackUserID] [slackUserID] [player name]",
+	Short: "Link a Slack user to the player matching the given name",
+	Args:  cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		form := url.Values{}
+		form.Add("user_id", args[0])
+		form.Add("text", fmt.Sprintf("<@%s> %s", args[1], strings.Join(args[2:], " ")))
+		return performPostRequest("/slack/command/map", strings.NewReader(form.Encode()))
+	},
+}
+
+var commandMergeCmd = &cobra.Command{
+	Use:   "merge [adminSlackUserID] [keepPlayerID] [mergePlayerID]",
+	Short: "Merge a duplicate player record into another",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		form := url.Values{}
+		form.Add("user_id", args[0])
+		form.Add("text", args[1]+" "+args[2])
+		return performPostRequest("/slack/command/merge", strings.NewReader(form.Encode()))
+	},
+}
+
+var commandUndoLastCmd = &cobra.Command{
+	Use:   "undo-last [adminSlackUserID] [matchID]",
+	Short: "Undo the stats of a match, or of the most recently played match if no match ID is given",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		form := url.Values{}
+		form.Add("user_id", args[0])
+		if len(args) > 1 {
+			form.Add("text", args[1])
+		}
+		return performPostRequest("/slack/command/undo-last", strings.NewReader(form.Encode()))
+	},
+}
+
+var commandRequeueCmd = &cobra.Command{
+	Use:   "requeue [adminSlackUserID] [matchID]",
+	Short: "Move a match marked for review back into processing",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		form := url.Values{}
+		form.Add("user_id", args[0])
+		form.Add("text", args[1])
+		return performPostRequest("/slack/command/requeue", strings.NewReader(form.Encode()))
+	},
+}
+
+var commandNewSeasonCmd = &cobra.Command{
+	Use:   "new-season [adminSlackUserID]",
+	Short: "Archive the current standings and start a new leaderboard season",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		form := url.Values{}
+		form.Add("user_id", args[0])
+		return performPostRequest("/slack/command/new-season", strings.NewReader(form.Encode()))
+	},
+}
+
+var commandResetBallsCmd = &cobra.Command{
+	Use:   "reset-balls [adminSlackUserID] [playerID]",
+	Short: "Reset the ball bringer count of every player, or of the given player",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		form := url.Values{}
+		form.Add("user_id", args[0])
+		if len(args) > 1 {
+			form.Add("text", args[1])
+		}
+		return performPostRequest("/slack/command/reset-balls", strings.NewReader(form.Encode()))
+	},
+}
+
+// signSlackRequest adds the timestamp and signature headers Slack sends with its requests.
+func signSlackRequest(req *http.Request, body []byte, now time.Time) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+}
+
 func performGetRequest(endpoint string) error {
 	fullURL := host + endpoint
 	if dryRun {
@@ -181,28 +570,32 @@ func performPostRequest(endpoint string, reqBody io.Reader) error {
 		fmt.Printf("Making POST request to %s\n", fullURL)
 	}
 
-	var req *http.Request
-	var err error
-
+	var buf []byte
 	if reqBody != nil {
 		// We need to buffer the body if we want to log it and send it.
-		buf, err := io.ReadAll(reqBody)
+		var err error
+		buf, err = io.ReadAll(reqBody)
 		if err != nil {
 			return fmt.Errorf("failed to read request body: %w", err)
 		}
 		if verbose {
 			fmt.Printf("Request Body: %s\n", string(buf))
 		}
-		req, err = http.NewRequest("POST", fullURL, bytes.NewBuffer(buf))
-		if err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
-		}
+	}
+
+	req, err := http.NewRequest("POST", fullURL, bytes.NewBuffer(buf))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if reqBody != nil {
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	} else {
-		req, err = http.NewRequest("POST", fullURL, nil)
-		if err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
-		}
+	}
+	// The server only runs slash commands that are signed the way Slack signs them.
+	if strings.HasPrefix(endpoint, "/slack/") && signingSecret != "" {
+		signSlackRequest(req, buf, time.Now())
+	}
+	if adminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+adminToken)
 	}
 
 	client := &http.Client{}
@@ -8,9 +8,11 @@ import (
 )
 
 var (
-	host    string
-	dryRun  bool
-	verbose bool
+	host          string
+	dryRun        bool
+	verbose       bool
+	signingSecret string
+	adminToken    string
 )
 
 var rootCmd = &cobra.Command{
@@ -24,6 +26,8 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&host, "host", "http://localhost:8080", "The host address of the server")
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the request without sending it")
 	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Print the response body")
+	rootCmd.PersistentFlags().StringVar(&signingSecret, "signing-secret", os.Getenv("SLACK_SIGNING_SECRET"), "The Slack signing secret used to sign slash commands (defaults to SLACK_SIGNING_SECRET)")
+	rootCmd.PersistentFlags().StringVar(&adminToken, "admin-token", os.Getenv("ADMIN_API_TOKEN"), "The token for the admin endpoints (defaults to ADMIN_API_TOKEN)")
 }
 
 func Execute() {
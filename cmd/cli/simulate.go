@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mauv0809/ideal-tribble/internal/playtomic"
+	"github.com/spf13/cobra"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var (
+	simulateMatchType string
+	simulateConfirmed bool
+)
+
+// simulateCmd walks a fake match through the same Pub/Sub endpoints the processor publishes to:
+// seed -> assign-ball-boy -> notify-booking -> (match played) -> notify-result -> update-player-stats.
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Seed a fake match and drive it through the processing pipeline",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var matchType playtomic.MatchType
+		switch simulateMatchType {
+		case "competitive":
+			matchType = playtomic.MatchTypeCompetition
+		case "friendly":
+			matchType = playtomic.MatchTypePractice
+		default:
+			return fmt.Errorf("unknown match type %q, expected competitive or friendly", simulateMatchType)
+		}
+
+		start := time.Now().Add(24 * time.Hour)
+		match := playtomic.PadelMatch{
+			MatchID:       fmt.Sprintf("sim-%d", time.Now().Unix()),
+			OwnerID:       "sim-p1",
+			OwnerName:     "Sim Player 1",
+			Start:         start.Unix(),
+			End:           start.Add(90 * time.Minute).Unix(),
+			CreatedAt:     time.Now().Unix(),
+			GameStatus:    playtomic.GameStatusPending,
+			ResultsStatus: playtomic.ResultsStatusWaitingFor,
+			ResourceName:  "Simulation Court",
+			MatchType:     matchType,
+			MatchTypeEnum: playtomic.MatchTypeEnumDoubles,
+			Teams: []playtomic.Team{
+				{ID: "0", Players: []playtomic.Player{{UserID: "sim-p1", Name: "Sim Player 1", Level: 2.5}, {UserID: "sim-p2", Name: "Sim Player 2", Level: 2.5}}},
+				{ID: "1", Players: []playtomic.Player{{UserID: "sim-p3", Name: "Sim Player 3", Level: 2.5}, {UserID: "sim-p4", Name: "Sim Player 4", Level: 2.5}}},
+			},
+		}
+
+		steps := []string{"/matches/seed", "/assign-ball-boy", "/notify-booking"}
+		if err := simulateSteps(&match, steps); err != nil {
+			return err
+		}
+
+		// The match is played: Playtomic would now report the game and, eventually, the confirmed result.
+		match.GameStatus = playtomic.GameStatusPlayed
+		match.Teams[0].TeamResult = "WON"
+		match.Teams[1].TeamResult = "LOST"
+		match.Results = []playtomic.SetResult{
+			{Name: "Set-1", Scores: map[string]int{"0": 6, "1": 3}},
+			{Name: "Set-2", Scores: map[string]int{"0": 6, "1": 4}},
+		}
+		if !simulateConfirmed {
+			match.ResultsStatus = playtomic.ResultsStatusWaitingFor
+			if err := simulateSteps(&match, []string{"/matches/seed"}); err != nil {
+				return err
+			}
+			fmt.Println("Results are not confirmed, so the processor leaves the match waiting for confirmation.")
+			return nil
+		}
+		match.ResultsStatus = playtomic.ResultsStatusConfirmed
+		return simulateSteps(&match, []string{"/matches/seed", "/notify-result", "/update-player-stats"})
+	},
+}
+
+// simulateSteps posts the match to each endpoint in turn and prints its processing status afterwards.
+func simulateSteps(match *playtomic.PadelMatch, endpoints []string) error {
+	for _, endpoint := range endpoints {
+		body, err := pubSubEnvelope(match)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("==> %s\n", endpoint)
+		if err := performPostRequest(endpoint, body); err != nil {
+			return err
+		}
+		if dryRun {
+			continue
+		}
+		status, err := fetchProcessingStatus(match.MatchID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Status: %s\n", status)
+		match.ProcessingStatus = status
+	}
+	return nil
+}
+
+// pubSubEnvelope encodes the match the way a Pub/Sub push subscription delivers it.
+func pubSubEnvelope(match *playtomic.PadelMatch) (*bytes.Reader, error) {
+	data, err := msgpack.Marshal(match)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode match: %w", err)
+	}
+	body, err := json.Marshal(map[string]any{
+		"subscription": "cli-simulate",
+		"message":      map[string]string{"data": base64.StdEncoding.EncodeToString(data)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode envelope: %w", err)
+	}
+	return bytes.NewReader(body), nil
+}
+
+// fetchProcessingStatus looks the match up in /matches and returns its stored processing status.
+func fetchProcessingStatus(matchID string) (playtomic.ProcessingStatus, error) {
+	resp, err := http.Get(host + "/matches")
+	if err != nil {
+		return "", fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var matches []playtomic.PadelMatch
+	if err := json.NewDecoder(resp.Body).Decode(&matches); err != nil {
+		return "", fmt.Errorf("failed to decode matches: %w", err)
+	}
+	for _, match := range matches {
+		if match.MatchID == matchID {
+			return match.ProcessingStatus, nil
+		}
+	}
+	return "", fmt.Errorf("match %s not found", matchID)
+}
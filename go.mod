@@ -10,7 +10,6 @@ require (
 	github.com/mattn/go-sqlite3 v1.14.28
 	github.com/pressly/goose/v3 v3.24.3
 	github.com/prometheus/client_golang v1.22.0
-	github.com/rafa-garcia/go-playtomic-api v0.1.0
 	github.com/slack-go/slack v0.17.3
 	github.com/spf13/cobra v1.8.1
 	github.com/stretchr/testify v1.10.0
@@ -0,0 +1,20 @@
+package clock
+
+import "time"
+
+// Clock provides the current time. It exists so time-based logic can be tested deterministically.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is a Clock backed by the system time.
+type realClock struct{}
+
+// New returns a Clock that reports the system time.
+func New() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
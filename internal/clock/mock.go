@@ -0,0 +1,37 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Mock is a Clock whose time only changes when told to. It is safe for concurrent use.
+type Mock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewMock creates a new mock clock set to the given time.
+func NewMock(now time.Time) *Mock {
+	return &Mock{now: now}
+}
+
+func (m *Mock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// Set moves the clock to the given time.
+func (m *Mock) Set(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = now
+}
+
+// Advance moves the clock forward by the given duration.
+func (m *Mock) Advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = m.now.Add(d)
+}
@@ -0,0 +1,53 @@
+package club
+
+import (
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Serialized teams and results start with a version byte so their layout can change without breaking rows
+// written by older releases. Rows written before versioning are plain msgpack arrays (or nil), whose first byte
+// is never a msgpack positive fixint, so they are told apart from versioned blobs and read as legacyBlobVersion.
+const (
+	legacyBlobVersion  byte = 0
+	blobVersion1       byte = 1
+	currentBlobVersion      = blobVersion1
+
+	// maxBlobVersion is the largest msgpack positive fixint, which no legacy blob can start with.
+	maxBlobVersion byte = 0x7f
+)
+
+// blobDecoders decodes the payload following the version byte for each known blob version. A struct change
+// that msgpack cannot absorb gets a new version whose decoder converts the old layout into the current one.
+var blobDecoders = map[byte]func(data []byte, v any) error{
+	legacyBlobVersion: msgpack.Unmarshal,
+	blobVersion1:      msgpack.Unmarshal,
+}
+
+// encodeBlob serializes v for a blob column, prefixed with the current blob version.
+func encodeBlob(v any) ([]byte, error) {
+	data, err := msgpack.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{currentBlobVersion}, data...), nil
+}
+
+// decodeBlob deserializes a blob written by encodeBlob, or by an older release, into v.
+func decodeBlob(blob []byte, v any) error {
+	version, data := blobVersion(blob)
+	decode, ok := blobDecoders[version]
+	if !ok {
+		return fmt.Errorf("unsupported blob version %d", version)
+	}
+	return decode(data, v)
+}
+
+// blobVersion splits a blob into its version and payload.
+func blobVersion(blob []byte) (byte, []byte) {
+	if len(blob) == 0 || blob[0] > maxBlobVersion {
+		return legacyBlobVersion, blob
+	}
+	return blob[0], blob[1:]
+}
@@ -0,0 +1,64 @@
+package club
+
+import (
+	"testing"
+
+	"github.com/mauv0809/ideal-tribble/internal/playtomic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestDecodeBlob(t *testing.T) {
+	teams := []playtomic.Team{{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p1", Name: "Player One", Level: 1.5}}}}
+
+	t.Run("decodes a v1 blob", func(t *testing.T) {
+		blob, err := encodeBlob(teams)
+		require.NoError(t, err)
+		assert.Equal(t, blobVersion1, blob[0])
+
+		var decoded []playtomic.Team
+		require.NoError(t, decodeBlob(blob, &decoded))
+		assert.Equal(t, teams, decoded)
+	})
+
+	t.Run("decodes an unversioned legacy blob", func(t *testing.T) {
+		blob, err := msgpack.Marshal(teams)
+		require.NoError(t, err)
+
+		var decoded []playtomic.Team
+		require.NoError(t, decodeBlob(blob, &decoded))
+		assert.Equal(t, teams, decoded)
+	})
+
+	t.Run("decodes a v2 blob through its registered decoder", func(t *testing.T) {
+		// Simulate a future layout that wraps the teams in an envelope, with a decoder converting it back.
+		const blobVersion2 byte = 2
+		type teamsV2 struct {
+			Teams []playtomic.Team
+		}
+		blobDecoders[blobVersion2] = func(data []byte, v any) error {
+			var envelope teamsV2
+			if err := msgpack.Unmarshal(data, &envelope); err != nil {
+				return err
+			}
+			*v.(*[]playtomic.Team) = envelope.Teams
+			return nil
+		}
+		defer delete(blobDecoders, blobVersion2)
+
+		data, err := msgpack.Marshal(teamsV2{Teams: teams})
+		require.NoError(t, err)
+		blob := append([]byte{blobVersion2}, data...)
+
+		var decoded []playtomic.Team
+		require.NoError(t, decodeBlob(blob, &decoded))
+		assert.Equal(t, teams, decoded)
+	})
+
+	t.Run("rejects an unknown version", func(t *testing.T) {
+		var decoded []playtomic.Team
+		err := decodeBlob([]byte{0x42, 0x90}, &decoded)
+		assert.ErrorContains(t, err, "unsupported blob version 66")
+	})
+}
@@ -0,0 +1,43 @@
+package club
+
+import "sort"
+
+// DiffPlayerStats returns each player's change in stats from the before period to the after period, ordered by
+// the most wins gained. Players who only appear in one of the periods count as zero in the other.
+func DiffPlayerStats(before, after []PlayerStats) []PlayerStatsDelta {
+	deltas := make(map[string]*PlayerStatsDelta)
+	delta := func(stat PlayerStats) *PlayerStatsDelta {
+		d, ok := deltas[stat.PlayerID]
+		if !ok {
+			d = &PlayerStatsDelta{PlayerID: stat.PlayerID, PlayerName: stat.PlayerName}
+			deltas[stat.PlayerID] = d
+		}
+		return d
+	}
+	for _, stat := range after {
+		d := delta(stat)
+		d.MatchesPlayed += stat.MatchesPlayed
+		d.MatchesWon += stat.MatchesWon
+		d.SetsWon += stat.SetsWon
+		d.GamesWon += stat.GamesWon
+	}
+	for _, stat := range before {
+		d := delta(stat)
+		d.MatchesPlayed -= stat.MatchesPlayed
+		d.MatchesWon -= stat.MatchesWon
+		d.SetsWon -= stat.SetsWon
+		d.GamesWon -= stat.GamesWon
+	}
+
+	result := make([]PlayerStatsDelta, 0, len(deltas))
+	for _, d := range deltas {
+		result = append(result, *d)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].MatchesWon != result[j].MatchesWon {
+			return result[i].MatchesWon > result[j].MatchesWon
+		}
+		return result[i].PlayerName < result[j].PlayerName
+	})
+	return result
+}
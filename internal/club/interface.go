@@ -8,19 +8,55 @@ type ClubStore interface {
 	UpsertMatches(matches []*playtomic.PadelMatch) error
 	UpdateProcessingStatus(matchID string, status playtomic.ProcessingStatus) error
 	GetMatchesForProcessing() ([]*playtomic.PadelMatch, error)
-	GetPlayerStats() ([]PlayerStats, error)
+	GetProcessingStatusCounts() (map[playtomic.ProcessingStatus]int, error)
+	CountMatches() (int, error)
+	GetMatchesStuckInStatus(status playtomic.ProcessingStatus, before int64) ([]*playtomic.PadelMatch, error)
+	GetUndigestedBookings() ([]*playtomic.PadelMatch, error)
+	MarkBookingsDigested(matchIDs []string) error
+	GetPlayerStats(minMatches int, sortBy StatsSortBy, competitiveOnly, allTime bool) ([]PlayerStats, error)
 	UpdatePlayerStats(match *playtomic.PadelMatch)
+	UndoMatchStats(matchID string) error
+	RequeueMatch(matchID string) (playtomic.ProcessingStatus, error)
+	GetResultCorrections() ([]ResultCorrection, error)
+	CorrectMatchStats(matchID string) error
+	GetLastStatsAppliedMatchID() (string, bool, error)
+	StartNewSeason() (Season, error)
 	AddPlayer(playerID, name string, level float64)
-	UpsertPlayers(players []PlayerInfo) error
+	UpsertPlayers(players []PlayerInfo) ([]PlayerInfo, error)
 	IsKnownPlayer(playerID string) bool
+	IsCorePlayer(playerID string) bool
+	SetCorePlayer(playerID string, core bool) error
+	IsDMOptedOut(playerID string) bool
+	SetDMOptOut(playerID string, optOut bool) error
 	Clear()
 	ClearMatch(matchID string)
+	UndeleteMatch(matchID string) error
+	CountPlayers() (int, error)
 	GetAllPlayers() ([]PlayerInfo, error)
 	GetPlayersSortedByLevel() ([]PlayerInfo, error)
-	GetAllMatches() ([]*playtomic.PadelMatch, error)
-	GetPlayerStatsByName(playerName string) (*PlayerStats, error)
+	GetBallBringerCounts() ([]PlayerInfo, error)
+	ResetBallBringerCounts() error
+	ResetBallBringerCount(playerID string) error
+	GetPlayerSpend(from, to int64) ([]PlayerSpend, error)
+	GetAllMatches(includeDeleted bool) ([]*playtomic.PadelMatch, error)
+	GetMatchesForWeek(weekStartDate int64) ([]*playtomic.PadelMatch, error)
+	GetMatch(matchID string) (*playtomic.PadelMatch, error)
+	GetNextMatchForPlayer(playerID string) (*playtomic.PadelMatch, error)
+	GetRecentForm(playerID string, n int) ([]bool, error)
+	GetStatsForRange(from, to int64) ([]PlayerStats, error)
+	GetPlayerStatsByName(playerName string, competitiveOnly bool) (*PlayerStats, error)
+	GetPlayerStatsByID(playerID string, competitiveOnly bool) (*PlayerStats, error)
 	GetPlayers(playerIDs []string) ([]PlayerInfo, error)
 	SetBallBringer(matchID, playerID, playerName string) error // Deprecated: Use AssignBallBringerAtomically instead
 	AssignBallBringerAtomically(matchID string, playerIDs []string) (string, string, error)
 	UpdateNotificationTimestamp(matchID string, notificationType string) error
+	SetBookingMessageTS(matchID, ts string) error
+	MarkLineupReminded(matchID string) (bool, error)
+	MergePlayers(keepID, mergeID string) error
+	ReencodeMatchBlobs() (int, error)
+	FindPlayersByName(name string) ([]PlayerInfo, error)
+	GetUnmappedPlayers() ([]PlayerInfo, error)
+	SetSlackUserID(playerID, slackUserID string) error
+	GetSlackUserIDByPlayerID(playerID string) (string, bool, error)
+	GetPlayerBySlackUserID(slackUserID string) (*PlayerInfo, bool, error)
 }
@@ -0,0 +1,41 @@
+package club
+
+import "sync"
+
+// matchLocks hands out a mutex per match ID, so work on one match doesn't wait for unrelated matches.
+// Entries are removed once no caller holds or waits for them.
+type matchLocks struct {
+	mu    sync.Mutex
+	locks map[string]*matchLock
+}
+
+type matchLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock locks the given match and returns the function that unlocks it.
+func (l *matchLocks) lock(matchID string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*matchLock)
+	}
+	ml, ok := l.locks[matchID]
+	if !ok {
+		ml = &matchLock{}
+		l.locks[matchID] = ml
+	}
+	ml.refs++
+	l.mu.Unlock()
+
+	ml.Lock()
+	return func() {
+		ml.Unlock()
+		l.mu.Lock()
+		ml.refs--
+		if ml.refs == 0 {
+			delete(l.locks, matchID)
+		}
+		l.mu.Unlock()
+	}
+}
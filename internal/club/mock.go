@@ -16,21 +16,57 @@ type MockStore struct {
 	UpsertMatchesFunc               func(matches []*playtomic.PadelMatch) error
 	UpdateProcessingStatusFunc      func(matchID string, status playtomic.ProcessingStatus) error
 	GetMatchesForProcessingFunc     func() ([]*playtomic.PadelMatch, error)
-	GetPlayerStatsFunc              func() ([]PlayerStats, error)
+	GetProcessingStatusCountsFunc   func() (map[playtomic.ProcessingStatus]int, error)
+	CountMatchesFunc                func() (int, error)
+	GetMatchesStuckInStatusFunc     func(status playtomic.ProcessingStatus, before int64) ([]*playtomic.PadelMatch, error)
+	GetUndigestedBookingsFunc       func() ([]*playtomic.PadelMatch, error)
+	MarkBookingsDigestedFunc        func(matchIDs []string) error
+	GetPlayerStatsFunc              func(minMatches int, sortBy StatsSortBy, competitiveOnly, allTime bool) ([]PlayerStats, error)
 	UpdatePlayerStatsFunc           func(match *playtomic.PadelMatch)
+	UndoMatchStatsFunc              func(matchID string) error
+	RequeueMatchFunc                func(matchID string) (playtomic.ProcessingStatus, error)
+	GetResultCorrectionsFunc        func() ([]ResultCorrection, error)
+	CorrectMatchStatsFunc           func(matchID string) error
+	GetLastStatsAppliedMatchIDFunc  func() (string, bool, error)
+	StartNewSeasonFunc              func() (Season, error)
 	AddPlayerFunc                   func(playerID, name string, level float64)
-	UpsertPlayersFunc               func(players []PlayerInfo) error
+	UpsertPlayersFunc               func(players []PlayerInfo) ([]PlayerInfo, error)
 	IsKnownPlayerFunc               func(playerID string) bool
+	IsCorePlayerFunc                func(playerID string) bool
+	SetCorePlayerFunc               func(playerID string, core bool) error
+	IsDMOptedOutFunc                func(playerID string) bool
+	SetDMOptOutFunc                 func(playerID string, optOut bool) error
 	ClearFunc                       func()
 	ClearMatchFunc                  func(matchID string)
+	UndeleteMatchFunc               func(matchID string) error
+	CountPlayersFunc                func() (int, error)
 	GetAllPlayersFunc               func() ([]PlayerInfo, error)
 	GetPlayersSortedByLevelFunc     func() ([]PlayerInfo, error)
-	GetAllMatchesFunc               func() ([]*playtomic.PadelMatch, error)
-	GetPlayerStatsByNameFunc        func(playerName string) (*PlayerStats, error)
+	GetBallBringerCountsFunc        func() ([]PlayerInfo, error)
+	ResetBallBringerCountsFunc      func() error
+	ResetBallBringerCountFunc       func(playerID string) error
+	GetPlayerSpendFunc              func(from, to int64) ([]PlayerSpend, error)
+	GetAllMatchesFunc               func(includeDeleted bool) ([]*playtomic.PadelMatch, error)
+	GetMatchesForWeekFunc           func(weekStartDate int64) ([]*playtomic.PadelMatch, error)
+	GetMatchFunc                    func(matchID string) (*playtomic.PadelMatch, error)
+	GetNextMatchForPlayerFunc       func(playerID string) (*playtomic.PadelMatch, error)
+	GetRecentFormFunc               func(playerID string, n int) ([]bool, error)
+	GetStatsForRangeFunc            func(from, to int64) ([]PlayerStats, error)
+	GetPlayerStatsByNameFunc        func(playerName string, competitiveOnly bool) (*PlayerStats, error)
+	GetPlayerStatsByIDFunc          func(playerID string, competitiveOnly bool) (*PlayerStats, error)
 	GetPlayersFunc                  func(playerIDs []string) ([]PlayerInfo, error)
 	SetBallBringerFunc              func(matchID, playerID, playerName string) error
 	AssignBallBringerAtomicallyFunc func(matchID string, playerIDs []string) (string, string, error)
 	UpdateNotificationTimestampFunc func(matchID string, notificationType string) error
+	SetBookingMessageTSFunc         func(matchID, ts string) error
+	MarkLineupRemindedFunc          func(matchID string) (bool, error)
+	MergePlayersFunc                func(keepID, mergeID string) error
+	ReencodeMatchBlobsFunc          func() (int, error)
+	FindPlayersByNameFunc           func(name string) ([]PlayerInfo, error)
+	GetUnmappedPlayersFunc          func() ([]PlayerInfo, error)
+	SetSlackUserIDFunc              func(playerID, slackUserID string) error
+	GetSlackUserIDByPlayerIDFunc    func(playerID string) (string, bool, error)
+	GetPlayerBySlackUserIDFunc      func(slackUserID string) (*PlayerInfo, bool, error)
 
 	// Call records
 	UpsertPlayersCalls          [][]PlayerInfo
@@ -47,6 +83,7 @@ type MockStore struct {
 		MatchID   string
 		PlayerIDs []string
 	}
+	GetSlackUserIDByPlayerIDCalls []string
 }
 
 // NewMockStore creates a new mock instance.
@@ -107,11 +144,56 @@ func (m *MockStore) GetMatchesForProcessing() ([]*playtomic.PadelMatch, error) {
 	return nil, nil
 }
 
-func (m *MockStore) GetPlayerStats() ([]PlayerStats, error) {
+func (m *MockStore) GetProcessingStatusCounts() (map[playtomic.ProcessingStatus]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetProcessingStatusCountsFunc != nil {
+		return m.GetProcessingStatusCountsFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockStore) CountMatches() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.CountMatchesFunc != nil {
+		return m.CountMatchesFunc()
+	}
+	return 0, nil
+}
+
+func (m *MockStore) GetMatchesStuckInStatus(status playtomic.ProcessingStatus, before int64) ([]*playtomic.PadelMatch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetMatchesStuckInStatusFunc != nil {
+		return m.GetMatchesStuckInStatusFunc(status, before)
+	}
+	return nil, nil
+}
+
+func (m *MockStore) GetUndigestedBookings() ([]*playtomic.PadelMatch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetUndigestedBookingsFunc != nil {
+		return m.GetUndigestedBookingsFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockStore) MarkBookingsDigested(matchIDs []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.MarkBookingsDigestedFunc != nil {
+		return m.MarkBookingsDigestedFunc(matchIDs)
+	}
+	return nil
+}
+
+func (m *MockStore) GetPlayerStats(minMatches int, sortBy StatsSortBy, competitiveOnly, allTime bool) ([]PlayerStats, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if m.GetPlayerStatsFunc != nil {
-		return m.GetPlayerStatsFunc()
+		return m.GetPlayerStatsFunc(minMatches, sortBy, competitiveOnly, allTime)
 	}
 	return nil, nil
 }
@@ -124,6 +206,60 @@ func (m *MockStore) UpdatePlayerStats(match *playtomic.PadelMatch) {
 	}
 }
 
+func (m *MockStore) UndoMatchStats(matchID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.UndoMatchStatsFunc != nil {
+		return m.UndoMatchStatsFunc(matchID)
+	}
+	return nil
+}
+
+func (m *MockStore) RequeueMatch(matchID string) (playtomic.ProcessingStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.RequeueMatchFunc != nil {
+		return m.RequeueMatchFunc(matchID)
+	}
+	return playtomic.StatusNew, nil
+}
+
+func (m *MockStore) GetResultCorrections() ([]ResultCorrection, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetResultCorrectionsFunc != nil {
+		return m.GetResultCorrectionsFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockStore) CorrectMatchStats(matchID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.CorrectMatchStatsFunc != nil {
+		return m.CorrectMatchStatsFunc(matchID)
+	}
+	return nil
+}
+
+func (m *MockStore) GetLastStatsAppliedMatchID() (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetLastStatsAppliedMatchIDFunc != nil {
+		return m.GetLastStatsAppliedMatchIDFunc()
+	}
+	return "", false, nil
+}
+
+func (m *MockStore) StartNewSeason() (Season, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.StartNewSeasonFunc != nil {
+		return m.StartNewSeasonFunc()
+	}
+	return Season{}, nil
+}
+
 func (m *MockStore) AddPlayer(playerID, name string, level float64) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -132,13 +268,13 @@ func (m *MockStore) AddPlayer(playerID, name string, level float64) {
 	}
 }
 
-func (m *MockStore) UpsertPlayers(players []PlayerInfo) error {
+func (m *MockStore) UpsertPlayers(players []PlayerInfo) ([]PlayerInfo, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if m.UpsertPlayersFunc != nil {
 		return m.UpsertPlayersFunc(players)
 	}
-	return nil
+	return nil, nil
 }
 
 func (m *MockStore) IsKnownPlayer(playerID string) bool {
@@ -150,6 +286,42 @@ func (m *MockStore) IsKnownPlayer(playerID string) bool {
 	return false
 }
 
+func (m *MockStore) IsCorePlayer(playerID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.IsCorePlayerFunc != nil {
+		return m.IsCorePlayerFunc(playerID)
+	}
+	return false
+}
+
+func (m *MockStore) SetCorePlayer(playerID string, core bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.SetCorePlayerFunc != nil {
+		return m.SetCorePlayerFunc(playerID, core)
+	}
+	return nil
+}
+
+func (m *MockStore) IsDMOptedOut(playerID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.IsDMOptedOutFunc != nil {
+		return m.IsDMOptedOutFunc(playerID)
+	}
+	return false
+}
+
+func (m *MockStore) SetDMOptOut(playerID string, optOut bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.SetDMOptOutFunc != nil {
+		return m.SetDMOptOutFunc(playerID, optOut)
+	}
+	return nil
+}
+
 func (m *MockStore) Clear() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -166,6 +338,24 @@ func (m *MockStore) ClearMatch(matchID string) {
 	}
 }
 
+func (m *MockStore) UndeleteMatch(matchID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.UndeleteMatchFunc != nil {
+		return m.UndeleteMatchFunc(matchID)
+	}
+	return nil
+}
+
+func (m *MockStore) CountPlayers() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.CountPlayersFunc != nil {
+		return m.CountPlayersFunc()
+	}
+	return 0, nil
+}
+
 func (m *MockStore) GetAllPlayers() ([]PlayerInfo, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -184,21 +374,111 @@ func (m *MockStore) GetPlayersSortedByLevel() ([]PlayerInfo, error) {
 	return nil, nil
 }
 
-func (m *MockStore) GetAllMatches() ([]*playtomic.PadelMatch, error) {
+func (m *MockStore) GetBallBringerCounts() ([]PlayerInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetBallBringerCountsFunc != nil {
+		return m.GetBallBringerCountsFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockStore) ResetBallBringerCounts() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ResetBallBringerCountsFunc != nil {
+		return m.ResetBallBringerCountsFunc()
+	}
+	return nil
+}
+
+func (m *MockStore) ResetBallBringerCount(playerID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ResetBallBringerCountFunc != nil {
+		return m.ResetBallBringerCountFunc(playerID)
+	}
+	return nil
+}
+
+func (m *MockStore) GetPlayerSpend(from, to int64) ([]PlayerSpend, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetPlayerSpendFunc != nil {
+		return m.GetPlayerSpendFunc(from, to)
+	}
+	return nil, nil
+}
+
+func (m *MockStore) GetAllMatches(includeDeleted bool) ([]*playtomic.PadelMatch, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if m.GetAllMatchesFunc != nil {
-		return m.GetAllMatchesFunc()
+		return m.GetAllMatchesFunc(includeDeleted)
+	}
+	return nil, nil
+}
+
+func (m *MockStore) GetMatchesForWeek(weekStartDate int64) ([]*playtomic.PadelMatch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetMatchesForWeekFunc != nil {
+		return m.GetMatchesForWeekFunc(weekStartDate)
+	}
+	return nil, nil
+}
+
+func (m *MockStore) GetMatch(matchID string) (*playtomic.PadelMatch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetMatchFunc != nil {
+		return m.GetMatchFunc(matchID)
+	}
+	return nil, nil
+}
+
+func (m *MockStore) GetNextMatchForPlayer(playerID string) (*playtomic.PadelMatch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetNextMatchForPlayerFunc != nil {
+		return m.GetNextMatchForPlayerFunc(playerID)
+	}
+	return nil, nil
+}
+
+func (m *MockStore) GetStatsForRange(from, to int64) ([]PlayerStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetStatsForRangeFunc != nil {
+		return m.GetStatsForRangeFunc(from, to)
 	}
 	return nil, nil
 }
 
-func (m *MockStore) GetPlayerStatsByName(playerName string) (*PlayerStats, error) {
+func (m *MockStore) GetRecentForm(playerID string, n int) ([]bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetRecentFormFunc != nil {
+		return m.GetRecentFormFunc(playerID, n)
+	}
+	return nil, nil
+}
+
+func (m *MockStore) GetPlayerStatsByName(playerName string, competitiveOnly bool) (*PlayerStats, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.GetPlayerStatsByNameCalls = append(m.GetPlayerStatsByNameCalls, playerName)
 	if m.GetPlayerStatsByNameFunc != nil {
-		return m.GetPlayerStatsByNameFunc(playerName)
+		return m.GetPlayerStatsByNameFunc(playerName, competitiveOnly)
+	}
+	return nil, nil
+}
+
+func (m *MockStore) GetPlayerStatsByID(playerID string, competitiveOnly bool) (*PlayerStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetPlayerStatsByIDFunc != nil {
+		return m.GetPlayerStatsByIDFunc(playerID, competitiveOnly)
 	}
 	return nil, nil
 }
@@ -245,3 +525,85 @@ func (m *MockStore) UpdateNotificationTimestamp(matchID string, notificationType
 	}
 	return nil
 }
+
+func (m *MockStore) SetBookingMessageTS(matchID, ts string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.SetBookingMessageTSFunc != nil {
+		return m.SetBookingMessageTSFunc(matchID, ts)
+	}
+	return nil
+}
+
+func (m *MockStore) MarkLineupReminded(matchID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.MarkLineupRemindedFunc != nil {
+		return m.MarkLineupRemindedFunc(matchID)
+	}
+	return true, nil
+}
+
+func (m *MockStore) MergePlayers(keepID, mergeID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.MergePlayersFunc != nil {
+		return m.MergePlayersFunc(keepID, mergeID)
+	}
+	return nil
+}
+
+func (m *MockStore) ReencodeMatchBlobs() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ReencodeMatchBlobsFunc != nil {
+		return m.ReencodeMatchBlobsFunc()
+	}
+	return 0, nil
+}
+
+func (m *MockStore) FindPlayersByName(name string) ([]PlayerInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.FindPlayersByNameFunc != nil {
+		return m.FindPlayersByNameFunc(name)
+	}
+	return nil, nil
+}
+
+func (m *MockStore) GetUnmappedPlayers() ([]PlayerInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetUnmappedPlayersFunc != nil {
+		return m.GetUnmappedPlayersFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockStore) SetSlackUserID(playerID, slackUserID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.SetSlackUserIDFunc != nil {
+		return m.SetSlackUserIDFunc(playerID, slackUserID)
+	}
+	return nil
+}
+
+func (m *MockStore) GetSlackUserIDByPlayerID(playerID string) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.GetSlackUserIDByPlayerIDCalls = append(m.GetSlackUserIDByPlayerIDCalls, playerID)
+	if m.GetSlackUserIDByPlayerIDFunc != nil {
+		return m.GetSlackUserIDByPlayerIDFunc(playerID)
+	}
+	return "", false, nil
+}
+
+func (m *MockStore) GetPlayerBySlackUserID(slackUserID string) (*PlayerInfo, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.GetPlayerBySlackUserIDFunc != nil {
+		return m.GetPlayerBySlackUserIDFunc(slackUserID)
+	}
+	return nil, false, nil
+}
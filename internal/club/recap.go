@@ -0,0 +1,66 @@
+package club
+
+import (
+	"sort"
+
+	"github.com/mauv0809/ideal-tribble/internal/playtomic"
+)
+
+// BuildWeeklyRecap summarizes the played matches among the matches of the week containing date and picks the
+// week's highlights.
+func BuildWeeklyRecap(date int64, matches []*playtomic.PadelMatch) WeeklyRecap {
+	recap := WeeklyRecap{WeekStart: getWeekStartDate(date)}
+	for _, match := range matches {
+		if match.GameStatus == playtomic.GameStatusPlayed {
+			recap.Matches = append(recap.Matches, match)
+		}
+	}
+	sort.SliceStable(recap.Matches, func(i, j int) bool { return recap.Matches[i].Start < recap.Matches[j].Start })
+
+	names := make(map[string]string)
+	wins := make(map[string]float64)
+	balls := make(map[string]float64)
+	firstLevel := make(map[string]float64)
+	levelGain := make(map[string]float64)
+	for _, match := range recap.Matches {
+		match.FillTeamResults()
+		for _, team := range match.Teams {
+			for _, player := range team.Players {
+				names[player.UserID] = player.Name
+				if team.TeamResult == "WON" {
+					wins[player.UserID]++
+				}
+				// Levels are recorded per match, so the gain is the change from the player's first match of the week.
+				if first, ok := firstLevel[player.UserID]; ok {
+					levelGain[player.UserID] = player.Level - first
+				} else {
+					firstLevel[player.UserID] = player.Level
+				}
+			}
+		}
+		if match.BallBringerID != "" {
+			names[match.BallBringerID] = match.BallBringerName
+			balls[match.BallBringerID]++
+		}
+	}
+
+	recap.TopPerformer = topHighlight(wins, names)
+	recap.MostImproved = topHighlight(levelGain, names)
+	recap.BallBringer = topHighlight(balls, names)
+	return recap
+}
+
+// topHighlight returns the player with the highest positive value, breaking ties by name. It returns nil if no
+// player has a positive value.
+func topHighlight(values map[string]float64, names map[string]string) *RecapHighlight {
+	var best *RecapHighlight
+	for playerID, value := range values {
+		if value <= 0 {
+			continue
+		}
+		if best == nil || value > best.Value || (value == best.Value && names[playerID] < best.PlayerName) {
+			best = &RecapHighlight{PlayerID: playerID, PlayerName: names[playerID], Value: value}
+		}
+	}
+	return best
+}
@@ -0,0 +1,51 @@
+package club
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/mattn/go-sqlite3"
+)
+
+var (
+	// lockRetryAttempts is how often withRetry tries an operation in total.
+	lockRetryAttempts = 4
+	// lockRetryBackoff is the wait before the first retry. It doubles with every further retry.
+	lockRetryBackoff = 50 * time.Millisecond
+)
+
+// withRetry runs fn and runs it again, with backoff, while it fails because the database is busy or locked.
+// This covers locks that outlast the busy timeout. fn must be safe to repeat, e.g. a whole transaction.
+// l is held while fn runs and released during the backoff, so a retry doesn't block the store's other callers.
+func withRetry(l sync.Locker, fn func() error) error {
+	backoff := lockRetryBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		l.Lock()
+		err = fn()
+		l.Unlock()
+		if err == nil || !isLockedError(err) || attempt >= lockRetryAttempts {
+			return err
+		}
+		log.Warn("Database is locked, retrying", "attempt", attempt, "backoff", backoff, "error", err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// isLockedError reports whether err is SQLite's SQLITE_BUSY or SQLITE_LOCKED.
+func isLockedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	// Remote databases only report the error as text.
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") || strings.Contains(msg, "SQLITE_BUSY")
+}
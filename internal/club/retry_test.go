@@ -0,0 +1,80 @@
+package club
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRetry(t *testing.T) {
+	defer func(backoff time.Duration) { lockRetryBackoff = backoff }(lockRetryBackoff)
+	lockRetryBackoff = time.Millisecond
+	var mu sync.Mutex
+
+	// failing returns an operation that fails with err the given number of times before it succeeds.
+	failing := func(times int, err error) (func() error, *int) {
+		calls := 0
+		return func() error {
+			calls++
+			if calls <= times {
+				return err
+			}
+			return nil
+		}, &calls
+	}
+
+	t.Run("retries a locked database until it succeeds", func(t *testing.T) {
+		fn, calls := failing(2, fmt.Errorf("failed to update match: %w", sqlite3.Error{Code: sqlite3.ErrBusy}))
+		assert.NoError(t, withRetry(&mu, fn))
+		assert.Equal(t, 3, *calls)
+	})
+
+	t.Run("recognizes locked errors from remote databases", func(t *testing.T) {
+		fn, calls := failing(1, errors.New("SQLITE_BUSY: database is locked"))
+		assert.NoError(t, withRetry(&mu, fn))
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		lockedErr := sqlite3.Error{Code: sqlite3.ErrLocked}
+		fn, calls := failing(lockRetryAttempts, lockedErr)
+		assert.ErrorIs(t, withRetry(&mu, fn), lockedErr)
+		assert.Equal(t, lockRetryAttempts, *calls)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		otherErr := errors.New("constraint failed")
+		fn, calls := failing(1, otherErr)
+		assert.ErrorIs(t, withRetry(&mu, fn), otherErr)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("releases the lock while backing off", func(t *testing.T) {
+		lockRetryBackoff = 100 * time.Millisecond
+		acquired := make(chan struct{})
+		calls := 0
+		err := withRetry(&mu, func() error {
+			calls++
+			if calls == 1 {
+				go func() {
+					mu.Lock()
+					mu.Unlock()
+					close(acquired)
+				}()
+				return sqlite3.Error{Code: sqlite3.ErrBusy}
+			}
+			select {
+			case <-acquired:
+				return nil
+			case <-time.After(time.Second):
+				return errors.New("lock was held during the backoff")
+			}
+		})
+		assert.NoError(t, err)
+	})
+}
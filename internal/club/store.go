@@ -2,49 +2,60 @@ package club
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/mauv0809/ideal-tribble/internal/clock"
 	"github.com/mauv0809/ideal-tribble/internal/playtomic"
-	"github.com/vmihailenco/msgpack/v5"
 )
 
 // New creates a new ClubStore.
 func New(db *sql.DB) ClubStore {
+	return NewWithClock(db, clock.New())
+}
+
+// NewWithClock creates a new club store that reads the current time from the given clock.
+func NewWithClock(db *sql.DB, clk clock.Clock) ClubStore {
 	return &store{
-		db: db,
+		db:    db,
+		clock: clk,
 	}
 }
 
 // UpsertMatch inserts a new match or updates an existing one. It is "dumb" and
 // does not change the processing status of an existing match.
 func (s *store) UpsertMatch(match *playtomic.PadelMatch) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	return withRetry(&s.mu, func() error { return s.upsertMatch(match) })
+}
 
+// upsertMatch upserts a single match in its own transaction. The caller must hold s.mu.
+func (s *store) upsertMatch(match *playtomic.PadelMatch) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
 
-	teamsBlob, err := msgpack.Marshal(match.Teams)
+	teamsBlob, err := encodeBlob(match.Teams)
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
-	resultsBlob, err := msgpack.Marshal(match.Results)
+	resultsBlob, err := encodeBlob(match.Results)
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
 
 	// This statement is the heart of the "dumb upsert".
-	// ON CONFLICT, it updates all fields EXCEPT processing_status.
+	// ON CONFLICT, it updates all fields EXCEPT processing_status. A cleared match that is fetched again is restored.
 	stmt, err := tx.Prepare(`
-		INSERT INTO matches (id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, processing_status)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO matches (id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, price_amount, price_currency, tenant_id, tenant_name, match_type, teams_blob, results_blob, processing_status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			owner_id = excluded.owner_id,
 			owner_name = excluded.owner_name,
@@ -57,11 +68,14 @@ func (s *store) UpsertMatch(match *playtomic.PadelMatch) error {
 			resource_name = excluded.resource_name,
 			access_code = excluded.access_code,
 			price = excluded.price,
+			price_amount = excluded.price_amount,
+			price_currency = excluded.price_currency,
 			tenant_id = excluded.tenant_id,
 			tenant_name = excluded.tenant_name,
 			match_type = excluded.match_type,
 			teams_blob = excluded.teams_blob,
-			results_blob = excluded.results_blob;
+			results_blob = excluded.results_blob,
+			deleted_at = NULL;
 	`)
 	if err != nil {
 		tx.Rollback()
@@ -69,7 +83,18 @@ func (s *store) UpsertMatch(match *playtomic.PadelMatch) error {
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(match.MatchID, match.OwnerID, match.OwnerName, match.Start, match.End, match.CreatedAt, match.Status, match.GameStatus, match.ResultsStatus, match.ResourceName, match.AccessCode, match.Price, match.Tenant.ID, match.Tenant.Name, match.MatchType, teamsBlob, resultsBlob, playtomic.StatusNew)
+	if err := s.recordResultChange(tx, match); err != nil {
+		tx.Rollback()
+		return err
+	}
+	result, err := resultColumns(tx, match, teamsBlob, resultsBlob)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	priceAmount, priceCurrency := parseMatchPrice(match)
+	_, err = stmt.Exec(match.MatchID, match.OwnerID, match.OwnerName, match.Start, match.End, match.CreatedAt, match.Status, result.gameStatus, result.resultsStatus, match.ResourceName, match.AccessCode, match.Price, priceAmount, priceCurrency, match.Tenant.ID, match.Tenant.Name, match.MatchType, result.teamsBlob, result.resultsBlob, playtomic.StatusNew)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -78,11 +103,91 @@ func (s *store) UpsertMatch(match *playtomic.PadelMatch) error {
 	return tx.Commit()
 }
 
+// recordResultChange compares the fetched results of a match with the results its stats were counted with. If they
+// differ, the match is marked as changed so the processor corrects its stats; if they are the same again, the mark
+// is cleared. Only results Playtomic has confirmed count as a correction, and a result reported through Slack is
+// kept, so it is never corrected. Matches whose stats have not been counted are left alone.
+func (s *store) recordResultChange(tx *sql.Tx, match *playtomic.PadelMatch) error {
+	if match.ResultsStatus != playtomic.ResultsStatusConfirmed {
+		return nil
+	}
+	var countedBlob []byte
+	var changedTs sql.NullInt64
+	var storedStatus playtomic.ResultsStatus
+	err := tx.QueryRow("SELECT counted_results_blob, results_changed_ts, results_status FROM matches WHERE id = ?", match.MatchID).Scan(&countedBlob, &changedTs, &storedStatus)
+	if err == sql.ErrNoRows || (err == nil && (countedBlob == nil || storedStatus == playtomic.ResultsStatusManuallyConfirmed)) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get counted results: %w", err)
+	}
+	counted := []playtomic.SetResult{}
+	if err := decodeBlob(countedBlob, &counted); err != nil {
+		return fmt.Errorf("failed to unmarshal counted results: %w", err)
+	}
+
+	changed := !playtomic.SameResults(counted, match.Results)
+	switch {
+	case changed && !changedTs.Valid:
+		log.Info("Match results changed after its stats were counted", "matchID", match.MatchID)
+		_, err = tx.Exec("UPDATE matches SET results_changed_ts = ? WHERE id = ?", s.clock.Now().Unix(), match.MatchID)
+	case !changed && changedTs.Valid:
+		_, err = tx.Exec("UPDATE matches SET results_changed_ts = NULL WHERE id = ?", match.MatchID)
+	}
+	return err
+}
+
+// matchResultColumns are the columns holding the result of a match.
+type matchResultColumns struct {
+	gameStatus    playtomic.GameStatus
+	resultsStatus playtomic.ResultsStatus
+	teamsBlob     []byte
+	resultsBlob   []byte
+}
+
+// resultColumns returns the result columns to upsert for a match. A result reported through Slack is kept when the
+// match is fetched again, as Playtomic usually doesn't have it yet; only a newly reported result or a cancellation
+// replaces it.
+func resultColumns(tx *sql.Tx, match *playtomic.PadelMatch, teamsBlob, resultsBlob []byte) (matchResultColumns, error) {
+	fetched := matchResultColumns{match.GameStatus, match.ResultsStatus, teamsBlob, resultsBlob}
+	if match.ResultsStatus == playtomic.ResultsStatusManuallyConfirmed || match.GameStatus == playtomic.GameStatusCanceled {
+		return fetched, nil
+	}
+	var stored matchResultColumns
+	err := tx.QueryRow("SELECT game_status, results_status, teams_blob, results_blob FROM matches WHERE id = ?", match.MatchID).Scan(&stored.gameStatus, &stored.resultsStatus, &stored.teamsBlob, &stored.resultsBlob)
+	if err == sql.ErrNoRows {
+		return fetched, nil
+	}
+	if err != nil {
+		return matchResultColumns{}, fmt.Errorf("failed to get stored result of match %s: %w", match.MatchID, err)
+	}
+	if stored.resultsStatus != playtomic.ResultsStatusManuallyConfirmed {
+		return fetched, nil
+	}
+	log.Debug("Keeping manually reported result of match", "matchID", match.MatchID)
+	return stored, nil
+}
+
+// parseMatchPrice returns the numeric price columns for a match, NULL when the price is empty or unparseable.
+func parseMatchPrice(match *playtomic.PadelMatch) (sql.NullInt64, sql.NullString) {
+	amount, currency, err := playtomic.ParsePrice(match.Price)
+	if err != nil {
+		log.Warn("Failed to parse match price", "error", err, "matchID", match.MatchID, "price", match.Price)
+		return sql.NullInt64{}, sql.NullString{}
+	}
+	if currency == "" {
+		return sql.NullInt64{}, sql.NullString{}
+	}
+	return sql.NullInt64{Int64: amount, Valid: true}, sql.NullString{String: currency, Valid: true}
+}
+
 // UpsertMatches inserts or updates multiple matches in a single transaction.
 func (s *store) UpsertMatches(matches []*playtomic.PadelMatch) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	return withRetry(&s.mu, func() error { return s.upsertMatches(matches) })
+}
 
+// upsertMatches upserts the matches in a single transaction. The caller must hold s.mu.
+func (s *store) upsertMatches(matches []*playtomic.PadelMatch) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -91,8 +196,8 @@ func (s *store) UpsertMatches(matches []*playtomic.PadelMatch) error {
 	defer tx.Rollback()
 
 	stmt, err := tx.Prepare(`
-		INSERT INTO matches (id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, processing_status)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO matches (id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, price_amount, price_currency, tenant_id, tenant_name, match_type, teams_blob, results_blob, processing_status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			owner_id = excluded.owner_id,
 			owner_name = excluded.owner_name,
@@ -105,11 +210,14 @@ func (s *store) UpsertMatches(matches []*playtomic.PadelMatch) error {
 			resource_name = excluded.resource_name,
 			access_code = excluded.access_code,
 			price = excluded.price,
+			price_amount = excluded.price_amount,
+			price_currency = excluded.price_currency,
 			tenant_id = excluded.tenant_id,
 			tenant_name = excluded.tenant_name,
 			match_type = excluded.match_type,
 			teams_blob = excluded.teams_blob,
-			results_blob = excluded.results_blob;
+			results_blob = excluded.results_blob,
+			deleted_at = NULL;
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
@@ -117,16 +225,25 @@ func (s *store) UpsertMatches(matches []*playtomic.PadelMatch) error {
 	defer stmt.Close()
 
 	for _, match := range matches {
-		teamsBlob, err := msgpack.Marshal(match.Teams)
+		teamsBlob, err := encodeBlob(match.Teams)
 		if err != nil {
 			return fmt.Errorf("failed to marshal teams for match %s: %w", match.MatchID, err)
 		}
-		resultsBlob, err := msgpack.Marshal(match.Results)
+		resultsBlob, err := encodeBlob(match.Results)
 		if err != nil {
 			return fmt.Errorf("failed to marshal results for match %s: %w", match.MatchID, err)
 		}
 
-		_, err = stmt.Exec(match.MatchID, match.OwnerID, match.OwnerName, match.Start, match.End, match.CreatedAt, match.Status, match.GameStatus, match.ResultsStatus, match.ResourceName, match.AccessCode, match.Price, match.Tenant.ID, match.Tenant.Name, match.MatchType, teamsBlob, resultsBlob, playtomic.StatusNew)
+		if err := s.recordResultChange(tx, match); err != nil {
+			return fmt.Errorf("failed to check results of match %s: %w", match.MatchID, err)
+		}
+		result, err := resultColumns(tx, match, teamsBlob, resultsBlob)
+		if err != nil {
+			return err
+		}
+
+		priceAmount, priceCurrency := parseMatchPrice(match)
+		_, err = stmt.Exec(match.MatchID, match.OwnerID, match.OwnerName, match.Start, match.End, match.CreatedAt, match.Status, result.gameStatus, result.resultsStatus, match.ResourceName, match.AccessCode, match.Price, priceAmount, priceCurrency, match.Tenant.ID, match.Tenant.Name, match.MatchType, result.teamsBlob, result.resultsBlob, playtomic.StatusNew)
 		if err != nil {
 			return fmt.Errorf("failed to execute statement for match %s: %w", match.MatchID, err)
 		}
@@ -137,11 +254,38 @@ func (s *store) UpsertMatches(matches []*playtomic.PadelMatch) error {
 
 // UpdateProcessingStatus transitions a match to a new state.
 func (s *store) UpdateProcessingStatus(matchID string, status playtomic.ProcessingStatus) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	return withRetry(&s.mu, func() error {
+		_, err := s.db.Exec("UPDATE matches SET processing_status = ?, status_updated_at = ? WHERE id = ?", status, s.clock.Now().Unix(), matchID)
+		return err
+	})
+}
 
-	_, err := s.db.Exec("UPDATE matches SET processing_status = ? WHERE id = ?", status, matchID)
-	return err
+// GetMatchesStuckInStatus returns the matches that have been in the given processing status since before the
+// given Unix time. Matches whose status has not changed since status changes were first recorded count as stuck.
+func (s *store) GetMatchesStuckInStatus(status playtomic.ProcessingStatus, before int64) ([]*playtomic.PadelMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, ball_bringer_id, ball_bringer_name, processing_status, booking_notified_ts, result_notified_ts, booking_message_ts, deleted_at
+		FROM matches
+		WHERE processing_status = ? AND COALESCE(status_updated_at, 0) < ? AND deleted_at IS NULL
+		ORDER BY start_time ASC
+	`, status, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query matches stuck in %s: %w", status, err)
+	}
+	defer rows.Close()
+
+	var matches []*playtomic.PadelMatch
+	for rows.Next() {
+		match, err := s.scanMatch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan match: %w", err)
+		}
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
 }
 
 // UpdateNotificationTimestamp updates the timestamp for a specific notification type for a match.
@@ -160,7 +304,7 @@ func (s *store) UpdateNotificationTimestamp(matchID string, notificationType str
 	}
 
 	query := fmt.Sprintf("UPDATE matches SET %s = ? WHERE id = ?", columnName)
-	_, err := s.db.Exec(query, time.Now().Unix(), matchID)
+	_, err := s.db.Exec(query, s.clock.Now().Unix(), matchID)
 	if err != nil {
 		return fmt.Errorf("failed to update %s timestamp for match %s: %w", notificationType, matchID, err)
 	}
@@ -168,18 +312,103 @@ func (s *store) UpdateNotificationTimestamp(matchID string, notificationType str
 	return nil
 }
 
+// GetUndigestedBookings returns the upcoming bookings waiting for the daily booking digest, ordered by start time.
+func (s *store) GetUndigestedBookings() ([]*playtomic.PadelMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, ball_bringer_id, ball_bringer_name, processing_status, booking_notified_ts, result_notified_ts, booking_message_ts, deleted_at
+		FROM matches
+		WHERE processing_status = ? AND booking_digested_ts IS NULL AND booking_notified_ts IS NULL AND deleted_at IS NULL
+		ORDER BY start_time ASC
+	`, playtomic.StatusBallBoyAssigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query undigested bookings: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []*playtomic.PadelMatch
+	for rows.Next() {
+		match, err := s.scanMatch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan match: %w", err)
+		}
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
+
+// MarkBookingsDigested records that the matches were posted in the daily booking digest, which counts as their
+// booking notification, and moves them on to BOOKING_NOTIFIED.
+func (s *store) MarkBookingsDigested(matchIDs []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := s.clock.Now().Unix()
+	for _, matchID := range matchIDs {
+		if _, err := tx.Exec(`
+			UPDATE matches
+			SET booking_digested_ts = ?, booking_notified_ts = ?, processing_status = ?, status_updated_at = ?
+			WHERE id = ?
+		`, now, now, playtomic.StatusBookingNotified, now, matchID); err != nil {
+			return fmt.Errorf("failed to mark match %s as digested: %w", matchID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Info("Marked bookings as digested", "matches", len(matchIDs))
+	return nil
+}
+
+// SetBookingMessageTS stores the Slack timestamp of the booking notification for a match.
+func (s *store) SetBookingMessageTS(matchID, ts string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.db.Exec("UPDATE matches SET booking_message_ts = ? WHERE id = ?", ts, matchID); err != nil {
+		return fmt.Errorf("failed to set booking message ts for match %s: %w", matchID, err)
+	}
+	return nil
+}
+
+// MarkLineupReminded records that the owner of the match was asked to complete its lineup. It returns false when
+// that had already been recorded, so the owner is only asked once.
+func (s *store) MarkLineupReminded(matchID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.Exec("UPDATE matches SET lineup_reminded_ts = ? WHERE id = ? AND lineup_reminded_ts IS NULL", s.clock.Now().Unix(), matchID)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark lineup reminder for match %s: %w", matchID, err)
+	}
+	marked, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark lineup reminder for match %s: %w", matchID, err)
+	}
+	return marked > 0, nil
+}
+
 // GetMatchesForProcessing retrieves all matches that are not yet in a completed state.
 func (s *store) GetMatchesForProcessing() ([]*playtomic.PadelMatch, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	rows, err := s.db.Query(`
-		SELECT id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, ball_bringer_id, ball_bringer_name, processing_status, booking_notified_ts, result_notified_ts
+		SELECT id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, ball_bringer_id, ball_bringer_name, processing_status, booking_notified_ts, result_notified_ts, booking_message_ts, deleted_at
 		FROM matches
-		WHERE processing_status != ?
+		WHERE deleted_at IS NULL
+		AND processing_status NOT IN (?, ?)
 		AND game_status != ?
 		AND (game_status != ? OR results_status != ?)
-	`, playtomic.StatusCompleted, playtomic.GameStatusCanceled, playtomic.GameStatusPlayed, playtomic.ResultsStatusWaitingFor)
+	`, playtomic.StatusCompleted, playtomic.StatusNeedsReview, playtomic.GameStatusCanceled, playtomic.GameStatusPlayed, playtomic.ResultsStatusWaitingFor)
 	if err != nil {
 		return nil, err
 	}
@@ -197,19 +426,57 @@ func (s *store) GetMatchesForProcessing() ([]*playtomic.PadelMatch, error) {
 	return matches, nil
 }
 
+// GetProcessingStatusCounts returns the number of matches in each processing status.
+// Statuses without any matches are omitted.
+func (s *store) GetProcessingStatusCounts() (map[playtomic.ProcessingStatus]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query("SELECT processing_status, COUNT(*) FROM matches WHERE deleted_at IS NULL GROUP BY processing_status")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query processing status counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[playtomic.ProcessingStatus]int)
+	for rows.Next() {
+		var status playtomic.ProcessingStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan processing status count: %w", err)
+		}
+		counts[status] = count
+	}
+	return counts, rows.Err()
+}
+
+// CountMatches returns the number of matches that are not soft-deleted.
+func (s *store) CountMatches() (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM matches WHERE deleted_at IS NULL").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count matches: %w", err)
+	}
+	return count, nil
+}
+
 // scanMatch is a helper function to scan a single match row.
 func (s *store) scanMatch(scanner interface{ Scan(...any) error }) (*playtomic.PadelMatch, error) {
 	var match playtomic.PadelMatch
 	var teamsBlob, resultsBlob []byte
 	var ballBringerID, ballBringerName sql.NullString
 	var bookingNotifiedTs, resultNotifiedTs sql.NullInt64 // New nullable timestamp fields
+	var bookingMessageTs sql.NullString
+	var deletedAt sql.NullInt64
 
 	err := scanner.Scan(
 		&match.MatchID, &match.OwnerID, &match.OwnerName, &match.Start, &match.End, &match.CreatedAt,
 		&match.Status, &match.GameStatus, &match.ResultsStatus, &match.ResourceName, &match.AccessCode, &match.Price,
 		&match.Tenant.ID, &match.Tenant.Name, &match.MatchType, &teamsBlob, &resultsBlob,
 		&ballBringerID, &ballBringerName, &match.ProcessingStatus,
-		&bookingNotifiedTs, &resultNotifiedTs, // Include new fields here
+		&bookingNotifiedTs, &resultNotifiedTs, &bookingMessageTs, &deletedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -217,6 +484,7 @@ func (s *store) scanMatch(scanner interface{ Scan(...any) error }) (*playtomic.P
 
 	match.BallBringerID = ballBringerID.String
 	match.BallBringerName = ballBringerName.String
+	match.BookingMessageTs = bookingMessageTs.String
 
 	// Assign nullable timestamps to match struct
 	if bookingNotifiedTs.Valid {
@@ -225,9 +493,12 @@ func (s *store) scanMatch(scanner interface{ Scan(...any) error }) (*playtomic.P
 	if resultNotifiedTs.Valid {
 		match.ResultNotifiedTs = &resultNotifiedTs.Int64
 	}
+	if deletedAt.Valid {
+		match.DeletedAt = &deletedAt.Int64
+	}
 
 	if len(teamsBlob) > 0 {
-		if err := msgpack.Unmarshal(teamsBlob, &match.Teams); err != nil {
+		if err := decodeBlob(teamsBlob, &match.Teams); err != nil {
 			log.Error("Failed to unmarshal teams_blob", "error", err, "matchID", match.MatchID)
 		}
 	} else {
@@ -235,7 +506,7 @@ func (s *store) scanMatch(scanner interface{ Scan(...any) error }) (*playtomic.P
 	}
 
 	if len(resultsBlob) > 0 {
-		if err := msgpack.Unmarshal(resultsBlob, &match.Results); err != nil {
+		if err := decodeBlob(resultsBlob, &match.Results); err != nil {
 			log.Error("Failed to unmarshal results_blob", "error", err, "matchID", match.MatchID)
 		}
 	} else {
@@ -259,8 +530,109 @@ func (s *store) updatePlayerStatsLocked(match *playtomic.PadelMatch) {
 		return
 	}
 
-	// Using a map to aggregate stats per player before updating the DB.
+	playerStats := aggregateMatchStats(match)
+	weekStart := getWeekStartDate(match.Start)
+
+	for playerID, stats := range playerStats {
+		if err := addPlayerStats(tx, playerID, stats, weekStart); err != nil {
+			log.Error("Failed to update player stats", "error", err, "playerID", playerID)
+		} else {
+			log.Info("Updated player stats", "playerID", playerID)
+		}
+	}
+
+	// Remember the results the stats were counted with, so a later correction by Playtomic can be detected, and the
+	// season they were counted in, so they are only undone or corrected while that season's totals are live.
+	season, err := currentSeason(tx)
+	if err != nil {
+		log.Error("Failed to get season of counted stats", "error", err, "matchID", match.MatchID)
+	}
+	countedBlob, err := encodeBlob(match.Results)
+	if err != nil {
+		log.Error("Failed to marshal counted results", "error", err, "matchID", match.MatchID)
+	} else if _, err := tx.Exec("UPDATE matches SET counted_results_blob = ?, results_changed_ts = NULL, stats_season_id = ? WHERE id = ?", countedBlob, season.ID, match.MatchID); err != nil {
+		log.Error("Failed to record counted results", "error", err, "matchID", match.MatchID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to commit player_stats transaction", "error", err)
+	}
+}
+
+// addPlayerStats adds the stats a player earned from a match to their totals and to the row for the week the match
+// was played, for period comparisons.
+func addPlayerStats(tx *sql.Tx, playerID string, stats map[string]int, weekStart int64) error {
+	_, err := tx.Exec(`
+		INSERT INTO player_stats (player_id, matches_played, matches_won, matches_lost, sets_won, sets_lost, games_won, games_lost)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(player_id) DO UPDATE SET
+			matches_played = matches_played + excluded.matches_played,
+			matches_won = matches_won + excluded.matches_won,
+			matches_lost = matches_lost + excluded.matches_lost,
+			sets_won = sets_won + excluded.sets_won,
+			sets_lost = sets_lost + excluded.sets_lost,
+			games_won = games_won + excluded.games_won,
+			games_lost = games_lost + excluded.games_lost;
+	`, playerID, stats["matches_played"], stats["matches_won"], stats["matches_lost"], stats["sets_won"], stats["sets_lost"], stats["games_won"], stats["games_lost"])
+	if err != nil {
+		return fmt.Errorf("failed to update player_stats: %w", err)
+	}
+	_, err = tx.Exec(`
+		INSERT INTO weekly_player_stats (week_start_date, player_id, matches_played, matches_won, matches_lost, sets_won, sets_lost, games_won, games_lost)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(week_start_date, player_id) DO UPDATE SET
+			matches_played = matches_played + excluded.matches_played,
+			matches_won = matches_won + excluded.matches_won,
+			matches_lost = matches_lost + excluded.matches_lost,
+			sets_won = sets_won + excluded.sets_won,
+			sets_lost = sets_lost + excluded.sets_lost,
+			games_won = games_won + excluded.games_won,
+			games_lost = games_lost + excluded.games_lost;
+	`, weekStart, playerID, stats["matches_played"], stats["matches_won"], stats["matches_lost"], stats["sets_won"], stats["sets_lost"], stats["games_won"], stats["games_lost"])
+	if err != nil {
+		return fmt.Errorf("failed to update weekly player stats for week %d: %w", weekStart, err)
+	}
+	return nil
+}
+
+// subtractPlayerStats removes the stats a player earned from a match from their totals and from the row for the
+// week the match was played.
+func subtractPlayerStats(tx *sql.Tx, playerID string, stats map[string]int, weekStart int64) error {
+	_, err := tx.Exec(`
+		UPDATE player_stats SET
+			matches_played = matches_played - ?,
+			matches_won = matches_won - ?,
+			matches_lost = matches_lost - ?,
+			sets_won = sets_won - ?,
+			sets_lost = sets_lost - ?,
+			games_won = games_won - ?,
+			games_lost = games_lost - ?
+		WHERE player_id = ?
+	`, stats["matches_played"], stats["matches_won"], stats["matches_lost"], stats["sets_won"], stats["sets_lost"], stats["games_won"], stats["games_lost"], playerID)
+	if err != nil {
+		return fmt.Errorf("failed to undo stats for player %s: %w", playerID, err)
+	}
+	_, err = tx.Exec(`
+		UPDATE weekly_player_stats SET
+			matches_played = matches_played - ?,
+			matches_won = matches_won - ?,
+			matches_lost = matches_lost - ?,
+			sets_won = sets_won - ?,
+			sets_lost = sets_lost - ?,
+			games_won = games_won - ?,
+			games_lost = games_lost - ?
+		WHERE week_start_date = ? AND player_id = ?
+	`, stats["matches_played"], stats["matches_won"], stats["matches_lost"], stats["sets_won"], stats["sets_lost"], stats["games_won"], stats["games_lost"], weekStart, playerID)
+	if err != nil {
+		return fmt.Errorf("failed to undo weekly stats for player %s: %w", playerID, err)
+	}
+	return nil
+}
+
+// aggregateMatchStats totals the stats each player in the match earned from it, keyed by player ID and player_stats column.
+func aggregateMatchStats(match *playtomic.PadelMatch) map[string]map[string]int {
 	playerStats := make(map[string]map[string]int)
+	match.FillTeamResults()
 
 	var winningTeamID string
 	for _, team := range match.Teams {
@@ -307,6 +679,10 @@ func (s *store) updatePlayerStatsLocked(match *playtomic.PadelMatch) {
 				break
 			}
 		}
+		// A match tiebreak is scored in points, so it counts as a single game for its winner.
+		if set.Tiebreak {
+			maxScore, minScore = 1, 0
+		}
 
 		// Update stats for the winning team's players
 		for _, team := range match.Teams {
@@ -326,175 +702,212 @@ func (s *store) updatePlayerStatsLocked(match *playtomic.PadelMatch) {
 			}
 		}
 	}
+	return playerStats
+}
 
-	for playerID, stats := range playerStats {
-		stmt, err := tx.Prepare(`
-			INSERT INTO player_stats (player_id, matches_played, matches_won, matches_lost, sets_won, sets_lost, games_won, games_lost)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-			ON CONFLICT(player_id) DO UPDATE SET
-				matches_played = matches_played + excluded.matches_played,
-				matches_won = matches_won + excluded.matches_won,
-				matches_lost = matches_lost + excluded.matches_lost,
-				sets_won = sets_won + excluded.sets_won,
-				sets_lost = sets_lost + excluded.sets_lost,
-				games_won = games_won + excluded.games_won,
-				games_lost = games_lost + excluded.games_lost;
-		`)
-		if err != nil {
-			log.Error("Failed to prepare player_stats statement", "error", err, "playerID", playerID)
-			continue
-		}
-		defer stmt.Close()
+// ErrStatsNotApplied is returned by UndoMatchStats when the match's stats are not currently counted,
+// either because it never reached the stats update or because it has already been undone.
+var ErrStatsNotApplied = errors.New("match stats have not been applied")
 
-		_, err = stmt.Exec(playerID, stats["matches_played"], stats["matches_won"], stats["matches_lost"], stats["sets_won"], stats["sets_lost"], stats["games_won"], stats["games_lost"])
-		if err != nil {
-			log.Error("Failed to execute player_stats statement", "error", err, "playerID", playerID)
-		} else {
-			log.Info("Updated player stats", "playerID", playerID)
+// ErrStatsArchived is returned by UndoMatchStats for a match whose stats were counted in a season that has since
+// ended, so they have been archived with that season.
+var ErrStatsArchived = errors.New("match stats belong to an archived season")
+
+// UndoMatchStats subtracts the stats a match contributed from player_stats and marks the match for review.
+// Only matches that have been played with confirmed results and have had their stats updated can be undone,
+// which also guards against undoing the same match twice.
+func (s *store) UndoMatchStats(matchID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRow(`
+		SELECT id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, ball_bringer_id, ball_bringer_name, processing_status, booking_notified_ts, result_notified_ts, booking_message_ts, deleted_at
+		FROM matches
+		WHERE id = ?
+	`, matchID)
+	match, err := s.scanMatch(row)
+	if err != nil {
+		return fmt.Errorf("failed to get match %s: %w", matchID, err)
+	}
+	if !statsApplied(match) {
+		return ErrStatsNotApplied
+	}
+	if err := checkStatsSeason(tx, matchID); err != nil {
+		return err
+	}
+
+	// Subtract what was counted, which differs from the stored results if Playtomic changed them since.
+	counted, err := countedResults(tx, matchID)
+	if err != nil {
+		return err
+	}
+	if counted != nil && !playtomic.SameResults(counted, match.Results) {
+		match = withResults(match, counted)
+	}
+	for playerID, stats := range aggregateMatchStats(match) {
+		if err := subtractPlayerStats(tx, playerID, stats, getWeekStartDate(match.Start)); err != nil {
+			return err
 		}
 	}
 
+	if _, err := tx.Exec("UPDATE matches SET processing_status = ?, status_updated_at = ?, counted_results_blob = NULL, results_changed_ts = NULL, stats_season_id = NULL WHERE id = ?", playtomic.StatusNeedsReview, s.clock.Now().Unix(), matchID); err != nil {
+		return fmt.Errorf("failed to mark match for review: %w", err)
+	}
 	if err := tx.Commit(); err != nil {
-		log.Error("Failed to commit player_stats transaction", "error", err)
+		return fmt.Errorf("failed to commit undo of match stats: %w", err)
 	}
+	log.Info("Undid player stats for match", "matchID", matchID)
+	return nil
 }
 
-// GetPlayerStatsByName retrieves the statistics for a single player by their name.
-// It performs a case-insensitive, fuzzy search (e.g., "morten" will match "Morten Voss").
-func (s *store) GetPlayerStatsByName(playerName string) (*PlayerStats, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	query := `
-		SELECT
-			p.id,
-			p.name,
-			COALESCE(ps.matches_played, 0),
-			COALESCE(ps.matches_won, 0),
-			COALESCE(ps.matches_lost, 0),
-			COALESCE(ps.sets_won, 0),
-			COALESCE(ps.sets_lost, 0),
-			COALESCE(ps.games_won, 0),
-			COALESCE(ps.games_lost, 0)
-		FROM players p
-		LEFT JOIN player_stats ps ON p.id = ps.player_id
-		WHERE p.name LIKE ? COLLATE NOCASE
-		LIMIT 1
-	`
-
-	var stat PlayerStats
-	// Use a fuzzy search pattern.
-	pattern := "%" + playerName + "%"
+// ErrMatchNotInReview is returned by RequeueMatch when there is no match marked for review with the given ID.
+var ErrMatchNotInReview = errors.New("match is not marked for review")
 
-	row := s.db.QueryRow(query, pattern)
-	err := row.Scan(
-		&stat.PlayerID,
-		&stat.PlayerName,
-		&stat.MatchesPlayed,
-		&stat.MatchesWon,
-		&stat.MatchesLost,
-		&stat.SetsWon,
-		&stat.SetsLost,
-		&stat.GamesWon,
-		&stat.GamesLost,
-	)
+// RequeueMatch moves a match marked for review back into processing and returns its new processing status. A played
+// match with a confirmed result resumes at RESULT_AVAILABLE, so its result is posted and its stats are counted again;
+// any other match starts over as NEW.
+func (s *store) RequeueMatch(matchID string) (playtomic.ProcessingStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
+	var gameStatus playtomic.GameStatus
+	var resultsStatus playtomic.ResultsStatus
+	err := s.db.QueryRow("SELECT game_status, results_status FROM matches WHERE id = ? AND processing_status = ? AND deleted_at IS NULL", matchID, playtomic.StatusNeedsReview).Scan(&gameStatus, &resultsStatus)
+	if err == sql.ErrNoRows {
+		return "", ErrMatchNotInReview
+	}
 	if err != nil {
-		if err == sql.ErrNoRows {
-			log.Info("No stats found for player matching pattern", "pattern", pattern)
-			return nil, fmt.Errorf("player matching '%s' not found", playerName)
-		}
-		log.Error("Failed to query player stats by name", "error", err, "pattern", pattern)
-		return nil, fmt.Errorf("database error: %w", err)
+		return "", fmt.Errorf("failed to get match %s: %w", matchID, err)
 	}
 
-	if stat.MatchesPlayed > 0 {
-		stat.WinPercentage = (float64(stat.MatchesWon) / float64(stat.MatchesPlayed)) * 100
+	status := playtomic.StatusNew
+	if gameStatus == playtomic.GameStatusPlayed && resultsStatus == playtomic.ResultsStatusConfirmed {
+		status = playtomic.StatusResultAvailable
 	}
-
-	log.Debug("Found player stats by name", "player", stat.PlayerName)
-	return &stat, nil
+	if _, err := s.db.Exec("UPDATE matches SET processing_status = ?, status_updated_at = ? WHERE id = ? AND processing_status = ?", status, s.clock.Now().Unix(), matchID, playtomic.StatusNeedsReview); err != nil {
+		return "", fmt.Errorf("failed to requeue match %s: %w", matchID, err)
+	}
+	log.Info("Requeued match after review", "matchID", matchID, "status", status)
+	return status, nil
 }
 
-func (s *store) GetPlayerStats() ([]PlayerStats, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// countedResults returns the results a match's stats were counted with, or nil if they were not recorded.
+func countedResults(tx *sql.Tx, matchID string) ([]playtomic.SetResult, error) {
+	var blob []byte
+	if err := tx.QueryRow("SELECT counted_results_blob FROM matches WHERE id = ?", matchID).Scan(&blob); err != nil {
+		return nil, fmt.Errorf("failed to get counted results of match %s: %w", matchID, err)
+	}
+	if blob == nil {
+		return nil, nil
+	}
+	results := []playtomic.SetResult{}
+	if err := decodeBlob(blob, &results); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal counted results of match %s: %w", matchID, err)
+	}
+	return results, nil
+}
 
-	rows, err := s.db.Query(`
-		SELECT
-			ps.player_id,
-			p.name,
-			ps.matches_played,
-			ps.matches_won,
-			ps.matches_lost,
-			ps.sets_won,
-			ps.sets_lost,
-			ps.games_won,
-			ps.games_lost
-		FROM player_stats ps
-		JOIN players p ON ps.player_id = p.id
-		ORDER BY ps.matches_won DESC, ps.sets_won DESC, ps.games_won DESC;
-	`)
+// checkStatsSeason returns ErrStatsArchived unless the match's stats were counted in the current season.
+// Matches counted before seasons were recorded on them belong to no season.
+func checkStatsSeason(tx *sql.Tx, matchID string) error {
+	var seasonID sql.NullInt64
+	if err := tx.QueryRow("SELECT stats_season_id FROM matches WHERE id = ?", matchID).Scan(&seasonID); err != nil {
+		return fmt.Errorf("failed to get stats season of match %s: %w", matchID, err)
+	}
+	season, err := currentSeason(tx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
+	if seasonID.Int64 != season.ID {
+		return ErrStatsArchived
+	}
+	return nil
+}
 
-	var stats []PlayerStats
-	for rows.Next() {
-		var stat PlayerStats
-		err := rows.Scan(
-			&stat.PlayerID,
-			&stat.PlayerName,
-			&stat.MatchesPlayed,
-			&stat.MatchesWon,
-			&stat.MatchesLost,
-			&stat.SetsWon,
-			&stat.SetsLost,
-			&stat.GamesWon,
-			&stat.GamesLost,
-		)
-		if err != nil {
-			return nil, err
-		}
-		if stat.MatchesPlayed > 0 {
-			stat.WinPercentage = (float64(stat.MatchesWon) / float64(stat.MatchesPlayed)) * 100
-		}
-		stats = append(stats, stat)
+// withResults returns a copy of the match with other results. The team results are cleared, so the winner is
+// worked out from the given results.
+func withResults(match *playtomic.PadelMatch, results []playtomic.SetResult) *playtomic.PadelMatch {
+	copied := *match
+	copied.Results = results
+	copied.Teams = make([]playtomic.Team, len(match.Teams))
+	for i, team := range match.Teams {
+		team.TeamResult = ""
+		copied.Teams[i] = team
 	}
-	return stats, nil
+	return &copied
 }
 
-func (s *store) AddPlayer(playerID, name string, level float64) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// GetResultCorrections returns the matches of the current season whose results Playtomic changed after their stats
+// were counted, together with the results the stats were counted with.
+func (s *store) GetResultCorrections() ([]ResultCorrection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	var exists bool
-	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM players WHERE id = ?)", playerID).Scan(&exists)
+	tx, err := s.db.Begin()
 	if err != nil {
-		log.Error("Failed to check if player exists", "error", err, "playerID", playerID)
-		return
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	if !exists {
-		_, err := s.db.Exec("INSERT INTO players (id, name, level) VALUES (?, ?, ?)", playerID, name, level)
+	season, err := currentSeason(tx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(`
+		SELECT id FROM matches
+		WHERE deleted_at IS NULL
+		AND processing_status IN (?, ?)
+		AND IFNULL(stats_season_id, 0) = ?
+		AND counted_results_blob IS NOT NULL
+		AND results_changed_ts IS NOT NULL
+		ORDER BY start_time ASC
+	`, playtomic.StatusStatsUpdated, playtomic.StatusCompleted, season.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query matches with corrected results: %w", err)
+	}
+	var matchIDs []string
+	for rows.Next() {
+		var matchID string
+		if err := rows.Scan(&matchID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan match ID: %w", err)
+		}
+		matchIDs = append(matchIDs, matchID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var corrections []ResultCorrection
+	for _, matchID := range matchIDs {
+		row := tx.QueryRow(`
+			SELECT id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, ball_bringer_id, ball_bringer_name, processing_status, booking_notified_ts, result_notified_ts, booking_message_ts, deleted_at
+			FROM matches
+			WHERE id = ?
+		`, matchID)
+		match, err := s.scanMatch(row)
 		if err != nil {
-			log.Error("Failed to add player", "error", err, "playerID", playerID)
-		} else {
-			log.Info("Discovered and added new player to the store", "playerID", playerID, "name", name, "player_level", level)
+			return nil, fmt.Errorf("failed to get match %s: %w", matchID, err)
 		}
-	} else {
-		_, err := s.db.Exec("UPDATE players SET name = ?, level = ? WHERE id = ?", name, level, playerID)
+		counted, err := countedResults(tx, matchID)
 		if err != nil {
-			log.Error("Failed to update player", "error", err, "playerID", playerID)
-		} else {
-			log.Info("Updated existing player in the store", "playerID", playerID, "name", name, "player_level", level)
+			return nil, err
 		}
+		corrections = append(corrections, ResultCorrection{Match: match, CountedResults: counted})
 	}
+	return corrections, nil
 }
 
-// UpsertPlayers inserts or updates multiple players in a single transaction.
-func (s *store) UpsertPlayers(players []PlayerInfo) error {
+// CorrectMatchStats replaces the stats a match contributed with the stats of its current results. The results the
+// stats were counted with are subtracted, the current results are added and recorded as counted.
+func (s *store) CorrectMatchStats(matchID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -504,89 +917,1145 @@ func (s *store) UpsertPlayers(players []PlayerInfo) error {
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.Prepare(`
-		INSERT INTO players (id, name, level)
-		VALUES (?, ?, ?)
-		ON CONFLICT(id) DO UPDATE SET
-			name = excluded.name,
-			level = excluded.level;
-	`)
+	row := tx.QueryRow(`
+		SELECT id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, ball_bringer_id, ball_bringer_name, processing_status, booking_notified_ts, result_notified_ts, booking_message_ts, deleted_at
+		FROM matches
+		WHERE id = ?
+	`, matchID)
+	match, err := s.scanMatch(row)
 	if err != nil {
-		return fmt.Errorf("failed to prepare statement for players: %w", err)
+		return fmt.Errorf("failed to get match %s: %w", matchID, err)
+	}
+	if !statsApplied(match) {
+		return ErrStatsNotApplied
+	}
+	if err := checkStatsSeason(tx, matchID); err != nil {
+		return err
+	}
+	counted, err := countedResults(tx, matchID)
+	if err != nil {
+		return err
+	}
+	if counted == nil {
+		return ErrStatsNotApplied
 	}
-	defer stmt.Close()
 
-	for _, player := range players {
-		if player.ID == "" {
-			log.Warn("Skipping player with empty ID")
-			continue
+	weekStart := getWeekStartDate(match.Start)
+	for playerID, stats := range aggregateMatchStats(withResults(match, counted)) {
+		if err := subtractPlayerStats(tx, playerID, stats, weekStart); err != nil {
+			return err
 		}
-		_, err := stmt.Exec(player.ID, player.Name, player.Level)
-		if err != nil {
-			return fmt.Errorf("failed to execute statement for player %s: %w", player.ID, err)
+	}
+	for playerID, stats := range aggregateMatchStats(match) {
+		if err := addPlayerStats(tx, playerID, stats, weekStart); err != nil {
+			return fmt.Errorf("failed to add corrected stats for player %s: %w", playerID, err)
 		}
 	}
 
-	return tx.Commit()
+	countedBlob, err := encodeBlob(match.Results)
+	if err != nil {
+		return fmt.Errorf("failed to marshal counted results: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE matches SET counted_results_blob = ?, results_changed_ts = NULL WHERE id = ?", countedBlob, matchID); err != nil {
+		return fmt.Errorf("failed to record counted results: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit stats correction: %w", err)
+	}
+	log.Info("Corrected player stats for match", "matchID", matchID)
+	return nil
 }
 
-func (s *store) IsKnownPlayer(playerID string) bool {
+// GetLastStatsAppliedMatchID returns the ID of the most recently played match whose stats are counted in player_stats.
+func (s *store) GetLastStatsAppliedMatchID() (string, bool, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	var exists bool
-	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM players WHERE id = ?)", playerID).Scan(&exists)
+	var matchID string
+	err := s.db.QueryRow(`
+		SELECT id FROM matches
+		WHERE deleted_at IS NULL
+		AND processing_status IN (?, ?)
+		AND game_status = ?
+		AND results_status IN (?, ?)
+		ORDER BY end_time DESC
+		LIMIT 1
+	`, playtomic.StatusStatsUpdated, playtomic.StatusCompleted, playtomic.GameStatusPlayed, playtomic.ResultsStatusConfirmed, playtomic.ResultsStatusManuallyConfirmed).Scan(&matchID)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
 	if err != nil {
-		log.Error("Failed to check if player exists", "error", err, "playerID", playerID)
-		return false
+		return "", false, fmt.Errorf("failed to get last match with applied stats: %w", err)
 	}
-	return exists
+	return matchID, true, nil
 }
 
-func (s *store) Clear() {
+// StartNewSeason ends the current season and starts a new one. The live player_stats are archived in
+// season_player_stats under the ended season and then reset, so the leaderboard starts over while all-time
+// stats can still be counted. It returns the new season.
+func (s *store) StartNewSeason() (Season, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	tx, err := s.db.Begin()
 	if err != nil {
-		log.Error("Failed to begin transaction for clearing store", "error", err)
-		return
+		return Season{}, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	_, err = tx.Exec("DELETE FROM matches")
+	ended, err := currentSeason(tx)
 	if err != nil {
-		log.Error("Failed to clear matches table", "error", err)
-		tx.Rollback()
-		return
+		return Season{}, err
+	}
+	if ended.ID == 0 {
+		// The seasons migration opens the first season, so this only happens if it was removed by hand.
+		res, err := tx.Exec("INSERT INTO seasons (started_at) VALUES (0)")
+		if err != nil {
+			return Season{}, fmt.Errorf("failed to open the first season: %w", err)
+		}
+		if ended.ID, err = res.LastInsertId(); err != nil {
+			return Season{}, fmt.Errorf("failed to get the first season: %w", err)
+		}
 	}
 
-	_, err = tx.Exec("DELETE FROM players")
+	_, err = tx.Exec(`
+		INSERT INTO season_player_stats (season_id, player_id, matches_played, matches_won, matches_lost, sets_won, sets_lost, games_won, games_lost)
+		SELECT ?, player_id, matches_played, matches_won, matches_lost, sets_won, sets_lost, games_won, games_lost
+		FROM player_stats WHERE matches_played > 0
+	`, ended.ID)
 	if err != nil {
-		log.Error("Failed to clear players table", "error", err)
-		tx.Rollback()
-		return
+		return Season{}, fmt.Errorf("failed to archive season stats: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM player_stats"); err != nil {
+		return Season{}, fmt.Errorf("failed to reset player stats: %w", err)
+	}
+
+	now := s.clock.Now().Unix()
+	if _, err := tx.Exec("UPDATE seasons SET ended_at = ? WHERE id = ?", now, ended.ID); err != nil {
+		return Season{}, fmt.Errorf("failed to end season %d: %w", ended.ID, err)
+	}
+	res, err := tx.Exec("INSERT INTO seasons (started_at) VALUES (?)", now)
+	if err != nil {
+		return Season{}, fmt.Errorf("failed to start season: %w", err)
+	}
+	season := Season{StartedAt: now}
+	if season.ID, err = res.LastInsertId(); err != nil {
+		return Season{}, fmt.Errorf("failed to get new season: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		log.Error("Failed to commit transaction for clearing store", "error", err)
+		return Season{}, fmt.Errorf("failed to commit new season: %w", err)
 	}
+	log.Info("Started new season", "seasonID", season.ID, "archivedSeasonID", ended.ID)
+	return season, nil
 }
 
-func (s *store) ClearMatch(matchID string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	_, err := s.db.Exec("DELETE FROM matches WHERE id = ?", matchID)
+// currentSeason returns the season that has not ended yet. Without one, everything counts as a single season
+// and a zero Season is returned.
+func currentSeason(q interface {
+	QueryRow(query string, args ...any) *sql.Row
+}) (Season, error) {
+	var season Season
+	err := q.QueryRow("SELECT id, started_at FROM seasons WHERE ended_at IS NULL ORDER BY id DESC LIMIT 1").Scan(&season.ID, &season.StartedAt)
+	if err == sql.ErrNoRows {
+		return Season{}, nil
+	}
 	if err != nil {
-		log.Error("Failed to clear match", "error", err, "matchID", matchID)
+		return Season{}, fmt.Errorf("failed to get current season: %w", err)
+	}
+	return season, nil
+}
+
+// statsApplied reports whether the match's stats have been added to player_stats.
+// Canceled matches and matches whose results expired are completed without a stats update.
+func statsApplied(match *playtomic.PadelMatch) bool {
+	if match.ProcessingStatus != playtomic.StatusStatsUpdated && match.ProcessingStatus != playtomic.StatusCompleted {
+		return false
+	}
+	return match.GameStatus == playtomic.GameStatusPlayed &&
+		(match.ResultsStatus == playtomic.ResultsStatusConfirmed || match.ResultsStatus == playtomic.ResultsStatusManuallyConfirmed)
+}
+
+// GetStatsForRange totals the weekly stats of every player over the weeks from the one containing from up to
+// the one starting at or before to. Players are ordered by wins.
+func (s *store) GetStatsForRange(from, to int64) ([]PlayerStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT
+			w.player_id,
+			p.name,
+			SUM(w.matches_played),
+			SUM(w.matches_won),
+			SUM(w.matches_lost),
+			SUM(w.sets_won),
+			SUM(w.sets_lost),
+			SUM(w.games_won),
+			SUM(w.games_lost)
+		FROM weekly_player_stats w
+		JOIN players p ON w.player_id = p.id
+		WHERE w.week_start_date >= ? AND w.week_start_date <= ?
+		GROUP BY w.player_id, p.name
+		ORDER BY SUM(w.matches_won) DESC, p.name ASC
+	`, getWeekStartDate(from), to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query weekly player stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []PlayerStats
+	for rows.Next() {
+		var stat PlayerStats
+		if err := rows.Scan(&stat.PlayerID, &stat.PlayerName, &stat.MatchesPlayed, &stat.MatchesWon, &stat.MatchesLost,
+			&stat.SetsWon, &stat.SetsLost, &stat.GamesWon, &stat.GamesLost); err != nil {
+			return nil, fmt.Errorf("failed to scan weekly player stats: %w", err)
+		}
+		stat.setWinRates()
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
+
+// GetPlayerStatsByName retrieves the statistics for a single player by their name.
+// It performs a case-insensitive, fuzzy search (e.g., "morten" will match "Morten Voss").
+func (s *store) GetPlayerStatsByName(playerName string, competitiveOnly bool) (*PlayerStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Use a fuzzy search pattern.
+	pattern := "%" + playerName + "%"
+
+	stat, err := s.playerStatsLocked("p.name LIKE ? COLLATE NOCASE", pattern, competitiveOnly)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Info("No stats found for player matching pattern", "pattern", pattern)
+			return nil, fmt.Errorf("player matching '%s' not found", playerName)
+		}
+		log.Error("Failed to query player stats by name", "error", err, "pattern", pattern)
+		return nil, err
+	}
+
+	log.Debug("Found player stats by name", "player", stat.PlayerName)
+	return stat, nil
+}
+
+// GetPlayerStatsByID retrieves the statistics for a single player by their ID.
+func (s *store) GetPlayerStatsByID(playerID string, competitiveOnly bool) (*PlayerStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stat, err := s.playerStatsLocked("p.id = ?", playerID, competitiveOnly)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("player %s not found", playerID)
+		}
+		log.Error("Failed to query player stats by ID", "error", err, "playerID", playerID)
+		return nil, err
+	}
+	return stat, nil
+}
+
+// playerStatsLocked returns the stats of the first player matching the condition, or sql.ErrNoRows. The caller
+// must hold s.mu.
+func (s *store) playerStatsLocked(condition string, arg any, competitiveOnly bool) (*PlayerStats, error) {
+	query := `
+		SELECT
+			p.id,
+			p.name,
+			COALESCE(ps.matches_played, 0),
+			COALESCE(ps.matches_won, 0),
+			COALESCE(ps.matches_lost, 0),
+			COALESCE(ps.sets_won, 0),
+			COALESCE(ps.sets_lost, 0),
+			COALESCE(ps.games_won, 0),
+			COALESCE(ps.games_lost, 0)
+		FROM players p
+		LEFT JOIN player_stats ps ON p.id = ps.player_id
+		WHERE ` + condition + `
+		LIMIT 1
+	`
+
+	var stat PlayerStats
+	row := s.db.QueryRow(query, arg)
+	err := row.Scan(
+		&stat.PlayerID,
+		&stat.PlayerName,
+		&stat.MatchesPlayed,
+		&stat.MatchesWon,
+		&stat.MatchesLost,
+		&stat.SetsWon,
+		&stat.SetsLost,
+		&stat.GamesWon,
+		&stat.GamesLost,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	if competitiveOnly {
+		season, err := currentSeason(s.db)
+		if err != nil {
+			return nil, err
+		}
+		competitive, err := s.competitivePlayerStatsLocked(season.ID, false)
+		if err != nil {
+			return nil, err
+		}
+		counted := PlayerStats{PlayerID: stat.PlayerID, PlayerName: stat.PlayerName}
+		if c, ok := competitive[stat.PlayerID]; ok {
+			counted = *c
+		}
+		stat = counted
+	}
+
+	stat.setWinRates()
+	return &stat, nil
+}
+
+// GetPlayerStats returns the stats of players with at least minMatches played, in the given order.
+// Only the current season is counted, unless allTime also counts the archived seasons.
+func (s *store) GetPlayerStats(minMatches int, sortBy StatsSortBy, competitiveOnly, allTime bool) ([]PlayerStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := statsOrderBy[sortBy]; !ok {
+		sortBy = SortByWins
+	}
+	if competitiveOnly {
+		var seasonID int64
+		if !allTime {
+			season, err := currentSeason(s.db)
+			if err != nil {
+				return nil, err
+			}
+			seasonID = season.ID
+		}
+		competitive, err := s.competitivePlayerStatsLocked(seasonID, allTime)
+		if err != nil {
+			return nil, err
+		}
+		var stats []PlayerStats
+		for _, stat := range competitive {
+			if stat.MatchesPlayed >= minMatches {
+				stats = append(stats, *stat)
+			}
+		}
+		sortPlayerStats(stats, sortBy)
+		rankPlayerStats(stats)
+		return stats, nil
+	}
+
+	orderBy := statsOrderBy[sortBy]
+	statsTable := "player_stats"
+	if allTime {
+		statsTable = allTimeStatsTable
+	}
+	rows, err := s.db.Query(`
+		SELECT
+			ps.player_id,
+			p.name,
+			ps.matches_played,
+			ps.matches_won,
+			ps.matches_lost,
+			ps.sets_won,
+			ps.sets_lost,
+			ps.games_won,
+			ps.games_lost
+		FROM `+statsTable+` ps
+		JOIN players p ON ps.player_id = p.id
+		WHERE ps.matches_played >= ?
+		ORDER BY `+orderBy+`;
+	`, minMatches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []PlayerStats
+	for rows.Next() {
+		var stat PlayerStats
+		err := rows.Scan(
+			&stat.PlayerID,
+			&stat.PlayerName,
+			&stat.MatchesPlayed,
+			&stat.MatchesWon,
+			&stat.MatchesLost,
+			&stat.SetsWon,
+			&stat.SetsLost,
+			&stat.GamesWon,
+			&stat.GamesLost,
+		)
+		if err != nil {
+			return nil, err
+		}
+		stat.setWinRates()
+		stats = append(stats, stat)
+	}
+	if sortBy == SortByConfidence {
+		sortPlayerStats(stats, sortBy)
+	}
+	rankPlayerStats(stats)
+	return stats, nil
+}
+
+// allTimeStatsTable totals the live stats of the current season with those archived for past seasons.
+// It has the columns of player_stats, so it can be queried in its place.
+const allTimeStatsTable = `(
+			SELECT player_id, SUM(matches_played) AS matches_played, SUM(matches_won) AS matches_won, SUM(matches_lost) AS matches_lost,
+				SUM(sets_won) AS sets_won, SUM(sets_lost) AS sets_lost, SUM(games_won) AS games_won, SUM(games_lost) AS games_lost
+			FROM (
+				SELECT player_id, matches_played, matches_won, matches_lost, sets_won, sets_lost, games_won, games_lost FROM player_stats
+				UNION ALL
+				SELECT player_id, matches_played, matches_won, matches_lost, sets_won, sets_lost, games_won, games_lost FROM season_player_stats
+			)
+			GROUP BY player_id
+		)`
+
+// competitivePlayerStatsLocked computes player stats counting only the competitive matches whose stats were counted
+// in the given season, or in any season with allTime.
+// player_stats does not record the type of the matches it totals, so the stats are rebuilt from the stored matches instead.
+func (s *store) competitivePlayerStatsLocked(seasonID int64, allTime bool) (map[string]*PlayerStats, error) {
+	rows, err := s.db.Query(`
+		SELECT id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, ball_bringer_id, ball_bringer_name, processing_status, booking_notified_ts, result_notified_ts, booking_message_ts, deleted_at
+		FROM matches
+		WHERE match_type = ? AND (? OR IFNULL(stats_season_id, 0) = ?) AND deleted_at IS NULL
+	`, playtomic.MatchTypeCompetition, allTime, seasonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query competitive matches: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make(map[string]*PlayerStats)
+	for rows.Next() {
+		match, err := s.scanMatch(rows)
+		if err != nil {
+			log.Error("Failed to scan match row", "error", err)
+			continue
+		}
+		if !statsApplied(match) {
+			continue
+		}
+		for playerID, counts := range aggregateMatchStats(match) {
+			stat, ok := stats[playerID]
+			if !ok {
+				stat = &PlayerStats{PlayerID: playerID}
+				stats[playerID] = stat
+			}
+			stat.MatchesPlayed += counts["matches_played"]
+			stat.MatchesWon += counts["matches_won"]
+			stat.MatchesLost += counts["matches_lost"]
+			stat.SetsWon += counts["sets_won"]
+			stat.SetsLost += counts["sets_lost"]
+			stat.GamesWon += counts["games_won"]
+			stat.GamesLost += counts["games_lost"]
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	nameRows, err := s.db.Query("SELECT id, name FROM players")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query player names: %w", err)
+	}
+	defer nameRows.Close()
+	for nameRows.Next() {
+		var id, name string
+		if err := nameRows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		if stat, ok := stats[id]; ok {
+			stat.PlayerName = name
+		}
+	}
+
+	for id, stat := range stats {
+		if stat.PlayerName == "" {
+			// Stats are only shown for known players, as with the player_stats join.
+			delete(stats, id)
+			continue
+		}
+		stat.setWinRates()
+	}
+	return stats, nameRows.Err()
+}
+
+func (s *store) AddPlayer(playerID, name string, level float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var exists bool
+	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM players WHERE id = ?)", playerID).Scan(&exists)
+	if err != nil {
+		log.Error("Failed to check if player exists", "error", err, "playerID", playerID)
+		return
+	}
+
+	if !exists {
+		_, err := s.db.Exec("INSERT INTO players (id, name, level) VALUES (?, ?, ?)", playerID, name, level)
+		if err != nil {
+			log.Error("Failed to add player", "error", err, "playerID", playerID)
+		} else {
+			log.Info("Discovered and added new player to the store", "playerID", playerID, "name", name, "player_level", level)
+		}
+	} else {
+		_, err := s.db.Exec("UPDATE players SET name = ?, level = ? WHERE id = ?", name, level, playerID)
+		if err != nil {
+			log.Error("Failed to update player", "error", err, "playerID", playerID)
+		} else {
+			log.Info("Updated existing player in the store", "playerID", playerID, "name", name, "player_level", level)
+		}
+	}
+}
+
+// UpsertPlayers inserts or updates multiple players in a single transaction.
+// It returns the players that were not known before and have been inserted.
+func (s *store) UpsertPlayers(players []PlayerInfo) ([]PlayerInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO players (id, name, level)
+		VALUES (?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			level = excluded.level;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare statement for players: %w", err)
+	}
+	defer stmt.Close()
+
+	var inserted []PlayerInfo
+	for _, player := range players {
+		if player.ID == "" {
+			log.Warn("Skipping player with empty ID")
+			continue
+		}
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM players WHERE id = ?)", player.ID).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to check for player %s: %w", player.ID, err)
+		}
+		_, err := stmt.Exec(player.ID, player.Name, player.Level)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute statement for player %s: %w", player.ID, err)
+		}
+		if !exists {
+			inserted = append(inserted, player)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+func (s *store) IsKnownPlayer(playerID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var exists bool
+	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM players WHERE id = ?)", playerID).Scan(&exists)
+	if err != nil {
+		log.Error("Failed to check if player exists", "error", err, "playerID", playerID)
+		return false
+	}
+	return exists
+}
+
+// IsCorePlayer reports whether the player is flagged as a core club member.
+func (s *store) IsCorePlayer(playerID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var isCore bool
+	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM players WHERE id = ? AND is_core = 1)", playerID).Scan(&isCore)
+	if err != nil {
+		log.Error("Failed to check if player is a core member", "error", err, "playerID", playerID)
+		return false
+	}
+	return isCore
+}
+
+// SetCorePlayer flags or unflags the player as a core club member.
+func (s *store) SetCorePlayer(playerID string, core bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, err := s.db.Exec("UPDATE players SET is_core = ? WHERE id = ?", core, playerID)
+	if err != nil {
+		return fmt.Errorf("failed to set core flag for player %s: %w", playerID, err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("player %s not found", playerID)
+	}
+	log.Info("Updated core member flag", "playerID", playerID, "core", core)
+	return nil
+}
+
+// IsDMOptedOut reports whether the player has asked not to receive direct messages.
+func (s *store) IsDMOptedOut(playerID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var optedOut bool
+	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM players WHERE id = ? AND dm_opt_out = 1)", playerID).Scan(&optedOut)
+	if err != nil {
+		log.Error("Failed to check if player opted out of direct messages", "error", err, "playerID", playerID)
+		return false
+	}
+	return optedOut
+}
+
+// SetDMOptOut records whether the player wants to receive direct messages.
+func (s *store) SetDMOptOut(playerID string, optOut bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, err := s.db.Exec("UPDATE players SET dm_opt_out = ? WHERE id = ?", optOut, playerID)
+	if err != nil {
+		return fmt.Errorf("failed to set direct message opt-out for player %s: %w", playerID, err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("player %s not found", playerID)
+	}
+	log.Info("Updated direct message opt-out", "playerID", playerID, "optOut", optOut)
+	return nil
+}
+
+func (s *store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Error("Failed to begin transaction for clearing store", "error", err)
+		return
+	}
+
+	_, err = tx.Exec("DELETE FROM matches")
+	if err != nil {
+		log.Error("Failed to clear matches table", "error", err)
+		tx.Rollback()
+		return
+	}
+
+	_, err = tx.Exec("DELETE FROM players")
+	if err != nil {
+		log.Error("Failed to clear players table", "error", err)
+		tx.Rollback()
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to commit transaction for clearing store", "error", err)
+	}
+}
+
+// ClearMatch soft-deletes a match: it is hidden from reads but kept, so UndeleteMatch can restore it. Upserting the
+// match again, e.g. when it is refetched, restores it as well.
+func (s *store) ClearMatch(matchID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.db.Exec("UPDATE matches SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL", s.clock.Now().Unix(), matchID)
+	if err != nil {
+		log.Error("Failed to clear match", "error", err, "matchID", matchID)
+	}
+}
+
+// ErrMatchNotDeleted is returned by UndeleteMatch when there is no soft-deleted match with the given ID.
+var ErrMatchNotDeleted = errors.New("match is not deleted")
+
+// UndeleteMatch restores a match soft-deleted by ClearMatch.
+func (s *store) UndeleteMatch(matchID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, err := s.db.Exec("UPDATE matches SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", matchID)
+	if err != nil {
+		return fmt.Errorf("failed to undelete match %s: %w", matchID, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to undelete match %s: %w", matchID, err)
+	}
+	if rows == 0 {
+		return ErrMatchNotDeleted
+	}
+	return nil
+}
+
+// CountPlayers returns the number of players in the club.
+func (s *store) CountPlayers() (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM players").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count players: %w", err)
+	}
+	return count, nil
+}
+
+func (s *store) GetAllPlayers() ([]PlayerInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rows, err := s.db.Query("SELECT id, name, ball_bringer_count, level FROM players ORDER BY name")
+	if err != nil {
+		log.Error("Failed to query all players", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var players []PlayerInfo
+	for rows.Next() {
+		var p PlayerInfo
+		var name sql.NullString
+		var level sql.NullFloat64
+		if err := rows.Scan(&p.ID, &name, &p.BallBringerCount, &level); err != nil {
+			log.Error("Failed to scan player row", "error", err)
+			continue
+		}
+		p.Name = name.String // handle NULL name from db
+		p.Level = level.Float64
+		players = append(players, p)
+	}
+	return players, nil
+}
+
+// GetPlayers retrieves information for a specific list of players.
+func (s *store) GetPlayers(playerIDs []string) ([]PlayerInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(playerIDs) == 0 {
+		return []PlayerInfo{}, nil
+	}
+
+	query := "SELECT id, name, ball_bringer_count, level FROM players WHERE id IN (?" + strings.Repeat(",?", len(playerIDs)-1) + ")"
+	args := make([]interface{}, len(playerIDs))
+	for i, id := range playerIDs {
+		args[i] = id
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		log.Error("Failed to query players by IDs", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var players []PlayerInfo
+	for rows.Next() {
+		var p PlayerInfo
+		var name sql.NullString
+		var level sql.NullFloat64
+		if err := rows.Scan(&p.ID, &name, &p.BallBringerCount, &level); err != nil {
+			log.Error("Failed to scan player row", "error", err)
+			continue // Or handle error more gracefully
+		}
+		p.Name = name.String
+		p.Level = level.Float64
+		players = append(players, p)
+	}
+	return players, nil
+}
+
+// SetBallBringer assigns a player as the ball bringer for a match and increments their count.
+// This function is now deprecated and replaced by AssignBallBringerAtomically to prevent race conditions.
+func (s *store) SetBallBringer(matchID, playerID, playerName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// Update the match with the ball bringer's details
+	_, err = tx.Exec("UPDATE matches SET ball_bringer_id = ?, ball_bringer_name = ? WHERE id = ?", playerID, playerName, matchID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update match with ball bringer: %w", err)
+	}
+
+	// Increment the player's ball bringer count
+	_, err = tx.Exec("UPDATE players SET ball_bringer_count = ball_bringer_count + 1 WHERE id = ?", playerID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to increment ball bringer count: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// AssignBallBringerAtomically finds the player with the minimum ball_bringer_count among the given player IDs,
+// assigns them as the ball bringer for the match, and atomically increments their count.
+// Any number of players is supported (e.g. 3-player round robins); blank and duplicate IDs are ignored.
+// Assignments are serialized per match only, so unrelated matches are assigned concurrently. Selecting and
+// incrementing the player happens in a single statement, so concurrent assignments sharing players can't pick
+// from stale counts.
+func (s *store) AssignBallBringerAtomically(matchID string, playerIDs []string) (string, string, error) {
+	unlock := s.matchLocks.lock(matchID)
+	defer unlock()
+
+	playerIDs = uniqueNonEmpty(playerIDs)
+	if len(playerIDs) == 0 {
+		return "", "", fmt.Errorf("no player IDs provided for ball bringer assignment")
+	}
+
+	// Check if a ball bringer is already assigned to this match. The match lock keeps this from going stale.
+	// s.mu is shared with other assignments, but excludes the writers that expect the store to themselves.
+	var existingBallBringerID, existingBallBringerName sql.NullString
+	s.mu.RLock()
+	err := s.db.QueryRow("SELECT ball_bringer_id, ball_bringer_name FROM matches WHERE id = ?", matchID).Scan(&existingBallBringerID, &existingBallBringerName)
+	s.mu.RUnlock()
+	if err != nil && err != sql.ErrNoRows {
+		return "", "", fmt.Errorf("failed to query existing ball bringer for match %s: %w", matchID, err)
+	}
+
+	if existingBallBringerID.Valid && existingBallBringerName.Valid {
+		log.Info("Ball bringer already assigned for match. Returning existing assignment.", "matchID", matchID, "playerID", existingBallBringerID.String, "playerName", existingBallBringerName.String)
+		return existingBallBringerID.String, existingBallBringerName.String, nil
+	}
+
+	var selectedPlayerID, selectedPlayerName string
+	err = withRetry(s.mu.RLocker(), func() error {
+		var err error
+		selectedPlayerID, selectedPlayerName, err = s.assignBallBringer(matchID, playerIDs)
+		return err
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	log.Info("Atomically assigned ball bringer", "matchID", matchID, "playerID", selectedPlayerID, "playerName", selectedPlayerName)
+	return selectedPlayerID, selectedPlayerName, nil
+}
+
+// assignBallBringer picks the player with the fewest balls brought, increments their count and assigns them to the
+// match in a single transaction. The caller must hold the match lock and s.mu for reading.
+func (s *store) assignBallBringer(matchID string, playerIDs []string) (string, string, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to begin transaction for atomic ball bringer assignment: %w", err)
+	}
+	defer tx.Rollback() // Rollback on error by default
+
+	// Find the player with the minimum ball_bringer_count among the provided playerIDs and increment their count.
+	// Doing both in one write means the transaction holds the write lock before it reads any counts.
+	query := `
+		UPDATE players
+		SET ball_bringer_count = ball_bringer_count + 1
+		WHERE id = (
+			SELECT id
+			FROM players
+			WHERE id IN (
+				?` + strings.Repeat(",?", len(playerIDs)-1) + `
+			)
+			ORDER BY ball_bringer_count ASC, name ASC -- Order by name for deterministic tie-breaking
+			LIMIT 1
+		)
+		RETURNING id, name;
+	`
+	args := ToAnySlice(playerIDs) // Helper to convert []string to []any
+
+	var selectedPlayerID string
+	var selectedPlayerName string
+	err = tx.QueryRow(query, args...).Scan(&selectedPlayerID, &selectedPlayerName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", "", fmt.Errorf("no eligible players found for ball bringer assignment among IDs: %v", playerIDs)
+		}
+		return "", "", fmt.Errorf("failed to select next ball bringer: %w", err)
+	}
+
+	// Update the match with the ball bringer's details
+	_, err = tx.Exec("UPDATE matches SET ball_bringer_id = ?, ball_bringer_name = ? WHERE id = ?", selectedPlayerID, selectedPlayerName, matchID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to update match %s with ball bringer %s: %w", matchID, selectedPlayerID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", "", fmt.Errorf("failed to commit atomic ball bringer assignment transaction: %w", err)
+	}
+	return selectedPlayerID, selectedPlayerName, nil
+}
+
+// MergePlayers folds the player identified by mergeID into keepID. Stats, weekly stats and ball bringer
+// counts are summed into the kept player, match references (owner, ball bringer and the players stored in
+// teams_blob) are repointed, and the merged player is deleted. Everything happens in a single transaction.
+func (s *store) MergePlayers(keepID, mergeID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if keepID == "" || mergeID == "" {
+		return fmt.Errorf("both player IDs are required to merge players")
+	}
+	if keepID == mergeID {
+		return fmt.Errorf("cannot merge player %s into itself", keepID)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for merging players: %w", err)
+	}
+	defer tx.Rollback()
+
+	var keepName sql.NullString
+	if err := tx.QueryRow("SELECT name FROM players WHERE id = ?", keepID).Scan(&keepName); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("player %s not found", keepID)
+		}
+		return fmt.Errorf("failed to query player %s: %w", keepID, err)
+	}
+	var mergeBallBringerCount int
+	if err := tx.QueryRow("SELECT ball_bringer_count FROM players WHERE id = ?", mergeID).Scan(&mergeBallBringerCount); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("player %s not found", mergeID)
+		}
+		return fmt.Errorf("failed to query player %s: %w", mergeID, err)
+	}
+
+	// The WHERE clause is required by SQLite to disambiguate INSERT ... SELECT ... ON CONFLICT.
+	_, err = tx.Exec(`
+		INSERT INTO player_stats (player_id, matches_played, matches_won, matches_lost, sets_won, sets_lost, games_won, games_lost)
+		SELECT ?, matches_played, matches_won, matches_lost, sets_won, sets_lost, games_won, games_lost
+		FROM player_stats WHERE player_id = ?
+		ON CONFLICT(player_id) DO UPDATE SET
+			matches_played = matches_played + excluded.matches_played,
+			matches_won = matches_won + excluded.matches_won,
+			matches_lost = matches_lost + excluded.matches_lost,
+			sets_won = sets_won + excluded.sets_won,
+			sets_lost = sets_lost + excluded.sets_lost,
+			games_won = games_won + excluded.games_won,
+			games_lost = games_lost + excluded.games_lost;
+	`, keepID, mergeID)
+	if err != nil {
+		return fmt.Errorf("failed to merge player stats: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO weekly_player_stats (week_start_date, player_id, matches_played, matches_won, matches_lost, sets_won, sets_lost, games_won, games_lost)
+		SELECT week_start_date, ?, matches_played, matches_won, matches_lost, sets_won, sets_lost, games_won, games_lost
+		FROM weekly_player_stats WHERE player_id = ?
+		ON CONFLICT(week_start_date, player_id) DO UPDATE SET
+			matches_played = matches_played + excluded.matches_played,
+			matches_won = matches_won + excluded.matches_won,
+			matches_lost = matches_lost + excluded.matches_lost,
+			sets_won = sets_won + excluded.sets_won,
+			sets_lost = sets_lost + excluded.sets_lost,
+			games_won = games_won + excluded.games_won,
+			games_lost = games_lost + excluded.games_lost;
+	`, keepID, mergeID)
+	if err != nil {
+		return fmt.Errorf("failed to merge weekly player stats: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO season_player_stats (season_id, player_id, matches_played, matches_won, matches_lost, sets_won, sets_lost, games_won, games_lost)
+		SELECT season_id, ?, matches_played, matches_won, matches_lost, sets_won, sets_lost, games_won, games_lost
+		FROM season_player_stats WHERE player_id = ?
+		ON CONFLICT(season_id, player_id) DO UPDATE SET
+			matches_played = matches_played + excluded.matches_played,
+			matches_won = matches_won + excluded.matches_won,
+			matches_lost = matches_lost + excluded.matches_lost,
+			sets_won = sets_won + excluded.sets_won,
+			sets_lost = sets_lost + excluded.sets_lost,
+			games_won = games_won + excluded.games_won,
+			games_lost = games_lost + excluded.games_lost;
+	`, keepID, mergeID)
+	if err != nil {
+		return fmt.Errorf("failed to merge season player stats: %w", err)
+	}
+
+	if _, err := tx.Exec("UPDATE players SET ball_bringer_count = ball_bringer_count + ? WHERE id = ?", mergeBallBringerCount, keepID); err != nil {
+		return fmt.Errorf("failed to merge ball bringer count: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE matches SET owner_id = ?, owner_name = ? WHERE owner_id = ?", keepID, keepName.String, mergeID); err != nil {
+		return fmt.Errorf("failed to reassign match owners: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE matches SET ball_bringer_id = ?, ball_bringer_name = ? WHERE ball_bringer_id = ?", keepID, keepName.String, mergeID); err != nil {
+		return fmt.Errorf("failed to reassign match ball bringers: %w", err)
+	}
+	if err := mergePlayerInTeamBlobs(tx, keepID, mergeID); err != nil {
+		return err
+	}
+
+	var mergeSlackUserID sql.NullString
+	if err := tx.QueryRow("SELECT slack_user_id FROM players WHERE id = ?", mergeID).Scan(&mergeSlackUserID); err != nil {
+		return fmt.Errorf("failed to read slack user of merged player %s: %w", mergeID, err)
+	}
+	// The merged player's stats are deleted explicitly, as ON DELETE CASCADE only applies on connections
+	// with foreign keys enabled.
+	for _, table := range []string{"player_stats", "weekly_player_stats", "season_player_stats"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE player_id = ?", mergeID); err != nil {
+			return fmt.Errorf("failed to delete %s of merged player %s: %w", table, mergeID, err)
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM players WHERE id = ?", mergeID); err != nil {
+		return fmt.Errorf("failed to delete merged player %s: %w", mergeID, err)
+	}
+	// Keep the Slack mapping of the merged player if the kept player has none. This has to happen after
+	// the delete, as a Slack user can only be mapped to one player.
+	if mergeSlackUserID.String != "" {
+		if _, err := tx.Exec("UPDATE players SET slack_user_id = ? WHERE id = ? AND (slack_user_id IS NULL OR slack_user_id = '')", mergeSlackUserID.String, keepID); err != nil {
+			return fmt.Errorf("failed to carry over slack user of merged player %s: %w", mergeID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit player merge transaction: %w", err)
+	}
+
+	log.Info("Merged duplicate player", "keepID", keepID, "mergeID", mergeID)
+	return nil
+}
+
+// mergePlayerInTeamBlobs rewrites the serialized teams of every match so that mergeID is replaced by keepID.
+func mergePlayerInTeamBlobs(tx *sql.Tx, keepID, mergeID string) error {
+	rows, err := tx.Query("SELECT id, teams_blob FROM matches WHERE teams_blob IS NOT NULL")
+	if err != nil {
+		return fmt.Errorf("failed to query match teams: %w", err)
+	}
+
+	updatedBlobs := make(map[string][]byte)
+	for rows.Next() {
+		var matchID string
+		var teamsBlob []byte
+		if err := rows.Scan(&matchID, &teamsBlob); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan match teams: %w", err)
+		}
+
+		var teams []playtomic.Team
+		if err := decodeBlob(teamsBlob, &teams); err != nil {
+			log.Error("Failed to unmarshal teams_blob", "error", err, "matchID", matchID)
+			continue
+		}
+
+		changed := false
+		for i := range teams {
+			for j := range teams[i].Players {
+				if teams[i].Players[j].UserID == mergeID {
+					teams[i].Players[j].UserID = keepID
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		blob, err := encodeBlob(teams)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to marshal teams for match %s: %w", matchID, err)
+		}
+		updatedBlobs[matchID] = blob
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("failed to iterate match teams: %w", err)
+	}
+	rows.Close()
+
+	for matchID, blob := range updatedBlobs {
+		if _, err := tx.Exec("UPDATE matches SET teams_blob = ? WHERE id = ?", blob, matchID); err != nil {
+			return fmt.Errorf("failed to update teams for match %s: %w", matchID, err)
+		}
+	}
+	return nil
+}
+
+// ReencodeMatchBlobs rewrites every match's teams and results blobs that are not at the current blob version,
+// so older rows can be migrated in place after a layout change. It returns the number of matches rewritten.
+func (s *store) ReencodeMatchBlobs() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT id, teams_blob, results_blob FROM matches")
+	if err != nil {
+		return 0, fmt.Errorf("failed to query match blobs: %w", err)
+	}
+
+	type reencoded struct{ teams, results []byte }
+	updated := make(map[string]reencoded)
+	for rows.Next() {
+		var matchID string
+		var teamsBlob, resultsBlob []byte
+		if err := rows.Scan(&matchID, &teamsBlob, &resultsBlob); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan match blobs: %w", err)
+		}
+		teamsVersion, _ := blobVersion(teamsBlob)
+		resultsVersion, _ := blobVersion(resultsBlob)
+		if teamsVersion == currentBlobVersion && resultsVersion == currentBlobVersion {
+			continue
+		}
+
+		var teams []playtomic.Team
+		var results []playtomic.SetResult
+		if err := decodeBlob(teamsBlob, &teams); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to decode teams for match %s: %w", matchID, err)
+		}
+		if err := decodeBlob(resultsBlob, &results); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to decode results for match %s: %w", matchID, err)
+		}
+		var blobs reencoded
+		if blobs.teams, err = encodeBlob(teams); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to encode teams for match %s: %w", matchID, err)
+		}
+		if blobs.results, err = encodeBlob(results); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to encode results for match %s: %w", matchID, err)
+		}
+		updated[matchID] = blobs
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, fmt.Errorf("failed to iterate match blobs: %w", err)
+	}
+	rows.Close()
+
+	for matchID, blobs := range updated {
+		if _, err := tx.Exec("UPDATE matches SET teams_blob = ?, results_blob = ? WHERE id = ?", blobs.teams, blobs.results, matchID); err != nil {
+			return 0, fmt.Errorf("failed to update blobs for match %s: %w", matchID, err)
+		}
 	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(updated), nil
 }
 
-func (s *store) GetAllPlayers() ([]PlayerInfo, error) {
+// GetPlayersSortedByLevel retrieves all players from the database, sorted by their level.
+func (s *store) GetPlayersSortedByLevel() ([]PlayerInfo, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	rows, err := s.db.Query("SELECT id, name, ball_bringer_count, level FROM players ORDER BY name")
+
+	rows, err := s.db.Query("SELECT id, name, ball_bringer_count, level FROM players ORDER BY level DESC")
 	if err != nil {
-		log.Error("Failed to query all players", "error", err)
+		log.Error("Failed to query all players sorted by level", "error", err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -600,31 +2069,21 @@ func (s *store) GetAllPlayers() ([]PlayerInfo, error) {
 			log.Error("Failed to scan player row", "error", err)
 			continue
 		}
-		p.Name = name.String // handle NULL name from db
+		p.Name = name.String
 		p.Level = level.Float64
 		players = append(players, p)
 	}
 	return players, nil
 }
 
-// GetPlayers retrieves information for a specific list of players.
-func (s *store) GetPlayers(playerIDs []string) ([]PlayerInfo, error) {
+// GetBallBringerCounts retrieves all players sorted by how many times they have brought balls.
+func (s *store) GetBallBringerCounts() ([]PlayerInfo, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	if len(playerIDs) == 0 {
-		return []PlayerInfo{}, nil
-	}
-
-	query := "SELECT id, name, ball_bringer_count, level FROM players WHERE id IN (?" + strings.Repeat(",?", len(playerIDs)-1) + ")"
-	args := make([]interface{}, len(playerIDs))
-	for i, id := range playerIDs {
-		args[i] = id
-	}
-
-	rows, err := s.db.Query(query, args...)
+	rows, err := s.db.Query("SELECT id, name, ball_bringer_count, level FROM players ORDER BY ball_bringer_count DESC, name ASC")
 	if err != nil {
-		log.Error("Failed to query players by IDs", "error", err)
+		log.Error("Failed to query ball bringer counts", "error", err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -636,7 +2095,7 @@ func (s *store) GetPlayers(playerIDs []string) ([]PlayerInfo, error) {
 		var level sql.NullFloat64
 		if err := rows.Scan(&p.ID, &name, &p.BallBringerCount, &level); err != nil {
 			log.Error("Failed to scan player row", "error", err)
-			continue // Or handle error more gracefully
+			continue
 		}
 		p.Name = name.String
 		p.Level = level.Float64
@@ -645,158 +2104,426 @@ func (s *store) GetPlayers(playerIDs []string) ([]PlayerInfo, error) {
 	return players, nil
 }
 
-// SetBallBringer assigns a player as the ball bringer for a match and increments their count.
-// This function is now deprecated and replaced by AssignBallBringerAtomically to prevent race conditions.
-func (s *store) SetBallBringer(matchID, playerID, playerName string) error {
+// ResetBallBringerCounts sets every player's ball bringer count back to zero, so the rotation starts over.
+func (s *store) ResetBallBringerCounts() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	tx, err := s.db.Begin()
+	res, err := s.db.Exec("UPDATE players SET ball_bringer_count = 0 WHERE ball_bringer_count != 0")
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return fmt.Errorf("failed to reset ball bringer counts: %w", err)
 	}
+	rows, _ := res.RowsAffected()
+	log.Info("Reset ball bringer counts", "players", rows)
+	return nil
+}
 
-	// Update the match with the ball bringer's details
-	_, err = tx.Exec("UPDATE matches SET ball_bringer_id = ?, ball_bringer_name = ? WHERE id = ?", playerID, playerName, matchID)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to update match with ball bringer: %w", err)
-	}
+// ResetBallBringerCount sets a single player's ball bringer count back to zero.
+func (s *store) ResetBallBringerCount(playerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	// Increment the player's ball bringer count
-	_, err = tx.Exec("UPDATE players SET ball_bringer_count = ball_bringer_count + 1 WHERE id = ?", playerID)
+	res, err := s.db.Exec("UPDATE players SET ball_bringer_count = 0 WHERE id = ?", playerID)
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to increment ball bringer count: %w", err)
+		return fmt.Errorf("failed to reset ball bringer count for player %s: %w", playerID, err)
 	}
-
-	return tx.Commit()
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("player %s not found", playerID)
+	}
+	log.Info("Reset ball bringer count", "playerID", playerID)
+	return nil
 }
 
-// AssignBallBringerAtomically finds the player with the minimum ball_bringer_count among the given player IDs,
-// assigns them as the ball bringer for the match, and atomically increments their count.
-func (s *store) AssignBallBringerAtomically(matchID string, playerIDs []string) (string, string, error) {
-	s.mu.Lock() // Ensure only one ball bringer assignment process runs at a time
-	defer s.mu.Unlock()
+// GetPlayerSpend splits the parsed price of every match starting in [from, to] evenly among its players
+// and sums each player's share per currency. A to of 0 means no upper bound. Matches without a parsed price and
+// canceled matches, which nobody pays for, are left out.
+func (s *store) GetPlayerSpend(from, to int64) ([]PlayerSpend, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	if len(playerIDs) == 0 {
-		return "", "", fmt.Errorf("no player IDs provided for ball bringer assignment")
+	query := "SELECT id, teams_blob, price_amount, price_currency FROM matches WHERE deleted_at IS NULL AND price_amount IS NOT NULL AND price_currency IS NOT NULL AND game_status != ? AND start_time >= ?"
+	args := []any{playtomic.GameStatusCanceled, from}
+	if to > 0 {
+		query += " AND start_time <= ?"
+		args = append(args, to)
 	}
-
-	tx, err := s.db.Begin()
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to begin transaction for atomic ball bringer assignment: %w", err)
+		return nil, fmt.Errorf("failed to query priced matches: %w", err)
 	}
-	defer tx.Rollback() // Rollback on error by default
+	defer rows.Close()
 
-	// Check if a ball bringer is already assigned to this match
-	var existingBallBringerID, existingBallBringerName sql.NullString
-	err = tx.QueryRow("SELECT ball_bringer_id, ball_bringer_name FROM matches WHERE id = ?", matchID).Scan(&existingBallBringerID, &existingBallBringerName)
-	if err != nil && err != sql.ErrNoRows {
-		return "", "", fmt.Errorf("failed to query existing ball bringer for match %s: %w", matchID, err)
+	spend := make(map[[2]string]*PlayerSpend)
+	for rows.Next() {
+		var matchID, currency string
+		var teamsBlob []byte
+		var amount int64
+		if err := rows.Scan(&matchID, &teamsBlob, &amount, &currency); err != nil {
+			log.Error("Failed to scan priced match row", "error", err)
+			continue
+		}
+		var teams []playtomic.Team
+		if err := decodeBlob(teamsBlob, &teams); err != nil {
+			log.Error("Failed to unmarshal teams", "error", err, "matchID", matchID)
+			continue
+		}
+		var players []playtomic.Player
+		for _, team := range teams {
+			for _, player := range team.Players {
+				if player.UserID != "" {
+					players = append(players, player)
+				}
+			}
+		}
+		if len(players) == 0 {
+			continue
+		}
+		// Cents that do not divide evenly go to the first players so the shares add up to the price.
+		share, remainder := amount/int64(len(players)), amount%int64(len(players))
+		for i, player := range players {
+			key := [2]string{player.UserID, currency}
+			entry, ok := spend[key]
+			if !ok {
+				entry = &PlayerSpend{PlayerID: player.UserID, PlayerName: player.Name, Currency: currency}
+				spend[key] = entry
+			}
+			entry.Matches++
+			entry.Amount += share
+			if int64(i) < remainder {
+				entry.Amount++
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	if existingBallBringerID.Valid && existingBallBringerName.Valid {
-		log.Info("Ball bringer already assigned for match. Returning existing assignment.", "matchID", matchID, "playerID", existingBallBringerID.String, "playerName", existingBallBringerName.String)
-		return existingBallBringerID.String, existingBallBringerName.String, nil
+	result := make([]PlayerSpend, 0, len(spend))
+	for _, entry := range spend {
+		result = append(result, *entry)
 	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Amount != result[j].Amount {
+			return result[i].Amount > result[j].Amount
+		}
+		if result[i].PlayerName != result[j].PlayerName {
+			return result[i].PlayerName < result[j].PlayerName
+		}
+		return result[i].Currency < result[j].Currency
+	})
+	return result, nil
+}
 
-	// Find the player with the minimum ball_bringer_count among the provided playerIDs
-	// Using SQL to find the minimum and then update ensures atomicity for selection and increment.
-	query := `
-		SELECT id, name
-		FROM players
-		WHERE id IN (
-			?` + strings.Repeat(",?", len(playerIDs)-1) + `
-		)
-		ORDER BY ball_bringer_count ASC, name ASC -- Order by name for deterministic tie-breaking
-		LIMIT 1;
-	`
-	args := ToAnySlice(playerIDs) // Helper to convert []string to []any
+// GetAllMatches retrieves all matches from the database. Soft-deleted matches are only included if includeDeleted is set.
+func (s *store) GetAllMatches(includeDeleted bool) ([]*playtomic.PadelMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	var selectedPlayerID string
-	var selectedPlayerName string
-	err = tx.QueryRow(query, args...).Scan(&selectedPlayerID, &selectedPlayerName)
+	rows, err := s.db.Query(`
+		SELECT id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, ball_bringer_id, ball_bringer_name, processing_status, booking_notified_ts, result_notified_ts, booking_message_ts, deleted_at
+		FROM matches
+		WHERE ? OR deleted_at IS NULL
+	`, includeDeleted)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return "", "", fmt.Errorf("no eligible players found for ball bringer assignment among IDs: %v", playerIDs)
+		log.Error("Failed to query all matches", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []*playtomic.PadelMatch
+	for rows.Next() {
+		match, err := s.scanMatch(rows)
+		if err != nil {
+			log.Error("Failed to scan match row", "error", err)
+			continue
 		}
-		return "", "", fmt.Errorf("failed to select next ball bringer: %w", err)
+		matches = append(matches, match)
 	}
+	return matches, nil
+}
+
+// GetMatchesForWeek returns the matches starting in the week (Monday to Sunday, UTC) containing weekStartDate,
+// ordered by start time.
+func (s *store) GetMatchesForWeek(weekStartDate int64) ([]*playtomic.PadelMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	// Atomically increment the selected player's ball bringer count
-	_, err = tx.Exec("UPDATE players SET ball_bringer_count = ball_bringer_count + 1 WHERE id = ?", selectedPlayerID)
+	weekStart := getWeekStartDate(weekStartDate)
+	rows, err := s.db.Query(`
+		SELECT id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, ball_bringer_id, ball_bringer_name, processing_status, booking_notified_ts, result_notified_ts, booking_message_ts, deleted_at
+		FROM matches
+		WHERE start_time >= ? AND start_time < ? AND deleted_at IS NULL
+		ORDER BY start_time ASC
+	`, weekStart, weekStart+int64((7*24*time.Hour).Seconds()))
 	if err != nil {
-		return "", "", fmt.Errorf("failed to increment ball bringer count for player %s: %w", selectedPlayerID, err)
+		return nil, fmt.Errorf("failed to query matches for week: %w", err)
 	}
+	defer rows.Close()
 
-	// Update the match with the ball bringer's details
-	_, err = tx.Exec("UPDATE matches SET ball_bringer_id = ?, ball_bringer_name = ? WHERE id = ?", selectedPlayerID, selectedPlayerName, matchID)
+	var matches []*playtomic.PadelMatch
+	for rows.Next() {
+		match, err := s.scanMatch(rows)
+		if err != nil {
+			log.Error("Failed to scan match row", "error", err)
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
+
+// getWeekStartDate returns the Unix time of midnight UTC on the Monday of the week containing ts,
+// as used for week_start_date.
+func getWeekStartDate(ts int64) int64 {
+	t := time.Unix(ts, 0).UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC).Unix()
+}
+
+// GetMatch retrieves a single match by its ID.
+func (s *store) GetMatch(matchID string) (*playtomic.PadelMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	row := s.db.QueryRow(`
+		SELECT id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, ball_bringer_id, ball_bringer_name, processing_status, booking_notified_ts, result_notified_ts, booking_message_ts, deleted_at
+		FROM matches
+		WHERE id = ? AND deleted_at IS NULL
+	`, matchID)
+	match, err := s.scanMatch(row)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to update match %s with ball bringer %s: %w", matchID, selectedPlayerID, err)
+		return nil, fmt.Errorf("failed to get match %s: %w", matchID, err)
 	}
+	return match, nil
+}
 
-	if err := tx.Commit(); err != nil {
-		return "", "", fmt.Errorf("failed to commit atomic ball bringer assignment transaction: %w", err)
+// GetNextMatchForPlayer returns the earliest match starting in the future that the player takes part in.
+// Canceled matches are ignored. It returns nil if the player has no upcoming match.
+func (s *store) GetNextMatchForPlayer(playerID string) (*playtomic.PadelMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Players are stored in teams_blob, so participation has to be checked after scanning.
+	rows, err := s.db.Query(`
+		SELECT id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, ball_bringer_id, ball_bringer_name, processing_status, booking_notified_ts, result_notified_ts, booking_message_ts, deleted_at
+		FROM matches
+		WHERE start_time > ? AND game_status != ? AND deleted_at IS NULL
+		ORDER BY start_time ASC
+	`, s.clock.Now().Unix(), playtomic.GameStatusCanceled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query upcoming matches: %w", err)
 	}
+	defer rows.Close()
 
-	log.Info("Atomically assigned ball bringer", "matchID", matchID, "playerID", selectedPlayerID, "playerName", selectedPlayerName)
-	return selectedPlayerID, selectedPlayerName, nil
+	for rows.Next() {
+		match, err := s.scanMatch(rows)
+		if err != nil {
+			log.Error("Failed to scan match row", "error", err)
+			continue
+		}
+		if _, ok := match.PlayerTeam(playerID); ok {
+			return match, nil
+		}
+	}
+	return nil, rows.Err()
 }
 
-// GetPlayersSortedByLevel retrieves all players from the database, sorted by their level.
-func (s *store) GetPlayersSortedByLevel() ([]PlayerInfo, error) {
+// GetRecentForm returns the results of the player's last n matches counted in the stats, oldest first.
+// A result is true for a win. Fewer than n results are returned if the player has played fewer matches.
+func (s *store) GetRecentForm(playerID string, n int) ([]bool, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	rows, err := s.db.Query("SELECT id, name, ball_bringer_count, level FROM players ORDER BY level DESC")
+	// Players are stored in teams_blob, so participation has to be checked after scanning.
+	rows, err := s.db.Query(`
+		SELECT id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, ball_bringer_id, ball_bringer_name, processing_status, booking_notified_ts, result_notified_ts, booking_message_ts, deleted_at
+		FROM matches
+		WHERE processing_status IN (?, ?) AND game_status = ? AND deleted_at IS NULL
+		ORDER BY start_time DESC
+	`, playtomic.StatusStatsUpdated, playtomic.StatusCompleted, playtomic.GameStatusPlayed)
 	if err != nil {
-		log.Error("Failed to query all players sorted by level", "error", err)
+		return nil, fmt.Errorf("failed to query played matches: %w", err)
+	}
+	defer rows.Close()
+
+	var form []bool
+	for rows.Next() && len(form) < n {
+		match, err := s.scanMatch(rows)
+		if err != nil {
+			log.Error("Failed to scan match row", "error", err)
+			continue
+		}
+		if !statsApplied(match) {
+			continue
+		}
+		match.FillTeamResults()
+		if team, ok := match.PlayerTeam(playerID); ok {
+			form = append(form, team.TeamResult == "WON")
+		}
+	}
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	slices.Reverse(form)
+	return form, nil
+}
+
+// FindPlayersByName returns the players whose name contains the given text, ignoring case, ordered by name.
+func (s *store) FindPlayersByName(name string) ([]PlayerInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`
+		SELECT id, name, ball_bringer_count, level
+		FROM players
+		WHERE name LIKE ? COLLATE NOCASE
+		ORDER BY name ASC
+	`, "%"+name+"%")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find players by name: %w", err)
+	}
 	defer rows.Close()
 
 	var players []PlayerInfo
 	for rows.Next() {
 		var p PlayerInfo
-		var name sql.NullString
+		var playerName sql.NullString
 		var level sql.NullFloat64
-		if err := rows.Scan(&p.ID, &name, &p.BallBringerCount, &level); err != nil {
-			log.Error("Failed to scan player row", "error", err)
-			continue
+		if err := rows.Scan(&p.ID, &playerName, &p.BallBringerCount, &level); err != nil {
+			return nil, fmt.Errorf("failed to scan player row: %w", err)
 		}
-		p.Name = name.String
+		p.Name = playerName.String
 		p.Level = level.Float64
 		players = append(players, p)
 	}
-	return players, nil
+	return players, rows.Err()
 }
 
-// GetAllMatches retrieves all matches from the database.
-func (s *store) GetAllMatches() ([]*playtomic.PadelMatch, error) {
+// GetUnmappedPlayers returns the players that are not linked to a Slack user yet, ordered by name.
+func (s *store) GetUnmappedPlayers() ([]PlayerInfo, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	rows, err := s.db.Query(`
-		SELECT id, owner_id, owner_name, start_time, end_time, created_at, status, game_status, results_status, resource_name, access_code, price, tenant_id, tenant_name, match_type, teams_blob, results_blob, ball_bringer_id, ball_bringer_name, processing_status, booking_notified_ts, result_notified_ts
-		FROM matches
+		SELECT id, name, ball_bringer_count, level
+		FROM players
+		WHERE slack_user_id IS NULL OR slack_user_id = ''
+		ORDER BY name ASC
 	`)
 	if err != nil {
-		log.Error("Failed to query all matches", "error", err)
-		return nil, err
+		return nil, fmt.Errorf("failed to query unmapped players: %w", err)
 	}
 	defer rows.Close()
 
-	var matches []*playtomic.PadelMatch
+	var players []PlayerInfo
 	for rows.Next() {
-		match, err := s.scanMatch(rows)
-		if err != nil {
-			log.Error("Failed to scan match row", "error", err)
+		var p PlayerInfo
+		var playerName sql.NullString
+		var level sql.NullFloat64
+		if err := rows.Scan(&p.ID, &playerName, &p.BallBringerCount, &level); err != nil {
+			return nil, fmt.Errorf("failed to scan player row: %w", err)
+		}
+		p.Name = playerName.String
+		p.Level = level.Float64
+		players = append(players, p)
+	}
+	return players, rows.Err()
+}
+
+// SetSlackUserID maps a player to a Slack user. An empty slackUserID removes the mapping. A Slack user can only be
+// mapped to one player, so a mapping to another player is moved to this one.
+func (s *store) SetSlackUserID(playerID, slackUserID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var value any
+	if slackUserID != "" {
+		value = slackUserID
+		if _, err := tx.Exec("UPDATE players SET slack_user_id = NULL WHERE slack_user_id = ? AND id != ?", slackUserID, playerID); err != nil {
+			return fmt.Errorf("failed to clear previous mapping of slack user %s: %w", slackUserID, err)
+		}
+	}
+	res, err := tx.Exec("UPDATE players SET slack_user_id = ? WHERE id = ?", value, playerID)
+	if err != nil {
+		return fmt.Errorf("failed to set slack user for player %s: %w", playerID, err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("player %s not found", playerID)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit slack user mapping: %w", err)
+	}
+	log.Info("Updated slack user mapping", "playerID", playerID, "slackUserID", slackUserID)
+	return nil
+}
+
+// GetSlackUserIDByPlayerID returns the Slack user mapped to the given player.
+// The boolean is false when the player is unknown or has no mapping.
+func (s *store) GetSlackUserIDByPlayerID(playerID string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var slackUserID sql.NullString
+	err := s.db.QueryRow("SELECT slack_user_id FROM players WHERE id = ?", playerID).Scan(&slackUserID)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get slack user for player %s: %w", playerID, err)
+	}
+	if slackUserID.String == "" {
+		return "", false, nil
+	}
+	return slackUserID.String, true, nil
+}
+
+// GetPlayerBySlackUserID returns the player mapped to the given Slack user.
+// The boolean is false when no player is mapped to it.
+func (s *store) GetPlayerBySlackUserID(slackUserID string) (*PlayerInfo, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if slackUserID == "" {
+		return nil, false, nil
+	}
+
+	var p PlayerInfo
+	var name sql.NullString
+	var level sql.NullFloat64
+	err := s.db.QueryRow("SELECT id, name, ball_bringer_count, level FROM players WHERE slack_user_id = ?", slackUserID).
+		Scan(&p.ID, &name, &p.BallBringerCount, &level)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get player for slack user %s: %w", slackUserID, err)
+	}
+	p.Name = name.String
+	p.Level = level.Float64
+	return &p, true, nil
+}
+
+// uniqueNonEmpty returns the given IDs without blanks and duplicates, preserving order.
+func uniqueNonEmpty(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
 			continue
 		}
-		matches = append(matches, match)
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		result = append(result, id)
 	}
-	return matches, nil
+	return result
 }
 
 func ToAnySlice[T any](s []T) []any {
@@ -1,21 +1,29 @@
 package club_test
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/mauv0809/ideal-tribble/internal/clock"
 	"github.com/mauv0809/ideal-tribble/internal/club"
+	"github.com/mauv0809/ideal-tribble/internal/config"
 	"github.com/mauv0809/ideal-tribble/internal/database"
 	"github.com/mauv0809/ideal-tribble/internal/playtomic"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
 )
 
 // setupTestDB creates a temporary in-memory SQLite database for testing.
 func setupTestDB(t *testing.T) (club.ClubStore, *sql.DB, func()) {
 	t.Helper()
 
-	db, dbTeardown, err := database.InitDB(":memory:", "", "", "../../migrations")
+	db, dbTeardown, err := database.InitDB(":memory:", "", "", "../../migrations", config.DBPoolConfig{})
 	require.NoError(t, err)
 
 	store := club.New(db)
@@ -70,6 +78,44 @@ func TestUpsertMatch(t *testing.T) {
 	assert.Equal(t, playtomic.StatusNew, matches[0].ProcessingStatus)
 }
 
+func TestUpsertMatch_ParsesPrice(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('owner1', 'owner name')`)
+	require.NoError(t, err)
+
+	readPrice := func(matchID string) (string, sql.NullInt64, sql.NullString) {
+		var price string
+		var amount sql.NullInt64
+		var currency sql.NullString
+		err := db.QueryRow("SELECT price, price_amount, price_currency FROM matches WHERE id = ?", matchID).Scan(&price, &amount, &currency)
+		require.NoError(t, err)
+		return price, amount, currency
+	}
+
+	require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m1", OwnerID: "owner1", Price: "10.50 DKK"}))
+	price, amount, currency := readPrice("m1")
+	assert.Equal(t, "10.50 DKK", price)
+	assert.Equal(t, sql.NullInt64{Int64: 1050, Valid: true}, amount)
+	assert.Equal(t, sql.NullString{String: "DKK", Valid: true}, currency)
+
+	require.NoError(t, store.UpsertMatches([]*playtomic.PadelMatch{
+		{MatchID: "m1", OwnerID: "owner1", Price: "12 EUR"},
+		{MatchID: "m2", OwnerID: "owner1"},
+		{MatchID: "m3", OwnerID: "owner1", Price: "free"},
+	}))
+	_, amount, currency = readPrice("m1")
+	assert.Equal(t, sql.NullInt64{Int64: 1200, Valid: true}, amount)
+	assert.Equal(t, sql.NullString{String: "EUR", Valid: true}, currency)
+	_, amount, currency = readPrice("m2")
+	assert.False(t, amount.Valid)
+	assert.False(t, currency.Valid)
+	price, amount, _ = readPrice("m3")
+	assert.Equal(t, "free", price, "an unparseable price is kept as is")
+	assert.False(t, amount.Valid)
+}
+
 func TestGetPlayers(t *testing.T) {
 	store, db, teardown := setupTestDB(t)
 	defer teardown()
@@ -140,193 +186,1593 @@ func TestSetBallBringer(t *testing.T) {
 	assert.Equal(t, 6, count)
 }
 
-func TestUpdateProcessingStatus(t *testing.T) {
+func TestAssignBallBringerAtomically(t *testing.T) {
 	store, db, teardown := setupTestDB(t)
 	defer teardown()
 
-	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('owner1', 'owner name')`)
+	_, err := db.Exec(`INSERT INTO players (id, name, ball_bringer_count) VALUES
+		('p1', 'Player One', 2),
+		('p2', 'Player Two', 1),
+		('p3', 'Player Three', 3)`)
 	require.NoError(t, err)
 
-	match := &playtomic.PadelMatch{MatchID: "match1", OwnerID: "owner1"}
-	err = store.UpsertMatch(match)
+	t.Run("assigns a bringer for a three-player match", func(t *testing.T) {
+		require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m1", OwnerID: "p1"}))
+
+		playerID, playerName, err := store.AssignBallBringerAtomically("m1", []string{"p1", "p2", "p3"})
+		require.NoError(t, err)
+		assert.Equal(t, "p2", playerID)
+		assert.Equal(t, "Player Two", playerName)
+
+		var count int
+		err = db.QueryRow("SELECT ball_bringer_count FROM players WHERE id = 'p2'").Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		var ballBringerID string
+		err = db.QueryRow("SELECT ball_bringer_id FROM matches WHERE id = 'm1'").Scan(&ballBringerID)
+		require.NoError(t, err)
+		assert.Equal(t, "p2", ballBringerID)
+	})
+
+	t.Run("ignores blank and duplicate player IDs", func(t *testing.T) {
+		require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m2", OwnerID: "p1"}))
+
+		// p1 and p2 are now tied on 2; ties are broken by name.
+		playerID, _, err := store.AssignBallBringerAtomically("m2", []string{"p3", "", "p1", "p3", "p2"})
+		require.NoError(t, err)
+		assert.Equal(t, "p1", playerID)
+	})
+
+	t.Run("rejects empty player lists", func(t *testing.T) {
+		_, _, err := store.AssignBallBringerAtomically("m3", []string{"", ""})
+		assert.Error(t, err)
+	})
+}
+
+func TestAssignBallBringerAtomically_Concurrent(t *testing.T) {
+	// Every connection to an in-memory database gets its own empty database, so concurrent work needs a file.
+	db, dbTeardown, err := database.InitDB(filepath.Join(t.TempDir(), "club.db"), "", "", "../../migrations", config.DBPoolConfig{BusyTimeout: 5 * time.Second})
+	require.NoError(t, err)
+	defer func() {
+		dbTeardown()
+		db.Close()
+	}()
+	store := club.New(db)
+
+	_, err = db.Exec(`INSERT INTO players (id, name) VALUES
+		('p1', 'Player One'),
+		('p2', 'Player Two'),
+		('p3', 'Player Three'),
+		('p4', 'Player Four')`)
 	require.NoError(t, err)
+	playerIDs := []string{"p1", "p2", "p3", "p4"}
 
-	err = store.UpdateProcessingStatus("match1", playtomic.StatusBookingNotified)
+	const matches = 40
+	for i := range matches {
+		require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: fmt.Sprintf("m%d", i), OwnerID: "p1"}))
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, matches*2)
+	for i := range matches {
+		// Assign every match twice to also race assignments for the same match.
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, err := store.AssignBallBringerAtomically(fmt.Sprintf("m%d", i), playerIDs)
+				errs <- err
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	// Every match got exactly one bringer, and always picking the lowest count keeps the counts even.
+	players, err := store.GetAllPlayers()
 	require.NoError(t, err)
+	for _, p := range players {
+		assert.Equal(t, matches/len(playerIDs), p.BallBringerCount, "player %s", p.ID)
+	}
 
-	matches, err := store.GetMatchesForProcessing()
+	var unassigned int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM matches WHERE ball_bringer_id IS NULL").Scan(&unassigned))
+	assert.Zero(t, unassigned)
+}
+
+func TestUpsertMatch_RetriesWhileLocked(t *testing.T) {
+	// A busy timeout far shorter than the lock leaves it to the store to retry.
+	pool := config.DBPoolConfig{BusyTimeout: time.Millisecond}
+	db, teardown, err := database.InitDB(filepath.Join(t.TempDir(), "club.db"), "", "", "../../migrations", pool)
 	require.NoError(t, err)
-	require.Len(t, matches, 1)
-	assert.Equal(t, playtomic.StatusBookingNotified, matches[0].ProcessingStatus)
+	defer teardown()
+	store := club.New(db)
+	store.AddPlayer("p1", "Player One", 1.0)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	_, err = tx.Exec("UPDATE players SET level = 2 WHERE id = 'p1'")
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(75 * time.Millisecond)
+		tx.Commit()
+	}()
+
+	require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m1", OwnerID: "p1"}))
+	match, err := store.GetMatch("m1")
+	require.NoError(t, err)
+	assert.Equal(t, "m1", match.MatchID)
 }
 
-func TestGetPlayerStatsByName(t *testing.T) {
+func TestMergePlayers(t *testing.T) {
 	store, db, teardown := setupTestDB(t)
 	defer teardown()
 
-	t.Run("finds player with stats", func(t *testing.T) {
-		_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('player1', 'Morten Voss')`)
+	_, err := db.Exec(`INSERT INTO players (id, name, ball_bringer_count) VALUES
+		('keep', 'Morten Voss', 2),
+		('dupe', 'Morten V', 1),
+		('p3', 'Player Three', 0)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO player_stats (player_id, matches_played, matches_won, matches_lost, sets_won, sets_lost, games_won, games_lost) VALUES
+		('keep', 3, 2, 1, 4, 2, 30, 20),
+		('dupe', 2, 1, 1, 2, 2, 20, 18)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO weekly_player_stats (week_start_date, player_id, matches_played, matches_won) VALUES
+		(100, 'keep', 1, 1),
+		(100, 'dupe', 1, 0),
+		(200, 'dupe', 1, 1)`)
+	require.NoError(t, err)
+
+	match := &playtomic.PadelMatch{
+		MatchID: "m1",
+		OwnerID: "dupe",
+		Teams: []playtomic.Team{
+			{ID: "t1", Players: []playtomic.Player{{UserID: "dupe", Name: "Morten V"}}},
+			{ID: "t2", Players: []playtomic.Player{{UserID: "p3", Name: "Player Three"}}},
+		},
+	}
+	require.NoError(t, store.UpsertMatch(match))
+	require.NoError(t, store.SetBallBringer("m1", "dupe", "Morten V"))
+
+	require.NoError(t, store.MergePlayers("keep", "dupe"))
+
+	t.Run("sums stats into the kept player", func(t *testing.T) {
+		stats, err := store.GetPlayerStatsByName("Morten Voss", false)
 		require.NoError(t, err)
-		_, err = db.Exec(`
-			INSERT INTO player_stats (player_id, matches_played, matches_won)
-			VALUES ('player1', 10, 8)`)
+		assert.Equal(t, "keep", stats.PlayerID)
+		assert.Equal(t, 5, stats.MatchesPlayed)
+		assert.Equal(t, 3, stats.MatchesWon)
+		assert.Equal(t, 2, stats.MatchesLost)
+		assert.Equal(t, 6, stats.SetsWon)
+		assert.Equal(t, 4, stats.SetsLost)
+		assert.Equal(t, 50, stats.GamesWon)
+		assert.Equal(t, 38, stats.GamesLost)
+
+		var played, won int
+		err = db.QueryRow("SELECT matches_played, matches_won FROM weekly_player_stats WHERE week_start_date = 100 AND player_id = 'keep'").Scan(&played, &won)
 		require.NoError(t, err)
-
-		stats, err := store.GetPlayerStatsByName("morten")
+		assert.Equal(t, 2, played)
+		assert.Equal(t, 1, won)
+		err = db.QueryRow("SELECT matches_played, matches_won FROM weekly_player_stats WHERE week_start_date = 200 AND player_id = 'keep'").Scan(&played, &won)
 		require.NoError(t, err)
-		require.NotNil(t, stats)
-
-		assert.Equal(t, "Morten Voss", stats.PlayerName)
-		assert.Equal(t, 10, stats.MatchesPlayed)
-		assert.Equal(t, 8, stats.MatchesWon)
-		assert.InDelta(t, 80.0, stats.WinPercentage, 0.01)
+		assert.Equal(t, 1, played)
+		assert.Equal(t, 1, won)
 	})
 
-	t.Run("returns zero stats for player with no stats entry", func(t *testing.T) {
-		_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('player2', 'New Player')`)
+	t.Run("removes the merged player", func(t *testing.T) {
+		assert.False(t, store.IsKnownPlayer("dupe"))
+
+		var count int
+		err := db.QueryRow("SELECT COUNT(*) FROM player_stats WHERE player_id = 'dupe'").Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+		err = db.QueryRow("SELECT COUNT(*) FROM weekly_player_stats WHERE player_id = 'dupe'").Scan(&count)
 		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
 
-		stats, err := store.GetPlayerStatsByName("New Player")
+	t.Run("repoints match references", func(t *testing.T) {
+		matches, err := store.GetAllMatches(false)
 		require.NoError(t, err)
-		require.NotNil(t, stats)
-		assert.Equal(t, "New Player", stats.PlayerName)
-		assert.Equal(t, 0, stats.MatchesPlayed)
+		require.Len(t, matches, 1)
+		assert.Equal(t, "keep", matches[0].OwnerID)
+		assert.Equal(t, "keep", matches[0].BallBringerID)
+		assert.Equal(t, "Morten Voss", matches[0].BallBringerName)
+		assert.Equal(t, "keep", matches[0].Teams[0].Players[0].UserID)
+		assert.Equal(t, "p3", matches[0].Teams[1].Players[0].UserID)
+
+		players, err := store.GetPlayers([]string{"keep"})
+		require.NoError(t, err)
+		require.Len(t, players, 1)
+		assert.Equal(t, 4, players[0].BallBringerCount)
 	})
 
-	t.Run("returns error when player not found", func(t *testing.T) {
-		stats, err := store.GetPlayerStatsByName("nonexistent")
-		assert.Error(t, err)
-		assert.Nil(t, stats)
+	t.Run("rejects invalid merges", func(t *testing.T) {
+		assert.Error(t, store.MergePlayers("keep", "keep"))
+		assert.Error(t, store.MergePlayers("keep", "unknown"))
+		assert.Error(t, store.MergePlayers("unknown", "p3"))
+		assert.True(t, store.IsKnownPlayer("p3"))
 	})
 }
 
-func TestGetPlayersSortedByLevel(t *testing.T) {
-	store, _, teardown := setupTestDB(t)
+func TestMergePlayers_PooledConnectionsWithoutForeignKeys(t *testing.T) {
+	pool := config.DBPoolConfig{MaxOpenConns: 2, MaxIdleConns: 2, BusyTimeout: 5 * time.Second}
+	db, teardown, err := database.InitDB(filepath.Join(t.TempDir(), "club.db"), "", "", "../../migrations", pool)
+	require.NoError(t, err)
 	defer teardown()
+	store := club.New(db)
 
-	players := []club.PlayerInfo{
-		{ID: "p1", Name: "Player A", Level: 1.5},
-		{ID: "p2", Name: "Player B", Level: 2.5},
-		{ID: "p3", Name: "Player C", Level: 0.5},
+	// Turn foreign keys off on every pooled connection, so the merge can't rely on ON DELETE CASCADE.
+	ctx := context.Background()
+	var conns []*sql.Conn
+	for range pool.MaxOpenConns {
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+		_, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF")
+		require.NoError(t, err)
+		conns = append(conns, conn)
 	}
-
-	for _, p := range players {
-		store.AddPlayer(p.ID, p.Name, p.Level)
+	for _, conn := range conns {
+		require.NoError(t, conn.Close())
 	}
 
-	sortedPlayers, err := store.GetPlayersSortedByLevel()
+	_, err = db.Exec(`INSERT INTO players (id, name) VALUES ('keep', 'Morten Voss'), ('dupe', 'Morten V')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO player_stats (player_id, matches_played) VALUES ('keep', 1), ('dupe', 2)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO weekly_player_stats (week_start_date, player_id, matches_played) VALUES (100, 'dupe', 2)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO season_player_stats (season_id, player_id, matches_played) VALUES (1, 'dupe', 2)`)
 	require.NoError(t, err)
-	assert.Len(t, sortedPlayers, 3)
 
-	assert.Equal(t, "Player B", sortedPlayers[0].Name)
-	assert.Equal(t, "Player A", sortedPlayers[1].Name)
-	assert.Equal(t, "Player C", sortedPlayers[2].Name)
+	require.NoError(t, store.MergePlayers("keep", "dupe"))
+
+	for _, table := range []string{"player_stats", "weekly_player_stats", "season_player_stats"} {
+		var orphans int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM "+table+" WHERE player_id = 'dupe'").Scan(&orphans))
+		assert.Zero(t, orphans, table)
+	}
+	stats, err := store.GetPlayerStatsByID("keep", false)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.MatchesPlayed)
 }
 
-func TestClear(t *testing.T) {
+func TestSlackUserMapping(t *testing.T) {
 	store, _, teardown := setupTestDB(t)
 	defer teardown()
 
-	// Add some data
-	store.AddPlayer("player1", "Player One", 1.0)
-	match := &playtomic.PadelMatch{MatchID: "m1", OwnerID: "player1"}
-	err := store.UpsertMatch(match)
-	require.NoError(t, err)
+	store.AddPlayer("mapped", "Mapped Player", 1.0)
+	store.AddPlayer("unmapped", "Unmapped Player", 1.0)
+	require.NoError(t, store.SetSlackUserID("mapped", "U123"))
 
-	// Clear the database
-	store.Clear()
+	t.Run("reverse lookup of a mapped player", func(t *testing.T) {
+		slackUserID, ok, err := store.GetSlackUserIDByPlayerID("mapped")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "U123", slackUserID)
+	})
 
-	// Verify all data is cleared
-	allPlayers, err := store.GetAllPlayers()
-	require.NoError(t, err)
-	assert.Len(t, allPlayers, 0)
+	t.Run("reverse lookup of an unmapped player", func(t *testing.T) {
+		slackUserID, ok, err := store.GetSlackUserIDByPlayerID("unmapped")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Empty(t, slackUserID)
+	})
 
-	matches, err := store.GetMatchesForProcessing()
-	require.NoError(t, err)
-	assert.Len(t, matches, 0)
+	t.Run("reverse lookup of a nonexistent player", func(t *testing.T) {
+		_, ok, err := store.GetSlackUserIDByPlayerID("nonexistent")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("forward lookup", func(t *testing.T) {
+		player, ok, err := store.GetPlayerBySlackUserID("U123")
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, "mapped", player.ID)
+		assert.Equal(t, "Mapped Player", player.Name)
+
+		_, ok, err = store.GetPlayerBySlackUserID("U999")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("unmapped players", func(t *testing.T) {
+		players, err := store.GetUnmappedPlayers()
+		require.NoError(t, err)
+		require.Len(t, players, 1)
+		assert.Equal(t, "unmapped", players[0].ID)
+		assert.Equal(t, "Unmapped Player", players[0].Name)
+	})
+
+	t.Run("remapping a slack user moves it to the new player", func(t *testing.T) {
+		require.NoError(t, store.SetSlackUserID("unmapped", "U123"))
+
+		player, ok, err := store.GetPlayerBySlackUserID("U123")
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, "unmapped", player.ID)
+		_, ok, err = store.GetSlackUserIDByPlayerID("mapped")
+		require.NoError(t, err)
+		assert.False(t, ok, "a slack user can only be mapped to one player")
+
+		require.NoError(t, store.SetSlackUserID("mapped", "U123"))
+		require.NoError(t, store.SetSlackUserID("mapped", "U123"), "mapping the same player again is a no-op")
+		player, ok, err = store.GetPlayerBySlackUserID("U123")
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, "mapped", player.ID)
+	})
+
+	t.Run("clearing a mapping", func(t *testing.T) {
+		require.NoError(t, store.SetSlackUserID("mapped", ""))
+		_, ok, err := store.GetSlackUserIDByPlayerID("mapped")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		players, err := store.GetUnmappedPlayers()
+		require.NoError(t, err)
+		assert.Len(t, players, 2)
+	})
+
+	t.Run("mapping a nonexistent player fails", func(t *testing.T) {
+		assert.Error(t, store.SetSlackUserID("nonexistent", "U456"))
+	})
 }
 
-func TestUpdatePlayerStats(t *testing.T) {
+func TestCorePlayer(t *testing.T) {
 	store, _, teardown := setupTestDB(t)
 	defer teardown()
 
-	t.Run("correctly updates stats for a single match", func(t *testing.T) {
-		store.AddPlayer("p1", "Morten Voss", 1.0)
-		store.AddPlayer("p2", "Player Two", 1.0)
-		store.AddPlayer("p3", "Player Three", 1.0)
-		store.AddPlayer("p4", "Player Four", 1.0)
+	store.AddPlayer("p1", "Player One", 1.0)
+	assert.False(t, store.IsCorePlayer("p1"))
+
+	require.NoError(t, store.SetCorePlayer("p1", true))
+	assert.True(t, store.IsCorePlayer("p1"))
+
+	require.NoError(t, store.SetCorePlayer("p1", false))
+	assert.False(t, store.IsCorePlayer("p1"))
+
+	assert.Error(t, store.SetCorePlayer("nonexistent", true))
+	assert.False(t, store.IsCorePlayer("nonexistent"))
+}
+
+func TestDMOptOut(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	store.AddPlayer("p1", "Player One", 1.0)
+	assert.False(t, store.IsDMOptedOut("p1"))
+
+	require.NoError(t, store.SetDMOptOut("p1", true))
+	assert.True(t, store.IsDMOptedOut("p1"))
+
+	require.NoError(t, store.SetDMOptOut("p1", false))
+	assert.False(t, store.IsDMOptedOut("p1"))
+
+	assert.Error(t, store.SetDMOptOut("nonexistent", true))
+}
+
+func TestStartNewSeason(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
 
+	store.AddPlayer("p1", "Player One", 1.0)
+	store.AddPlayer("p2", "Player Two", 1.0)
+	playMatch := func(id string, start int64, winner string) *playtomic.PadelMatch {
+		loser := "p2"
+		if winner == "p2" {
+			loser = "p1"
+		}
 		match := &playtomic.PadelMatch{
-			MatchID: "match1",
-			OwnerID: "p1",
+			MatchID:       id,
+			OwnerID:       "p1",
+			Start:         start,
+			GameStatus:    playtomic.GameStatusPlayed,
+			ResultsStatus: playtomic.ResultsStatusConfirmed,
+			MatchType:     playtomic.MatchTypeCompetition,
 			Teams: []playtomic.Team{
-				{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p1", Name: "Morten Voss"}, {UserID: "p2", Name: "Player Two"}}},
-				{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p3", Name: "Player Three"}, {UserID: "p4", Name: "Player Four"}}},
-			},
-			Results: []playtomic.SetResult{
-				{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 4}},
-				{Name: "Set-2", Scores: map[string]int{"t1": 7, "t2": 5}},
+				{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: winner}}},
+				{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: loser}}},
 			},
+			Results: []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 2}}},
 		}
-
+		require.NoError(t, store.UpsertMatch(match))
 		store.UpdatePlayerStats(match)
+		require.NoError(t, store.UpdateProcessingStatus(id, playtomic.StatusCompleted))
+		return match
+	}
 
-		stats, err := store.GetPlayerStatsByName("Morten Voss")
-		require.NoError(t, err)
-		assert.Equal(t, 1, stats.MatchesPlayed)
-		assert.Equal(t, 1, stats.MatchesWon)
-		assert.Equal(t, 0, stats.MatchesLost)
-		assert.Equal(t, 2, stats.SetsWon)
-		assert.Equal(t, 0, stats.SetsLost)
-		assert.Equal(t, 13, stats.GamesWon)
-		assert.Equal(t, 9, stats.GamesLost)
-		assert.InDelta(t, 100.0, stats.WinPercentage, 0.01)
+	seasonStart := time.Now().Unix()
+	playMatch("old", seasonStart-3600, "p1")
+
+	season, err := store.StartNewSeason()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), season.ID)
+	assert.GreaterOrEqual(t, season.StartedAt, seasonStart)
+
+	// The live stats are reset...
+	stats, err := store.GetPlayerStats(0, club.SortByWins, false, false)
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+	stats, err = store.GetPlayerStats(0, club.SortByWins, true, false)
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+
+	// ...and archived under the season that ended.
+	var archivedWins int
+	require.NoError(t, db.QueryRow("SELECT matches_won FROM season_player_stats WHERE season_id = 1 AND player_id = 'p1'").Scan(&archivedWins))
+	assert.Equal(t, 1, archivedWins)
+
+	playMatch("new", season.StartedAt+3600, "p2")
+
+	stats, err = store.GetPlayerStats(1, club.SortByWins, false, false)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.Equal(t, "p2", stats[0].PlayerID)
+	assert.Equal(t, 1, stats[0].MatchesPlayed)
 
-		stats, err = store.GetPlayerStatsByName("Player Three")
+	for _, competitiveOnly := range []bool{false, true} {
+		stats, err = store.GetPlayerStats(1, club.SortByWins, competitiveOnly, true)
 		require.NoError(t, err)
-		assert.Equal(t, 1, stats.MatchesPlayed)
-		assert.Equal(t, 0, stats.MatchesWon)
-		assert.Equal(t, 1, stats.MatchesLost)
-		assert.Equal(t, 0, stats.SetsWon)
-		assert.Equal(t, 2, stats.SetsLost)
-		assert.Equal(t, 9, stats.GamesWon)
-		assert.Equal(t, 13, stats.GamesLost)
-		assert.InDelta(t, 0.0, stats.WinPercentage, 0.01)
-	})
+		require.Len(t, stats, 2)
+		for _, stat := range stats {
+			assert.Equal(t, 2, stat.MatchesPlayed, "competitiveOnly=%v", competitiveOnly)
+			assert.Equal(t, 1, stat.MatchesWon, "competitiveOnly=%v", competitiveOnly)
+		}
+	}
+
+	assert.ErrorIs(t, store.UndoMatchStats("old"), club.ErrStatsArchived)
+	require.NoError(t, store.UndoMatchStats("new"))
+
+	// A match that started before the new season but is counted after it belongs to the new season.
+	late := playMatch("late", season.StartedAt-1800, "p2")
+	stats, err = store.GetPlayerStats(1, club.SortByWins, true, false)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.Equal(t, "p2", stats[0].PlayerID)
+	assert.Equal(t, 1, stats[0].MatchesWon)
+
+	corrected := *late
+	corrected.Teams = []playtomic.Team{
+		{ID: "t1", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p2"}}},
+		{ID: "t2", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p1"}}},
+	}
+	corrected.Results = []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 2, "t2": 6}}}
+	require.NoError(t, store.UpsertMatch(&corrected))
+	corrections, err := store.GetResultCorrections()
+	require.NoError(t, err)
+	require.Len(t, corrections, 1)
+	assert.Equal(t, "late", corrections[0].Match.MatchID)
+	require.NoError(t, store.CorrectMatchStats("late"))
+	require.NoError(t, store.UndoMatchStats("late"))
 }
 
-func TestUpdateNotificationTimestamp(t *testing.T) {
+func TestUpdateProcessingStatus(t *testing.T) {
 	store, db, teardown := setupTestDB(t)
 	defer teardown()
 
-	// Insert a player to satisfy foreign key constraint
-	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('p1', 'Player One')`)
+	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('owner1', 'owner name')`)
 	require.NoError(t, err)
 
-	// Insert a match with initial null timestamps
-	match := &playtomic.PadelMatch{
-		MatchID:          "test_match_id",
-		OwnerID:          "p1",
-		OwnerName:        "Player One",
-		Start:            1678886400, // Example Unix timestamp
-		End:              1678890000,
-		CreatedAt:        1678880000,
-		Status:           "STATUS_OPEN",
-		GameStatus:       "GAME_STATUS_UNKNOWN",
-		ResultsStatus:    "RESULTS_STATUS_WAITING",
-		ResourceName:     "Court 1",
-		Tenant:           playtomic.Tenant{ID: "tenant1", Name: "Tenant Name"},
-		ProcessingStatus: "NEW",
-		MatchType:        "PADEL_MATCH",
-	}
-	require.NoError(t, store.UpsertMatch(match))
+	match := &playtomic.PadelMatch{MatchID: "match1", OwnerID: "owner1"}
+	err = store.UpsertMatch(match)
+	require.NoError(t, err)
 
-	// Test updating booking_notified_ts
-	err = store.UpdateNotificationTimestamp("test_match_id", "booking")
+	err = store.UpdateProcessingStatus("match1", playtomic.StatusBookingNotified)
 	require.NoError(t, err)
 
-	var bookingTS sql.NullInt64
-	err = db.QueryRow("SELECT booking_notified_ts FROM matches WHERE id = ?", "test_match_id").Scan(&bookingTS)
+	matches, err := store.GetMatchesForProcessing()
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, playtomic.StatusBookingNotified, matches[0].ProcessingStatus)
+}
+
+func TestGetProcessingStatusCounts(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('owner1', 'owner name')`)
+	require.NoError(t, err)
+
+	for _, id := range []string{"m1", "m2", "m3", "m4"} {
+		require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: id, OwnerID: "owner1"}))
+	}
+	require.NoError(t, store.UpdateProcessingStatus("m2", playtomic.StatusBookingNotified))
+	require.NoError(t, store.UpdateProcessingStatus("m3", playtomic.StatusCompleted))
+	require.NoError(t, store.UpdateProcessingStatus("m4", playtomic.StatusCompleted))
+
+	counts, err := store.GetProcessingStatusCounts()
+	require.NoError(t, err)
+	assert.Equal(t, map[playtomic.ProcessingStatus]int{
+		playtomic.StatusNew:             1,
+		playtomic.StatusBookingNotified: 1,
+		playtomic.StatusCompleted:       2,
+	}, counts)
+}
+
+func TestGetMatchesStuckInStatus(t *testing.T) {
+	_, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	now := time.Date(2024, 3, 15, 18, 0, 0, 0, time.UTC)
+	clk := clock.NewMock(now)
+	store := club.NewWithClock(db, clk)
+
+	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('owner1', 'owner name')`)
+	require.NoError(t, err)
+	for _, id := range []string{"stuck", "recent", "other"} {
+		require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: id, OwnerID: "owner1"}))
+	}
+	require.NoError(t, store.UpdateProcessingStatus("stuck", playtomic.StatusAssigningBallBringer))
+	require.NoError(t, store.UpdateProcessingStatus("other", playtomic.StatusCompleted))
+	clk.Advance(13 * time.Hour)
+	require.NoError(t, store.UpdateProcessingStatus("recent", playtomic.StatusAssigningBallBringer))
+
+	matches, err := store.GetMatchesStuckInStatus(playtomic.StatusAssigningBallBringer, clk.Now().Add(-12*time.Hour).Unix())
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "stuck", matches[0].MatchID)
+}
+
+func TestBookingDigest(t *testing.T) {
+	_, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	now := time.Date(2024, 3, 15, 18, 0, 0, 0, time.UTC)
+	store := club.NewWithClock(db, clock.NewMock(now))
+
+	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('owner1', 'owner name')`)
+	require.NoError(t, err)
+	for _, id := range []string{"later", "sooner", "notified", "new"} {
+		start := now.Add(48 * time.Hour)
+		if id == "sooner" {
+			start = now.Add(24 * time.Hour)
+		}
+		require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: id, OwnerID: "owner1", Start: start.Unix()}))
+	}
+	for _, id := range []string{"later", "sooner", "notified"} {
+		require.NoError(t, store.UpdateProcessingStatus(id, playtomic.StatusBallBoyAssigned))
+	}
+	require.NoError(t, store.UpdateNotificationTimestamp("notified", "booking"))
+
+	matches, err := store.GetUndigestedBookings()
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "sooner", matches[0].MatchID)
+	assert.Equal(t, "later", matches[1].MatchID)
+
+	require.NoError(t, store.MarkBookingsDigested([]string{"sooner", "later"}))
+	matches, err = store.GetUndigestedBookings()
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	match, err := store.GetMatch("sooner")
+	require.NoError(t, err)
+	assert.Equal(t, playtomic.StatusBookingNotified, match.ProcessingStatus)
+	require.NotNil(t, match.BookingNotifiedTs)
+	assert.Equal(t, now.Unix(), *match.BookingNotifiedTs)
+}
+
+func TestGetPlayerStats(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('lucky', 'Lucky Player'), ('regular', 'Regular Player')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		INSERT INTO player_stats (player_id, matches_played, matches_won)
+		VALUES ('lucky', 1, 1), ('regular', 10, 6)`)
+	require.NoError(t, err)
+
+	t.Run("includes everyone at the default threshold", func(t *testing.T) {
+		stats, err := store.GetPlayerStats(1, club.SortByWins, false, false)
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+		assert.Equal(t, "Regular Player", stats[0].PlayerName)
+		assert.Equal(t, "Lucky Player", stats[1].PlayerName)
+	})
+
+	t.Run("filters players below the threshold", func(t *testing.T) {
+		stats, err := store.GetPlayerStats(5, club.SortByWins, false, false)
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.Equal(t, "Regular Player", stats[0].PlayerName)
+	})
+
+	t.Run("keeps individual stats unfiltered", func(t *testing.T) {
+		stats, err := store.GetPlayerStatsByName("Lucky Player", false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats.MatchesPlayed)
+	})
+}
+
+func TestGetPlayerStats_Rank(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('a', 'Anna'), ('b', 'Bo'), ('c', 'Carl'), ('d', 'Dina')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		INSERT INTO player_stats (player_id, matches_played, matches_won, sets_won, games_won)
+		VALUES ('a', 10, 8, 16, 100), ('b', 10, 6, 12, 80), ('c', 10, 4, 8, 60), ('d', 10, 2, 4, 40)`)
+	require.NoError(t, err)
+
+	ranks := func(stats []club.PlayerStats) map[string]int {
+		result := make(map[string]int)
+		for _, stat := range stats {
+			result[stat.PlayerName] = stat.Rank
+		}
+		return result
+	}
+
+	t.Run("ranks players without ties in order", func(t *testing.T) {
+		stats, err := store.GetPlayerStats(1, club.SortByWins, false, false)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"Anna": 1, "Bo": 2, "Carl": 3, "Dina": 4}, ranks(stats))
+	})
+
+	t.Run("tied players share a rank and the next rank skips their places", func(t *testing.T) {
+		_, err := db.Exec(`UPDATE player_stats SET matches_won = 6, sets_won = 12, games_won = 80 WHERE player_id = 'c'`)
+		require.NoError(t, err)
+
+		for _, sortBy := range []club.StatsSortBy{club.SortByWins, club.SortByConfidence} {
+			stats, err := store.GetPlayerStats(1, sortBy, false, false)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]int{"Anna": 1, "Bo": 2, "Carl": 2, "Dina": 4}, ranks(stats), sortBy)
+		}
+	})
+}
+
+func TestGetPlayerStats_SortBy(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	// The busy player has the most wins, the efficient player the best win percentage,
+	// and the grinder the most sets and games.
+	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('busy', 'Busy'), ('efficient', 'Efficient'), ('grinder', 'Grinder')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		INSERT INTO player_stats (player_id, matches_played, matches_won, sets_won, games_won)
+		VALUES ('busy', 20, 10, 22, 150), ('efficient', 4, 4, 8, 50), ('grinder', 15, 6, 30, 200)`)
+	require.NoError(t, err)
+
+	names := func(stats []club.PlayerStats) []string {
+		var result []string
+		for _, stat := range stats {
+			result = append(result, stat.PlayerName)
+		}
+		return result
+	}
+
+	testCases := []struct {
+		sortBy   club.StatsSortBy
+		expected []string
+	}{
+		{club.SortByWins, []string{"Busy", "Grinder", "Efficient"}},
+		{club.SortByWinPercentage, []string{"Efficient", "Busy", "Grinder"}},
+		{club.SortBySets, []string{"Grinder", "Busy", "Efficient"}},
+		{club.SortByGames, []string{"Grinder", "Busy", "Efficient"}},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.sortBy), func(t *testing.T) {
+			stats, err := store.GetPlayerStats(1, tc.sortBy, false, false)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, names(stats))
+		})
+	}
+
+	t.Run("parses sort options", func(t *testing.T) {
+		sortBy, err := club.ParseStatsSortBy("")
+		require.NoError(t, err)
+		assert.Equal(t, club.SortByWins, sortBy)
+
+		sortBy, err = club.ParseStatsSortBy("WinPct")
+		require.NoError(t, err)
+		assert.Equal(t, club.SortByWinPercentage, sortBy)
+
+		_, err = club.ParseStatsSortBy("level")
+		assert.Error(t, err)
+	})
+}
+
+func TestGetPlayerStats_Confident(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	// The streak player has a perfect record over three matches, the veteran a strong one over fifty.
+	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('streak', 'Streak'), ('veteran', 'Veteran'), ('average', 'Average')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		INSERT INTO player_stats (player_id, matches_played, matches_won)
+		VALUES ('streak', 3, 3), ('veteran', 50, 40), ('average', 20, 12)`)
+	require.NoError(t, err)
+
+	names := func(stats []club.PlayerStats) []string {
+		var result []string
+		for _, stat := range stats {
+			result = append(result, stat.PlayerName)
+		}
+		return result
+	}
+
+	raw, err := store.GetPlayerStats(1, club.SortByWinPercentage, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Streak", "Veteran", "Average"}, names(raw))
+
+	confident, err := store.GetPlayerStats(1, club.SortByConfidence, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Veteran", "Streak", "Average"}, names(confident))
+
+	assert.InDelta(t, 66.9, confident[0].WinPctLowerBound, 0.1)
+	assert.InDelta(t, 43.9, confident[1].WinPctLowerBound, 0.1)
+	assert.InDelta(t, 38.7, confident[2].WinPctLowerBound, 0.1)
+	for _, stat := range confident {
+		assert.Less(t, stat.WinPctLowerBound, stat.WinPercentage)
+	}
+
+	sortBy, err := club.ParseStatsSortBy("confident")
+	require.NoError(t, err)
+	assert.Equal(t, club.SortByConfidence, sortBy)
+}
+
+func TestGetPlayerStats_CompetitiveOnly(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	store.AddPlayer("p1", "Morten Voss", 1.0)
+	store.AddPlayer("p2", "Player Two", 1.0)
+	newMatch := func(id string, matchType playtomic.MatchType, winner string) *playtomic.PadelMatch {
+		loser := map[string]string{"p1": "p2", "p2": "p1"}[winner]
+		return &playtomic.PadelMatch{
+			MatchID:       id,
+			OwnerID:       "p1",
+			MatchType:     matchType,
+			GameStatus:    playtomic.GameStatusPlayed,
+			ResultsStatus: playtomic.ResultsStatusConfirmed,
+			Teams: []playtomic.Team{
+				{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: winner}}},
+				{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: loser}}},
+			},
+			Results: []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 2}}},
+		}
+	}
+	for _, match := range []*playtomic.PadelMatch{
+		newMatch("competitive", playtomic.MatchTypeCompetition, "p1"),
+		newMatch("practice-1", playtomic.MatchTypePractice, "p2"),
+		newMatch("practice-2", playtomic.MatchTypePractice, "p2"),
+	} {
+		require.NoError(t, store.UpsertMatch(match))
+		store.UpdatePlayerStats(match)
+		require.NoError(t, store.UpdateProcessingStatus(match.MatchID, playtomic.StatusCompleted))
+	}
+
+	t.Run("all matches are counted without the filter", func(t *testing.T) {
+		stats, err := store.GetPlayerStats(1, club.SortByWins, false, false)
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+		assert.Equal(t, "p2", stats[0].PlayerID)
+		assert.Equal(t, 3, stats[0].MatchesPlayed)
+	})
+
+	t.Run("practice matches are excluded with the filter", func(t *testing.T) {
+		stats, err := store.GetPlayerStats(1, club.SortByWins, true, false)
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+		assert.Equal(t, "p1", stats[0].PlayerID)
+		assert.Equal(t, "Morten Voss", stats[0].PlayerName)
+		assert.Equal(t, 1, stats[0].MatchesPlayed)
+		assert.Equal(t, 1, stats[0].MatchesWon)
+		assert.Equal(t, 6, stats[0].GamesWon)
+		assert.InDelta(t, 100.0, stats[0].WinPercentage, 0.01)
+		assert.Equal(t, "p2", stats[1].PlayerID)
+		assert.Equal(t, 1, stats[1].MatchesLost)
+	})
+
+	t.Run("player stats by name honour the filter", func(t *testing.T) {
+		stats, err := store.GetPlayerStatsByName("player two", true)
+		require.NoError(t, err)
+		assert.Equal(t, "Player Two", stats.PlayerName)
+		assert.Equal(t, 1, stats.MatchesPlayed)
+		assert.Equal(t, 0, stats.MatchesWon)
+	})
+}
+
+func TestGetPlayerStatsByName(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	t.Run("finds player with stats", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('player1', 'Morten Voss')`)
+		require.NoError(t, err)
+		_, err = db.Exec(`
+			INSERT INTO player_stats (player_id, matches_played, matches_won)
+			VALUES ('player1', 10, 8)`)
+		require.NoError(t, err)
+
+		stats, err := store.GetPlayerStatsByName("morten", false)
+		require.NoError(t, err)
+		require.NotNil(t, stats)
+
+		assert.Equal(t, "Morten Voss", stats.PlayerName)
+		assert.Equal(t, 10, stats.MatchesPlayed)
+		assert.Equal(t, 8, stats.MatchesWon)
+		assert.InDelta(t, 80.0, stats.WinPercentage, 0.01)
+	})
+
+	t.Run("returns zero stats for player with no stats entry", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('player2', 'New Player')`)
+		require.NoError(t, err)
+
+		stats, err := store.GetPlayerStatsByName("New Player", false)
+		require.NoError(t, err)
+		require.NotNil(t, stats)
+		assert.Equal(t, "New Player", stats.PlayerName)
+		assert.Equal(t, 0, stats.MatchesPlayed)
+	})
+
+	t.Run("returns error when player not found", func(t *testing.T) {
+		stats, err := store.GetPlayerStatsByName("nonexistent", false)
+		assert.Error(t, err)
+		assert.Nil(t, stats)
+	})
+
+	t.Run("finds player by ID", func(t *testing.T) {
+		stats, err := store.GetPlayerStatsByID("player1", false)
+		require.NoError(t, err)
+		require.NotNil(t, stats)
+		assert.Equal(t, "Morten Voss", stats.PlayerName)
+		assert.Equal(t, 8, stats.MatchesWon)
+
+		_, err = store.GetPlayerStatsByID("player", false)
+		assert.Error(t, err, "IDs are not matched fuzzily")
+	})
+}
+
+func TestGetPlayersSortedByLevel(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	players := []club.PlayerInfo{
+		{ID: "p1", Name: "Player A", Level: 1.5},
+		{ID: "p2", Name: "Player B", Level: 2.5},
+		{ID: "p3", Name: "Player C", Level: 0.5},
+	}
+
+	for _, p := range players {
+		store.AddPlayer(p.ID, p.Name, p.Level)
+	}
+
+	sortedPlayers, err := store.GetPlayersSortedByLevel()
+	require.NoError(t, err)
+	assert.Len(t, sortedPlayers, 3)
+
+	assert.Equal(t, "Player B", sortedPlayers[0].Name)
+	assert.Equal(t, "Player A", sortedPlayers[1].Name)
+	assert.Equal(t, "Player C", sortedPlayers[2].Name)
+}
+
+func TestGetBallBringerCounts(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	_, err := db.Exec(`INSERT INTO players (id, name, ball_bringer_count) VALUES
+		('p1', 'Player A', 1),
+		('p2', 'Player B', 4),
+		('p3', 'Player C', 2),
+		('p4', 'Player D', 2)`)
+	require.NoError(t, err)
+
+	players, err := store.GetBallBringerCounts()
+	require.NoError(t, err)
+	require.Len(t, players, 4)
+
+	assert.Equal(t, "Player B", players[0].Name)
+	assert.Equal(t, 4, players[0].BallBringerCount)
+	// Ties are broken by name.
+	assert.Equal(t, "Player C", players[1].Name)
+	assert.Equal(t, "Player D", players[2].Name)
+	assert.Equal(t, "Player A", players[3].Name)
+	assert.Equal(t, 1, players[3].BallBringerCount)
+}
+
+func TestResetBallBringerCounts(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	_, err := db.Exec(`INSERT INTO players (id, name, ball_bringer_count) VALUES
+		('p1', 'Player A', 3),
+		('p2', 'Player B', 5),
+		('p3', 'Player C', 1)`)
+	require.NoError(t, err)
+
+	require.NoError(t, store.ResetBallBringerCount("p2"))
+	counts := func() map[string]int {
+		players, err := store.GetBallBringerCounts()
+		require.NoError(t, err)
+		counts := make(map[string]int)
+		for _, p := range players {
+			counts[p.ID] = p.BallBringerCount
+		}
+		return counts
+	}
+	assert.Equal(t, map[string]int{"p1": 3, "p2": 0, "p3": 1}, counts())
+
+	require.NoError(t, store.ResetBallBringerCounts())
+	assert.Equal(t, map[string]int{"p1": 0, "p2": 0, "p3": 0}, counts())
+
+	assert.Error(t, store.ResetBallBringerCount("unknown"))
+}
+
+func TestGetPlayerSpend(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('p1', 'Player One')`)
+	require.NoError(t, err)
+
+	doubles := []playtomic.Team{
+		{Players: []playtomic.Player{{UserID: "p1", Name: "Player One"}, {UserID: "p2", Name: "Player Two"}}},
+		{Players: []playtomic.Player{{UserID: "p3", Name: "Player Three"}, {UserID: "p4", Name: "Player Four"}}},
+	}
+	singles := []playtomic.Team{
+		{Players: []playtomic.Player{{UserID: "p1", Name: "Player One"}}},
+		{Players: []playtomic.Player{{UserID: "p2", Name: "Player Two"}}},
+	}
+	require.NoError(t, store.UpsertMatches([]*playtomic.PadelMatch{
+		{MatchID: "m1", OwnerID: "p1", Start: 100, Price: "40 EUR", Teams: doubles},
+		{MatchID: "m2", OwnerID: "p1", Start: 200, Price: "10.01 EUR", Teams: singles},
+		{MatchID: "m3", OwnerID: "p1", Start: 300, Teams: doubles}, // No price, left out.
+		{MatchID: "m4", OwnerID: "p1", Start: 400, Price: "100 EUR", Teams: doubles},
+		{MatchID: "m5", OwnerID: "p1", Start: 250, Price: "60 EUR", Teams: doubles, GameStatus: playtomic.GameStatusCanceled}, // Canceled, left out.
+	}))
+
+	spend, err := store.GetPlayerSpend(0, 300)
+	require.NoError(t, err)
+	require.Len(t, spend, 4)
+	byPlayer := make(map[string]club.PlayerSpend)
+	for _, entry := range spend {
+		byPlayer[entry.PlayerID] = entry
+	}
+	assert.Equal(t, club.PlayerSpend{PlayerID: "p1", PlayerName: "Player One", Currency: "EUR", Matches: 2, Amount: 1000 + 501}, byPlayer["p1"])
+	assert.Equal(t, club.PlayerSpend{PlayerID: "p2", PlayerName: "Player Two", Currency: "EUR", Matches: 2, Amount: 1000 + 500}, byPlayer["p2"])
+	assert.Equal(t, int64(1000), byPlayer["p3"].Amount)
+	assert.Equal(t, "p1", spend[0].PlayerID, "the biggest spender comes first")
+
+	var total int64
+	for _, entry := range spend {
+		total += entry.Amount
+	}
+	assert.Equal(t, int64(4000+1001), total, "the shares add up to the match prices")
+
+	spend, err = store.GetPlayerSpend(400, 0)
+	require.NoError(t, err)
+	require.Len(t, spend, 4)
+	assert.Equal(t, int64(2500), spend[0].Amount)
+}
+
+func TestClear(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	// Add some data
+	store.AddPlayer("player1", "Player One", 1.0)
+	match := &playtomic.PadelMatch{MatchID: "m1", OwnerID: "player1"}
+	err := store.UpsertMatch(match)
+	require.NoError(t, err)
+
+	// Clear the database
+	store.Clear()
+
+	// Verify all data is cleared
+	allPlayers, err := store.GetAllPlayers()
+	require.NoError(t, err)
+	assert.Len(t, allPlayers, 0)
+
+	matches, err := store.GetMatchesForProcessing()
+	require.NoError(t, err)
+	assert.Len(t, matches, 0)
+}
+
+func TestClearMatch_SoftDeletes(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	store.AddPlayer("player1", "Player One", 1.0)
+	require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m1", OwnerID: "player1"}))
+	require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m2", OwnerID: "player1"}))
+
+	store.ClearMatch("m1")
+
+	matches, err := store.GetAllMatches(false)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "m2", matches[0].MatchID)
+	_, err = store.GetMatch("m1")
+	assert.Error(t, err, "a soft-deleted match is hidden")
+
+	matches, err = store.GetAllMatches(true)
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	for _, match := range matches {
+		if match.MatchID == "m1" {
+			assert.NotNil(t, match.DeletedAt)
+		} else {
+			assert.Nil(t, match.DeletedAt)
+		}
+	}
+
+	require.NoError(t, store.UndeleteMatch("m1"))
+	match, err := store.GetMatch("m1")
+	require.NoError(t, err)
+	assert.Nil(t, match.DeletedAt)
+	matches, err = store.GetAllMatches(false)
+	require.NoError(t, err)
+	assert.Len(t, matches, 2)
+
+	assert.ErrorIs(t, store.UndeleteMatch("m1"), club.ErrMatchNotDeleted)
+	assert.ErrorIs(t, store.UndeleteMatch("unknown"), club.ErrMatchNotDeleted)
+}
+
+func TestClearMatch_UpsertRestores(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	store.AddPlayer("player1", "Player One", 1.0)
+	require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m1", OwnerID: "player1"}))
+	require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m2", OwnerID: "player1"}))
+	store.ClearMatch("m1")
+	store.ClearMatch("m2")
+
+	// Fetching a cleared match again brings it back, as it did when clearing deleted the row.
+	require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m1", OwnerID: "player1", ResourceName: "Court 2"}))
+	require.NoError(t, store.UpsertMatches([]*playtomic.PadelMatch{{MatchID: "m2", OwnerID: "player1"}}))
+
+	match, err := store.GetMatch("m1")
+	require.NoError(t, err)
+	assert.Nil(t, match.DeletedAt)
+	assert.Equal(t, "Court 2", match.ResourceName)
+	matches, err := store.GetAllMatches(false)
+	require.NoError(t, err)
+	assert.Len(t, matches, 2)
+}
+
+func TestUpsertMatch_KeepsManualResult(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	store.AddPlayer("p1", "Player One", 1.0)
+	fetched := func() *playtomic.PadelMatch {
+		return &playtomic.PadelMatch{
+			MatchID:       "m1",
+			OwnerID:       "p1",
+			ResourceName:  "Court 1",
+			GameStatus:    playtomic.GameStatusPending,
+			ResultsStatus: playtomic.ResultsStatusWaitingFor,
+			Teams: []playtomic.Team{
+				{ID: "t1", Players: []playtomic.Player{{UserID: "p1", Name: "Player One"}}},
+				{ID: "t2", Players: []playtomic.Player{{UserID: "p2", Name: "Player Two"}}},
+			},
+		}
+	}
+	require.NoError(t, store.UpsertMatch(fetched()))
+	reported := fetched()
+	require.NoError(t, reported.ApplyManualResult("t1", []playtomic.SetScore{{Own: 6, Opponent: 4}, {Own: 6, Opponent: 3}}))
+	require.NoError(t, store.UpsertMatch(reported))
+
+	// Playtomic doesn't have the result yet when the match is fetched again.
+	require.NoError(t, store.UpsertMatch(fetched()))
+	refetched := fetched()
+	refetched.ResourceName = "Court 2"
+	require.NoError(t, store.UpsertMatches([]*playtomic.PadelMatch{refetched}))
+
+	match, err := store.GetMatch("m1")
+	require.NoError(t, err)
+	assert.Equal(t, playtomic.ResultsStatusManuallyConfirmed, match.ResultsStatus)
+	assert.Equal(t, playtomic.GameStatusPlayed, match.GameStatus)
+	assert.Equal(t, "WON", match.Teams[0].TeamResult)
+	assert.Len(t, match.Results, 2)
+	assert.Equal(t, "Court 2", match.ResourceName, "the other fields are still updated")
+}
+
+func TestUpsertMatch_CancellationReplacesManualResult(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	store.AddPlayer("p1", "Player One", 1.0)
+	match := &playtomic.PadelMatch{
+		MatchID:       "m1",
+		OwnerID:       "p1",
+		GameStatus:    playtomic.GameStatusPending,
+		ResultsStatus: playtomic.ResultsStatusWaitingFor,
+		Teams: []playtomic.Team{
+			{ID: "t1", Players: []playtomic.Player{{UserID: "p1", Name: "Player One"}}},
+			{ID: "t2", Players: []playtomic.Player{{UserID: "p2", Name: "Player Two"}}},
+		},
+	}
+	reported := *match
+	require.NoError(t, reported.ApplyManualResult("t1", []playtomic.SetScore{{Own: 6, Opponent: 4}}))
+	require.NoError(t, store.UpsertMatch(&reported))
+
+	canceled := *match
+	canceled.GameStatus = playtomic.GameStatusCanceled
+	require.NoError(t, store.UpsertMatch(&canceled))
+
+	stored, err := store.GetMatch("m1")
+	require.NoError(t, err)
+	assert.Equal(t, playtomic.GameStatusCanceled, stored.GameStatus)
+	assert.Equal(t, playtomic.ResultsStatusWaitingFor, stored.ResultsStatus)
+	assert.Empty(t, stored.Results)
+}
+
+func TestUpsertMatch_ManualResultIsNotCorrected(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	store.AddPlayer("p1", "Player One", 1.0)
+	store.AddPlayer("p2", "Player Two", 1.0)
+	match := &playtomic.PadelMatch{
+		MatchID:       "m1",
+		OwnerID:       "p1",
+		GameStatus:    playtomic.GameStatusPending,
+		ResultsStatus: playtomic.ResultsStatusWaitingFor,
+		Teams: []playtomic.Team{
+			{ID: "t1", Players: []playtomic.Player{{UserID: "p1", Name: "Player One"}}},
+			{ID: "t2", Players: []playtomic.Player{{UserID: "p2", Name: "Player Two"}}},
+		},
+	}
+	reported := *match
+	require.NoError(t, reported.ApplyManualResult("t1", []playtomic.SetScore{{Own: 6, Opponent: 4}}))
+	require.NoError(t, store.UpsertMatch(&reported))
+	store.UpdatePlayerStats(&reported)
+
+	// Playtomic later confirms a different score, but the reported result stands.
+	confirmed := *match
+	confirmed.GameStatus = playtomic.GameStatusPlayed
+	confirmed.ResultsStatus = playtomic.ResultsStatusConfirmed
+	confirmed.Results = []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 3, "t2": 6}}}
+	require.NoError(t, store.UpsertMatch(&confirmed))
+
+	corrections, err := store.GetResultCorrections()
+	require.NoError(t, err)
+	assert.Empty(t, corrections)
+}
+
+func TestUpsertPlayers_ReturnsNewPlayers(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	inserted, err := store.UpsertPlayers([]club.PlayerInfo{{ID: "p1", Name: "Player One"}, {ID: "p2", Name: "Player Two"}})
+	require.NoError(t, err)
+	assert.Len(t, inserted, 2)
+
+	inserted, err = store.UpsertPlayers([]club.PlayerInfo{{ID: "p1", Name: "Player One Renamed", Level: 2.5}, {ID: "p3", Name: "Player Three"}})
+	require.NoError(t, err)
+	require.Len(t, inserted, 1, "Updates to known players should not be reported as new")
+	assert.Equal(t, "p3", inserted[0].ID)
+
+	inserted, err = store.UpsertPlayers([]club.PlayerInfo{{ID: "p2", Name: "Player Two"}})
+	require.NoError(t, err)
+	assert.Empty(t, inserted)
+}
+
+func TestUpdatePlayerStats(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	t.Run("correctly updates stats for a single match", func(t *testing.T) {
+		store.AddPlayer("p1", "Morten Voss", 1.0)
+		store.AddPlayer("p2", "Player Two", 1.0)
+		store.AddPlayer("p3", "Player Three", 1.0)
+		store.AddPlayer("p4", "Player Four", 1.0)
+
+		match := &playtomic.PadelMatch{
+			MatchID: "match1",
+			OwnerID: "p1",
+			Teams: []playtomic.Team{
+				{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p1", Name: "Morten Voss"}, {UserID: "p2", Name: "Player Two"}}},
+				{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p3", Name: "Player Three"}, {UserID: "p4", Name: "Player Four"}}},
+			},
+			Results: []playtomic.SetResult{
+				{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 4}},
+				{Name: "Set-2", Scores: map[string]int{"t1": 7, "t2": 5}},
+			},
+		}
+
+		store.UpdatePlayerStats(match)
+
+		stats, err := store.GetPlayerStatsByName("Morten Voss", false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats.MatchesPlayed)
+		assert.Equal(t, 1, stats.MatchesWon)
+		assert.Equal(t, 0, stats.MatchesLost)
+		assert.Equal(t, 2, stats.SetsWon)
+		assert.Equal(t, 0, stats.SetsLost)
+		assert.Equal(t, 13, stats.GamesWon)
+		assert.Equal(t, 9, stats.GamesLost)
+		assert.InDelta(t, 100.0, stats.WinPercentage, 0.01)
+
+		stats, err = store.GetPlayerStatsByName("Player Three", false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats.MatchesPlayed)
+		assert.Equal(t, 0, stats.MatchesWon)
+		assert.Equal(t, 1, stats.MatchesLost)
+		assert.Equal(t, 0, stats.SetsWon)
+		assert.Equal(t, 2, stats.SetsLost)
+		assert.Equal(t, 9, stats.GamesWon)
+		assert.Equal(t, 13, stats.GamesLost)
+		assert.InDelta(t, 0.0, stats.WinPercentage, 0.01)
+	})
+
+	t.Run("counts a match tiebreak as a single game", func(t *testing.T) {
+		store.AddPlayer("p5", "Player Five", 1.0)
+		store.AddPlayer("p6", "Player Six", 1.0)
+		store.AddPlayer("p7", "Player Seven", 1.0)
+		store.AddPlayer("p8", "Player Eight", 1.0)
+
+		match := &playtomic.PadelMatch{
+			MatchID: "match2",
+			OwnerID: "p5",
+			Teams: []playtomic.Team{
+				{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p5", Name: "Player Five"}, {UserID: "p6", Name: "Player Six"}}},
+				{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p7", Name: "Player Seven"}, {UserID: "p8", Name: "Player Eight"}}},
+			},
+			Results: []playtomic.SetResult{
+				{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 4}},
+				{Name: "Set-2", Scores: map[string]int{"t1": 3, "t2": 6}},
+				{Name: "Set-3", Scores: map[string]int{"t1": 10, "t2": 8}, Tiebreak: true},
+			},
+		}
+
+		store.UpdatePlayerStats(match)
+
+		stats, err := store.GetPlayerStatsByName("Player Five", false)
+		require.NoError(t, err)
+		assert.Equal(t, 2, stats.SetsWon)
+		assert.Equal(t, 1, stats.SetsLost)
+		assert.Equal(t, 10, stats.GamesWon)
+		assert.Equal(t, 10, stats.GamesLost)
+	})
+}
+
+func TestUpdatePlayerStats_DerivesMissingTeamResult(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	store.AddPlayer("p1", "Player One", 1.0)
+	store.AddPlayer("p2", "Player Two", 1.0)
+	// Playtomic left out TeamResult, but the sets clearly go to t2.
+	store.UpdatePlayerStats(&playtomic.PadelMatch{
+		MatchID: "m1",
+		OwnerID: "p1",
+		Teams: []playtomic.Team{
+			{ID: "t1", Players: []playtomic.Player{{UserID: "p1", Name: "Player One"}}},
+			{ID: "t2", Players: []playtomic.Player{{UserID: "p2", Name: "Player Two"}}},
+		},
+		Results: []playtomic.SetResult{
+			{Name: "Set-1", Scores: map[string]int{"t1": 3, "t2": 6}},
+			{Name: "Set-2", Scores: map[string]int{"t1": 4, "t2": 6}},
+		},
+	})
+
+	stats, err := store.GetPlayerStatsByName("Player Two", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.MatchesWon)
+	assert.Equal(t, 0, stats.MatchesLost)
+	stats, err = store.GetPlayerStatsByName("Player One", false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.MatchesWon)
+	assert.Equal(t, 1, stats.MatchesLost)
+}
+
+func TestMatchResults_TiebreakBlobCompatibility(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	store.AddPlayer("p1", "Player One", 1.0)
+	require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{
+		MatchID: "m1",
+		OwnerID: "p1",
+		Results: []playtomic.SetResult{{Name: "Set-3", Scores: map[string]int{"t1": 10, "t2": 7}, Tiebreak: true}},
+	}))
+	match, err := store.GetMatch("m1")
+	require.NoError(t, err)
+	require.Len(t, match.Results, 1)
+	assert.True(t, match.Results[0].Tiebreak)
+
+	// Rows written before the flag existed hold results without it and must decode as regular sets.
+	legacyBlob, err := msgpack.Marshal([]struct {
+		Name   string
+		Scores map[string]int
+	}{{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 2}}})
+	require.NoError(t, err)
+	_, err = db.Exec("UPDATE matches SET results_blob = ? WHERE id = ?", legacyBlob, "m1")
+	require.NoError(t, err)
+
+	match, err = store.GetMatch("m1")
+	require.NoError(t, err)
+	require.Len(t, match.Results, 1)
+	assert.Equal(t, "Set-1", match.Results[0].Name)
+	assert.Equal(t, 6, match.Results[0].Scores["t1"])
+	assert.False(t, match.Results[0].Tiebreak)
+}
+
+func TestReencodeMatchBlobs(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	store.AddPlayer("p1", "Player One", 1.0)
+	teams := []playtomic.Team{{ID: "t1", Players: []playtomic.Player{{UserID: "p1", Name: "Player One"}}}}
+	results := []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 6}}}
+	require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: "current", OwnerID: "p1", Teams: teams, Results: results}))
+	require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: "legacy", OwnerID: "p1"}))
+
+	// Rows written before blobs were versioned hold plain msgpack.
+	legacyTeams, err := msgpack.Marshal(teams)
+	require.NoError(t, err)
+	legacyResults, err := msgpack.Marshal(results)
+	require.NoError(t, err)
+	_, err = db.Exec("UPDATE matches SET teams_blob = ?, results_blob = ? WHERE id = ?", legacyTeams, legacyResults, "legacy")
+	require.NoError(t, err)
+
+	count, err := store.ReencodeMatchBlobs()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	var teamsBlob, resultsBlob []byte
+	require.NoError(t, db.QueryRow("SELECT teams_blob, results_blob FROM matches WHERE id = ?", "legacy").Scan(&teamsBlob, &resultsBlob))
+	assert.Equal(t, byte(1), teamsBlob[0])
+	assert.Equal(t, byte(1), resultsBlob[0])
+
+	match, err := store.GetMatch("legacy")
+	require.NoError(t, err)
+	assert.Equal(t, teams, match.Teams)
+	assert.Equal(t, results, match.Results)
+
+	count, err = store.ReencodeMatchBlobs()
+	require.NoError(t, err)
+	assert.Equal(t, 0, count, "already migrated rows are left alone")
+}
+
+func TestUndoMatchStats(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	store.AddPlayer("p1", "Morten Voss", 1.0)
+	store.AddPlayer("p2", "Player Two", 1.0)
+	match := &playtomic.PadelMatch{
+		MatchID:       "match1",
+		OwnerID:       "p1",
+		End:           1678890000,
+		GameStatus:    playtomic.GameStatusPlayed,
+		ResultsStatus: playtomic.ResultsStatusConfirmed,
+		Teams: []playtomic.Team{
+			{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p1", Name: "Morten Voss"}}},
+			{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p2", Name: "Player Two"}}},
+		},
+		Results: []playtomic.SetResult{
+			{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 4}},
+			{Name: "Set-2", Scores: map[string]int{"t1": 7, "t2": 5}},
+		},
+	}
+	require.NoError(t, store.UpsertMatch(match))
+
+	err := store.UndoMatchStats("match1")
+	assert.ErrorIs(t, err, club.ErrStatsNotApplied, "Stats cannot be undone before they are applied")
+
+	store.UpdatePlayerStats(match)
+	require.NoError(t, store.UpdateProcessingStatus("match1", playtomic.StatusCompleted))
+
+	lastID, found, err := store.GetLastStatsAppliedMatchID()
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "match1", lastID)
+
+	require.NoError(t, store.UndoMatchStats("match1"))
+
+	for _, name := range []string{"Morten Voss", "Player Two"} {
+		stats, err := store.GetPlayerStatsByName(name, false)
+		require.NoError(t, err)
+		assert.Equal(t, 0, stats.MatchesPlayed, name)
+		assert.Equal(t, 0, stats.MatchesWon, name)
+		assert.Equal(t, 0, stats.MatchesLost, name)
+		assert.Equal(t, 0, stats.SetsWon, name)
+		assert.Equal(t, 0, stats.SetsLost, name)
+		assert.Equal(t, 0, stats.GamesWon, name)
+		assert.Equal(t, 0, stats.GamesLost, name)
+	}
+	weekly, err := store.GetStatsForRange(0, match.End)
+	require.NoError(t, err)
+	for _, stats := range weekly {
+		assert.Equal(t, 0, stats.MatchesPlayed, "the weekly stats are undone too")
+	}
+
+	undone, err := store.GetMatch("match1")
+	require.NoError(t, err)
+	assert.Equal(t, playtomic.StatusNeedsReview, undone.ProcessingStatus)
+
+	err = store.UndoMatchStats("match1")
+	assert.ErrorIs(t, err, club.ErrStatsNotApplied, "A match should not be undone twice")
+	_, found, err = store.GetLastStatsAppliedMatchID()
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestRequeueMatch(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	store.AddPlayer("p1", "Player One", 1.0)
+	require.NoError(t, store.UpsertMatches([]*playtomic.PadelMatch{
+		{MatchID: "played", OwnerID: "p1", GameStatus: playtomic.GameStatusPlayed, ResultsStatus: playtomic.ResultsStatusConfirmed},
+		{MatchID: "pending", OwnerID: "p1", GameStatus: playtomic.GameStatusPending, ResultsStatus: playtomic.ResultsStatusWaitingFor},
+	}))
+
+	_, err := store.RequeueMatch("played")
+	assert.ErrorIs(t, err, club.ErrMatchNotInReview, "Only matches marked for review can be requeued")
+	_, err = store.RequeueMatch("unknown")
+	assert.ErrorIs(t, err, club.ErrMatchNotInReview)
+
+	require.NoError(t, store.UpdateProcessingStatus("played", playtomic.StatusNeedsReview))
+	require.NoError(t, store.UpdateProcessingStatus("pending", playtomic.StatusNeedsReview))
+
+	status, err := store.RequeueMatch("played")
+	require.NoError(t, err)
+	assert.Equal(t, playtomic.StatusResultAvailable, status)
+	status, err = store.RequeueMatch("pending")
+	require.NoError(t, err)
+	assert.Equal(t, playtomic.StatusNew, status)
+
+	for id, want := range map[string]playtomic.ProcessingStatus{"played": playtomic.StatusResultAvailable, "pending": playtomic.StatusNew} {
+		match, err := store.GetMatch(id)
+		require.NoError(t, err)
+		assert.Equal(t, want, match.ProcessingStatus, id)
+	}
+}
+
+func TestCorrectMatchStats(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	store.AddPlayer("p1", "Morten Voss", 1.0)
+	store.AddPlayer("p2", "Player Two", 1.0)
+	match := &playtomic.PadelMatch{
+		MatchID:       "match1",
+		OwnerID:       "p1",
+		End:           1678890000,
+		GameStatus:    playtomic.GameStatusPlayed,
+		ResultsStatus: playtomic.ResultsStatusConfirmed,
+		Teams: []playtomic.Team{
+			{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p1", Name: "Morten Voss"}}},
+			{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p2", Name: "Player Two"}}},
+		},
+		Results: []playtomic.SetResult{
+			{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 4}},
+			{Name: "Set-2", Scores: map[string]int{"t1": 7, "t2": 5}},
+		},
+	}
+	require.NoError(t, store.UpsertMatch(match))
+	store.UpdatePlayerStats(match)
+	require.NoError(t, store.UpdateProcessingStatus("match1", playtomic.StatusCompleted))
+
+	// Fetching the same results again is not a correction.
+	require.NoError(t, store.UpsertMatch(match))
+	corrections, err := store.GetResultCorrections()
+	require.NoError(t, err)
+	assert.Empty(t, corrections)
+
+	// Neither are results Playtomic has not confirmed.
+	unconfirmed := *match
+	unconfirmed.ResultsStatus = playtomic.ResultsStatusValidating
+	unconfirmed.Results = nil
+	require.NoError(t, store.UpsertMatch(&unconfirmed))
+	corrections, err = store.GetResultCorrections()
+	require.NoError(t, err)
+	assert.Empty(t, corrections)
+
+	// Playtomic swaps the scores after the stats were counted.
+	corrected := *match
+	corrected.Teams = []playtomic.Team{
+		{ID: "t1", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p1", Name: "Morten Voss"}}},
+		{ID: "t2", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p2", Name: "Player Two"}}},
+	}
+	corrected.Results = []playtomic.SetResult{
+		{Name: "Set-1", Scores: map[string]int{"t1": 4, "t2": 6}},
+		{Name: "Set-2", Scores: map[string]int{"t1": 5, "t2": 7}},
+	}
+	require.NoError(t, store.UpsertMatch(&corrected))
+
+	corrections, err = store.GetResultCorrections()
+	require.NoError(t, err)
+	require.Len(t, corrections, 1)
+	assert.Equal(t, "match1", corrections[0].Match.MatchID)
+	assert.Equal(t, corrected.Results, corrections[0].Match.Results)
+	assert.Equal(t, match.Results, corrections[0].CountedResults)
+
+	require.NoError(t, store.CorrectMatchStats("match1"))
+
+	morten, err := store.GetPlayerStatsByName("Morten Voss", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, morten.MatchesPlayed)
+	assert.Equal(t, 0, morten.MatchesWon)
+	assert.Equal(t, 1, morten.MatchesLost)
+	assert.Equal(t, 0, morten.SetsWon)
+	assert.Equal(t, 2, morten.SetsLost)
+	assert.Equal(t, 9, morten.GamesWon)
+	assert.Equal(t, 13, morten.GamesLost)
+	other, err := store.GetPlayerStatsByName("Player Two", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, other.MatchesPlayed)
+	assert.Equal(t, 1, other.MatchesWon)
+	assert.Equal(t, 2, other.SetsWon)
+	assert.Equal(t, 13, other.GamesWon)
+
+	weekly, err := store.GetStatsForRange(0, match.End)
+	require.NoError(t, err)
+	for _, stats := range weekly {
+		assert.Equal(t, 1, stats.MatchesPlayed, "the weekly stats are corrected too")
+		assert.Equal(t, stats.PlayerID == "p2", stats.MatchesWon == 1, stats.PlayerID)
+	}
+
+	corrections, err = store.GetResultCorrections()
+	require.NoError(t, err)
+	assert.Empty(t, corrections, "A corrected match should not be corrected again")
+
+	// Undoing the match subtracts the corrected stats.
+	// Results changing back before they are corrected are not a correction either.
+	require.NoError(t, store.UpsertMatch(match))
+	corrections, err = store.GetResultCorrections()
+	require.NoError(t, err)
+	require.Len(t, corrections, 1)
+	require.NoError(t, store.UpsertMatch(&corrected))
+	corrections, err = store.GetResultCorrections()
+	require.NoError(t, err)
+	assert.Empty(t, corrections)
+
+	require.NoError(t, store.UndoMatchStats("match1"))
+	morten, err = store.GetPlayerStatsByName("Morten Voss", false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, morten.MatchesPlayed)
+	assert.Equal(t, 0, morten.GamesWon)
+	assert.ErrorIs(t, store.CorrectMatchStats("match1"), club.ErrStatsNotApplied)
+}
+
+func TestUpdateNotificationTimestamp(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	// Insert a player to satisfy foreign key constraint
+	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('p1', 'Player One')`)
+	require.NoError(t, err)
+
+	// Insert a match with initial null timestamps
+	match := &playtomic.PadelMatch{
+		MatchID:          "test_match_id",
+		OwnerID:          "p1",
+		OwnerName:        "Player One",
+		Start:            1678886400, // Example Unix timestamp
+		End:              1678890000,
+		CreatedAt:        1678880000,
+		Status:           "STATUS_OPEN",
+		GameStatus:       "GAME_STATUS_UNKNOWN",
+		ResultsStatus:    "RESULTS_STATUS_WAITING",
+		ResourceName:     "Court 1",
+		Tenant:           playtomic.Tenant{ID: "tenant1", Name: "Tenant Name"},
+		ProcessingStatus: "NEW",
+		MatchType:        "PADEL_MATCH",
+	}
+	require.NoError(t, store.UpsertMatch(match))
+
+	// Test updating booking_notified_ts
+	err = store.UpdateNotificationTimestamp("test_match_id", "booking")
+	require.NoError(t, err)
+
+	var bookingTS sql.NullInt64
+	err = db.QueryRow("SELECT booking_notified_ts FROM matches WHERE id = ?", "test_match_id").Scan(&bookingTS)
 	require.NoError(t, err)
 	assert.True(t, bookingTS.Valid)
 	assert.NotZero(t, bookingTS.Int64) // Check that a timestamp was set
@@ -345,3 +1791,317 @@ func TestUpdateNotificationTimestamp(t *testing.T) {
 	err = store.UpdateNotificationTimestamp("non_existent_match", "booking")
 	require.NoError(t, err)
 }
+
+func TestSetBookingMessageTS(t *testing.T) {
+	store, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('p1', 'Player One')`)
+	require.NoError(t, err)
+	require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m1", OwnerID: "p1", ProcessingStatus: playtomic.StatusNew}))
+
+	match, err := store.GetMatch("m1")
+	require.NoError(t, err)
+	assert.Empty(t, match.BookingMessageTs)
+
+	require.NoError(t, store.SetBookingMessageTS("m1", "1700000000.000100"))
+
+	match, err = store.GetMatch("m1")
+	require.NoError(t, err)
+	assert.Equal(t, "1700000000.000100", match.BookingMessageTs)
+}
+
+func TestUpdateNotificationTimestamp_UsesClock(t *testing.T) {
+	_, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	now := time.Date(2024, 3, 15, 18, 30, 0, 0, time.UTC)
+	store := club.NewWithClock(db, clock.NewMock(now))
+
+	_, err := db.Exec(`INSERT INTO players (id, name) VALUES ('p1', 'Player One')`)
+	require.NoError(t, err)
+	require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{
+		MatchID:          "m1",
+		OwnerID:          "p1",
+		OwnerName:        "Player One",
+		ProcessingStatus: playtomic.StatusNew,
+	}))
+
+	require.NoError(t, store.UpdateNotificationTimestamp("m1", "booking"))
+
+	var bookingTS int64
+	err = db.QueryRow("SELECT booking_notified_ts FROM matches WHERE id = ?", "m1").Scan(&bookingTS)
+	require.NoError(t, err)
+	assert.Equal(t, now.Unix(), bookingTS)
+}
+
+func TestGetNextMatchForPlayer(t *testing.T) {
+	_, db, teardown := setupTestDB(t)
+	defer teardown()
+
+	now := time.Date(2024, 3, 15, 18, 30, 0, 0, time.UTC)
+	store := club.NewWithClock(db, clock.NewMock(now))
+
+	store.AddPlayer("p1", "Player One", 1.0)
+	store.AddPlayer("p2", "Player Two", 1.0)
+	teams := func(playerIDs ...string) []playtomic.Team {
+		team := playtomic.Team{ID: "t1"}
+		for _, id := range playerIDs {
+			team.Players = append(team.Players, playtomic.Player{UserID: id})
+		}
+		return []playtomic.Team{team}
+	}
+	require.NoError(t, store.UpsertMatches([]*playtomic.PadelMatch{
+		{MatchID: "past", OwnerID: "p1", Start: now.Add(-time.Hour).Unix(), GameStatus: playtomic.GameStatusPlayed, Teams: teams("p1", "p2")},
+		{MatchID: "later", OwnerID: "p1", Start: now.Add(48 * time.Hour).Unix(), GameStatus: playtomic.GameStatusPending, Teams: teams("p1", "p2")},
+		{MatchID: "soon", OwnerID: "p1", Start: now.Add(24 * time.Hour).Unix(), GameStatus: playtomic.GameStatusPending, Teams: teams("p1")},
+		{MatchID: "canceled", OwnerID: "p1", Start: now.Add(time.Hour).Unix(), GameStatus: playtomic.GameStatusCanceled, Teams: teams("p1", "p2")},
+	}))
+
+	t.Run("returns the earliest upcoming match", func(t *testing.T) {
+		match, err := store.GetNextMatchForPlayer("p1")
+		require.NoError(t, err)
+		require.NotNil(t, match)
+		assert.Equal(t, "soon", match.MatchID)
+	})
+
+	t.Run("skips matches the player is not in", func(t *testing.T) {
+		match, err := store.GetNextMatchForPlayer("p2")
+		require.NoError(t, err)
+		require.NotNil(t, match)
+		assert.Equal(t, "later", match.MatchID)
+	})
+
+	t.Run("returns nil without an upcoming match", func(t *testing.T) {
+		match, err := store.GetNextMatchForPlayer("p3")
+		require.NoError(t, err)
+		assert.Nil(t, match)
+	})
+}
+
+func TestGetMatchesForWeek(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	store.AddPlayer("p1", "Player One", 1.0)
+	// The week of Monday 11 March 2024, UTC.
+	for id, start := range map[string]time.Time{
+		"sunday-before": time.Date(2024, 3, 10, 23, 59, 59, 0, time.UTC),
+		"monday":        time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+		"sunday":        time.Date(2024, 3, 17, 23, 59, 59, 0, time.UTC),
+		"monday-after":  time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC),
+	} {
+		require.NoError(t, store.UpsertMatch(&playtomic.PadelMatch{MatchID: id, OwnerID: "p1", Start: start.Unix()}))
+	}
+
+	ids := func(matches []*playtomic.PadelMatch) []string {
+		var result []string
+		for _, match := range matches {
+			result = append(result, match.MatchID)
+		}
+		return result
+	}
+
+	matches, err := store.GetMatchesForWeek(time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC).Unix())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"monday", "sunday"}, ids(matches))
+
+	matches, err = store.GetMatchesForWeek(time.Date(2024, 3, 13, 15, 0, 0, 0, time.UTC).Unix())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"monday", "sunday"}, ids(matches), "any time in the week selects the whole week")
+
+	matches, err = store.GetMatchesForWeek(time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC).Unix())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sunday-before"}, ids(matches))
+}
+
+func TestGetRecentForm(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	for _, id := range []string{"p1", "p2", "p3", "p4", "p5"} {
+		store.AddPlayer(id, "Player "+id, 1.0)
+	}
+	newMatch := func(id string, start int64, winner, loser string) *playtomic.PadelMatch {
+		return &playtomic.PadelMatch{
+			MatchID:       id,
+			OwnerID:       "p1",
+			Start:         start,
+			GameStatus:    playtomic.GameStatusPlayed,
+			ResultsStatus: playtomic.ResultsStatusConfirmed,
+			Teams: []playtomic.Team{
+				{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: winner}}},
+				{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: loser}}},
+			},
+		}
+	}
+	// p1 goes W L W W L W against p2, and plays p3 twice along the way.
+	for i, match := range []*playtomic.PadelMatch{
+		newMatch("m1", 100, "p1", "p2"),
+		newMatch("m2", 200, "p2", "p1"),
+		newMatch("m3", 300, "p1", "p3"),
+		newMatch("m4", 400, "p1", "p2"),
+		newMatch("m5", 500, "p3", "p1"),
+		newMatch("m6", 600, "p1", "p2"),
+	} {
+		require.NoError(t, store.UpsertMatch(match), "match %d", i)
+		require.NoError(t, store.UpdateProcessingStatus(match.MatchID, playtomic.StatusCompleted))
+	}
+	// A played match whose result has not been confirmed yet is not part of the form.
+	unconfirmed := newMatch("m7", 700, "p2", "p1")
+	unconfirmed.ResultsStatus = playtomic.ResultsStatusWaitingFor
+	require.NoError(t, store.UpsertMatch(unconfirmed))
+	// Playtomic sometimes omits the team results, leaving only the set scores to tell the winner.
+	scoresOnly := newMatch("m8", 800, "p4", "p5")
+	scoresOnly.Teams[0].TeamResult, scoresOnly.Teams[1].TeamResult = "", ""
+	scoresOnly.Results = []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 2}}}
+	require.NoError(t, store.UpsertMatch(scoresOnly))
+	require.NoError(t, store.UpdateProcessingStatus(scoresOnly.MatchID, playtomic.StatusCompleted))
+
+	t.Run("returns the last n results oldest first", func(t *testing.T) {
+		form, err := store.GetRecentForm("p1", 5)
+		require.NoError(t, err)
+		assert.Equal(t, []bool{false, true, true, false, true}, form)
+	})
+
+	t.Run("returns fewer results for players with fewer matches", func(t *testing.T) {
+		form, err := store.GetRecentForm("p3", 5)
+		require.NoError(t, err)
+		assert.Equal(t, []bool{false, true}, form)
+	})
+
+	t.Run("derives results from the set scores", func(t *testing.T) {
+		form, err := store.GetRecentForm("p4", 5)
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true}, form)
+
+		form, err = store.GetRecentForm("p5", 5)
+		require.NoError(t, err)
+		assert.Equal(t, []bool{false}, form)
+	})
+
+	t.Run("returns nothing for players without matches", func(t *testing.T) {
+		form, err := store.GetRecentForm("unknown", 5)
+		require.NoError(t, err)
+		assert.Empty(t, form)
+	})
+}
+
+func TestGetStatsForRange(t *testing.T) {
+	store, _, teardown := setupTestDB(t)
+	defer teardown()
+
+	for _, id := range []string{"p1", "p2", "p3", "p4"} {
+		store.AddPlayer(id, "Player "+id, 1.0)
+	}
+	week1 := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	week2 := week1.AddDate(0, 0, 7)
+	played := func(id string, start time.Time, winners, losers [2]string) *playtomic.PadelMatch {
+		return &playtomic.PadelMatch{
+			MatchID: id,
+			OwnerID: winners[0],
+			Start:   start.Unix(),
+			Teams: []playtomic.Team{
+				{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: winners[0]}, {UserID: winners[1]}}},
+				{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: losers[0]}, {UserID: losers[1]}}},
+			},
+			Results: []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 3}}},
+		}
+	}
+	// Week 1: p1 and p2 win twice. Week 2: p3 and p4 win twice, p1 and p3 once more together.
+	store.UpdatePlayerStats(played("w1-a", week1.Add(18*time.Hour), [2]string{"p1", "p2"}, [2]string{"p3", "p4"}))
+	store.UpdatePlayerStats(played("w1-b", week1.AddDate(0, 0, 3), [2]string{"p1", "p2"}, [2]string{"p3", "p4"}))
+	store.UpdatePlayerStats(played("w2-a", week2.Add(18*time.Hour), [2]string{"p3", "p4"}, [2]string{"p1", "p2"}))
+	store.UpdatePlayerStats(played("w2-b", week2.AddDate(0, 0, 2), [2]string{"p3", "p4"}, [2]string{"p1", "p2"}))
+	store.UpdatePlayerStats(played("w2-c", week2.AddDate(0, 0, 6), [2]string{"p1", "p3"}, [2]string{"p2", "p4"}))
+
+	before, err := store.GetStatsForRange(week1.AddDate(0, 0, 2).Unix(), week2.Unix()-1)
+	require.NoError(t, err)
+	require.Len(t, before, 4)
+	assert.Equal(t, "Player p1", before[0].PlayerName)
+	assert.Equal(t, 2, before[0].MatchesWon)
+	assert.Equal(t, 12, before[0].GamesWon)
+
+	after, err := store.GetStatsForRange(week2.Unix(), week2.AddDate(0, 0, 7).Unix()-1)
+	require.NoError(t, err)
+	require.Len(t, after, 4)
+	assert.Equal(t, "Player p3", after[0].PlayerName)
+	assert.Equal(t, 3, after[0].MatchesWon)
+	assert.Equal(t, 3, after[0].MatchesPlayed)
+
+	both, err := store.GetStatsForRange(week1.Unix(), week2.AddDate(0, 0, 7).Unix()-1)
+	require.NoError(t, err)
+	require.Len(t, both, 4)
+	assert.Equal(t, 5, both[0].MatchesPlayed)
+
+	deltas := club.DiffPlayerStats(before, after)
+	require.Len(t, deltas, 4)
+	assert.Equal(t, club.PlayerStatsDelta{PlayerID: "p3", PlayerName: "Player p3", MatchesPlayed: 1, MatchesWon: 3, SetsWon: 3, GamesWon: 12}, deltas[0])
+	assert.Equal(t, "p4", deltas[1].PlayerID)
+	assert.Equal(t, 2, deltas[1].MatchesWon)
+	assert.Equal(t, "p1", deltas[2].PlayerID)
+	assert.Equal(t, -1, deltas[2].MatchesWon)
+	assert.Equal(t, "p2", deltas[3].PlayerID)
+	assert.Equal(t, -2, deltas[3].MatchesWon)
+}
+
+func TestBuildWeeklyRecap(t *testing.T) {
+	monday := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+	played := func(id string, start time.Time, won, lost []playtomic.Player, ballBringerID, ballBringerName string) *playtomic.PadelMatch {
+		return &playtomic.PadelMatch{
+			MatchID:         id,
+			Start:           start.Unix(),
+			GameStatus:      playtomic.GameStatusPlayed,
+			BallBringerID:   ballBringerID,
+			BallBringerName: ballBringerName,
+			Teams: []playtomic.Team{
+				{ID: "t1", TeamResult: "WON", Players: won},
+				{ID: "t2", TeamResult: "LOST", Players: lost},
+			},
+		}
+	}
+	matches := []*playtomic.PadelMatch{
+		played("m2", monday.AddDate(0, 0, 3),
+			[]playtomic.Player{{UserID: "p1", Name: "Anna", Level: 2.0}, {UserID: "p3", Name: "Carl", Level: 2.6}},
+			[]playtomic.Player{{UserID: "p2", Name: "Bo", Level: 2.0}, {UserID: "p4", Name: "Dan", Level: 1.0}},
+			"p2", "Bo"),
+		played("m1", monday.AddDate(0, 0, 1),
+			[]playtomic.Player{{UserID: "p1", Name: "Anna", Level: 1.9}, {UserID: "p2", Name: "Bo", Level: 2.1}},
+			[]playtomic.Player{{UserID: "p3", Name: "Carl", Level: 2.2}, {UserID: "p4", Name: "Dan", Level: 1.1}},
+			"p2", "Bo"),
+		{MatchID: "upcoming", Start: monday.AddDate(0, 0, 5).Unix(), GameStatus: playtomic.GameStatusUnknown},
+	}
+
+	recap := club.BuildWeeklyRecap(monday.AddDate(0, 0, 2).Unix(), matches)
+
+	assert.Equal(t, monday.Unix(), recap.WeekStart)
+	require.Len(t, recap.Matches, 2)
+	assert.Equal(t, "m1", recap.Matches[0].MatchID)
+	assert.Equal(t, "m2", recap.Matches[1].MatchID)
+	require.NotNil(t, recap.TopPerformer)
+	assert.Equal(t, "Anna", recap.TopPerformer.PlayerName)
+	assert.Equal(t, 2.0, recap.TopPerformer.Value)
+	require.NotNil(t, recap.MostImproved)
+	assert.Equal(t, "Carl", recap.MostImproved.PlayerName)
+	assert.InDelta(t, 0.4, recap.MostImproved.Value, 0.001)
+	require.NotNil(t, recap.BallBringer)
+	assert.Equal(t, "p2", recap.BallBringer.PlayerID)
+	assert.Equal(t, 2.0, recap.BallBringer.Value)
+
+	// Without team results the winner is derived from the set scores.
+	scoresOnly := played("m3", monday.AddDate(0, 0, 2),
+		[]playtomic.Player{{UserID: "p5", Name: "Eva", Level: 1.0}},
+		[]playtomic.Player{{UserID: "p6", Name: "Finn", Level: 1.0}},
+		"", "")
+	scoresOnly.Teams[0].TeamResult, scoresOnly.Teams[1].TeamResult = "", ""
+	scoresOnly.Results = []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 3, "t2": 6}}, {Name: "Set-2", Scores: map[string]int{"t1": 4, "t2": 6}}}
+	derived := club.BuildWeeklyRecap(monday.Unix(), []*playtomic.PadelMatch{scoresOnly})
+	require.NotNil(t, derived.TopPerformer)
+	assert.Equal(t, "Finn", derived.TopPerformer.PlayerName)
+
+	empty := club.BuildWeeklyRecap(monday.Unix(), nil)
+	assert.Empty(t, empty.Matches)
+	assert.Nil(t, empty.TopPerformer)
+	assert.Nil(t, empty.MostImproved)
+	assert.Nil(t, empty.BallBringer)
+}
@@ -2,17 +2,29 @@ package club
 
 import (
 	"database/sql"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"sync"
+
+	"github.com/mauv0809/ideal-tribble/internal/clock"
+	"github.com/mauv0809/ideal-tribble/internal/playtomic"
 )
 
 // store handles all database operations for the club.
 type store struct {
-	db *sql.DB
-	mu sync.RWMutex
+	db    *sql.DB
+	mu    sync.RWMutex
+	clock clock.Clock
+	// matchLocks serializes ball bringer assignment per match.
+	matchLocks matchLocks
 }
 
 // PlayerStats represents a player's statistics for the leaderboard.
 type PlayerStats struct {
+	// Rank is the player's place on the leaderboard, shared by players with the same record (1, 2, 2, 4).
+	Rank          int     `json:"rank"`
 	PlayerID      string  `json:"player_id"`
 	PlayerName    string  `json:"player_name"`
 	MatchesPlayed int     `json:"matches_played"`
@@ -23,6 +35,161 @@ type PlayerStats struct {
 	GamesWon      int     `json:"games_won"`
 	GamesLost     int     `json:"games_lost"`
 	WinPercentage float64 `json:"win_percentage"`
+	// WinPctLowerBound is the lower bound of the 95% Wilson score interval for the win percentage.
+	// Unlike WinPercentage it rewards a long record over a handful of lucky results.
+	WinPctLowerBound float64 `json:"win_pct_lower_bound"`
+}
+
+// PlayerStatsDelta is the change in a player's stats from one period to another.
+type PlayerStatsDelta struct {
+	PlayerID      string `json:"player_id"`
+	PlayerName    string `json:"player_name"`
+	MatchesPlayed int    `json:"matches_played"`
+	MatchesWon    int    `json:"matches_won"`
+	SetsWon       int    `json:"sets_won"`
+	GamesWon      int    `json:"games_won"`
+}
+
+// wilsonZ is the standard normal quantile for a 95% confidence interval.
+const wilsonZ = 1.96
+
+// setWinRates derives the win percentage and its confidence lower bound from the match totals.
+func (s *PlayerStats) setWinRates() {
+	if s.MatchesPlayed == 0 {
+		return
+	}
+	n := float64(s.MatchesPlayed)
+	p := float64(s.MatchesWon) / n
+	s.WinPercentage = p * 100
+
+	z2 := wilsonZ * wilsonZ
+	centre := p + z2/(2*n)
+	margin := wilsonZ * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	s.WinPctLowerBound = (centre - margin) / (1 + z2/n) * 100
+}
+
+// SameRecord reports whether two players have identical records, so neither ranks above the other.
+func (s PlayerStats) SameRecord(other PlayerStats) bool {
+	return s.MatchesPlayed == other.MatchesPlayed && s.MatchesWon == other.MatchesWon &&
+		s.SetsWon == other.SetsWon && s.SetsLost == other.SetsLost &&
+		s.GamesWon == other.GamesWon && s.GamesLost == other.GamesLost
+}
+
+// rankPlayerStats sets the rank of each player on an ordered leaderboard. Players with the same record share a
+// rank, and the places they share are skipped.
+func rankPlayerStats(stats []PlayerStats) {
+	for i := range stats {
+		if i > 0 && stats[i].SameRecord(stats[i-1]) {
+			stats[i].Rank = stats[i-1].Rank
+		} else {
+			stats[i].Rank = i + 1
+		}
+	}
+}
+
+// StatsSortBy selects the ordering of the stats leaderboard.
+type StatsSortBy string
+
+const (
+	SortByWins          StatsSortBy = "wins"
+	SortByWinPercentage StatsSortBy = "winpct"
+	SortBySets          StatsSortBy = "sets"
+	SortByGames         StatsSortBy = "games"
+	SortByConfidence    StatsSortBy = "confident"
+)
+
+// statsOrderBy maps each sort option to its ORDER BY clause. Ties fall back to the default wins ordering.
+var statsOrderBy = map[StatsSortBy]string{
+	SortByWins:          "ps.matches_won DESC, ps.sets_won DESC, ps.games_won DESC",
+	SortByWinPercentage: "CAST(ps.matches_won AS REAL) / NULLIF(ps.matches_played, 0) DESC, ps.matches_won DESC, ps.sets_won DESC, ps.games_won DESC",
+	SortBySets:          "ps.sets_won DESC, ps.matches_won DESC, ps.games_won DESC",
+	SortByGames:         "ps.games_won DESC, ps.matches_won DESC, ps.sets_won DESC",
+	// The Wilson lower bound is not computed in SQL; these rows are re-sorted once it is known.
+	SortByConfidence: "ps.matches_won DESC, ps.sets_won DESC, ps.games_won DESC",
+}
+
+// statsSortKeys mirrors statsOrderBy for stats computed outside of SQL. Keys are compared in order, highest first.
+var statsSortKeys = map[StatsSortBy]func(PlayerStats) []float64{
+	SortByWins: func(s PlayerStats) []float64 {
+		return []float64{float64(s.MatchesWon), float64(s.SetsWon), float64(s.GamesWon)}
+	},
+	SortByWinPercentage: func(s PlayerStats) []float64 {
+		return []float64{s.WinPercentage, float64(s.MatchesWon), float64(s.SetsWon), float64(s.GamesWon)}
+	},
+	SortBySets: func(s PlayerStats) []float64 {
+		return []float64{float64(s.SetsWon), float64(s.MatchesWon), float64(s.GamesWon)}
+	},
+	SortByGames: func(s PlayerStats) []float64 {
+		return []float64{float64(s.GamesWon), float64(s.MatchesWon), float64(s.SetsWon)}
+	},
+	SortByConfidence: func(s PlayerStats) []float64 {
+		return []float64{s.WinPctLowerBound, float64(s.MatchesWon), float64(s.SetsWon), float64(s.GamesWon)}
+	},
+}
+
+// sortPlayerStats sorts stats in the same order GetPlayerStats uses for the given sort option.
+func sortPlayerStats(stats []PlayerStats, sortBy StatsSortBy) {
+	keys := statsSortKeys[sortBy]
+	slices.SortFunc(stats, func(a, b PlayerStats) int {
+		if c := slices.Compare(keys(b), keys(a)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.PlayerID, b.PlayerID)
+	})
+}
+
+// ParseStatsSortBy parses a leaderboard sort option. An empty value selects the default wins ordering.
+func ParseStatsSortBy(value string) (StatsSortBy, error) {
+	if value == "" {
+		return SortByWins, nil
+	}
+	sortBy := StatsSortBy(strings.ToLower(strings.TrimSpace(value)))
+	if _, ok := statsOrderBy[sortBy]; !ok {
+		return "", fmt.Errorf("unknown sort option %q, expected one of wins, winpct, confident, sets, games", value)
+	}
+	return sortBy, nil
+}
+
+// PlayerSpend is a player's share of match prices over a period, in cents of a single currency.
+type PlayerSpend struct {
+	PlayerID   string `json:"player_id"`
+	PlayerName string `json:"player_name"`
+	Currency   string `json:"currency"`
+	Matches    int    `json:"matches"`
+	Amount     int64  `json:"amount"`
+}
+
+// WeeklyRecap summarizes a week (Monday to Sunday, UTC) of played matches.
+type WeeklyRecap struct {
+	WeekStart int64
+	Matches   []*playtomic.PadelMatch
+	// TopPerformer won the most matches, MostImproved gained the most level and BallBringer brought balls most often.
+	// Each is nil when nobody qualifies.
+	TopPerformer *RecapHighlight
+	MostImproved *RecapHighlight
+	BallBringer  *RecapHighlight
+}
+
+// RecapHighlight is a player singled out in the weekly recap. Value is the number of wins, the level gained or the
+// number of times balls were brought, depending on the highlight.
+type RecapHighlight struct {
+	PlayerID   string
+	PlayerName string
+	Value      float64
+}
+
+// Season is a period the leaderboard is counted over. The current season has no EndedAt.
+type Season struct {
+	ID        int64
+	StartedAt int64
+	EndedAt   *int64
+}
+
+// ResultCorrection is a match whose results Playtomic changed after its stats were counted. CountedResults are the
+// results the stats were counted with.
+type ResultCorrection struct {
+	Match          *playtomic.PadelMatch
+	CountedResults []playtomic.SetResult
 }
 
 // PlayerInfo represents a player in the store.
@@ -2,6 +2,9 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/joho/godotenv"
@@ -23,6 +26,72 @@ func Load() Config {
 		return "" // This line is never reached
 	}
 
+	// A helper function to get an optional env var, falling back to a default when unset.
+	getEnvDefault := func(key, fallback string) string {
+		if value, ok := os.LookupEnv(key); ok && value != "" {
+			return value
+		}
+		return fallback
+	}
+
+	// A helper function to get an optional integer env var, falling back to a default when unset.
+	getEnvInt := func(key string, fallback int) int {
+		value, ok := os.LookupEnv(key)
+		if !ok || value == "" {
+			return fallback
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			log.Fatalf("Error: Environment variable %s must be an integer, got %q.", key, value)
+		}
+		return parsed
+	}
+
+	// A helper function to get an optional boolean env var, falling back to a default when unset.
+	getEnvBool := func(key string, fallback bool) bool {
+		value, ok := os.LookupEnv(key)
+		if !ok || value == "" {
+			return fallback
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Error: Environment variable %s must be a boolean, got %q.", key, value)
+		}
+		return parsed
+	}
+
+	// A helper function to get an optional comma separated list.
+	getEnvList := func(key string) []string {
+		value, ok := os.LookupEnv(key)
+		if !ok || value == "" {
+			return nil
+		}
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+
+	// A helper function to get an optional comma separated list of key=value pairs, e.g. "a=1,b=2".
+	getEnvMap := func(key string) map[string]string {
+		value, ok := os.LookupEnv(key)
+		if !ok || value == "" {
+			return nil
+		}
+		parsed := make(map[string]string)
+		for _, pair := range strings.Split(value, ",") {
+			k, v, found := strings.Cut(strings.TrimSpace(pair), "=")
+			if !found || k == "" || v == "" {
+				log.Fatalf("Error: Environment variable %s must be a comma separated list of key=value pairs, got %q.", key, value)
+			}
+			parsed[k] = v
+		}
+		return parsed
+	}
+
 	cfg := Config{
 		DBName:        getEnv("DB_NAME"),
 		MigrationsDir: "./migrations",
@@ -30,6 +99,28 @@ func Load() Config {
 			Token:         getEnv("SLACK_BOT_TOKEN"),
 			ChannelID:     getEnv("SLACK_CHANNEL_ID"),
 			SigningSecret: getEnv("SLACK_SIGNING_SECRET"),
+			Channels:      getEnvMap("SLACK_CHANNELS"),
+			// Beyond ~50 rows the table would exceed Slack's 3000 character section limit.
+			CompactLeaderboardMax: getEnvInt("LEADERBOARD_COMPACT_MAX_PLAYERS", 0),
+			Theme: MessageTheme{
+				BookingHeader:          getEnvDefault("THEME_BOOKING_HEADER", ""),
+				ResultHeader:           getEnvDefault("THEME_RESULT_HEADER", ""),
+				LeaderboardHeader:      getEnvDefault("THEME_LEADERBOARD_HEADER", ""),
+				LevelLeaderboardHeader: getEnvDefault("THEME_LEVEL_LEADERBOARD_HEADER", ""),
+				BallBringersHeader:     getEnvDefault("THEME_BALL_BRINGERS_HEADER", ""),
+				NextMatchHeader:        getEnvDefault("THEME_NEXT_MATCH_HEADER", ""),
+				PracticeResultHeader:   getEnvDefault("THEME_PRACTICE_RESULT_HEADER", ""),
+				PracticeResultMode:     getEnvDefault("THEME_PRACTICE_RESULT_MODE", ""),
+				FriendlyResultHeader:   getEnvDefault("THEME_FRIENDLY_RESULT_HEADER", ""),
+				FriendlyEmoji:          getEnvDefault("THEME_FRIENDLY_EMOJI", ""),
+				BallEmoji:              getEnvDefault("THEME_BALL_EMOJI", ""),
+				TrophyEmoji:            getEnvDefault("THEME_TROPHY_EMOJI", ""),
+				Medals:                 getEnvList("THEME_MEDALS"),
+				TopTenMarker:           getEnvDefault("THEME_TOP_TEN_MARKER", ""),
+			},
+			DelayedResponses: getEnvBool("SLACK_DELAYED_RESPONSES", false),
+			HideGuests:       getEnvBool("HIDE_GUEST_PLAYERS", false),
+			Locale:           getEnvDefault("LOCALE", "en"),
 		},
 		TenantID: getEnv("TENANT_ID"),
 		Port:     getEnv("PORT"),
@@ -43,6 +134,35 @@ func Load() Config {
 			EventKey:   getEnv("INNGEST_EVENT_KEY"),
 		},*/
 		ProjectID: getEnv("GCP_PROJECT"),
+		DBPool: DBPoolConfig{
+			MaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 10),
+			MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 5),
+			ConnMaxLifetime: time.Duration(getEnvInt("DB_CONN_MAX_LIFETIME_SECONDS", 300)) * time.Second,
+			BusyTimeout:     time.Duration(getEnvInt("DB_BUSY_TIMEOUT_MS", 5000)) * time.Millisecond,
+		},
+		Notifications: NotificationConfig{
+			QuietHoursStart:          getEnvInt("QUIET_HOURS_START", 0),
+			QuietHoursEnd:            getEnvInt("QUIET_HOURS_END", 0),
+			ThreadResults:            getEnvBool("THREAD_RESULT_NOTIFICATIONS", false),
+			BallBringerDM:            getEnvBool("BALL_BRINGER_DM", false),
+			NewPlayers:               getEnvBool("NEW_PLAYER_NOTIFICATIONS", false),
+			IncompleteMatchDM:        getEnvBool("INCOMPLETE_MATCH_DM", false),
+			BookingGrace:             time.Duration(getEnvInt("BOOKING_NOTIFICATION_GRACE_MINUTES", 15)) * time.Minute,
+			BookingNotifyMaxLeadTime: time.Duration(getEnvInt("BOOKING_NOTIFY_MAX_LEAD_DAYS", 0)) * 24 * time.Hour,
+			DailyDigest:              getEnvBool("DAILY_BOOKING_DIGEST", false),
+		},
+		AdminSlackUserIDs:          getEnvList("ADMIN_SLACK_USER_IDS"),
+		AdminAPIToken:              getEnvDefault("ADMIN_API_TOKEN", ""),
+		Notifier:                   getEnvDefault("NOTIFIER", "slack"),
+		WebhookURL:                 getEnvDefault("WEBHOOK_URL", ""),
+		MatchCacheTTL:              time.Duration(getEnvInt("MATCH_CACHE_TTL_SECONDS", 300)) * time.Second,
+		MinMatchesForLeaderboard:   getEnvInt("MIN_MATCHES_FOR_LEADERBOARD", 1),
+		ProcessConcurrency:         getEnvInt("PROCESS_CONCURRENCY", 4),
+		MaxFetchDays:               getEnvInt("MAX_FETCH_DAYS", 90),
+		BallBringerAssignWindow:    time.Duration(getEnvInt("BALL_BRINGER_ASSIGN_WINDOW_HOURS", 0)) * time.Hour,
+		StuckMatchAfter:            time.Duration(getEnvInt("STUCK_MATCH_AFTER_HOURS", 12)) * time.Hour,
+		EnableDestructiveEndpoints: getEnvBool("ENABLE_DESTRUCTIVE_ENDPOINTS", false),
+		EnableTestEndpoints:        getEnvBool("ENABLE_TEST_ENDPOINTS", false),
 	}
 	return cfg
 }
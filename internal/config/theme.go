@@ -0,0 +1,89 @@
+package config
+
+// Ways of showing the outcome of a finished match.
+const (
+	// ResultModeScored shows the winner and the set scores.
+	ResultModeScored = "scored"
+	// ResultModePlayers only lists who played.
+	ResultModePlayers = "players"
+	// ResultModeFriendly shows the set scores under the friendly header and labels, without crowning a winner.
+	ResultModeFriendly = "friendly"
+)
+
+// MessageTheme holds the emoji and copy used in notifications, so clubs can change the tone without code changes.
+type MessageTheme struct {
+	BookingHeader          string
+	ResultHeader           string
+	LeaderboardHeader      string
+	LevelLeaderboardHeader string
+	BallBringersHeader     string
+	NextMatchHeader        string
+	// PracticeResultHeader heads the results of practice matches. It follows a custom ResultHeader unless set.
+	PracticeResultHeader string
+	// PracticeResultMode is how practice results are shown: ResultModePlayers, ResultModeScored or ResultModeFriendly.
+	// Competition results are always scored.
+	PracticeResultMode string
+	// FriendlyResultHeader heads practice results shown in ResultModeFriendly.
+	FriendlyResultHeader string
+	// FriendlyEmoji takes the place of the trophy next to the winners of a friendly.
+	FriendlyEmoji string
+	// BallEmoji prefixes the ball bringer lines.
+	BallEmoji string
+	// TrophyEmoji decorates winners and player stats.
+	TrophyEmoji string
+	// Medals are shown next to the top ranks of the leaderboards, first place first.
+	Medals []string
+	// TopTenMarker is shown next to the ranks after the medals, up to tenth place. Empty shows nothing.
+	TopTenMarker string
+}
+
+// DefaultMessageTheme returns the built-in theme.
+func DefaultMessageTheme() MessageTheme {
+	return MessageTheme{
+		BookingHeader:          "🎾 New match booked! 🎾",
+		ResultHeader:           "🎾 Match finished! 🎾",
+		LeaderboardHeader:      "🏆 Player Leaderboard 🏆",
+		LevelLeaderboardHeader: "🏆 Player Leaderboard (by Level) 🏆",
+		BallBringersHeader:     "🎾 Ball Bringer Standings 🎾",
+		NextMatchHeader:        "🎾 Your next match 🎾",
+		PracticeResultHeader:   "🎾 Match finished! 🎾",
+		PracticeResultMode:     ResultModePlayers,
+		FriendlyResultHeader:   "🎾 Friendly finished! 🎾",
+		FriendlyEmoji:          "🤝",
+		BallEmoji:              "🎾",
+		TrophyEmoji:            "🏆",
+		Medals:                 []string{"🥇", "🥈", "🥉"},
+	}
+}
+
+// WithDefaults returns the theme with every unset field taken from the default theme.
+func (t MessageTheme) WithDefaults() MessageTheme {
+	return t.WithDefaultsFrom(DefaultMessageTheme())
+}
+
+// WithDefaultsFrom returns the theme with every unset field taken from the given defaults.
+func (t MessageTheme) WithDefaultsFrom(defaults MessageTheme) MessageTheme {
+	orDefault := func(value, fallback string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	}
+	// A custom result header is used for practice results too, unless they have their own.
+	t.PracticeResultHeader = orDefault(t.PracticeResultHeader, orDefault(t.ResultHeader, defaults.PracticeResultHeader))
+	t.BookingHeader = orDefault(t.BookingHeader, defaults.BookingHeader)
+	t.ResultHeader = orDefault(t.ResultHeader, defaults.ResultHeader)
+	t.LeaderboardHeader = orDefault(t.LeaderboardHeader, defaults.LeaderboardHeader)
+	t.LevelLeaderboardHeader = orDefault(t.LevelLeaderboardHeader, defaults.LevelLeaderboardHeader)
+	t.BallBringersHeader = orDefault(t.BallBringersHeader, defaults.BallBringersHeader)
+	t.NextMatchHeader = orDefault(t.NextMatchHeader, defaults.NextMatchHeader)
+	t.PracticeResultMode = orDefault(t.PracticeResultMode, defaults.PracticeResultMode)
+	t.FriendlyResultHeader = orDefault(t.FriendlyResultHeader, defaults.FriendlyResultHeader)
+	t.FriendlyEmoji = orDefault(t.FriendlyEmoji, defaults.FriendlyEmoji)
+	t.BallEmoji = orDefault(t.BallEmoji, defaults.BallEmoji)
+	t.TrophyEmoji = orDefault(t.TrophyEmoji, defaults.TrophyEmoji)
+	if len(t.Medals) == 0 {
+		t.Medals = defaults.Medals
+	}
+	return t
+}
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // Config holds all configuration for the application.
 type Config struct {
 	DBName        string
@@ -8,18 +10,93 @@ type Config struct {
 	Slack         SlackConfig
 	TenantID      string
 	Turso         TursoConfig
+	DBPool        DBPoolConfig
 	//Inngest        InngestConfig
-	ProjectID string
+	ProjectID     string
+	Notifications NotificationConfig
+	// AdminSlackUserIDs are the Slack users allowed to run admin slash commands.
+	AdminSlackUserIDs []string
+	// AdminAPIToken is the bearer token required by the admin HTTP endpoints that change players and matches.
+	// When empty, those endpoints reject every request.
+	AdminAPIToken string
+	// Notifier selects the notification backend: "slack" (default) or "webhook".
+	Notifier   string
+	WebhookURL string
+	// MatchCacheTTL is how long match details fetched from Playtomic are cached. Zero disables the cache.
+	MatchCacheTTL time.Duration
+	// MinMatchesForLeaderboard is the number of matches a player must have played to appear on the leaderboard.
+	MinMatchesForLeaderboard int
+	// ProcessConcurrency is the number of matches processed at the same time by a processing run.
+	ProcessConcurrency int
+	// MaxFetchDays caps how many days a fetch may span, whether given as 'days' or as a 'from'/'to' range. Zero means no cap.
+	MaxFetchDays int
+	// StuckMatchAfter is how long a match may wait for a Pub/Sub handler in one processing status before it is
+	// reported as stuck. Zero disables the check.
+	StuckMatchAfter time.Duration
+	// BallBringerAssignWindow holds back ball bringer assignment, and with it the booking notification, until the
+	// match starts within this window, so players who join late are part of the rotation. Zero assigns straight away.
+	BallBringerAssignWindow time.Duration
+	// EnableDestructiveEndpoints registers endpoints that wipe data, such as /clear. Keep it off in production.
+	EnableDestructiveEndpoints bool
+	// EnableTestEndpoints registers endpoints for local testing, such as /matches/seed. Keep it off in production.
+	EnableTestEndpoints bool
 }
 type SlackConfig struct {
 	Token         string
 	ChannelID     string
 	SigningSecret string
+	// Channels routes notification types (e.g. "result") to their own channel. Unmapped types use ChannelID.
+	Channels map[string]string
+	// CompactLeaderboardMax is the largest leaderboard rendered as a single table instead of one block per player.
+	CompactLeaderboardMax int
+	Theme                 MessageTheme
+	// Locale selects the language of notification text, e.g. "en" (default) or "da".
+	Locale string
+	// DelayedResponses acknowledges slow slash commands straight away and posts the result to their response_url.
+	DelayedResponses bool
+	// HideGuests leaves players who are not club members, i.e. neither core players nor linked to a Slack user, out
+	// of the booking notification's player list. Otherwise they are listed and labeled as guests.
+	HideGuests bool
 }
 type TursoConfig struct {
 	PrimaryURL string
 	AuthToken  string
 }
+
+// DBPoolConfig tunes the database connection pool. Zero values keep the database/sql defaults.
+type DBPoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	// BusyTimeout is how long a local SQLite connection waits on a locked database before failing with
+	// "database is locked". Zero keeps the driver default. It doesn't apply to remote Turso databases.
+	BusyTimeout time.Duration
+}
+
+// NotificationConfig controls when notifications are allowed to go out.
+type NotificationConfig struct {
+	// QuietHoursStart and QuietHoursEnd are hours of the day (0-23, club local time) between which
+	// booking notifications are held back. The window may wrap midnight. Equal values disable it.
+	QuietHoursStart int
+	QuietHoursEnd   int
+	// ThreadResults posts result notifications as replies to the match's booking notification, when it is known.
+	ThreadResults bool
+	// BallBringerDM sends the assigned ball bringer a direct message, if their Slack user is known.
+	BallBringerDM bool
+	// BookingGrace is how long after a match has started its booking notification may still be sent.
+	// Later than that, the notification is skipped.
+	BookingGrace time.Duration
+	// BookingNotifyMaxLeadTime holds back the booking notification of a match until it starts within this window,
+	// so matches booked far ahead do not clutter the channel. Zero notifies straight away.
+	BookingNotifyMaxLeadTime time.Duration
+	// DailyDigest batches booking notifications into one digest message, posted when /digest/daily is called,
+	// instead of posting one message per booking.
+	DailyDigest bool
+	// NewPlayers announces players seen for the first time, so admins can link them to their Slack users.
+	NewPlayers bool
+	// IncompleteMatchDM sends the owner of a match with missing players a direct message asking them to complete the lineup.
+	IncompleteMatchDM bool
+}
 type InngestConfig struct {
 	SingingKey string
 	EventKey   string
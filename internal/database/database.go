@@ -3,25 +3,30 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
 
 	"github.com/charmbracelet/log"
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/mauv0809/ideal-tribble/internal/config"
 	"github.com/pressly/goose/v3" // NEW: Import goose
 	_ "github.com/tursodatabase/libsql-client-go/libsql"
 )
 
 // InitDB initializes the database and ensures the schema is up to date.
-func InitDB(dbName string, primaryUrl string, authToken string, migrationsDir string) (*sql.DB, func(), error) {
+func InitDB(dbName string, primaryUrl string, authToken string, migrationsDir string, pool config.DBPoolConfig) (*sql.DB, func(), error) {
 	// For local-only databases, dbName is the filename.
 	// For embedded replicas, dbName is the local file, and primaryUrl is the remote.
 	// We handle the local-only case separately for clarity.
 	if primaryUrl == "" {
 		log.Info("Initializing local-only SQLite database", "path", dbName)
-		db, err := sql.Open("libsql", "file:"+dbName)
+		db, err := sql.Open("libsql", localDSN(dbName, pool))
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to open local database: %w", err)
 		}
+		applyPoolConfig(db, pool)
 		if err = createTables(db, migrationsDir); err != nil {
 			db.Close() // Close on error
 			return nil, nil, fmt.Errorf("failed to create tables for local db: %w", err)
@@ -32,12 +37,14 @@ func InitDB(dbName string, primaryUrl string, authToken string, migrationsDir st
 		return db, teardown, nil
 	}
 	//Remote only database
+	// Turso handles locking and journaling on the server, so the local SQLite settings don't apply.
 	log.Info("Initializing Turso database", "url", primaryUrl)
 	db, err := sql.Open("libsql", primaryUrl+"?authToken="+authToken)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to open db %s: %s", primaryUrl, err)
 		return nil, nil, fmt.Errorf("failed to open remote database: %w", err)
 	}
+	applyPoolConfig(db, pool)
 	if err = createTables(db, migrationsDir); err != nil {
 		db.Close() // Close on error
 		return nil, nil, fmt.Errorf("failed to create tables for local db: %w", err)
@@ -113,3 +120,75 @@ func createTables(db *sql.DB, migrationsDir string) error {
 	log.Info("Database initialized successfully")
 	return nil
 }
+
+// localDSN returns the data source name for a local SQLite database. Every connection enforces foreign keys,
+// file databases use WAL journaling, so readers don't block the writer, and every connection waits up to the
+// configured busy timeout for a lock. These are DSN parameters rather than PRAGMAs so they apply to each
+// connection in the pool.
+func localDSN(dbName string, pool config.DBPoolConfig) string {
+	params := url.Values{}
+	params.Set("_foreign_keys", "1")
+	if dbName != ":memory:" {
+		params.Set("_journal_mode", "WAL")
+	}
+	if pool.BusyTimeout > 0 {
+		params.Set("_busy_timeout", strconv.FormatInt(pool.BusyTimeout.Milliseconds(), 10))
+	}
+	return "file:" + dbName + "?" + params.Encode()
+}
+
+// applyPoolConfig applies the configured connection pool limits, leaving the database/sql defaults for zero values.
+func applyPoolConfig(db *sql.DB, pool config.DBPoolConfig) {
+	if pool.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(pool.MaxOpenConns)
+	}
+	if pool.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	if pool.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	}
+}
+
+// MigrationStatus describes the schema migrations applied to the database.
+type MigrationStatus struct {
+	Applied []string `json:"applied"`
+	Version int64    `json:"version"`
+}
+
+// GetMigrationStatus returns the migrations in migrationsDir that have been applied, in order, and the current schema version.
+func GetMigrationStatus(db *sql.DB, migrationsDir string) (MigrationStatus, error) {
+	version, err := goose.GetDBVersion(db)
+	if err != nil {
+		return MigrationStatus{}, fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("SELECT version_id FROM %s WHERE is_applied = 1", goose.TableName()))
+	if err != nil {
+		return MigrationStatus{}, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+	applied := make(map[int64]bool)
+	for rows.Next() {
+		var v int64
+		if err := rows.Scan(&v); err != nil {
+			return MigrationStatus{}, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[v] = true
+	}
+	if err := rows.Err(); err != nil {
+		return MigrationStatus{}, err
+	}
+
+	migrations, err := goose.CollectMigrations(migrationsDir, 0, goose.MaxVersion)
+	if err != nil {
+		return MigrationStatus{}, fmt.Errorf("failed to collect migrations: %w", err)
+	}
+	status := MigrationStatus{Applied: []string{}, Version: version}
+	for _, m := range migrations {
+		if applied[m.Version] {
+			status.Applied = append(status.Applied, filepath.Base(m.Source))
+		}
+	}
+	return status, nil
+}
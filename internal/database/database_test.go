@@ -1,15 +1,21 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/mauv0809/ideal-tribble/internal/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestInitDB_CreatesTables(t *testing.T) {
 
-	db, teardown, err := InitDB(":memory:", "", "", "../../migrations")
+	db, teardown, err := InitDB(":memory:", "", "", "../../migrations", config.DBPoolConfig{})
 	require.NoError(t, err, "InitDB should not return an error")
 	if teardown != nil {
 		defer teardown()
@@ -35,3 +41,131 @@ func TestInitDB_CreatesTables(t *testing.T) {
 	require.NoError(t, err, "Querying for players_stats table should not produce an error")
 	assert.Equal(t, "player_stats", playersStatsTableName, "The 'players_stats' table should be created")
 }
+
+func TestInitDB_AppliesPoolConfig(t *testing.T) {
+	pool := config.DBPoolConfig{MaxOpenConns: 3, MaxIdleConns: 1, ConnMaxLifetime: time.Minute}
+	db, teardown, err := InitDB(":memory:", "", "", "../../migrations", pool)
+	require.NoError(t, err)
+	defer teardown()
+
+	assert.Equal(t, 3, db.Stats().MaxOpenConnections)
+
+	// Holding three connections and releasing them should leave only MaxIdleConns idle.
+	ctx := context.Background()
+	var conns []*sql.Conn
+	for i := 0; i < 3; i++ {
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		require.NoError(t, conn.Close())
+	}
+	stats := db.Stats()
+	assert.Equal(t, 1, stats.Idle)
+	assert.Equal(t, int64(2), stats.MaxIdleClosed)
+}
+
+func TestInitDB_LocalFileUsesWALAndBusyTimeout(t *testing.T) {
+	pool := config.DBPoolConfig{BusyTimeout: 2 * time.Second}
+	db, teardown, err := InitDB(filepath.Join(t.TempDir(), "club.db"), "", "", "../../migrations", pool)
+	require.NoError(t, err)
+	defer teardown()
+
+	var journalMode string
+	require.NoError(t, db.QueryRow("PRAGMA journal_mode").Scan(&journalMode))
+	assert.Equal(t, "wal", journalMode)
+	var busyTimeout int
+	require.NoError(t, db.QueryRow("PRAGMA busy_timeout").Scan(&busyTimeout))
+	assert.Equal(t, 2000, busyTimeout)
+}
+
+func TestInitDB_ForeignKeysOnEveryConnection(t *testing.T) {
+	pool := config.DBPoolConfig{MaxOpenConns: 3, MaxIdleConns: 3}
+	db, teardown, err := InitDB(filepath.Join(t.TempDir(), "club.db"), "", "", "../../migrations", pool)
+	require.NoError(t, err)
+	defer teardown()
+
+	ctx := context.Background()
+	for range pool.MaxOpenConns {
+		// Each connection is held open, so every iteration checks a different one.
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+		defer conn.Close()
+		var foreignKeys int
+		require.NoError(t, conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys))
+		assert.Equal(t, 1, foreignKeys)
+	}
+}
+
+func TestInitDB_ConcurrentWritesWaitForLock(t *testing.T) {
+	// holdWriteLock writes in a transaction on its own connection and commits after a while.
+	holdWriteLock := func(t *testing.T, db *sql.DB) chan error {
+		t.Helper()
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		_, err = tx.Exec("INSERT INTO players (id, name) VALUES ('holder', 'Holder')")
+		require.NoError(t, err)
+		done := make(chan error, 1)
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			done <- tx.Commit()
+		}()
+		return done
+	}
+
+	t.Run("fails without waiting long enough", func(t *testing.T) {
+		pool := config.DBPoolConfig{BusyTimeout: time.Millisecond}
+		db, teardown, err := InitDB(filepath.Join(t.TempDir(), "club.db"), "", "", "../../migrations", pool)
+		require.NoError(t, err)
+		defer teardown()
+
+		done := holdWriteLock(t, db)
+		_, err = db.Exec("INSERT INTO players (id, name) VALUES ('writer', 'Writer')")
+		assert.ErrorContains(t, err, "database is locked")
+		require.NoError(t, <-done)
+	})
+
+	t.Run("succeeds once the lock is released", func(t *testing.T) {
+		pool := config.DBPoolConfig{BusyTimeout: 5 * time.Second}
+		db, teardown, err := InitDB(filepath.Join(t.TempDir(), "club.db"), "", "", "../../migrations", pool)
+		require.NoError(t, err)
+		defer teardown()
+
+		done := holdWriteLock(t, db)
+		errs := make(chan error, 5)
+		for i := range 5 {
+			go func() {
+				_, err := db.Exec("INSERT INTO players (id, name) VALUES (?, 'Writer')", fmt.Sprintf("writer%d", i))
+				errs <- err
+			}()
+		}
+		for range 5 {
+			assert.NoError(t, <-errs)
+		}
+		require.NoError(t, <-done)
+
+		var count int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM players").Scan(&count))
+		assert.Equal(t, 6, count)
+	})
+}
+
+func TestGetMigrationStatus(t *testing.T) {
+	db, teardown, err := InitDB(":memory:", "", "", "../../migrations", config.DBPoolConfig{})
+	require.NoError(t, err)
+	defer teardown()
+
+	status, err := GetMigrationStatus(db, "../../migrations")
+	require.NoError(t, err)
+
+	files, err := filepath.Glob("../../migrations/*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	var expected []string
+	for _, file := range files {
+		expected = append(expected, filepath.Base(file))
+	}
+	assert.Equal(t, expected, status.Applied, "Every migration fixture should be applied")
+	assert.Equal(t, int64(len(files)), status.Version, "The schema should be at the latest migration")
+}
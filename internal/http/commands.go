@@ -0,0 +1,104 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// Command is a Slack slash command served at /slack/command/<Name>.
+type Command struct {
+	Name        string
+	Usage       string // Arguments shown in /help, e.g. "[player name]"
+	Description string
+	Admin       bool // Only admins (see ADMIN_SLACK_USER_IDS) may run the command
+	Handler     http.HandlerFunc
+}
+
+// CommandRegistry holds the slash commands the server answers. It is used both to register their routes and to
+// list them in /help, so a new command only has to be added in one place.
+type CommandRegistry struct {
+	commands []Command
+}
+
+// Register adds a command to the registry. Commands are listed in /help in the order they are registered.
+func (c *CommandRegistry) Register(cmd Command) {
+	c.commands = append(c.commands, cmd)
+}
+
+// Commands returns the registered commands in registration order.
+func (c *CommandRegistry) Commands() []Command {
+	return c.commands
+}
+
+// registerCommandRoutes registers a route for every command in the registry. Admin commands are guarded so
+// their handlers only run for admins.
+func (s *Server) registerCommandRoutes() {
+	for _, cmd := range s.Commands.Commands() {
+		middlewares := []Middleware{s.VerifySlackSignature, paramsMiddleware}
+		if cmd.Admin {
+			middlewares = append(middlewares, s.adminOnly)
+		}
+		s.Router.Handle("/slack/command/"+cmd.Name, Chain(cmd.Handler, middlewares...))
+	}
+}
+
+// commandRegistry returns the registry of every slash command the server answers.
+func (s *Server) commandRegistry() *CommandRegistry {
+	registry := &CommandRegistry{}
+	registry.Register(Command{Name: "help", Description: "Lists the available commands.", Handler: s.HelpCommandHandler()})
+	registry.Register(Command{Name: "leaderboard", Usage: "[alltime] [competitive] [sort]", Description: "Shows the player leaderboard, e.g. `/leaderboard competitive winpct`.", Handler: s.LeaderboardCommandHandler()})
+	registry.Register(Command{Name: "player-stats", Usage: "[competitive] <player name|@slackuser>", Description: "Shows the stats of a player.", Handler: s.PlayerStatsCommandHandler()})
+	registry.Register(Command{Name: "level-leaderboard", Description: "Shows the players ranked by level.", Handler: s.LevelLeaderboardCommandHandler()})
+	registry.Register(Command{Name: "balls", Description: "Shows how many times each player has brought balls.", Handler: s.BallBringerCommandHandler()})
+	registry.Register(Command{Name: "roster", Usage: "[page]", Description: "Lists all players and their level.", Handler: s.RosterCommandHandler()})
+	registry.Register(Command{Name: "leaderboard-diff", Usage: "<period> <period>", Description: "Compares the player stats of two periods, e.g. `/leaderboard-diff last-week this-week`.", Handler: s.LeaderboardDiffCommandHandler()})
+	registry.Register(Command{Name: "form", Usage: "<player name>", Description: "Shows a player's last five results.", Handler: s.FormCommandHandler()})
+	registry.Register(Command{Name: "next", Description: "Shows your next upcoming match.", Handler: s.NextMatchCommandHandler()})
+	registry.Register(Command{Name: "match-status", Usage: "<match id>", Description: "Explains where a match is in processing and what happens next.", Handler: s.MatchStatusCommandHandler()})
+	registry.Register(Command{Name: "notifications", Usage: "[on|off]", Description: "Turns the bot's direct messages to you off or on.", Handler: s.NotificationsCommandHandler()})
+	registry.Register(Command{Name: "report-result", Usage: "<match id> <set scores>", Description: "Enters the score of a match you played, e.g. `/report-result <match id> 6-4,6-3`.", Handler: s.ReportResultCommandHandler()})
+	registry.Register(Command{Name: "refresh", Description: "Fetches recent matches from Playtomic.", Admin: true, Handler: s.RefreshCommandHandler()})
+	registry.Register(Command{Name: "process", Description: "Runs match processing.", Admin: true, Handler: s.ProcessCommandHandler()})
+	registry.Register(Command{Name: "map", Usage: "@slackuser <player name>", Description: "Links a Slack user to a player.", Admin: true, Handler: s.MapPlayerCommandHandler()})
+	registry.Register(Command{Name: "merge", Usage: "<keep id> <duplicate id>", Description: "Merges a duplicate player record into another.", Admin: true, Handler: s.MergePlayersCommandHandler()})
+	registry.Register(Command{Name: "unmapped", Description: "Lists the players that are not linked to a Slack user.", Admin: true, Handler: s.UnmappedPlayersCommandHandler()})
+	registry.Register(Command{Name: "undo-last", Usage: "[match id]", Description: "Reverts the stats of a match and marks it for review.", Admin: true, Handler: s.UndoLastCommandHandler()})
+	registry.Register(Command{Name: "requeue", Usage: "<match id>", Description: "Moves a match marked for review back into processing.", Admin: true, Handler: s.RequeueCommandHandler()})
+	registry.Register(Command{Name: "new-season", Description: "Archives the standings and starts a new season.", Admin: true, Handler: s.NewSeasonCommandHandler()})
+	registry.Register(Command{Name: "reset-balls", Usage: "[player id]", Description: "Resets the ball bringer counts.", Admin: true, Handler: s.ResetBallBringerCountsCommandHandler()})
+	return registry
+}
+
+// HelpCommandHandler returns a handler for the /help Slack command, which lists the registered commands.
+func (s *Server) HelpCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		respondWithSlackMsg(w, formatHelp(s.Commands.Commands()))
+	}
+}
+
+// formatHelp creates the /help message listing each command with its usage and description.
+func formatHelp(commands []Command) slack.Message {
+	lines := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		usage := "/" + cmd.Name
+		if cmd.Usage != "" {
+			usage += " " + cmd.Usage
+		}
+		description := cmd.Description
+		if cmd.Admin {
+			description = "Admin only. " + description
+		}
+		lines = append(lines, fmt.Sprintf("• `%s` – %s", usage, description))
+	}
+	return slack.NewBlockMessage(
+		slack.NewHeaderBlock(slack.NewTextBlockObject("plain_text", "Available commands", false, false)),
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", strings.Join(lines, "\n"), false, false), nil, nil),
+	)
+}
@@ -1,20 +1,30 @@
 package http
 
 import (
+	"bytes"
+	"database/sql"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"io"
 
 	"github.com/charmbracelet/log"
 	"github.com/mauv0809/ideal-tribble/internal/club"
+	"github.com/mauv0809/ideal-tribble/internal/database"
+	"github.com/mauv0809/ideal-tribble/internal/notifier"
 	"github.com/mauv0809/ideal-tribble/internal/playtomic"
 	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
 )
 
 func (s *Server) HealthCheckHandler() http.HandlerFunc {
@@ -25,17 +35,31 @@ func (s *Server) HealthCheckHandler() http.HandlerFunc {
 	}
 }
 
+// requestedByHeader identifies who triggered an administrative request, for the audit log.
+const requestedByHeader = "X-Requested-By"
+
+// ClearStoreHandler clears a single match when given 'matchID'. Clearing the entire store wipes all matches and
+// players, so it additionally requires 'confirm=true'.
 func (s *Server) ClearStoreHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		requestedBy := r.Header.Get(requestedByHeader)
+		if requestedBy == "" {
+			requestedBy = "unknown"
+		}
 		matchID := r.URL.Query().Get("matchID")
 		if matchID != "" {
-			log.Info("Received request to clear a specific match", "matchID", matchID)
+			log.Info("Received request to clear a specific match", "matchID", matchID, "requestedBy", requestedBy)
 			s.Store.ClearMatch(matchID)
 			w.WriteHeader(http.StatusOK)
 			fmt.Fprintf(w, "Cleared match %s from store!", matchID)
 			log.Info("Successfully cleared match from store", "matchID", matchID)
 		} else {
-			log.Info("Received request to clear entire store")
+			if r.URL.Query().Get("confirm") != "true" {
+				log.Warn("Rejected request to clear entire store without confirmation", "requestedBy", requestedBy)
+				http.Error(w, "Clearing the entire store requires 'confirm=true'", http.StatusBadRequest)
+				return
+			}
+			log.Info("Received request to clear entire store", "requestedBy", requestedBy)
 			s.Store.Clear()
 			w.WriteHeader(http.StatusOK)
 			fmt.Fprint(w, "Store cleared!")
@@ -44,6 +68,122 @@ func (s *Server) ClearStoreHandler() http.HandlerFunc {
 	}
 }
 
+// UndeleteMatchHandler restores a match that was soft-deleted through /clear?matchID=.
+func (s *Server) UndeleteMatchHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		matchID := r.URL.Query().Get("matchID")
+		if matchID == "" {
+			http.Error(w, "'matchID' is required", http.StatusBadRequest)
+			return
+		}
+
+		if isDryRunFromContext(r) {
+			log.Info("[Dry Run] Would have undeleted match", "matchID", matchID)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, "Would restore match %s.", matchID)
+			return
+		}
+
+		if err := s.Store.UndeleteMatch(matchID); err != nil {
+			if errors.Is(err, club.ErrMatchNotDeleted) {
+				http.Error(w, fmt.Sprintf("Match %s is not deleted", matchID), http.StatusNotFound)
+				return
+			}
+			log.Error("Failed to undelete match", "error", err, "matchID", matchID)
+			http.Error(w, "Failed to undelete match", http.StatusInternalServerError)
+			return
+		}
+		log.Info("Restored soft-deleted match", "matchID", matchID, "requestedBy", r.Header.Get(requestedByHeader))
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "Restored match %s.", matchID)
+	}
+}
+
+// MergePlayersHandler merges a duplicate player record into another one.
+func (s *Server) MergePlayersHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keepID := r.URL.Query().Get("keep")
+		mergeID := r.URL.Query().Get("merge")
+		if keepID == "" || mergeID == "" {
+			http.Error(w, "Both 'keep' and 'merge' player IDs are required", http.StatusBadRequest)
+			return
+		}
+
+		if isDryRunFromContext(r) {
+			log.Info("[Dry Run] Would have merged players", "keepID", keepID, "mergeID", mergeID)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, "Would merge player %s into %s.", mergeID, keepID)
+			return
+		}
+
+		log.Info("Received request to merge players", "keepID", keepID, "mergeID", mergeID)
+		if err := s.Store.MergePlayers(keepID, mergeID); err != nil {
+			log.Error("Failed to merge players", "error", err, "keepID", keepID, "mergeID", mergeID)
+			http.Error(w, fmt.Sprintf("Failed to merge players: %s", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "Merged player %s into %s.", mergeID, keepID)
+	}
+}
+
+// SlackUserHandler maps a player to their Slack user. An empty 'slack_user' removes the mapping.
+func (s *Server) SlackUserHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playerID := r.URL.Query().Get("player")
+		slackUserID := r.URL.Query().Get("slack_user")
+		if playerID == "" {
+			http.Error(w, "Query parameter 'player' is required", http.StatusBadRequest)
+			return
+		}
+
+		if isDryRunFromContext(r) {
+			log.Info("[Dry Run] Would have updated slack user mapping", "playerID", playerID, "slackUserID", slackUserID)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, "Would map player %s to slack user %q.", playerID, slackUserID)
+			return
+		}
+
+		if err := s.Store.SetSlackUserID(playerID, slackUserID); err != nil {
+			log.Error("Failed to update slack user mapping", "error", err, "playerID", playerID)
+			http.Error(w, fmt.Sprintf("Failed to update slack user mapping: %s", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "Mapped player %s to slack user %q.", playerID, slackUserID)
+	}
+}
+
+// CorePlayerHandler flags a player as a core club member, or unflags them with 'core=false'.
+func (s *Server) CorePlayerHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playerID := r.URL.Query().Get("player")
+		if playerID == "" {
+			http.Error(w, "Query parameter 'player' is required", http.StatusBadRequest)
+			return
+		}
+		core := r.URL.Query().Get("core") != "false"
+
+		if isDryRunFromContext(r) {
+			log.Info("[Dry Run] Would have updated core member flag", "playerID", playerID, "core", core)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, "Would set core member flag of player %s to %t.", playerID, core)
+			return
+		}
+
+		if err := s.Store.SetCorePlayer(playerID, core); err != nil {
+			log.Error("Failed to update core member flag", "error", err, "playerID", playerID)
+			http.Error(w, fmt.Sprintf("Failed to update core member flag: %s", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "Set core member flag of player %s to %t.", playerID, core)
+	}
+}
+
 func (s *Server) FetchMatchesHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		log.Info("Starting match fetch...")
@@ -64,74 +204,136 @@ func (s *Server) FetchMatchesHandler() http.HandlerFunc {
 
 		startDate := time.Now().AddDate(0, 0, -daysToSubtract)
 
-		params := &playtomic.SearchMatchesParams{
-			SportID:       "PADEL",
-			HasPlayers:    true,
-			Sort:          "start_date,ASC",
-			TenantIDs:     []string{s.Cfg.TenantID},
-			FromStartDate: startDate.Format("2006-01-02") + "T00:00:00",
+		// An explicit 'from'/'to' range (YYYY-MM-DD) takes precedence over 'days'.
+		var endDate time.Time
+		if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+			parsedFrom, err := time.Parse("2006-01-02", fromStr)
+			if err != nil {
+				http.Error(w, "Invalid 'from' date, expected YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			startDate = parsedFrom
 		}
-		log.Info("Fetching matches from", "startDate", startDate)
-		matches, err := s.PlaytomicClient.GetMatches(params)
-		if err != nil {
-			log.Error("Error fetching Playtomic bookings", "error", err)
-			http.Error(w, "Failed to fetch matches", http.StatusInternalServerError)
+		if toStr := r.URL.Query().Get("to"); toStr != "" {
+			parsedTo, err := time.Parse("2006-01-02", toStr)
+			if err != nil {
+				http.Error(w, "Invalid 'to' date, expected YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			endDate = parsedTo
+		}
+		if !endDate.IsZero() && startDate.Format("2006-01-02") > endDate.Format("2006-01-02") {
+			http.Error(w, "'from' must not be after 'to'", http.StatusBadRequest)
 			return
 		}
+		if maxDays := s.Cfg.MaxFetchDays; maxDays > 0 {
+			rangeEnd := endDate
+			if rangeEnd.IsZero() {
+				rangeEnd = time.Now()
+			}
+			if earliest := rangeEnd.AddDate(0, 0, -maxDays); startDate.Format("2006-01-02") < earliest.Format("2006-01-02") {
+				log.Warn("Requested fetch range exceeds the maximum fetch depth. Clamping.", "from", startDate.Format("2006-01-02"), "to", rangeEnd.Format("2006-01-02"), "max_days", maxDays)
+				startDate = earliest
+			}
+		}
 
-		log.Info("Found matches from API", "count", len(matches))
+		if _, _, err := s.fetchMatches(startDate, endDate, isDryRun); err != nil {
+			if errors.Is(err, errSaveMatches) {
+				http.Error(w, "Failed to save matches", http.StatusInternalServerError)
+			} else {
+				http.Error(w, "Failed to fetch matches", http.StatusInternalServerError)
+			}
+			return
+		}
 
-		var clubMatchesToUpsert []*playtomic.PadelMatch
-		var mu sync.Mutex
-		var wg sync.WaitGroup
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "Match fetch completed.")
+	}
+}
 
-		for _, match := range matches {
+// errSaveMatches marks a fetch that reached Playtomic but could not store the matches.
+var errSaveMatches = errors.New("failed to save matches")
 
-			wg.Add(1)
-			go func(matchID string) {
-				defer wg.Done()
-				if match.OwnerID == nil || !s.Store.IsKnownPlayer(*match.OwnerID) {
-					log.Debug("Skipping non-club match", "matchID", matchID)
-					return
-				}
-				specificMatch, err := s.PlaytomicClient.GetSpecificMatch(matchID)
-				if err != nil {
-					log.Error("Error fetching specific match", "matchID", matchID, "error", err)
-					return
-				}
+// fetchMatches fetches the matches starting between startDate and endDate (open-ended if zero) from Playtomic
+// and stores the club matches among them. It returns the number of matches found and the number of club matches.
+func (s *Server) fetchMatches(startDate, endDate time.Time, dryRun bool) (int, int, error) {
+	params := &playtomic.SearchMatchesParams{
+		SportID:       "PADEL",
+		HasPlayers:    true,
+		Sort:          "start_date,ASC",
+		TenantIDs:     []string{s.Cfg.TenantID},
+		FromStartDate: startDate.Format("2006-01-02") + "T00:00:00",
+	}
+	if !endDate.IsZero() {
+		params.ToStartDate = endDate.Format("2006-01-02") + "T23:59:59"
+	}
+	log.Info("Fetching matches from", "startDate", startDate, "endDate", params.ToStartDate)
+	matches, err := s.PlaytomicClient.GetMatches(params)
+	if err != nil {
+		log.Error("Error fetching Playtomic bookings", "error", err)
+		return 0, 0, fmt.Errorf("failed to fetch matches: %w", err)
+	}
 
-				if !isClubMatch(specificMatch, s.Store) {
-					log.Debug("Skipping non-club match", "matchID", matchID)
-					return
-				}
+	log.Info("Found matches from API", "count", len(matches))
 
-				mu.Lock()
-				clubMatchesToUpsert = append(clubMatchesToUpsert, &specificMatch)
-				mu.Unlock()
-			}(match.MatchID)
-		}
-		wg.Wait()
+	var clubMatchesToUpsert []*playtomic.PadelMatch
+	var mu sync.Mutex
+	var wg sync.WaitGroup
 
-		if len(clubMatchesToUpsert) > 0 {
-			if !isDryRun {
-				log.Info("Upserting club matches", "count", len(clubMatchesToUpsert))
-				if err := s.Store.UpsertMatches(clubMatchesToUpsert); err != nil {
-					log.Error("Failed to bulk upsert matches", "error", err)
-					http.Error(w, "Failed to save matches", http.StatusInternalServerError)
-					return
-				}
-			} else {
-				log.Info("[Dry Run] Would have upserted club matches", "count", len(clubMatchesToUpsert))
+	for _, match := range matches {
+
+		wg.Add(1)
+		go func(matchID string) {
+			defer wg.Done()
+			if match.OwnerID == nil || !s.Store.IsKnownPlayer(*match.OwnerID) {
+				log.Debug("Skipping non-club match", "matchID", matchID)
+				return
+			}
+			specificMatch, err := s.PlaytomicClient.GetSpecificMatch(matchID)
+			if errors.Is(err, playtomic.ErrIncompleteMatch) {
+				log.Warn("Skipping match with missing fields", "matchID", matchID, "error", err)
+				s.Metrics.IncInvalidMatches()
+				return
+			}
+			if err != nil {
+				log.Error("Error fetching specific match", "matchID", matchID, "error", err)
+				return
 			}
-		}
 
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprintln(w, "Match fetch completed.")
-		log.Info("Match fetch finished.", "total_api_matches", len(matches), "club_matches_found", len(clubMatchesToUpsert))
+			if !isClubMatch(specificMatch, s.Store) {
+				log.Debug("Skipping non-club match", "matchID", matchID)
+				return
+			}
+
+			mu.Lock()
+			clubMatchesToUpsert = append(clubMatchesToUpsert, &specificMatch)
+			mu.Unlock()
+		}(match.MatchID)
+	}
+	wg.Wait()
+
+	if len(clubMatchesToUpsert) > 0 {
+		if !dryRun {
+			log.Info("Upserting club matches", "count", len(clubMatchesToUpsert))
+			if err := s.Store.UpsertMatches(clubMatchesToUpsert); err != nil {
+				log.Error("Failed to bulk upsert matches", "error", err)
+				return len(matches), 0, fmt.Errorf("%w: %w", errSaveMatches, err)
+			}
+		} else {
+			log.Info("[Dry Run] Would have upserted club matches", "count", len(clubMatchesToUpsert))
+		}
 	}
+
+	log.Info("Match fetch finished.", "total_api_matches", len(matches), "club_matches_found", len(clubMatchesToUpsert))
+	return len(matches), len(clubMatchesToUpsert), nil
 }
 
+// isClubMatch reports whether enough of the match's players are known to treat it as a club match.
+// Matches booked by a core member are always club matches.
 func isClubMatch(match playtomic.PadelMatch, store club.ClubStore) bool {
+	if match.OwnerID != "" && store.IsCorePlayer(match.OwnerID) {
+		return true
+	}
 	knownPlayers := 0
 	totalPlayers := 0
 	for _, team := range match.Teams {
@@ -151,117 +353,83 @@ func isClubMatch(match playtomic.PadelMatch, store club.ClubStore) bool {
 	}
 	return false
 }
+
+// decodePubSubMatch reads a Pub/Sub push request and decodes the match it carries. Malformed payloads
+// are answered with a 400, so Pub/Sub does not retry them, and the raw body is logged for inspection.
+// It returns false when a response has already been written.
+func (s *Server) decodePubSubMatch(w http.ResponseWriter, r *http.Request, description string) (*playtomic.PadelMatch, bool) {
+	bodyBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Error("Failed to read request body", "error", err)
+		http.Error(w, "Failed to read request body", http.StatusInternalServerError)
+		return nil, false
+	}
+	log.Debug(description, "body", string(bodyBytes))
+	// Define a small struct to decode the incoming JSON's `data` field
+	var pubsubMsg struct {
+		Subscription string `json:"subscription"`
+		Message      struct {
+			Data string `json:"data"` // base64-encoded message payload
+		} `json:"message"`
+	}
+
+	// Parse the outer JSON to get `data`
+	if err := json.Unmarshal(bodyBytes, &pubsubMsg); err != nil {
+		log.Error("Failed to unmarshal wrapper JSON", "error", err, "body", string(bodyBytes))
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return nil, false
+	}
+	// Decode base64 to raw MessagePack bytes
+	rawData, err := base64.StdEncoding.DecodeString(pubsubMsg.Message.Data)
+	if err != nil {
+		log.Error("Failed to decode base64 data", "error", err, "body", string(bodyBytes))
+		http.Error(w, "Invalid base64 data", http.StatusBadRequest)
+		return nil, false
+	}
+	match := &playtomic.PadelMatch{}
+	if err := s.pubsub.ProcessMessage(rawData, match); err != nil {
+		log.Error("Failed to decode match from message", "error", err, "body", string(bodyBytes))
+		http.Error(w, "Invalid match payload", http.StatusBadRequest)
+		return nil, false
+	}
+	if match.MatchID == "" {
+		log.Error("Received match without an ID", "body", string(bodyBytes))
+		http.Error(w, "Match payload is missing a match ID", http.StatusBadRequest)
+		return nil, false
+	}
+	return match, true
+}
+
 func (s *Server) BallBoyHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		bodyBytes, err := io.ReadAll(r.Body)
-		if err != nil {
-			log.Error("Failed to read request body", "error", err)
-			http.Error(w, "Failed to read request body", http.StatusInternalServerError)
-			return
-		}
-		log.Debug("Received ball boy message", "body", string(bodyBytes))
-		// Define a small struct to decode the incoming JSON's `data` field
-		var pubsubMsg struct {
-			Subscription string `json:"subscription"`
-			Message      struct {
-				Data string `json:"data"` // base64-encoded message payload
-				// You can add other fields if needed
-			} `json:"message"`
-		}
-
-		// Parse the outer JSON to get `data`
-		if err := json.Unmarshal(bodyBytes, &pubsubMsg); err != nil {
-			log.Error("Failed to unmarshal wrapper JSON", "error", err)
-			http.Error(w, "Invalid JSON", http.StatusBadRequest)
-			return
-		}
-		// Decode base64 to raw MessagePack bytes
-		rawData, err := base64.StdEncoding.DecodeString(pubsubMsg.Message.Data)
-		if err != nil {
-			log.Error("Failed to decode base64 data", "error", err)
-			http.Error(w, "Invalid base64 data", http.StatusBadRequest)
+		match, ok := s.decodePubSubMatch(w, r, "Received ball boy message")
+		if !ok {
 			return
 		}
 		isDryRun := isDryRunFromContext(r)
-		match := playtomic.PadelMatch{}
-		s.pubsub.ProcessMessage(rawData, &match)
-		s.Processor.AssignBallBringer(&match, isDryRun)
+		s.Processor.AssignBallBringer(match, isDryRun)
 		w.Write([]byte("OK"))
 	}
 }
 func (s *Server) UpdatePlayerStatsHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		bodyBytes, err := io.ReadAll(r.Body)
-		if err != nil {
-			log.Error("Failed to read request body", "error", err)
-			http.Error(w, "Failed to read request body", http.StatusInternalServerError)
-			return
-		}
-		log.Debug("Received update player stats message", "body", string(bodyBytes))
-		// Define a small struct to decode the incoming JSON's `data` field
-		var pubsubMsg struct {
-			Subscription string `json:"subscription"`
-			Message      struct {
-				Data string `json:"data"` // base64-encoded message payload
-				// You can add other fields if needed
-			} `json:"message"`
-		}
-
-		// Parse the outer JSON to get `data`
-		if err := json.Unmarshal(bodyBytes, &pubsubMsg); err != nil {
-			log.Error("Failed to unmarshal wrapper JSON", "error", err)
-			http.Error(w, "Invalid JSON", http.StatusBadRequest)
-			return
-		}
-		// Decode base64 to raw MessagePack bytes
-		rawData, err := base64.StdEncoding.DecodeString(pubsubMsg.Message.Data)
-		if err != nil {
-			log.Error("Failed to decode base64 data", "error", err)
-			http.Error(w, "Invalid base64 data", http.StatusBadRequest)
+		match, ok := s.decodePubSubMatch(w, r, "Received update player stats message")
+		if !ok {
 			return
 		}
 		isDryRun := isDryRunFromContext(r)
-		match := playtomic.PadelMatch{}
-		s.pubsub.ProcessMessage(rawData, &match)
-		s.Processor.UpdatePlayerStats(&match, isDryRun)
+		s.Processor.UpdatePlayerStats(match, isDryRun)
 		w.Write([]byte("OK"))
 	}
 }
 func (s *Server) NotifyBookingHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		bodyBytes, err := io.ReadAll(r.Body)
-		if err != nil {
-			log.Error("Failed to read request body", "error", err)
-			http.Error(w, "Failed to read request body", http.StatusInternalServerError)
-			return
-		}
-		log.Debug("Recieved notify booking message", "body", string(bodyBytes))
-		// Define a small struct to decode the incoming JSON's `data` field
-		var pubsubMsg struct {
-			Subscription string `json:"subscription"`
-			Message      struct {
-				Data string `json:"data"` // base64-encoded message payload
-				// You can add other fields if needed
-			} `json:"message"`
-		}
-
-		// Parse the outer JSON to get `data`
-		if err := json.Unmarshal(bodyBytes, &pubsubMsg); err != nil {
-			log.Error("Failed to unmarshal wrapper JSON", "error", err)
-			http.Error(w, "Invalid JSON", http.StatusBadRequest)
-			return
-		}
-		// Decode base64 to raw MessagePack bytes
-		rawData, err := base64.StdEncoding.DecodeString(pubsubMsg.Message.Data)
-		if err != nil {
-			log.Error("Failed to decode base64 data", "error", err)
-			http.Error(w, "Invalid base64 data", http.StatusBadRequest)
+		match, ok := s.decodePubSubMatch(w, r, "Received notify booking message")
+		if !ok {
 			return
 		}
 		isDryRun := isDryRunFromContext(r)
-		match := playtomic.PadelMatch{}
-		s.pubsub.ProcessMessage(rawData, &match)
-		err = s.Processor.NotifyBooking(&match, isDryRun)
+		err := s.Processor.NotifyBooking(match, isDryRun)
 		if err != nil {
 			log.Error("Failed to notify booking", "error", err)
 			http.Error(w, "Failed to notify booking", http.StatusInternalServerError)
@@ -272,42 +440,48 @@ func (s *Server) NotifyBookingHandler() http.HandlerFunc {
 }
 func (s *Server) NotifyResultHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		bodyBytes, err := io.ReadAll(r.Body)
-		if err != nil {
-			log.Error("Failed to read request body", "error", err)
-			http.Error(w, "Failed to read request body", http.StatusInternalServerError)
+		match, ok := s.decodePubSubMatch(w, r, "Received notify result message")
+		if !ok {
 			return
 		}
-		log.Debug("Recieved notify booking message", "body", string(bodyBytes))
-		// Define a small struct to decode the incoming JSON's `data` field
-		var pubsubMsg struct {
-			Subscription string `json:"subscription"`
-			Message      struct {
-				Data string `json:"data"` // base64-encoded message payload
-				// You can add other fields if needed
-			} `json:"message"`
+		isDryRun := isDryRunFromContext(r)
+		err := s.Processor.NotifyResult(match, isDryRun)
+		if err != nil {
+			log.Error("Failed to notify result", "error", err)
+			http.Error(w, "Failed to notify result", http.StatusInternalServerError)
+			return
 		}
+		w.Write([]byte("OK"))
+	}
+}
 
-		// Parse the outer JSON to get `data`
-		if err := json.Unmarshal(bodyBytes, &pubsubMsg); err != nil {
-			log.Error("Failed to unmarshal wrapper JSON", "error", err)
-			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+// SeedMatchHandler stores a match pushed in a Pub/Sub envelope, together with its players, as a NEW match.
+// It lets local tooling such as the CLI simulate command run a match through the pipeline without Playtomic.
+func (s *Server) SeedMatchHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		match, ok := s.decodePubSubMatch(w, r, "Received seed match message")
+		if !ok {
 			return
 		}
-		// Decode base64 to raw MessagePack bytes
-		rawData, err := base64.StdEncoding.DecodeString(pubsubMsg.Message.Data)
-		if err != nil {
-			log.Error("Failed to decode base64 data", "error", err)
-			http.Error(w, "Invalid base64 data", http.StatusBadRequest)
+		if isDryRunFromContext(r) {
+			log.Info("[Dry Run] Would have seeded match", "matchID", match.MatchID)
+			w.Write([]byte("OK"))
 			return
 		}
-		isDryRun := isDryRunFromContext(r)
-		match := playtomic.PadelMatch{}
-		s.pubsub.ProcessMessage(rawData, &match)
-		err = s.Processor.NotifyResult(&match, isDryRun)
-		if err != nil {
-			log.Error("Failed to notify result", "error", err)
-			http.Error(w, "Failed to notify result", http.StatusInternalServerError)
+		var players []club.PlayerInfo
+		for _, team := range match.Teams {
+			for _, player := range team.Players {
+				players = append(players, club.PlayerInfo{ID: player.UserID, Name: player.Name, Level: player.Level})
+			}
+		}
+		if _, err := s.Store.UpsertPlayers(players); err != nil {
+			log.Error("Failed to seed players", "error", err, "matchID", match.MatchID)
+			http.Error(w, "Failed to seed players", http.StatusInternalServerError)
+			return
+		}
+		if err := s.Store.UpsertMatch(match); err != nil {
+			log.Error("Failed to seed match", "error", err, "matchID", match.MatchID)
+			http.Error(w, "Failed to seed match", http.StatusInternalServerError)
 			return
 		}
 		w.Write([]byte("OK"))
@@ -326,6 +500,93 @@ func (s *Server) ProcessMatchesHandler() http.HandlerFunc {
 	}
 }
 
+// ProcessMatchHandler runs a single match through the processor, which is handy when debugging one match
+// without churning all of them. It responds with the match's processing status afterwards.
+func (s *Server) ProcessMatchHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		matchID := r.PathValue("id")
+		isDryRun := isDryRunFromContext(r)
+
+		match, err := s.Store.GetMatch(matchID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				http.Error(w, fmt.Sprintf("Match %s not found", matchID), http.StatusNotFound)
+				return
+			}
+			log.Error("Failed to get match", "error", err, "matchID", matchID)
+			http.Error(w, "Failed to get match", http.StatusInternalServerError)
+			return
+		}
+
+		initialStatus := match.ProcessingStatus
+		s.Processor.ProcessMatch(match, isDryRun)
+		log.Info("Processed single match", "matchID", matchID, "from", initialStatus, "to", match.ProcessingStatus, "dryRun", isDryRun)
+
+		w.Header().Set("Content-Type", "application/json")
+		response := map[string]any{
+			"matchID":          match.MatchID,
+			"previousStatus":   initialStatus,
+			"processingStatus": match.ProcessingStatus,
+			"dryRun":           isDryRun,
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Error("Failed to encode match processing status to JSON", "error", err)
+		}
+	}
+}
+
+// RefetchMatchHandler fetches a stored match from Playtomic again and updates it, e.g. when players joined after
+// it was first fetched. Its processing status is kept. It responds with the refreshed match as JSON. Match details
+// fetched within MATCH_CACHE_TTL_SECONDS may be served from the client's cache.
+func (s *Server) RefetchMatchHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		matchID := r.PathValue("id")
+		isDryRun := isDryRunFromContext(r)
+
+		stored, err := s.Store.GetMatch(matchID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				http.Error(w, fmt.Sprintf("Match %s not found", matchID), http.StatusNotFound)
+				return
+			}
+			log.Error("Failed to get match", "error", err, "matchID", matchID)
+			http.Error(w, "Failed to get match", http.StatusInternalServerError)
+			return
+		}
+
+		// A cached copy could be the very state the refetch is meant to replace.
+		fetched, err := s.PlaytomicClient.RefreshSpecificMatch(matchID)
+		if err != nil {
+			log.Error("Error fetching specific match", "matchID", matchID, "error", err)
+			http.Error(w, "Failed to fetch match", http.StatusBadGateway)
+			return
+		}
+
+		refreshed := &fetched
+		if isDryRun {
+			log.Info("[Dry Run] Would have updated match", "matchID", matchID)
+			refreshed.ProcessingStatus = stored.ProcessingStatus
+		} else {
+			if err := s.Store.UpsertMatch(&fetched); err != nil {
+				log.Error("Failed to update match", "error", err, "matchID", matchID)
+				http.Error(w, "Failed to save match", http.StatusInternalServerError)
+				return
+			}
+			if refreshed, err = s.Store.GetMatch(matchID); err != nil {
+				log.Error("Failed to get refetched match", "error", err, "matchID", matchID)
+				http.Error(w, "Failed to get match", http.StatusInternalServerError)
+				return
+			}
+			log.Info("Refetched match", "matchID", matchID)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(refreshed); err != nil {
+			log.Error("Failed to encode match to JSON", "error", err)
+		}
+	}
+}
+
 func (s *Server) ListMembersHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		refresh := r.URL.Query().Get("refresh") == "true"
@@ -346,9 +607,10 @@ func (s *Server) ListMembersHandler() http.HandlerFunc {
 	}
 }
 
+// ListMatchesHandler returns all matches as JSON. Soft-deleted matches are only listed with 'include_deleted=true'.
 func (s *Server) ListMatchesHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		matches, err := s.Store.GetAllMatches()
+		matches, err := s.Store.GetAllMatches(r.URL.Query().Get("include_deleted") == "true")
 		if err != nil {
 			http.Error(w, "Failed to get matches", http.StatusInternalServerError)
 			log.Error("Failed to get matches from store", "error", err)
@@ -361,68 +623,307 @@ func (s *Server) ListMatchesHandler() http.HandlerFunc {
 	}
 }
 
-// LeaderboardHandler returns a handler that serves the player statistics leaderboard.
-func (s *Server) LeaderboardHandler() http.HandlerFunc {
+// WeekMatchesHandler returns the matches of the week (Monday to Sunday, UTC) containing the 'date' param (YYYY-MM-DD).
+func (s *Server) WeekMatchesHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		stats, err := s.Store.GetPlayerStats()
+		date, err := time.Parse("2006-01-02", r.URL.Query().Get("date"))
 		if err != nil {
-			http.Error(w, "Failed to get player stats", http.StatusInternalServerError)
-			log.Error("Failed to get player stats from store", "error", err)
+			http.Error(w, "Invalid 'date', expected YYYY-MM-DD", http.StatusBadRequest)
 			return
 		}
-
+		matches, err := s.Store.GetMatchesForWeek(date.Unix())
+		if err != nil {
+			http.Error(w, "Failed to get matches", http.StatusInternalServerError)
+			log.Error("Failed to get matches for week from store", "error", err, "date", date)
+			return
+		}
+		if matches == nil {
+			matches = []*playtomic.PadelMatch{}
+		}
 		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(stats); err != nil {
-			log.Error("Failed to encode player stats to JSON", "error", err)
+		if err := json.NewEncoder(w).Encode(matches); err != nil {
+			log.Error("Failed to encode matches to JSON", "error", err)
 		}
 	}
 }
 
-// respondWithSlackMsg is a helper to format and write a Slack message as an HTTP response.
-func respondWithSlackMsg(w http.ResponseWriter, msg slack.Message) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(msg); err != nil {
-		log.Error("Failed to encode slack message to JSON", "error", err)
+// PostWeeklyRecapHandler posts the recap of the week containing the optional 'date' param (YYYY-MM-DD).
+// Without it the previous week is recapped, so a Monday morning schedule covers the week that just ended.
+func (s *Server) PostWeeklyRecapHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		date := time.Now().AddDate(0, 0, -7)
+		if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+			parsedDate, err := time.Parse("2006-01-02", dateStr)
+			if err != nil {
+				http.Error(w, "Invalid 'date', expected YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			date = parsedDate
+		}
+		matches, err := s.Store.GetMatchesForWeek(date.Unix())
+		if err != nil {
+			http.Error(w, "Failed to get matches", http.StatusInternalServerError)
+			log.Error("Failed to get matches for week from store", "error", err, "date", date)
+			return
+		}
+		recap := club.BuildWeeklyRecap(date.Unix(), matches)
+		if err := s.Notifier.SendWeeklyRecap(recap, isDryRunFromContext(r)); err != nil {
+			http.Error(w, "Failed to post weekly recap", http.StatusInternalServerError)
+			log.Error("Failed to post weekly recap", "error", err)
+			return
+		}
+		w.Write([]byte("OK"))
 	}
 }
 
-// LeaderboardCommandHandler returns a handler for the /leaderboard Slack command.
-func (s *Server) LeaderboardCommandHandler() http.HandlerFunc {
+// PostDailyDigestHandler posts the day's new bookings as one digest message when DAILY_BOOKING_DIGEST is enabled.
+// Meant to be called on a schedule; bookings already digested are not posted again.
+func (s *Server) PostDailyDigestHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		stats, err := s.Store.GetPlayerStats()
+		if !s.Cfg.Notifications.DailyDigest {
+			w.Write([]byte("Daily booking digest is disabled."))
+			return
+		}
+		count, err := s.Processor.PostBookingDigest(isDryRunFromContext(r))
 		if err != nil {
-			http.Error(w, "Failed to get player stats", http.StatusInternalServerError)
-			log.Error("Failed to get player stats from store", "error", err)
+			http.Error(w, "Failed to post daily digest", http.StatusInternalServerError)
+			log.Error("Failed to post daily booking digest", "error", err)
 			return
 		}
+		fmt.Fprintf(w, "Posted a digest of %d bookings.", count)
+	}
+}
 
-		msg, err := s.Notifier.FormatLeaderboardResponse(stats)
+// ProcessingStatusCountsHandler returns the number of matches in each processing status as JSON.
+func (s *Server) ProcessingStatusCountsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		counts, err := s.Store.GetProcessingStatusCounts()
 		if err != nil {
-			http.Error(w, "Failed to format leaderboard", http.StatusInternalServerError)
-			log.Error("Failed to format leaderboard", "error", err)
+			http.Error(w, "Failed to get processing status counts", http.StatusInternalServerError)
+			log.Error("Failed to get processing status counts from store", "error", err)
 			return
 		}
 
-		slackMsg, ok := msg.(slack.Message)
-		if !ok {
-			http.Error(w, "Invalid message format for Slack", http.StatusInternalServerError)
-			log.Error("Failed to cast message to slack.Message")
-			return
+		// Always report every known status so dashboards get a stable set of keys.
+		for _, status := range playtomic.ProcessingStatuses {
+			if _, ok := counts[status]; !ok {
+				counts[status] = 0
+			}
 		}
 
-		respondWithSlackMsg(w, slackMsg)
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(counts); err != nil {
+			log.Error("Failed to encode processing status counts to JSON", "error", err)
+		}
 	}
 }
 
-// PlayerStatsCommandHandler returns a handler for the /player-stats Slack command.
-func (s *Server) PlayerStatsCommandHandler() http.HandlerFunc {
+// ClubStats is the club overview returned by /stats/club. TopPlayer is the leader of the current season's
+// leaderboard, nil before anyone qualifies for it.
+type ClubStats struct {
+	TotalPlayers    int               `json:"total_players"`
+	TotalMatches    int               `json:"total_matches"`
+	MatchesThisWeek int               `json:"matches_this_week"`
+	TopPlayer       *club.PlayerStats `json:"top_player"`
+}
+
+// ClubStatsHandler returns an overview of the club as JSON, for a dashboard tile: the number of players and
+// matches, the matches this week (Monday to Sunday, UTC) and the top player.
+func (s *Server) ClubStatsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var stats ClubStats
+		var err error
+		if stats.TotalPlayers, err = s.Store.CountPlayers(); err != nil {
+			http.Error(w, "Failed to count players", http.StatusInternalServerError)
+			log.Error("Failed to count players in store", "error", err)
+			return
+		}
+		if stats.TotalMatches, err = s.Store.CountMatches(); err != nil {
+			http.Error(w, "Failed to count matches", http.StatusInternalServerError)
+			log.Error("Failed to count matches in store", "error", err)
+			return
+		}
+		thisWeek, err := s.Store.GetMatchesForWeek(time.Now().Unix())
+		if err != nil {
+			http.Error(w, "Failed to get matches", http.StatusInternalServerError)
+			log.Error("Failed to get matches for week from store", "error", err)
+			return
+		}
+		stats.MatchesThisWeek = len(thisWeek)
+		leaderboard, err := s.Store.GetPlayerStats(s.Cfg.MinMatchesForLeaderboard, club.SortByWins, false, false)
+		if err != nil {
+			http.Error(w, "Failed to get player stats", http.StatusInternalServerError)
+			log.Error("Failed to get player stats from store", "error", err)
+			return
+		}
+		if len(leaderboard) > 0 {
+			stats.TopPlayer = &leaderboard[0]
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(stats); err != nil {
+			log.Error("Failed to encode club stats to JSON", "error", err)
+		}
+	}
+}
+
+// MigrationStatusHandler returns a handler that reports which schema migrations have been applied.
+func (s *Server) MigrationStatusHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status, err := database.GetMigrationStatus(s.DB, s.Cfg.MigrationsDir)
+		if err != nil {
+			http.Error(w, "Failed to get migration status", http.StatusInternalServerError)
+			log.Error("Failed to get migration status", "error", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(status); err != nil {
+			log.Error("Failed to encode migration status to JSON", "error", err)
+		}
+	}
+}
+
+// ReencodeBlobsHandler re-encodes the teams and results blobs of matches stored in an older blob version.
+// It is a one-time migration to run after a release changes the blob layout.
+func (s *Server) ReencodeBlobsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if isDryRunFromContext(r) {
+			log.Info("[Dry Run] Would have re-encoded match blobs")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, "Would re-encode match blobs.")
+			return
+		}
+
+		count, err := s.Store.ReencodeMatchBlobs()
+		if err != nil {
+			log.Error("Failed to re-encode match blobs", "error", err)
+			http.Error(w, fmt.Sprintf("Failed to re-encode match blobs: %s", err), http.StatusInternalServerError)
+			return
+		}
+		log.Info("Re-encoded match blobs", "count", count)
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "Re-encoded blobs of %d matches.", count)
+	}
+}
+
+// LeaderboardHandler returns a handler that serves the player statistics leaderboard.
+func (s *Server) LeaderboardHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sortBy, err := club.ParseStatsSortBy(r.URL.Query().Get("sort"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		competitiveOnly := r.URL.Query().Get("competitive") == "true"
+		allTime := r.URL.Query().Get("all_time") == "true"
+		stats, err := s.Store.GetPlayerStats(s.Cfg.MinMatchesForLeaderboard, sortBy, competitiveOnly, allTime)
+		if err != nil {
+			http.Error(w, "Failed to get player stats", http.StatusInternalServerError)
+			log.Error("Failed to get player stats from store", "error", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(stats); err != nil {
+			log.Error("Failed to encode player stats to JSON", "error", err)
+		}
+	}
+}
+
+// SpendReportHandler returns each player's share of match prices. An optional 'from'/'to' range
+// (YYYY-MM-DD, both inclusive) limits the matches by start date; without it all priced matches count.
+func (s *Server) SpendReportHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var from, to int64
+		if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+			parsedFrom, err := time.Parse("2006-01-02", fromStr)
+			if err != nil {
+				http.Error(w, "Invalid 'from' date, expected YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			from = parsedFrom.Unix()
+		}
+		if toStr := r.URL.Query().Get("to"); toStr != "" {
+			parsedTo, err := time.Parse("2006-01-02", toStr)
+			if err != nil {
+				http.Error(w, "Invalid 'to' date, expected YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			to = parsedTo.AddDate(0, 0, 1).Unix() - 1
+		}
+		if to > 0 && from > to {
+			http.Error(w, "'from' must not be after 'to'", http.StatusBadRequest)
+			return
+		}
+
+		spend, err := s.Store.GetPlayerSpend(from, to)
+		if err != nil {
+			http.Error(w, "Failed to get player spend", http.StatusInternalServerError)
+			log.Error("Failed to get player spend from store", "error", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(spend); err != nil {
+			log.Error("Failed to encode player spend to JSON", "error", err)
+		}
+	}
+}
+
+// respondWithSlackMsg is a helper to format and write a Slack message as an HTTP response.
+func respondWithSlackMsg(w http.ResponseWriter, msg slack.Message) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(msg); err != nil {
+		log.Error("Failed to encode slack message to JSON", "error", err)
+	}
+}
+
+// LeaderboardCommandHandler returns a handler for the /leaderboard Slack command.
+// The command text may name a sort option, e.g. "/leaderboard winpct", "competitive" to leave out
+// practice matches, e.g. "/leaderboard competitive sets", and "alltime" to count past seasons too,
+// e.g. "/leaderboard alltime competitive".
+func (s *Server) LeaderboardCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		allTime, text := cutAllTime(r.FormValue("text"))
+		competitiveOnly, text := cutCompetitive(text)
+		sortBy, err := club.ParseStatsSortBy(text)
+		if err != nil {
+			respondWithSlackText(w, err.Error())
+			return
+		}
+		s.respondToSlackCommand(w, r, func() (slack.Message, error) {
+			stats, err := s.Store.GetPlayerStats(s.Cfg.MinMatchesForLeaderboard, sortBy, competitiveOnly, allTime)
+			if err != nil {
+				log.Error("Failed to get player stats from store", "error", err)
+				return slack.Message{}, errors.New("Failed to get player stats")
+			}
+
+			msg, err := s.Notifier.FormatLeaderboardResponse(stats)
+			if err != nil {
+				log.Error("Failed to format leaderboard", "error", err)
+				return slack.Message{}, errors.New("Failed to format leaderboard")
+			}
+			return toSlackMessage(msg)
+		})
+	}
+}
+
+// PlayerStatsCommandHandler returns a handler for the /player-stats Slack command.
+// Prefixing the name with "competitive" counts competitive matches only. Instead of a name, the command takes a
+// Slack mention, e.g. "/player-stats @morten", which shows the stats of the player linked to that Slack user.
+func (s *Server) PlayerStatsCommandHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if err := r.ParseForm(); err != nil {
 			http.Error(w, "Error parsing form", http.StatusBadRequest)
 			return
 		}
-		playerName := r.FormValue("text")
+		competitiveOnly, playerName := cutCompetitive(r.FormValue("text"))
 		if playerName == "" {
 			http.Error(w, "Player name is required.", http.StatusBadRequest)
 			return
@@ -430,18 +931,68 @@ func (s *Server) PlayerStatsCommandHandler() http.HandlerFunc {
 
 		log.Info("Received player stats command", "player", playerName)
 
-		stats, err := s.Store.GetPlayerStatsByName(playerName)
-		var msg any
+		s.respondToSlackCommand(w, r, func() (slack.Message, error) {
+			if parts := slackUserMentionPattern.FindStringSubmatch(playerName); parts != nil {
+				return s.mentionedPlayerStats(parts[1], competitiveOnly)
+			}
+
+			stats, err := s.Store.GetPlayerStatsByName(playerName, competitiveOnly)
+			var msg any
+			if err != nil {
+				log.Warn("Could not find player stats", "player", playerName, "error", err)
+				msg, err = s.Notifier.FormatPlayerNotFoundResponse(playerName)
+			} else {
+				msg, err = s.Notifier.FormatPlayerStatsResponse(stats, playerName)
+			}
+
+			if err != nil {
+				log.Error("Failed to format player stats", "error", err)
+				return slack.Message{}, errors.New("Failed to format player stats")
+			}
+			return toSlackMessage(msg)
+		})
+	}
+}
+
+// mentionedPlayerStats builds the /player-stats response for the player linked to a mentioned Slack user.
+func (s *Server) mentionedPlayerStats(slackUserID string, competitiveOnly bool) (slack.Message, error) {
+	player, found, err := s.Store.GetPlayerBySlackUserID(slackUserID)
+	if err != nil {
+		log.Error("Failed to get player by Slack user", "error", err, "slackUserID", slackUserID)
+		return slack.Message{}, errors.New("Failed to look up player")
+	}
+	if !found {
+		text := fmt.Sprintf("<@%s> is not linked to a Playtomic player yet. Ask an admin to link them with `/map`, or look them up by name.", slackUserID)
+		return slack.Message{Msg: slack.Msg{ResponseType: slack.ResponseTypeEphemeral, Text: text}}, nil
+	}
+
+	stats, err := s.Store.GetPlayerStatsByID(player.ID, competitiveOnly)
+	if err != nil {
+		log.Error("Failed to get player stats", "error", err, "playerID", player.ID)
+		return slack.Message{}, errors.New("Failed to get player stats")
+	}
+	msg, err := s.Notifier.FormatPlayerStatsResponse(stats, player.Name)
+	if err != nil {
+		log.Error("Failed to format player stats", "error", err)
+		return slack.Message{}, errors.New("Failed to format player stats")
+	}
+	return toSlackMessage(msg)
+}
+
+// LevelLeaderboardCommandHandler returns a handler for the /level-leaderboard Slack command.
+func (s *Server) LevelLeaderboardCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		players, err := s.Store.GetPlayersSortedByLevel()
 		if err != nil {
-			log.Warn("Could not find player stats", "player", playerName, "error", err)
-			msg, err = s.Notifier.FormatPlayerNotFoundResponse(playerName)
-		} else {
-			msg, err = s.Notifier.FormatPlayerStatsResponse(stats, playerName)
+			http.Error(w, "Failed to get players", http.StatusInternalServerError)
+			log.Error("Failed to get players sorted by level from store", "error", err)
+			return
 		}
 
+		msg, err := s.Notifier.FormatLevelLeaderboardResponse(players)
 		if err != nil {
-			http.Error(w, "Failed to format player stats", http.StatusInternalServerError)
-			log.Error("Failed to format player stats", "error", err)
+			http.Error(w, "Failed to format level leaderboard", http.StatusInternalServerError)
+			log.Error("Failed to format level leaderboard", "error", err)
 			return
 		}
 
@@ -451,24 +1002,71 @@ func (s *Server) PlayerStatsCommandHandler() http.HandlerFunc {
 			log.Error("Failed to cast message to slack.Message")
 			return
 		}
+
 		respondWithSlackMsg(w, slackMsg)
 	}
 }
 
-// LevelLeaderboardCommandHandler returns a handler for the /level-leaderboard Slack command.
-func (s *Server) LevelLeaderboardCommandHandler() http.HandlerFunc {
+// BallBringerCommandHandler returns a handler for the /balls Slack command.
+func (s *Server) BallBringerCommandHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		players, err := s.Store.GetPlayersSortedByLevel()
+		players, err := s.Store.GetBallBringerCounts()
+		if err != nil {
+			http.Error(w, "Failed to get ball bringer counts", http.StatusInternalServerError)
+			log.Error("Failed to get ball bringer counts from store", "error", err)
+			return
+		}
+
+		msg, err := s.Notifier.FormatBallBringerResponse(players)
+		if err != nil {
+			http.Error(w, "Failed to format ball bringer standings", http.StatusInternalServerError)
+			log.Error("Failed to format ball bringer standings", "error", err)
+			return
+		}
+
+		slackMsg, ok := msg.(slack.Message)
+		if !ok {
+			http.Error(w, "Invalid message format for Slack", http.StatusInternalServerError)
+			log.Error("Failed to cast message to slack.Message")
+			return
+		}
+
+		respondWithSlackMsg(w, slackMsg)
+	}
+}
+
+// RosterCommandHandler returns a handler for the /roster Slack command, listing all players by name.
+// Large rosters are split into pages, e.g. "/roster 2" shows the second page.
+func (s *Server) RosterCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		page := 1
+		if text := strings.TrimSpace(r.FormValue("text")); text != "" {
+			parsed, err := strconv.Atoi(text)
+			if err != nil || parsed < 1 {
+				respondWithSlackText(w, "Usage: /roster [page]")
+				return
+			}
+			page = parsed
+		}
+
+		players, err := s.Store.GetAllPlayers()
 		if err != nil {
 			http.Error(w, "Failed to get players", http.StatusInternalServerError)
-			log.Error("Failed to get players sorted by level from store", "error", err)
+			log.Error("Failed to get players from store", "error", err)
 			return
 		}
+		sort.Slice(players, func(i, j int) bool {
+			return strings.ToLower(players[i].Name) < strings.ToLower(players[j].Name)
+		})
 
-		msg, err := s.Notifier.FormatLevelLeaderboardResponse(players)
+		msg, err := s.Notifier.FormatRosterResponse(players, page)
 		if err != nil {
-			http.Error(w, "Failed to format level leaderboard", http.StatusInternalServerError)
-			log.Error("Failed to format level leaderboard", "error", err)
+			http.Error(w, "Failed to format roster", http.StatusInternalServerError)
+			log.Error("Failed to format roster", "error", err)
 			return
 		}
 
@@ -483,6 +1081,916 @@ func (s *Server) LevelLeaderboardCommandHandler() http.HandlerFunc {
 	}
 }
 
+// formLength is the number of recent results shown by the /form command.
+const formLength = 5
+
+// FormCommandHandler returns a handler for the /form Slack command, showing a player's last results as win/loss emoji.
+// The player is found by name the same way as for /player-stats.
+func (s *Server) FormCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		playerName := strings.TrimSpace(r.FormValue("text"))
+		if playerName == "" {
+			respondWithSlackText(w, "Usage: /form <name>")
+			return
+		}
+
+		s.respondToSlackCommand(w, r, func() (slack.Message, error) {
+			players, err := s.Store.FindPlayersByName(playerName)
+			if err != nil {
+				log.Error("Failed to find players by name", "name", playerName, "error", err)
+				return slack.Message{}, errors.New("Failed to look up player")
+			}
+			var msg any
+			if len(players) == 0 {
+				msg, err = s.Notifier.FormatPlayerNotFoundResponse(playerName)
+			} else {
+				var form []bool
+				form, err = s.Store.GetRecentForm(players[0].ID, formLength)
+				if err != nil {
+					log.Error("Failed to get recent form", "playerID", players[0].ID, "error", err)
+					return slack.Message{}, errors.New("Failed to get recent form")
+				}
+				msg, err = s.Notifier.FormatFormResponse(players[0].Name, form)
+			}
+			if err != nil {
+				log.Error("Failed to format form", "error", err)
+				return slack.Message{}, errors.New("Failed to format form")
+			}
+			return toSlackMessage(msg)
+		})
+	}
+}
+
+// LeaderboardDiffCommandHandler returns a handler for the /leaderboard-diff Slack command.
+// It compares the players' weekly stats between two periods, e.g. "/leaderboard-diff last-week this-week".
+func (s *Server) LeaderboardDiffCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		args := strings.Fields(r.FormValue("text"))
+		if len(args) != 2 {
+			respondWithSlackText(w, "Usage: /leaderboard-diff last-week this-week (or YYYY-MM-DD..YYYY-MM-DD ranges)")
+			return
+		}
+		now := time.Now()
+		var periods [2][2]int64
+		for i, arg := range args {
+			from, to, err := parseStatsPeriod(arg, now)
+			if err != nil {
+				respondWithSlackText(w, err.Error())
+				return
+			}
+			periods[i] = [2]int64{from, to}
+		}
+
+		s.respondToSlackCommand(w, r, func() (slack.Message, error) {
+			before, err := s.Store.GetStatsForRange(periods[0][0], periods[0][1])
+			if err != nil {
+				log.Error("Failed to get stats for range", "period", args[0], "error", err)
+				return slack.Message{}, errors.New("Failed to get player stats")
+			}
+			after, err := s.Store.GetStatsForRange(periods[1][0], periods[1][1])
+			if err != nil {
+				log.Error("Failed to get stats for range", "period", args[1], "error", err)
+				return slack.Message{}, errors.New("Failed to get player stats")
+			}
+			msg, err := s.Notifier.FormatLeaderboardDiffResponse(args[0], args[1], club.DiffPlayerStats(before, after))
+			if err != nil {
+				log.Error("Failed to format leaderboard diff", "error", err)
+				return slack.Message{}, errors.New("Failed to format leaderboard diff")
+			}
+			return toSlackMessage(msg)
+		})
+	}
+}
+
+// parseStatsPeriod turns a /leaderboard-diff period into a Unix time range. A period is "this-week", "last-week"
+// or a "YYYY-MM-DD..YYYY-MM-DD" range; weeks run Monday to Sunday in UTC.
+func parseStatsPeriod(period string, now time.Time) (int64, int64, error) {
+	now = now.UTC()
+	thisWeek := time.Date(now.Year(), now.Month(), now.Day()-(int(now.Weekday())+6)%7, 0, 0, 0, 0, time.UTC)
+	switch period {
+	case "this-week":
+		return thisWeek.Unix(), now.Unix(), nil
+	case "last-week":
+		return thisWeek.AddDate(0, 0, -7).Unix(), thisWeek.Unix() - 1, nil
+	}
+	fromStr, toStr, ok := strings.Cut(period, "..")
+	if !ok {
+		return 0, 0, fmt.Errorf("Unknown period %q, expected this-week, last-week or YYYY-MM-DD..YYYY-MM-DD", period)
+	}
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid start date %q, expected YYYY-MM-DD", fromStr)
+	}
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid end date %q, expected YYYY-MM-DD", toStr)
+	}
+	if to.Before(from) {
+		return 0, 0, fmt.Errorf("Period %q ends before it starts", period)
+	}
+	return from.Unix(), to.Unix(), nil
+}
+
+// ReportResultCommandHandler returns a handler for the /report-result Slack command.
+// It lets a player in a match enter the score, e.g. "/report-result <matchID> 6-4,6-3", when Playtomic is slow to confirm it.
+// Scores are read from the reporting player's team's perspective.
+func (s *Server) ReportResultCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		dryRun := isDryRunFromContext(r)
+		args := strings.Fields(r.FormValue("text"))
+		if len(args) < 2 {
+			respondWithSlackText(w, "Usage: /report-result <matchID> <set scores>, e.g. /report-result abc123 6-4,6-3")
+			return
+		}
+		matchID := args[0]
+		slackUserID := r.FormValue("user_id")
+
+		sets, err := playtomic.ParseSetScores(strings.Join(args[1:], ""))
+		if err != nil {
+			respondWithSlackText(w, fmt.Sprintf("Could not read the score: %s", err))
+			return
+		}
+
+		match, err := s.Store.GetMatch(matchID)
+		if err != nil || match == nil {
+			log.Warn("Could not find match for reported result", "matchID", matchID, "error", err)
+			respondWithSlackText(w, fmt.Sprintf("Could not find match %s.", matchID))
+			return
+		}
+
+		if match.GameStatus == playtomic.GameStatusCanceled {
+			respondWithSlackText(w, fmt.Sprintf("Match %s was canceled, so it has no result to report.", matchID))
+			return
+		}
+		if match.End > time.Now().Unix() {
+			respondWithSlackText(w, fmt.Sprintf("Match %s hasn't been played yet. Report its result once it has ended.", matchID))
+			return
+		}
+
+		// Stats are counted from the result once it is posted, so it can't be changed any more after that.
+		switch match.ProcessingStatus {
+		case playtomic.StatusResultNotified, playtomic.StatusStatsUpdated, playtomic.StatusCompleted:
+			respondWithSlackText(w, fmt.Sprintf("The result of match %s has already been posted and counted, so it can't be reported any more.", matchID))
+			return
+		}
+
+		player, found, err := s.Store.GetPlayerBySlackUserID(slackUserID)
+		if err != nil {
+			http.Error(w, "Failed to look up player", http.StatusInternalServerError)
+			log.Error("Failed to get player by Slack user", "error", err, "slackUserID", slackUserID)
+			return
+		}
+		var team playtomic.Team
+		ok := false
+		if found {
+			team, ok = match.PlayerTeam(player.ID)
+		}
+		if !ok {
+			log.Warn("Rejected result reported by non-participant", "matchID", matchID, "slackUserID", slackUserID)
+			respondWithSlackText(w, "Only players in the match can report its result. If you played it, ask an admin to link your Slack user to your player.")
+			return
+		}
+
+		if err := match.ApplyManualResult(team.ID, sets); err != nil {
+			respondWithSlackText(w, fmt.Sprintf("Could not record the result: %s", err))
+			return
+		}
+
+		log.Info("Received manual match result", "matchID", matchID, "playerID", player.ID, "sets", sets)
+		if !dryRun {
+			if err := s.Store.UpsertMatch(match); err != nil {
+				http.Error(w, "Failed to save match result", http.StatusInternalServerError)
+				log.Error("Failed to save manual match result", "error", err, "matchID", matchID)
+				return
+			}
+		} else {
+			log.Info("[Dry Run] Would have saved manual match result", "matchID", matchID)
+		}
+		s.Processor.ProcessMatch(match, dryRun)
+
+		respondWithSlackText(w, fmt.Sprintf("Thanks! The result for match %s has been recorded.", matchID))
+	}
+}
+
+// NextMatchCommandHandler returns a handler for the /next Slack command, which shows the caller's next upcoming match.
+func (s *Server) NextMatchCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		slackUserID := r.FormValue("user_id")
+
+		player, found, err := s.Store.GetPlayerBySlackUserID(slackUserID)
+		if err != nil {
+			http.Error(w, "Failed to look up player", http.StatusInternalServerError)
+			log.Error("Failed to get player by Slack user", "error", err, "slackUserID", slackUserID)
+			return
+		}
+		if !found {
+			respondWithSlackText(w, "Your Slack user is not linked to a Playtomic player yet. Ask an admin to link it, then try again.")
+			return
+		}
+
+		match, err := s.Store.GetNextMatchForPlayer(player.ID)
+		if err != nil {
+			http.Error(w, "Failed to get next match", http.StatusInternalServerError)
+			log.Error("Failed to get next match for player", "error", err, "playerID", player.ID)
+			return
+		}
+		if match == nil {
+			respondWithSlackText(w, "You have no upcoming matches.")
+			return
+		}
+
+		msg, err := s.Notifier.FormatNextMatchResponse(match, player.ID)
+		if err != nil {
+			http.Error(w, "Failed to format next match", http.StatusInternalServerError)
+			log.Error("Failed to format next match", "error", err)
+			return
+		}
+		slackMsg, ok := msg.(slack.Message)
+		if !ok {
+			http.Error(w, "Invalid message format for Slack", http.StatusInternalServerError)
+			log.Error("Failed to cast message to slack.Message")
+			return
+		}
+		respondWithSlackMsg(w, slackMsg)
+	}
+}
+
+// MatchStatusCommandHandler returns a handler for the /match-status Slack command. It explains where a match is in
+// the processing state machine, which helps answer why a booking or result has not been posted yet.
+func (s *Server) MatchStatusCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		matchID := strings.TrimSpace(r.FormValue("text"))
+		if matchID == "" {
+			respondWithSlackText(w, "Usage: /match-status <match id>")
+			return
+		}
+
+		match, err := s.Store.GetMatch(matchID)
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithSlackText(w, fmt.Sprintf("Match %s was not found.", matchID))
+			return
+		}
+		if err != nil {
+			http.Error(w, "Failed to get match", http.StatusInternalServerError)
+			log.Error("Failed to get match", "error", err, "matchID", matchID)
+			return
+		}
+		respondWithSlackText(w, formatMatchStatus(match))
+	}
+}
+
+// formatMatchStatus describes a match's processing status, when its notifications were sent and what is expected
+// to happen to it next.
+func formatMatchStatus(match *playtomic.PadelMatch) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Match %s is %s.\n", match.MatchID, match.ProcessingStatus)
+	fmt.Fprintf(&b, "Booking notification: %s\n", formatNotifiedAt(match.BookingNotifiedTs))
+	fmt.Fprintf(&b, "Result notification: %s\n", formatNotifiedAt(match.ResultNotifiedTs))
+	if match.DeletedAt != nil {
+		b.WriteString("The match has been deleted, so it is not processed any further.")
+		return b.String()
+	}
+	b.WriteString("Next: " + nextTransition(match))
+	return b.String()
+}
+
+func formatNotifiedAt(ts *int64) string {
+	if ts == nil {
+		return "not sent"
+	}
+	return "sent " + time.Unix(*ts, 0).UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// nextTransition explains in plain language what the processor will do with a match in its current status.
+func nextTransition(match *playtomic.PadelMatch) string {
+	switch match.ProcessingStatus {
+	case playtomic.StatusNew:
+		return "a ball bringer will be assigned on the next processing run, unless the match is already played or canceled."
+	case playtomic.StatusAssigningBallBringer:
+		return "waiting for the ball bringer assignment to finish. If this takes long, the assignment message may have been lost."
+	case playtomic.StatusBallBoyAssigned:
+		return "the booking notification will be posted on the next processing run outside quiet hours, once the match is within the booking lead time."
+	case playtomic.StatusBookingNotified:
+		if match.GameStatus != playtomic.GameStatusPlayed {
+			return fmt.Sprintf("waiting for the match to be played (game status %s).", match.GameStatus)
+		}
+		return fmt.Sprintf("waiting for the result to be confirmed in Playtomic (results status %s).", match.ResultsStatus)
+	case playtomic.StatusResultAvailable:
+		return "the result will be posted on the next processing run."
+	case playtomic.StatusResultNotified:
+		return "player stats will be updated on the next processing run."
+	case playtomic.StatusStatsUpdated:
+		return "the match will be marked as completed on the next processing run."
+	case playtomic.StatusCompleted:
+		return "nothing, processing is complete."
+	case playtomic.StatusNeedsReview:
+		return "nothing until an admin requeues the match with `/requeue`."
+	default:
+		return "unknown, the processing status is not recognized."
+	}
+}
+
+// NotificationsCommandHandler lets players turn the bot's direct messages to them off and on again.
+// Without an argument it tells them their current setting.
+func (s *Server) NotificationsCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		slackUserID := r.FormValue("user_id")
+
+		player, found, err := s.Store.GetPlayerBySlackUserID(slackUserID)
+		if err != nil {
+			http.Error(w, "Failed to look up player", http.StatusInternalServerError)
+			log.Error("Failed to get player by Slack user", "error", err, "slackUserID", slackUserID)
+			return
+		}
+		if !found {
+			respondWithSlackText(w, "Your Slack user is not linked to a Playtomic player yet. Ask an admin to link it, then try again.")
+			return
+		}
+
+		var optOut bool
+		switch strings.ToLower(strings.TrimSpace(r.FormValue("text"))) {
+		case "off":
+			optOut = true
+		case "on":
+			optOut = false
+		case "":
+			if s.Store.IsDMOptedOut(player.ID) {
+				respondWithSlackText(w, "Direct messages are off. Use `/notifications on` to turn them back on.")
+			} else {
+				respondWithSlackText(w, "Direct messages are on. Use `/notifications off` to turn them off.")
+			}
+			return
+		default:
+			respondWithSlackText(w, "Usage: /notifications [on|off]")
+			return
+		}
+
+		if isDryRunFromContext(r) {
+			log.Info("[Dry Run] Would have updated direct message opt-out", "playerID", player.ID, "optOut", optOut)
+		} else if err := s.Store.SetDMOptOut(player.ID, optOut); err != nil {
+			http.Error(w, "Failed to update notification preference", http.StatusInternalServerError)
+			log.Error("Failed to set direct message opt-out", "error", err, "playerID", player.ID)
+			return
+		}
+		if optOut {
+			respondWithSlackText(w, "Direct messages are now off. You'll still see everything posted in the channel.")
+		} else {
+			respondWithSlackText(w, "Direct messages are now on.")
+		}
+	}
+}
+
+// SlackEventsHandler returns a handler for the Slack Events API. It answers the URL verification challenge and
+// publishes a user's home tab when they open it.
+func (s *Server) SlackEventsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Error reading body", http.StatusBadRequest)
+			return
+		}
+		// The request signature is checked by VerifySlackSignature, so the deprecated verification token is not used.
+		event, err := slackevents.ParseEvent(json.RawMessage(body), slackevents.OptionNoVerifyToken())
+		if err != nil {
+			log.Error("Failed to parse Slack event", "error", err)
+			http.Error(w, "Invalid event", http.StatusBadRequest)
+			return
+		}
+
+		switch event.Type {
+		case slackevents.URLVerification:
+			var challenge slackevents.ChallengeResponse
+			if err := json.Unmarshal(body, &challenge); err != nil {
+				http.Error(w, "Invalid challenge", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(challenge.Challenge))
+		case slackevents.CallbackEvent:
+			homeOpened, ok := event.InnerEvent.Data.(*slackevents.AppHomeOpenedEvent)
+			if !ok || homeOpened.Tab != "home" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			// Slack expects an answer within three seconds, so the view is published in the background.
+			isDryRun := isDryRunFromContext(r)
+			done := s.Processor.Track()
+			go func() {
+				defer done()
+				view, err := s.homeView(homeOpened.User)
+				if err != nil {
+					log.Error("Failed to build home view", "error", err, "slackUserID", homeOpened.User)
+					return
+				}
+				if err := s.Notifier.PublishHomeView(homeOpened.User, view, isDryRun); err != nil {
+					log.Error("Failed to publish home view", "error", err, "slackUserID", homeOpened.User)
+				}
+			}()
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+}
+
+// homeView gathers the stats, next match and form of the player linked to the Slack user.
+func (s *Server) homeView(slackUserID string) (notifier.HomeView, error) {
+	player, found, err := s.Store.GetPlayerBySlackUserID(slackUserID)
+	if err != nil {
+		return notifier.HomeView{}, fmt.Errorf("failed to look up player: %w", err)
+	}
+	if !found {
+		return notifier.HomeView{}, nil
+	}
+
+	view := notifier.HomeView{Player: player}
+	if view.Stats, err = s.Store.GetPlayerStatsByID(player.ID, false); err != nil {
+		return notifier.HomeView{}, fmt.Errorf("failed to get player stats: %w", err)
+	}
+	if view.NextMatch, err = s.Store.GetNextMatchForPlayer(player.ID); err != nil {
+		return notifier.HomeView{}, fmt.Errorf("failed to get next match: %w", err)
+	}
+	if view.Form, err = s.Store.GetRecentForm(player.ID, formLength); err != nil {
+		return notifier.HomeView{}, fmt.Errorf("failed to get recent form: %w", err)
+	}
+	return view, nil
+}
+
+// cutCompetitive reports whether slash command text starts with the "competitive" keyword and returns the rest of the text.
+func cutCompetitive(text string) (bool, string) {
+	return cutKeyword(text, "competitive")
+}
+
+// cutAllTime reports whether slash command text starts with the "alltime" keyword and returns the rest of the text.
+func cutAllTime(text string) (bool, string) {
+	return cutKeyword(text, "alltime")
+}
+
+// cutKeyword reports whether slash command text starts with the keyword, ignoring case, and returns the rest of the text.
+func cutKeyword(text, keyword string) (bool, string) {
+	fields := strings.Fields(text)
+	if len(fields) > 0 && strings.EqualFold(fields[0], keyword) {
+		return true, strings.Join(fields[1:], " ")
+	}
+	return false, strings.TrimSpace(text)
+}
+
+// normalizeName lowercases a name and strips everything but letters and digits, so "morten.voss" matches "Morten Voss".
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// respondWithSlackText responds to a Slack command with a plain text message only visible to the caller.
+func respondWithSlackText(w http.ResponseWriter, text string) {
+	respondWithSlackMsg(w, slack.Message{Msg: slack.Msg{ResponseType: slack.ResponseTypeEphemeral, Text: text}})
+}
+
+// responseURLClient posts delayed responses to Slack's response_url.
+var responseURLClient = &http.Client{Timeout: 10 * time.Second}
+
+// respondDelayed posts a message to a slash command's response_url, for responses that are sent after the
+// command has been acknowledged. Slack accepts these for 30 minutes after the command.
+func respondDelayed(responseURL string, msg slack.Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delayed response: %w", err)
+	}
+	resp, err := responseURLClient.Post(responseURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post delayed response: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("delayed response rejected with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// respondToSlackCommand sends the response built by build. With delayed responses enabled, the command is
+// acknowledged straight away and the response is built in the background and posted to the response_url.
+// Errors returned by build are shown to the user, so they should be short and not leak internals.
+func (s *Server) respondToSlackCommand(w http.ResponseWriter, r *http.Request, build func() (slack.Message, error)) {
+	responseURL := r.FormValue("response_url")
+	if !s.Cfg.Slack.DelayedResponses || responseURL == "" {
+		msg, err := build()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		respondWithSlackMsg(w, msg)
+		return
+	}
+
+	done := s.Processor.Track()
+	go func() {
+		defer done()
+		msg, err := build()
+		if err != nil {
+			msg = slack.Message{Msg: slack.Msg{ResponseType: slack.ResponseTypeEphemeral, Text: err.Error()}}
+		}
+		if err := respondDelayed(responseURL, msg); err != nil {
+			log.Error("Failed to send delayed Slack response", "error", err)
+		}
+	}()
+	respondWithSlackText(w, "Working on it…")
+}
+
+// toSlackMessage converts a formatted notifier response to a Slack message.
+func toSlackMessage(msg any) (slack.Message, error) {
+	slackMsg, ok := msg.(slack.Message)
+	if !ok {
+		log.Error("Failed to cast message to slack.Message")
+		return slack.Message{}, errors.New("Invalid message format for Slack")
+	}
+	return slackMsg, nil
+}
+
+// runSlackCommandAsync acknowledges an admin slash command straight away and runs the work in the background,
+// posting its summary to the command's response_url. This keeps slow work clear of Slack's 3 second timeout.
+func (s *Server) runSlackCommandAsync(w http.ResponseWriter, r *http.Request, ack string, work func() string) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Error parsing form", http.StatusBadRequest)
+		return
+	}
+	responseURL := r.FormValue("response_url")
+	if responseURL == "" {
+		http.Error(w, "Missing response_url", http.StatusBadRequest)
+		return
+	}
+
+	done := s.Processor.Track()
+	go func() {
+		defer done()
+		summary := work()
+		msg := slack.Message{Msg: slack.Msg{ResponseType: slack.ResponseTypeEphemeral, Text: summary}}
+		if err := respondDelayed(responseURL, msg); err != nil {
+			log.Error("Failed to send delayed Slack response", "error", err)
+		}
+	}()
+	respondWithSlackText(w, ack)
+}
+
+// RefreshCommandHandler returns a handler for the admin /refresh Slack command, which fetches recent matches from Playtomic.
+func (s *Server) RefreshCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		dryRun := isDryRunFromContext(r)
+		s.runSlackCommandAsync(w, r, "Fetching matches from Playtomic…", func() string {
+			s.Metrics.IncFetcherRuns()
+			found, saved, err := s.fetchMatches(time.Now().AddDate(0, 0, -1), time.Time{}, dryRun)
+			if err != nil {
+				return fmt.Sprintf("Fetching matches failed: %s", err)
+			}
+			return fmt.Sprintf("Fetched %d matches from Playtomic, %d of them club matches.", found, saved)
+		})
+	}
+}
+
+// ProcessCommandHandler returns a handler for the admin /process Slack command, which runs match processing.
+func (s *Server) ProcessCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		dryRun := isDryRunFromContext(r)
+		s.runSlackCommandAsync(w, r, "Processing matches…", func() string {
+			s.Processor.ProcessMatches(dryRun)
+			return "Match processing completed."
+		})
+	}
+}
+
+// UndoLastCommandHandler returns a handler for the admin /undo-last Slack command. It reverts the stats of the
+// given match, or of the most recently played match with stats applied, and marks the match for review.
+func (s *Server) UndoLastCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		dryRun := isDryRunFromContext(r)
+
+		matchID := strings.TrimSpace(r.FormValue("text"))
+		if matchID == "" {
+			lastID, found, err := s.Store.GetLastStatsAppliedMatchID()
+			if err != nil {
+				http.Error(w, "Failed to find the last match", http.StatusInternalServerError)
+				log.Error("Failed to get last match with applied stats", "error", err)
+				return
+			}
+			if !found {
+				respondWithSlackText(w, "There are no match stats to undo.")
+				return
+			}
+			matchID = lastID
+		}
+
+		if dryRun {
+			log.Info("[Dry Run] Would have undone match stats", "matchID", matchID)
+			respondWithSlackText(w, fmt.Sprintf("[Dry Run] Would have undone the stats for match %s.", matchID))
+			return
+		}
+		if err := s.Store.UndoMatchStats(matchID); err != nil {
+			if errors.Is(err, club.ErrStatsNotApplied) {
+				respondWithSlackText(w, fmt.Sprintf("The stats for match %s are not counted, so there is nothing to undo.", matchID))
+				return
+			}
+			if errors.Is(err, club.ErrStatsArchived) {
+				respondWithSlackText(w, fmt.Sprintf("Match %s was counted in an earlier season, so its stats are archived and can't be undone.", matchID))
+				return
+			}
+			http.Error(w, "Failed to undo match stats", http.StatusInternalServerError)
+			log.Error("Failed to undo match stats", "error", err, "matchID", matchID)
+			return
+		}
+		log.Info("Match stats undone by admin", "matchID", matchID, "user", r.FormValue("user_id"))
+		respondWithSlackText(w, fmt.Sprintf("Undid the stats for match %s. It is marked for review and will not be processed again until an admin runs `/requeue %s`.", matchID, matchID))
+	}
+}
+
+// RequeueCommandHandler returns a handler for the admin /requeue Slack command. It moves a match marked for review
+// back into processing, e.g. after /undo-last or once an incomplete lineup has been sorted out.
+func (s *Server) RequeueCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		matchID := strings.TrimSpace(r.FormValue("text"))
+		if matchID == "" {
+			respondWithSlackText(w, "Usage: `/requeue <match id>`")
+			return
+		}
+
+		if isDryRunFromContext(r) {
+			log.Info("[Dry Run] Would have requeued match", "matchID", matchID)
+			respondWithSlackText(w, fmt.Sprintf("[Dry Run] Would have requeued match %s.", matchID))
+			return
+		}
+		status, err := s.Store.RequeueMatch(matchID)
+		if err != nil {
+			if errors.Is(err, club.ErrMatchNotInReview) {
+				respondWithSlackText(w, fmt.Sprintf("Match %s is not marked for review, so there is nothing to requeue.", matchID))
+				return
+			}
+			http.Error(w, "Failed to requeue match", http.StatusInternalServerError)
+			log.Error("Failed to requeue match", "error", err, "matchID", matchID)
+			return
+		}
+		log.Info("Match requeued by admin", "matchID", matchID, "status", status, "user", r.FormValue("user_id"))
+		respondWithSlackText(w, fmt.Sprintf("Requeued match %s as %s. It is picked up on the next processing run.", matchID, status))
+	}
+}
+
+// MergePlayersCommandHandler returns a handler for the admin /merge Slack command. It merges a duplicate player
+// record into the player to keep, like the /players/merge endpoint.
+func (s *Server) MergePlayersCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		args := strings.Fields(r.FormValue("text"))
+		if len(args) != 2 {
+			respondWithSlackText(w, "Usage: `/merge <player id to keep> <duplicate player id>`")
+			return
+		}
+		keepID, mergeID := args[0], args[1]
+
+		if isDryRunFromContext(r) {
+			log.Info("[Dry Run] Would have merged players", "keepID", keepID, "mergeID", mergeID)
+			respondWithSlackText(w, fmt.Sprintf("[Dry Run] Would have merged player %s into %s.", mergeID, keepID))
+			return
+		}
+		if err := s.Store.MergePlayers(keepID, mergeID); err != nil {
+			log.Error("Failed to merge players", "error", err, "keepID", keepID, "mergeID", mergeID)
+			respondWithSlackText(w, fmt.Sprintf("Could not merge player %s into %s: %s", mergeID, keepID, err))
+			return
+		}
+		log.Info("Players merged by admin", "keepID", keepID, "mergeID", mergeID, "user", r.FormValue("user_id"))
+		respondWithSlackText(w, fmt.Sprintf("Merged player %s into %s.", mergeID, keepID))
+	}
+}
+
+// NewSeasonCommandHandler returns a handler for the admin /new-season Slack command. It archives the current
+// standings and resets the leaderboard for a new season.
+func (s *Server) NewSeasonCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+
+		if isDryRunFromContext(r) {
+			log.Info("[Dry Run] Would have started a new season")
+			respondWithSlackText(w, "[Dry Run] Would have archived the current standings and started a new season.")
+			return
+		}
+		season, err := s.Store.StartNewSeason()
+		if err != nil {
+			http.Error(w, "Failed to start a new season", http.StatusInternalServerError)
+			log.Error("Failed to start a new season", "error", err)
+			return
+		}
+		log.Info("New season started by admin", "seasonID", season.ID, "user", r.FormValue("user_id"))
+		respondWithSlackText(w, fmt.Sprintf("Season %d has started and the leaderboard is reset. The previous standings are archived; use `/leaderboard alltime` to include them.", season.ID))
+	}
+}
+
+// ResetBallBringerCountsCommandHandler returns a handler for the admin /reset-balls Slack command. It sets every
+// player's ball bringer count back to zero, or only the given player's, e.g. at the start of a season.
+func (s *Server) ResetBallBringerCountsCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		playerID := strings.TrimSpace(r.FormValue("text"))
+
+		if isDryRunFromContext(r) {
+			log.Info("[Dry Run] Would have reset ball bringer counts", "playerID", playerID)
+			if playerID == "" {
+				respondWithSlackText(w, "[Dry Run] Would have reset the ball bringer count of every player.")
+			} else {
+				respondWithSlackText(w, fmt.Sprintf("[Dry Run] Would have reset the ball bringer count of player %s.", playerID))
+			}
+			return
+		}
+
+		if playerID == "" {
+			if err := s.Store.ResetBallBringerCounts(); err != nil {
+				http.Error(w, "Failed to reset ball bringer counts", http.StatusInternalServerError)
+				log.Error("Failed to reset ball bringer counts", "error", err)
+				return
+			}
+			log.Info("Ball bringer counts reset by admin", "user", r.FormValue("user_id"))
+			respondWithSlackText(w, "Reset the ball bringer count of every player. The rotation starts over.")
+			return
+		}
+		if !s.Store.IsKnownPlayer(playerID) {
+			respondWithSlackText(w, fmt.Sprintf("Player %s was not found.", playerID))
+			return
+		}
+		if err := s.Store.ResetBallBringerCount(playerID); err != nil {
+			http.Error(w, "Failed to reset ball bringer count", http.StatusInternalServerError)
+			log.Error("Failed to reset ball bringer count", "error", err, "playerID", playerID)
+			return
+		}
+		log.Info("Ball bringer count reset by admin", "playerID", playerID, "user", r.FormValue("user_id"))
+		respondWithSlackText(w, fmt.Sprintf("Reset the ball bringer count of player %s.", playerID))
+	}
+}
+
+// slackMentionPattern matches a Slack user mention followed by text, e.g. "<@U123|morten> Morten Voss".
+var slackMentionPattern = regexp.MustCompile(`^<@([A-Z0-9]+)(?:\|[^>]*)?>\s+(.+)$`)
+
+// slackUserMentionPattern matches text that is only a Slack user mention, e.g. "<@U123|morten>".
+var slackUserMentionPattern = regexp.MustCompile(`^<@([A-Z0-9]+)(?:\|[^>]*)?>$`)
+
+// MapPlayerCommandHandler returns a handler for the admin /map Slack command, e.g. "/map @morten Morten Voss",
+// which links a Slack user to the player whose name matches. Ambiguous names are answered with the candidates.
+// Links are only ever made by admins, here or through POST /players/slack-user, so no mapping status or confidence
+// is recorded with them.
+func (s *Server) MapPlayerCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		dryRun := isDryRunFromContext(r)
+
+		parts := slackMentionPattern.FindStringSubmatch(strings.TrimSpace(r.FormValue("text")))
+		if parts == nil {
+			respondWithSlackText(w, "Usage: /map @slackuser Player Name")
+			return
+		}
+		slackUserID, playerName := parts[1], strings.TrimSpace(parts[2])
+
+		candidates, err := s.Store.FindPlayersByName(playerName)
+		if err != nil {
+			http.Error(w, "Failed to look up player", http.StatusInternalServerError)
+			log.Error("Failed to find players by name", "error", err, "name", playerName)
+			return
+		}
+		player, ok := pickPlayer(candidates, playerName)
+		if !ok {
+			if len(candidates) == 0 {
+				respondWithSlackText(w, fmt.Sprintf("Could not find a player matching %q.", playerName))
+				return
+			}
+			names := make([]string, 0, len(candidates))
+			for _, c := range candidates {
+				names = append(names, fmt.Sprintf("• %s (%s)", c.Name, c.ID))
+			}
+			respondWithSlackText(w, fmt.Sprintf("%q matches several players, please be more specific:\n%s", playerName, strings.Join(names, "\n")))
+			return
+		}
+
+		// A Slack user links to one player, so a link to another player is moved.
+		previous, linked, err := s.Store.GetPlayerBySlackUserID(slackUserID)
+		if err != nil {
+			http.Error(w, "Failed to look up player", http.StatusInternalServerError)
+			log.Error("Failed to get player by Slack user", "error", err, "slackUserID", slackUserID)
+			return
+		}
+		moved := ""
+		if linked && previous.ID != player.ID {
+			moved = fmt.Sprintf(" It was linked to %s (%s) before.", previous.Name, previous.ID)
+		}
+
+		if dryRun {
+			log.Info("[Dry Run] Would have mapped player to Slack user", "playerID", player.ID, "slackUserID", slackUserID)
+			respondWithSlackText(w, fmt.Sprintf("[Dry Run] Would have linked <@%s> to %s (%s).%s", slackUserID, player.Name, player.ID, moved))
+			return
+		}
+		if err := s.Store.SetSlackUserID(player.ID, slackUserID); err != nil {
+			http.Error(w, "Failed to map player", http.StatusInternalServerError)
+			log.Error("Failed to set slack user for player", "error", err, "playerID", player.ID)
+			return
+		}
+		log.Info("Player mapped to Slack user by admin", "playerID", player.ID, "slackUserID", slackUserID, "admin", r.FormValue("user_id"))
+		respondWithSlackText(w, fmt.Sprintf("Linked <@%s> to %s (%s).%s", slackUserID, player.Name, player.ID, moved))
+	}
+}
+
+// UnmappedPlayersCommandHandler returns a handler for the admin /unmapped Slack command, which lists the players
+// that are not linked to a Slack user yet, with their IDs, so admins can work through them with /map.
+func (s *Server) UnmappedPlayersCommandHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+
+		players, err := s.Store.GetUnmappedPlayers()
+		if err != nil {
+			http.Error(w, "Failed to get unmapped players", http.StatusInternalServerError)
+			log.Error("Failed to get unmapped players from store", "error", err)
+			return
+		}
+
+		msg, err := s.Notifier.FormatUnmappedPlayersResponse(players)
+		if err != nil {
+			http.Error(w, "Failed to format unmapped players", http.StatusInternalServerError)
+			log.Error("Failed to format unmapped players", "error", err)
+			return
+		}
+
+		slackMsg, ok := msg.(slack.Message)
+		if !ok {
+			http.Error(w, "Invalid message format for Slack", http.StatusInternalServerError)
+			log.Error("Failed to cast message to slack.Message")
+			return
+		}
+
+		respondWithSlackMsg(w, slackMsg)
+	}
+}
+
+// pickPlayer chooses the player a name refers to: the only candidate, or the one whose name matches exactly
+// when several names contain it.
+func pickPlayer(candidates []club.PlayerInfo, name string) (club.PlayerInfo, bool) {
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+	var exact []club.PlayerInfo
+	for _, c := range candidates {
+		if normalizeName(c.Name) == normalizeName(name) {
+			exact = append(exact, c)
+		}
+	}
+	if len(exact) == 1 {
+		return exact[0], true
+	}
+	return club.PlayerInfo{}, false
+}
+
 /*func (s *Server) SendInngestEventHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		data := map[string]interface{}{"matchId": "1234-556435", "test": "test"}
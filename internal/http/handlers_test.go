@@ -1,6 +1,9 @@
 package http
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -24,6 +27,7 @@ import (
 	"github.com/slack-go/slack"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
 
 	"bytes"
 	"crypto/hmac"
@@ -31,27 +35,30 @@ import (
 	"encoding/hex"
 )
 
-const testSlackSigningSecret = "test-signing-secret"
+const (
+	testSlackSigningSecret = "test-signing-secret"
+	testAdminAPIToken      = "test-admin-token"
+)
 
 // setupTestServer initializes a new server with a test database and mock clients.
 func setupTestServer(t *testing.T, playtomicClient playtomic.PlaytomicClient, notifier notifier.Notifier, slackSigningSecret string) (*Server, func()) {
 	t.Helper()
 
 	// For handlers that use the store, we need a real db connection for now.
-	db, dbTeardown, err := database.InitDB(":memory:", "", "", "../../migrations")
+	db, dbTeardown, err := database.InitDB(":memory:", "", "", "../../migrations", config.DBPoolConfig{})
 	require.NoError(t, err)
 
 	clubStore := club.New(db)
-	cfg := config.Config{Slack: config.SlackConfig{SigningSecret: slackSigningSecret}} // Use a default config with the provided secret
+	cfg := config.Config{Slack: config.SlackConfig{SigningSecret: slackSigningSecret}, AdminAPIToken: testAdminAPIToken, MigrationsDir: "../../migrations"} // Use a default config with the provided secret
 
 	reg := prometheus.NewRegistry()
 	metricsSvc := metrics.NewService(reg)
 	metricsHandler := metrics.NewMetricsHandler(reg)
 	pubsub := pubsub.NewMock("TEST")
-	proc := processor.New(clubStore, notifier, metricsSvc, pubsub)
+	proc := processor.New(clubStore, notifier, metricsSvc, pubsub, cfg)
 
 	// A real mux is needed to prevent the router from being nil.
-	server := NewServer(clubStore, metricsSvc, metricsHandler, cfg, playtomicClient, notifier, proc, nil)
+	server := NewServer(clubStore, db, metricsSvc, metricsHandler, cfg, playtomicClient, notifier, proc, nil)
 
 	teardown := func() {
 		if dbTeardown != nil {
@@ -66,12 +73,18 @@ func setupTestServer(t *testing.T, playtomicClient playtomic.PlaytomicClient, no
 // including the necessary signature and timestamp headers for verification.
 func createSlackCommandRequest(t *testing.T, targetURL string, form url.Values, signingSecret string) *http.Request {
 	t.Helper()
+	return createSignedSlackRequest(t, targetURL, "application/x-www-form-urlencoded", form.Encode(), signingSecret)
+}
+
+// createSignedSlackRequest creates a POST request signed the way Slack signs its requests.
+func createSignedSlackRequest(t *testing.T, targetURL, contentType, payload string, signingSecret string) *http.Request {
+	t.Helper()
 
-	body := strings.NewReader(form.Encode())
+	body := strings.NewReader(payload)
 	req, err := http.NewRequest("POST", targetURL, body)
 	require.NoError(t, err)
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Content-Type", contentType)
 
 	// Generate a timestamp within a reasonable range (e.g., +/- 5 minutes)
 	timestamp := time.Now().Unix()
@@ -97,6 +110,13 @@ func createSlackCommandRequest(t *testing.T, targetURL string, form url.Values,
 	return req
 }
 
+// adminRequest creates a request to an admin endpoint that carries the test admin API token.
+func adminRequest(method, target string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	req.Header.Set("Authorization", "Bearer "+testAdminAPIToken)
+	return req
+}
+
 func TestHealthCheckHandler(t *testing.T) {
 	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
 	defer teardown()
@@ -132,9 +152,139 @@ func TestListMembersHandler(t *testing.T) {
 	assert.Contains(t, rr.Body.String(), "player2")
 }
 
+func TestMergePlayersHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
+	defer teardown()
+
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+	server.Store.AddPlayer("p1-dupe", "Player One", 1.0)
+
+	t.Run("requires both player IDs", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/players/merge?keep=p1", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+testAdminAPIToken)
+
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("does not merge on dry run", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/players/merge?keep=p1&merge=p1-dupe&dry_run=true", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+testAdminAPIToken)
+
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.True(t, server.Store.IsKnownPlayer("p1-dupe"))
+	})
+
+	t.Run("merges players", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/players/merge?keep=p1&merge=p1-dupe", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+testAdminAPIToken)
+
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.True(t, server.Store.IsKnownPlayer("p1"))
+		assert.False(t, server.Store.IsKnownPlayer("p1-dupe"))
+	})
+
+	t.Run("returns error for unknown player", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/players/merge?keep=p1&merge=unknown", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+testAdminAPIToken)
+
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
+
+func TestProcessingStatusCountsHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
+	defer teardown()
+
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+	require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m1", OwnerID: "p1"}))
+	require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m2", OwnerID: "p1"}))
+	require.NoError(t, server.Store.UpdateProcessingStatus("m2", playtomic.StatusCompleted))
+
+	req, err := http.NewRequest("GET", "/stats/processing", nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var counts map[string]int
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &counts))
+	assert.Equal(t, 1, counts["NEW"])
+	assert.Equal(t, 1, counts["COMPLETED"])
+	assert.Equal(t, 0, counts["BOOKING_NOTIFIED"])
+	assert.Len(t, counts, len(playtomic.ProcessingStatuses))
+}
+
+func TestClubStatsHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
+	defer teardown()
+
+	getClubStats := func() ClubStats {
+		req, err := http.NewRequest("GET", "/stats/club", nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var stats ClubStats
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &stats))
+		return stats
+	}
+
+	assert.Equal(t, ClubStats{}, getClubStats(), "An empty club has no top player")
+
+	server.Store.AddPlayer("p1", "Morten Voss", 1.0)
+	server.Store.AddPlayer("p2", "Player Two", 1.0)
+	server.Store.AddPlayer("p3", "Player Three", 1.0)
+	now := time.Now()
+	played := &playtomic.PadelMatch{
+		MatchID:       "m1",
+		OwnerID:       "p1",
+		Start:         now.Unix(),
+		End:           now.Unix(),
+		GameStatus:    playtomic.GameStatusPlayed,
+		ResultsStatus: playtomic.ResultsStatusConfirmed,
+		Teams: []playtomic.Team{
+			{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p1", Name: "Morten Voss"}}},
+			{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p2", Name: "Player Two"}}},
+		},
+		Results: []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 3}}},
+	}
+	require.NoError(t, server.Store.UpsertMatch(played))
+	server.Store.UpdatePlayerStats(played)
+	require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m2", OwnerID: "p1", Start: now.AddDate(0, 0, -14).Unix()}))
+	require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m3", OwnerID: "p1", Start: now.Unix()}))
+	server.Store.ClearMatch("m3")
+
+	stats := getClubStats()
+	assert.Equal(t, 3, stats.TotalPlayers)
+	assert.Equal(t, 2, stats.TotalMatches, "Soft-deleted matches are not counted")
+	assert.Equal(t, 1, stats.MatchesThisWeek)
+	require.NotNil(t, stats.TopPlayer)
+	assert.Equal(t, "Morten Voss", stats.TopPlayer.PlayerName)
+	assert.Equal(t, 1, stats.TopPlayer.MatchesWon)
+}
+
 func TestPlayerStatsCommandHandler(t *testing.T) {
+	var formattedStats *club.PlayerStats
 	mockNotifier := notifier.NewMock()
 	mockNotifier.FormatPlayerStatsResponseFunc = func(stats *club.PlayerStats, query string) (any, error) {
+		formattedStats = stats
 		return slack.Message{}, nil
 	}
 	mockNotifier.FormatPlayerNotFoundResponseFunc = func(query string) (any, error) {
@@ -179,6 +329,34 @@ func TestPlayerStatsCommandHandler(t *testing.T) {
 		assert.Equal(t, http.StatusOK, rr.Code)
 	})
 
+	t.Run("resolves a mention to the mapped player", func(t *testing.T) {
+		require.NoError(t, server.Store.SetSlackUserID("p2", "U222"))
+		formattedStats = nil
+
+		req := createSlackCommandRequest(t, "/slack/command/player-stats", url.Values{"text": {"<@U222|two>"}}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		require.NotNil(t, formattedStats)
+		assert.Equal(t, "p2", formattedStats.PlayerID)
+		assert.Equal(t, 1, formattedStats.MatchesWon)
+	})
+
+	t.Run("explains a mention of an unmapped user", func(t *testing.T) {
+		formattedStats = nil
+
+		req := createSlackCommandRequest(t, "/slack/command/player-stats", url.Values{"text": {"<@U999>"}}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Nil(t, formattedStats)
+		var msg slack.Message
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &msg))
+		assert.Contains(t, msg.Text, "<@U999> is not linked to a Playtomic player yet")
+	})
+
 	t.Run("handles not found player", func(t *testing.T) {
 		form := url.Values{}
 		form.Set("text", "Unknown")
@@ -246,6 +424,66 @@ func TestPlayerStatsCommandHandler(t *testing.T) {
 	})
 }
 
+func TestLeaderboardHandler_Sort(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
+	defer teardown()
+
+	server.Store.AddPlayer("p1", "Busy", 1.0)
+	server.Store.AddPlayer("p2", "Efficient", 1.0)
+	for i := 0; i < 3; i++ {
+		server.Store.UpdatePlayerStats(&playtomic.PadelMatch{
+			MatchID: fmt.Sprintf("win-%d", i),
+			Teams: []playtomic.Team{
+				{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p1"}}},
+				{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p3"}}},
+			},
+		})
+	}
+	for i := 0; i < 2; i++ {
+		server.Store.UpdatePlayerStats(&playtomic.PadelMatch{
+			MatchID: fmt.Sprintf("loss-%d", i),
+			Teams: []playtomic.Team{
+				{ID: "t1", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p1"}}},
+				{ID: "t2", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p3"}}},
+			},
+		})
+	}
+	for i := 0; i < 2; i++ {
+		server.Store.UpdatePlayerStats(&playtomic.PadelMatch{
+			MatchID: fmt.Sprintf("perfect-%d", i),
+			Teams: []playtomic.Team{
+				{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p2"}}},
+				{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p3"}}},
+			},
+		})
+	}
+
+	leaderboard := func(query string) (int, []club.PlayerStats) {
+		req, err := http.NewRequest("GET", "/leaderboard"+query, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+		var stats []club.PlayerStats
+		if rr.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&stats))
+		}
+		return rr.Code, stats
+	}
+
+	code, stats := leaderboard("")
+	require.Equal(t, http.StatusOK, code)
+	require.GreaterOrEqual(t, len(stats), 2)
+	assert.Equal(t, "p1", stats[0].PlayerID)
+
+	code, stats = leaderboard("?sort=winpct")
+	require.Equal(t, http.StatusOK, code)
+	require.GreaterOrEqual(t, len(stats), 2)
+	assert.Equal(t, "p2", stats[0].PlayerID)
+
+	code, _ = leaderboard("?sort=bogus")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
 func TestLevelLeaderboardCommandHandler(t *testing.T) {
 	mockNotifier := notifier.NewMock()
 	mockNotifier.FormatLevelLeaderboardResponseFunc = func(players []club.PlayerInfo) (any, error) {
@@ -267,113 +505,1869 @@ func TestLevelLeaderboardCommandHandler(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
 
-func TestFetchMatchesHandler(t *testing.T) {
-	mockClient := playtomic.NewMockClient()
-	ownerID := "p1"
-	// Mock the GetMatches endpoint
-	mockClient.GetMatchesFunc = func(params *playtomic.SearchMatchesParams) ([]playtomic.MatchSummary, error) {
-		return []playtomic.MatchSummary{
-			{MatchID: "m1", OwnerID: &ownerID},
-			{MatchID: "m2", OwnerID: nil}, // No owner, should be skipped
-		}, nil
-	}
-	// Mock the GetSpecificMatch endpoint
-	mockClient.GetSpecificMatchFunc = func(matchID string) (playtomic.PadelMatch, error) {
-		return playtomic.PadelMatch{
-			MatchID: matchID,
-			OwnerID: ownerID,
-			Teams: []playtomic.Team{
-				{Players: []playtomic.Player{{UserID: "p1"}, {UserID: "p2"}}},
-				{Players: []playtomic.Player{{UserID: "p3"}, {UserID: "p4"}}},
-			},
-		}, nil
+func TestBallBringerCommandHandler(t *testing.T) {
+	var formattedPlayers []club.PlayerInfo
+	mockNotifier := notifier.NewMock()
+	mockNotifier.FormatBallBringerResponseFunc = func(players []club.PlayerInfo) (any, error) {
+		formattedPlayers = players
+		return slack.Message{}, nil
 	}
-
-	server, teardown := setupTestServer(t, mockClient, notifier.NewMock(), "")
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), mockNotifier, testSlackSigningSecret)
 	defer teardown()
-	// Add a known player so that a match is fetched
-	server.Store.AddPlayer("p1", "Player One", 1.0)
-	server.Store.AddPlayer("p2", "Player Two", 1.0)
-	server.Store.AddPlayer("p3", "Player Three", 1.0)
-	server.Store.AddPlayer("p4", "Player Four", 1.0)
 
-	req, err := http.NewRequest("GET", "/fetch", nil)
+	server.Store.AddPlayer("p1", "Player A", 1.0)
+	server.Store.AddPlayer("p2", "Player B", 1.0)
+	require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m1", OwnerID: "p1"}))
+	_, _, err := server.Store.AssignBallBringerAtomically("m1", []string{"p2"})
 	require.NoError(t, err)
 
+	req := createSlackCommandRequest(t, "/slack/command/balls", url.Values{}, testSlackSigningSecret)
+
 	rr := httptest.NewRecorder()
-	handler := server.FetchMatchesHandler()
-	handler.ServeHTTP(rr, req)
+	server.Router.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, formattedPlayers, 2)
+	assert.Equal(t, "Player B", formattedPlayers[0].Name)
+	assert.Equal(t, 1, formattedPlayers[0].BallBringerCount)
+	assert.Equal(t, "Player A", formattedPlayers[1].Name)
+}
 
-	// Verify that the correct match was upserted
-	matches, err := server.Store.GetAllMatches()
-	require.NoError(t, err)
-	require.Len(t, matches, 1)
-	assert.Equal(t, "m1", matches[0].MatchID)
-	assert.Equal(t, playtomic.StatusNew, matches[0].ProcessingStatus)
+func TestRosterCommandHandler(t *testing.T) {
+	var formattedPlayers []club.PlayerInfo
+	var formattedPage int
+	mockNotifier := notifier.NewMock()
+	mockNotifier.FormatRosterResponseFunc = func(players []club.PlayerInfo, page int) (any, error) {
+		formattedPlayers, formattedPage = players, page
+		return slack.Message{}, nil
+	}
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), mockNotifier, testSlackSigningSecret)
+	defer teardown()
+
+	server.Store.AddPlayer("p1", "bo", 1.0)
+	server.Store.AddPlayer("p2", "Anna", 2.0)
+
+	rr := httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, createSlackCommandRequest(t, "/slack/command/roster", url.Values{"text": {"2"}}, testSlackSigningSecret))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, formattedPlayers, 2)
+	assert.Equal(t, "Anna", formattedPlayers[0].Name, "players are sorted by name")
+	assert.Equal(t, "bo", formattedPlayers[1].Name)
+	assert.Equal(t, 2, formattedPage)
+
+	rr = httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, createSlackCommandRequest(t, "/slack/command/roster", url.Values{"text": {"first"}}, testSlackSigningSecret))
+	assert.Contains(t, rr.Body.String(), "Usage: /roster [page]")
 }
 
-func TestProcessMatchesHandler(t *testing.T) {
-	t.Run("sends booking notification for new match", func(t *testing.T) {
-		server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
-		defer teardown()
+func TestUnmappedPlayersCommandHandler(t *testing.T) {
+	var formattedPlayers []club.PlayerInfo
+	mockNotifier := notifier.NewMock()
+	mockNotifier.FormatUnmappedPlayersResponseFunc = func(players []club.PlayerInfo) (any, error) {
+		formattedPlayers = players
+		return slack.Message{}, nil
+	}
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), mockNotifier, testSlackSigningSecret)
+	defer teardown()
+	server.Cfg.AdminSlackUserIDs = []string{"UADMIN"}
 
-		server.Store.AddPlayer("p1", "Player One", 1.0)
-		match := &playtomic.PadelMatch{
-			MatchID:          "m1",
-			OwnerID:          "p1",
-			ProcessingStatus: playtomic.StatusNew,
-			Start:            time.Now().Unix(),
-		}
-		require.NoError(t, server.Store.UpsertMatch(match))
+	server.Store.AddPlayer("p1", "Bo", 1.0)
+	server.Store.AddPlayer("p2", "Anna", 1.0)
+	server.Store.AddPlayer("p3", "Mapped", 1.0)
+	require.NoError(t, server.Store.SetSlackUserID("p3", "U333"))
 
-		req, err := http.NewRequest("GET", "/process", nil)
-		require.NoError(t, err)
+	t.Run("rejects non-admins", func(t *testing.T) {
 		rr := httptest.NewRecorder()
-		handler := server.ProcessMatchesHandler()
-		handler.ServeHTTP(rr, req)
+		server.Router.ServeHTTP(rr, createSlackCommandRequest(t, "/slack/command/unmapped", url.Values{"user_id": {"UOTHER"}}, testSlackSigningSecret))
+		assert.Contains(t, rr.Body.String(), "only admins")
+		assert.Nil(t, formattedPlayers)
+	})
 
+	t.Run("lists the unmapped players by name", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, createSlackCommandRequest(t, "/slack/command/unmapped", url.Values{"user_id": {"UADMIN"}}, testSlackSigningSecret))
 		assert.Equal(t, http.StatusOK, rr.Code)
-		matches, err := server.Store.GetAllMatches()
-		require.NoError(t, err)
-		require.Len(t, matches, 1)
-		assert.Equal(t, playtomic.StatusAssigningBallBringer, matches[0].ProcessingStatus)
+		require.Len(t, formattedPlayers, 2)
+		assert.Equal(t, "p2", formattedPlayers[0].ID)
+		assert.Equal(t, "p1", formattedPlayers[1].ID)
 	})
+}
 
-	t.Run("sends result notification for new but already played match", func(t *testing.T) {
-		server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
-		defer teardown()
+func TestLeaderboardDiffCommandHandler(t *testing.T) {
+	var periods []string
+	var deltas []club.PlayerStatsDelta
+	mockNotifier := notifier.NewMock()
+	mockNotifier.FormatLeaderboardDiffResponseFunc = func(before, after string, d []club.PlayerStatsDelta) (any, error) {
+		periods, deltas = []string{before, after}, d
+		return slack.Message{}, nil
+	}
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), mockNotifier, testSlackSigningSecret)
+	defer teardown()
 
-		// Add players to the store first to satisfy foreign key constraints.
-		server.Store.AddPlayer("p1", "Player One", 1.0)
-		server.Store.AddPlayer("p2", "Player Two", 1.0)
-		server.Store.AddPlayer("p3", "Player Three", 1.0)
-		server.Store.AddPlayer("p4", "Player Four", 1.0)
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+	server.Store.AddPlayer("p2", "Player Two", 1.0)
+	for i, start := range []time.Time{
+		time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 12, 18, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 14, 18, 0, 0, 0, time.UTC),
+	} {
+		server.Store.UpdatePlayerStats(&playtomic.PadelMatch{
+			MatchID: fmt.Sprintf("m%d", i),
+			Start:   start.Unix(),
+			Teams: []playtomic.Team{
+				{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p1"}}},
+				{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p2"}}},
+			},
+		})
+	}
+
+	rr := httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, createSlackCommandRequest(t, "/slack/command/leaderboard-diff", url.Values{"text": {"2024-03-04..2024-03-10 2024-03-11..2024-03-17"}}, testSlackSigningSecret))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []string{"2024-03-04..2024-03-10", "2024-03-11..2024-03-17"}, periods)
+	require.Len(t, deltas, 2)
+	assert.Equal(t, "Player One", deltas[0].PlayerName)
+	assert.Equal(t, 1, deltas[0].MatchesWon)
+	assert.Equal(t, 1, deltas[0].MatchesPlayed)
+	assert.Equal(t, 0, deltas[1].MatchesWon)
+
+	for _, text := range []string{"", "last-week", "yesterday today", "2024-03-10..2024-03-04 this-week"} {
+		periods = nil
+		rr = httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, createSlackCommandRequest(t, "/slack/command/leaderboard-diff", url.Values{"text": {text}}, testSlackSigningSecret))
+		assert.Equal(t, http.StatusOK, rr.Code, text)
+		assert.Nil(t, periods, "invalid periods are answered without formatting: %q", text)
+	}
+}
+
+func TestParseStatsPeriod(t *testing.T) {
+	now := time.Date(2024, 3, 14, 12, 0, 0, 0, time.UTC) // A Thursday
+	monday := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+
+	from, to, err := parseStatsPeriod("this-week", now)
+	require.NoError(t, err)
+	assert.Equal(t, monday.Unix(), from)
+	assert.Equal(t, now.Unix(), to)
+
+	from, to, err = parseStatsPeriod("last-week", now)
+	require.NoError(t, err)
+	assert.Equal(t, monday.AddDate(0, 0, -7).Unix(), from)
+	assert.Equal(t, monday.Unix()-1, to)
+}
+
+func TestFormCommandHandler(t *testing.T) {
+	var formattedName string
+	var formattedForm []bool
+	mockNotifier := notifier.NewMock()
+	mockNotifier.FormatFormResponseFunc = func(playerName string, form []bool) (any, error) {
+		formattedName, formattedForm = playerName, form
+		return slack.Message{}, nil
+	}
+	notFound := ""
+	mockNotifier.FormatPlayerNotFoundResponseFunc = func(query string) (any, error) {
+		notFound = query
+		return slack.Message{}, nil
+	}
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), mockNotifier, testSlackSigningSecret)
+	defer teardown()
+
+	server.Store.AddPlayer("p1", "Morten Voss", 1.0)
+	server.Store.AddPlayer("p2", "Player Two", 1.0)
+	for i, winner := range []string{"p1", "p2", "p1"} {
 		match := &playtomic.PadelMatch{
-			MatchID:          "m1",
-			OwnerID:          "p1",
-			ProcessingStatus: playtomic.StatusNew,
-			GameStatus:       playtomic.GameStatusPlayed,
-			ResultsStatus:    playtomic.ResultsStatusConfirmed,
-			Start:            time.Now().Unix(),
+			MatchID:       fmt.Sprintf("m%d", i),
+			OwnerID:       "p1",
+			Start:         int64(100 * (i + 1)),
+			GameStatus:    playtomic.GameStatusPlayed,
+			ResultsStatus: playtomic.ResultsStatusConfirmed,
 			Teams: []playtomic.Team{
-				{Players: []playtomic.Player{{UserID: "p1"}, {UserID: "p2"}}},
-				{Players: []playtomic.Player{{UserID: "p3"}, {UserID: "p4"}}},
+				{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: winner}}},
+				{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: map[string]string{"p1": "p2", "p2": "p1"}[winner]}}},
 			},
 		}
 		require.NoError(t, server.Store.UpsertMatch(match))
+		require.NoError(t, server.Store.UpdateProcessingStatus(match.MatchID, playtomic.StatusCompleted))
+	}
 
-		req, err := http.NewRequest("GET", "/process", nil)
-		require.NoError(t, err)
-		rr := httptest.NewRecorder()
-		handler := server.ProcessMatchesHandler()
-		handler.ServeHTTP(rr, req)
+	rr := httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, createSlackCommandRequest(t, "/slack/command/form", url.Values{"text": {"morten"}}, testSlackSigningSecret))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "Morten Voss", formattedName)
+	assert.Equal(t, []bool{true, false, true}, formattedForm)
+
+	rr = httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, createSlackCommandRequest(t, "/slack/command/form", url.Values{"text": {"nobody"}}, testSlackSigningSecret))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "nobody", notFound)
+
+	rr = httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, createSlackCommandRequest(t, "/slack/command/form", url.Values{}, testSlackSigningSecret))
+	assert.Contains(t, rr.Body.String(), "Usage: /form")
+}
+
+func TestSlackEventsHandler(t *testing.T) {
+	mockNotifier := notifier.NewMock()
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), mockNotifier, testSlackSigningSecret)
+	defer teardown()
+
+	server.Store.AddPlayer("p1", "Morten Voss", 1.0)
+	require.NoError(t, server.Store.SetSlackUserID("p1", "U123"))
 
+	t.Run("answers the URL verification challenge", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, createSignedSlackRequest(t, "/slack/events", "application/json",
+			`{"type": "url_verification", "challenge": "abc123"}`, testSlackSigningSecret))
 		assert.Equal(t, http.StatusOK, rr.Code)
-		matches, err := server.Store.GetAllMatches()
+		assert.Equal(t, "abc123", rr.Body.String())
+	})
+
+	homeOpened := func(t *testing.T, slackUserID string) {
+		t.Helper()
+		payload := fmt.Sprintf(`{"type": "event_callback", "event": {"type": "app_home_opened", "user": %q, "tab": "home"}}`, slackUserID)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, createSignedSlackRequest(t, "/slack/events", "application/json", payload, testSlackSigningSecret))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		require.NoError(t, server.Processor.Drain(context.Background()))
+	}
+
+	t.Run("publishes the home view of a linked user", func(t *testing.T) {
+		mockNotifier.Reset()
+		homeOpened(t, "U123")
+		require.Len(t, mockNotifier.PublishHomeViewCalls, 1)
+		call := mockNotifier.PublishHomeViewCalls[0]
+		assert.Equal(t, "U123", call.SlackUserID)
+		require.NotNil(t, call.View.Player)
+		assert.Equal(t, "p1", call.View.Player.ID)
+		require.NotNil(t, call.View.Stats)
+		assert.Equal(t, "p1", call.View.Stats.PlayerID)
+		assert.Equal(t, 0, call.View.Stats.MatchesPlayed)
+		assert.Nil(t, call.View.NextMatch)
+	})
+
+	t.Run("publishes a prompt view for an unlinked user", func(t *testing.T) {
+		mockNotifier.Reset()
+		homeOpened(t, "U999")
+		require.Len(t, mockNotifier.PublishHomeViewCalls, 1)
+		assert.Nil(t, mockNotifier.PublishHomeViewCalls[0].View.Player)
+	})
+}
+
+func TestReportResultCommandHandler(t *testing.T) {
+	setup := func(t *testing.T) (*Server, func()) {
+		server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+		server.Store.AddPlayer("p1", "Morten Voss", 1.0)
+		require.NoError(t, server.Store.SetSlackUserID("p1", "U1"))
+		server.Store.AddPlayer("p5", "Not Playing", 1.0)
+		require.NoError(t, server.Store.SetSlackUserID("p5", "U5"))
+		require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{
+			MatchID:          "m1",
+			OwnerID:          "p1",
+			GameStatus:       playtomic.GameStatusPending,
+			ResultsStatus:    playtomic.ResultsStatusWaitingFor,
+			ProcessingStatus: playtomic.StatusBookingNotified,
+			Teams: []playtomic.Team{
+				{ID: "t1", Players: []playtomic.Player{{UserID: "p1", Name: "Morten Voss"}, {UserID: "p2", Name: "Player Two"}}},
+				{ID: "t2", Players: []playtomic.Player{{UserID: "p3", Name: "Player Three"}, {UserID: "p4", Name: "Player Four"}}},
+			},
+		}))
+		return server, teardown
+	}
+
+	t.Run("records the result for a participant", func(t *testing.T) {
+		server, teardown := setup(t)
+		defer teardown()
+
+		req := createSlackCommandRequest(t, "/slack/command/report-result", url.Values{
+			"user_id": {"U1"},
+			"text":    {"m1 6-4,6-3"},
+		}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "has been recorded")
+
+		match, err := server.Store.GetMatch("m1")
+		require.NoError(t, err)
+		assert.Equal(t, playtomic.ResultsStatusManuallyConfirmed, match.ResultsStatus)
+		assert.Equal(t, playtomic.GameStatusPlayed, match.GameStatus)
+		assert.Equal(t, "WON", match.Teams[0].TeamResult)
+		assert.Equal(t, "LOST", match.Teams[1].TeamResult)
+		require.Len(t, match.Results, 2)
+		assert.Equal(t, 6, match.Results[1].Scores["t1"])
+		assert.Equal(t, 3, match.Results[1].Scores["t2"])
+		assert.NotEqual(t, playtomic.StatusBookingNotified, match.ProcessingStatus, "The processor should have been nudged")
+	})
+
+	t.Run("rejects non-participants", func(t *testing.T) {
+		server, teardown := setup(t)
+		defer teardown()
+
+		// A Slack user named like a player is not trusted; only the linked player counts.
+		for _, form := range []url.Values{
+			{"user_id": {"U5"}, "text": {"m1 6-4,6-3"}},
+			{"user_id": {"UUNKNOWN"}, "user_name": {"morten.voss"}, "text": {"m1 6-4,6-3"}},
+		} {
+			req := createSlackCommandRequest(t, "/slack/command/report-result", form, testSlackSigningSecret)
+			rr := httptest.NewRecorder()
+			server.Router.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Contains(t, rr.Body.String(), "Only players in the match")
+		}
+
+		match, err := server.Store.GetMatch("m1")
+		require.NoError(t, err)
+		assert.Equal(t, playtomic.ResultsStatusWaitingFor, match.ResultsStatus)
+		assert.Empty(t, match.Results)
+	})
+
+	t.Run("rejects matches whose result is already counted", func(t *testing.T) {
+		server, teardown := setup(t)
+		defer teardown()
+		require.NoError(t, server.Store.UpdateProcessingStatus("m1", playtomic.StatusStatsUpdated))
+
+		req := createSlackCommandRequest(t, "/slack/command/report-result", url.Values{
+			"user_id": {"U1"},
+			"text":    {"m1 6-4,6-3"},
+		}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		assert.Contains(t, rr.Body.String(), "already been posted and counted")
+		match, err := server.Store.GetMatch("m1")
+		require.NoError(t, err)
+		assert.Equal(t, playtomic.StatusStatsUpdated, match.ProcessingStatus)
+		assert.Empty(t, match.Results)
+	})
+
+	t.Run("rejects matches that haven't been played", func(t *testing.T) {
+		server, teardown := setup(t)
+		defer teardown()
+		upcoming, err := server.Store.GetMatch("m1")
+		require.NoError(t, err)
+		upcoming.End = time.Now().Add(7 * 24 * time.Hour).Unix()
+		require.NoError(t, server.Store.UpsertMatch(upcoming))
+
+		req := createSlackCommandRequest(t, "/slack/command/report-result", url.Values{
+			"user_id": {"U1"},
+			"text":    {"m1 6-4,6-3"},
+		}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		assert.Contains(t, rr.Body.String(), "hasn't been played yet")
+		match, err := server.Store.GetMatch("m1")
+		require.NoError(t, err)
+		assert.Equal(t, playtomic.GameStatusPending, match.GameStatus)
+		assert.Empty(t, match.Results)
+	})
+
+	t.Run("rejects canceled matches", func(t *testing.T) {
+		server, teardown := setup(t)
+		defer teardown()
+		canceled, err := server.Store.GetMatch("m1")
+		require.NoError(t, err)
+		canceled.GameStatus = playtomic.GameStatusCanceled
+		require.NoError(t, server.Store.UpsertMatch(canceled))
+
+		req := createSlackCommandRequest(t, "/slack/command/report-result", url.Values{
+			"user_id": {"U1"},
+			"text":    {"m1 6-4,6-3"},
+		}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		assert.Contains(t, rr.Body.String(), "was canceled")
+		match, err := server.Store.GetMatch("m1")
+		require.NoError(t, err)
+		assert.Equal(t, playtomic.GameStatusCanceled, match.GameStatus)
+		assert.Empty(t, match.Results)
+	})
+
+	t.Run("rejects unparseable scores", func(t *testing.T) {
+		server, teardown := setup(t)
+		defer teardown()
+
+		req := createSlackCommandRequest(t, "/slack/command/report-result", url.Values{
+			"user_id": {"U1"},
+			"text":    {"m1 6:4"},
+		}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		assert.Contains(t, rr.Body.String(), "Could not read the score")
+	})
+}
+
+// createPubSubPushRequest wraps a msgpack encoded payload in a Pub/Sub push envelope.
+func createPubSubPushRequest(t *testing.T, targetURL string, payload any) *http.Request {
+	t.Helper()
+
+	data, err := msgpack.Marshal(payload)
+	require.NoError(t, err)
+	body, err := json.Marshal(map[string]any{
+		"subscription": "test-subscription",
+		"message":      map[string]string{"data": base64.StdEncoding.EncodeToString(data)},
+	})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest("POST", targetURL, bytes.NewReader(body))
+	require.NoError(t, err)
+	return req
+}
+
+func TestPubSubHandlers_Validation(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
+	defer teardown()
+	pubsubClient := pubsub.NewMock("TEST")
+	pubsubClient.ProcessMessageFunc = func(data []byte, returnValue any) error {
+		return msgpack.Unmarshal(data, returnValue)
+	}
+	server.pubsub = pubsubClient
+
+	handlers := map[string]http.Handler{
+		"/assign-ball-boy":     server.BallBoyHandler(),
+		"/update-player-stats": server.UpdatePlayerStatsHandler(),
+		"/notify-booking":      server.NotifyBookingHandler(),
+		"/notify-result":       server.NotifyResultHandler(),
+	}
+	for path, handler := range handlers {
+		t.Run(path+" rejects a match without an ID", func(t *testing.T) {
+			req := createPubSubPushRequest(t, path, playtomic.PadelMatch{ResourceName: "Court 1"})
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+
+		t.Run(path+" rejects a payload that is not a match", func(t *testing.T) {
+			req := createPubSubPushRequest(t, path, []string{"not", "a", "match"})
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+
+	t.Run("accepts a valid match", func(t *testing.T) {
+		req := createPubSubPushRequest(t, "/notify-booking", playtomic.PadelMatch{
+			MatchID:          "m1",
+			ProcessingStatus: playtomic.StatusBallBoyAssigned,
+		})
+		rr := httptest.NewRecorder()
+		server.NotifyBookingHandler().ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+}
+
+func TestGuardedEndpoints(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
+	defer teardown()
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+
+	t.Run("disabled by default", func(t *testing.T) {
+		for _, path := range []string{"/clear", "/matches/seed"} {
+			rr := httptest.NewRecorder()
+			server.Router.ServeHTTP(rr, httptest.NewRequest("POST", path, nil))
+			assert.Equal(t, http.StatusNotFound, rr.Code, path)
+		}
+		assert.True(t, server.Store.IsKnownPlayer("p1"), "the store must not be cleared")
+	})
+
+	t.Run("clear works when destructive endpoints are enabled", func(t *testing.T) {
+		server.Cfg.EnableDestructiveEndpoints = true
+		server.Router = http.NewServeMux()
+		server.routes()
+
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, httptest.NewRequest("POST", "/clear", nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, "a full clear needs confirmation")
+		assert.True(t, server.Store.IsKnownPlayer("p1"), "the store must not be cleared")
+
+		rr = httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, httptest.NewRequest("POST", "/clear?confirm=false", nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.True(t, server.Store.IsKnownPlayer("p1"), "the store must not be cleared")
+
+		rr = httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, httptest.NewRequest("POST", "/clear?matchID=m1", nil))
+		assert.Equal(t, http.StatusOK, rr.Code, "a match-scoped clear needs no confirmation")
+
+		req := httptest.NewRequest("POST", "/clear?confirm=true", nil)
+		req.Header.Set("X-Requested-By", "ops@example.com")
+		rr = httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.False(t, server.Store.IsKnownPlayer("p1"))
+	})
+}
+
+func TestUndeleteMatchHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
+	defer teardown()
+
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+	require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m1", OwnerID: "p1"}))
+	server.Store.ClearMatch("m1")
+
+	rr := httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, httptest.NewRequest("GET", "/matches", nil))
+	var matches []playtomic.PadelMatch
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &matches))
+	assert.Empty(t, matches)
+
+	rr = httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, httptest.NewRequest("GET", "/matches?include_deleted=true", nil))
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &matches))
+	assert.Len(t, matches, 1)
+
+	rr = httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, adminRequest("POST", "/matches/undelete?matchID=m1"))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	restored, err := server.Store.GetMatch("m1")
+	require.NoError(t, err)
+	assert.Equal(t, "m1", restored.MatchID)
+
+	rr = httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, adminRequest("POST", "/matches/undelete?matchID=m1"))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, adminRequest("POST", "/matches/undelete"))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestProcessMatchHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
+	defer teardown()
+
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+	require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m1", OwnerID: "p1", GameStatus: playtomic.GameStatusCanceled}))
+
+	t.Run("dry run reports the transition without persisting it", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, adminRequest("POST", "/process/m1?dry_run=true"))
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, "NEW", response["previousStatus"])
+		assert.Equal(t, "COMPLETED", response["processingStatus"])
+		assert.Equal(t, true, response["dryRun"])
+
+		match, err := server.Store.GetMatch("m1")
+		require.NoError(t, err)
+		assert.Equal(t, playtomic.StatusNew, match.ProcessingStatus)
+	})
+
+	t.Run("new canceled match is completed", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, adminRequest("POST", "/process/m1"))
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, "m1", response["matchID"])
+		assert.Equal(t, "COMPLETED", response["processingStatus"])
+
+		match, err := server.Store.GetMatch("m1")
+		require.NoError(t, err)
+		assert.Equal(t, playtomic.StatusCompleted, match.ProcessingStatus)
+	})
+
+	t.Run("unknown match", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, adminRequest("POST", "/process/missing"))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestRefetchMatchHandler(t *testing.T) {
+	mockClient := playtomic.NewMockClient()
+	server, teardown := setupTestServer(t, mockClient, notifier.NewMock(), "")
+	defer teardown()
+
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+	require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{
+		MatchID: "m1",
+		OwnerID: "p1",
+		Teams: []playtomic.Team{
+			{ID: "t1", Players: []playtomic.Player{{UserID: "p1", Name: "Player One"}}},
+			{ID: "t2"},
+		},
+	}))
+	require.NoError(t, server.Store.UpdateProcessingStatus("m1", playtomic.StatusBookingNotified))
+
+	updatedTeams := []playtomic.Team{
+		{ID: "t1", Players: []playtomic.Player{{UserID: "p1", Name: "Player One"}, {UserID: "p2", Name: "Player Two"}}},
+		{ID: "t2", Players: []playtomic.Player{{UserID: "p3", Name: "Player Three"}, {UserID: "p4", Name: "Player Four"}}},
+	}
+	mockClient.RefreshSpecificMatchFunc = func(matchID string) (playtomic.PadelMatch, error) {
+		return playtomic.PadelMatch{MatchID: matchID, OwnerID: "p1", Teams: updatedTeams}, nil
+	}
+
+	t.Run("dry run returns the fetched match without saving it", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, adminRequest("POST", "/refetch/m1?dry_run=true"))
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response playtomic.PadelMatch
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, updatedTeams, response.Teams)
+
+		match, err := server.Store.GetMatch("m1")
+		require.NoError(t, err)
+		assert.Len(t, match.Teams[1].Players, 0)
+	})
+
+	t.Run("refreshes the teams and keeps the processing status", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, adminRequest("POST", "/refetch/m1"))
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response playtomic.PadelMatch
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, updatedTeams, response.Teams)
+		assert.Equal(t, playtomic.StatusBookingNotified, response.ProcessingStatus)
+
+		match, err := server.Store.GetMatch("m1")
+		require.NoError(t, err)
+		assert.Equal(t, updatedTeams, match.Teams)
+		assert.Equal(t, playtomic.StatusBookingNotified, match.ProcessingStatus)
+	})
+
+	t.Run("fetch failure", func(t *testing.T) {
+		mockClient.RefreshSpecificMatchFunc = func(matchID string) (playtomic.PadelMatch, error) {
+			return playtomic.PadelMatch{}, playtomic.ErrIncompleteMatch
+		}
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, adminRequest("POST", "/refetch/m1"))
+		assert.Equal(t, http.StatusBadGateway, rr.Code)
+	})
+
+	t.Run("unknown match", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, adminRequest("POST", "/refetch/missing"))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestRefetchMatchHandler_BypassesCache(t *testing.T) {
+	resourceName := "Court 1"
+	playtomicServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{
+			"owner_id": "p1",
+			"start_date": "2025-07-09T18:00:00",
+			"end_date": "2025-07-09T19:30:00",
+			"created_at": "2025-07-08T10:00:00",
+			"resource_name": %q,
+			"teams": [{ "team_id": "0", "players": [{ "user_id": "p1" }] }]
+		}`, resourceName)
+	}))
+	defer playtomicServer.Close()
+	client := playtomic.NewClient(time.Hour, nil).(*playtomic.APIClient)
+	client.BaseURL = playtomicServer.URL
+
+	server, teardown := setupTestServer(t, client, notifier.NewMock(), "")
+	defer teardown()
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+
+	// The fetch caches the match before it is moved to another court.
+	warm, err := client.GetSpecificMatch("m1")
+	require.NoError(t, err)
+	require.NoError(t, server.Store.UpsertMatch(&warm))
+	resourceName = "Court 2"
+
+	rr := httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, adminRequest("POST", "/refetch/m1"))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	match, err := server.Store.GetMatch("m1")
+	require.NoError(t, err)
+	assert.Equal(t, "Court 2", match.ResourceName)
+}
+
+func TestSeedMatchHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
+	defer teardown()
+	pubsubClient := pubsub.NewMock("TEST")
+	pubsubClient.ProcessMessageFunc = func(data []byte, returnValue any) error {
+		return msgpack.Unmarshal(data, returnValue)
+	}
+	server.pubsub = pubsubClient
+
+	req := createPubSubPushRequest(t, "/matches/seed", playtomic.PadelMatch{
+		MatchID:      "sim-1",
+		OwnerID:      "p1",
+		ResourceName: "Court 1",
+		Teams: []playtomic.Team{
+			{Players: []playtomic.Player{{UserID: "p1", Name: "Player One"}}},
+			{Players: []playtomic.Player{{UserID: "p2", Name: "Player Two"}}},
+		},
+	})
+	rr := httptest.NewRecorder()
+	server.SeedMatchHandler().ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	match, err := server.Store.GetMatch("sim-1")
+	require.NoError(t, err)
+	assert.Equal(t, playtomic.StatusNew, match.ProcessingStatus)
+	players, err := server.Store.GetAllPlayers()
+	require.NoError(t, err)
+	assert.Len(t, players, 2)
+}
+
+func TestFetchMatchesHandler(t *testing.T) {
+	mockClient := playtomic.NewMockClient()
+	ownerID := "p1"
+	// Mock the GetMatches endpoint
+	mockClient.GetMatchesFunc = func(params *playtomic.SearchMatchesParams) ([]playtomic.MatchSummary, error) {
+		return []playtomic.MatchSummary{
+			{MatchID: "m1", OwnerID: &ownerID},
+			{MatchID: "m2", OwnerID: nil}, // No owner, should be skipped
+		}, nil
+	}
+	// Mock the GetSpecificMatch endpoint
+	mockClient.GetSpecificMatchFunc = func(matchID string) (playtomic.PadelMatch, error) {
+		return playtomic.PadelMatch{
+			MatchID: matchID,
+			OwnerID: ownerID,
+			Teams: []playtomic.Team{
+				{Players: []playtomic.Player{{UserID: "p1"}, {UserID: "p2"}}},
+				{Players: []playtomic.Player{{UserID: "p3"}, {UserID: "p4"}}},
+			},
+		}, nil
+	}
+
+	server, teardown := setupTestServer(t, mockClient, notifier.NewMock(), "")
+	defer teardown()
+	// Add a known player so that a match is fetched
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+	server.Store.AddPlayer("p2", "Player Two", 1.0)
+	server.Store.AddPlayer("p3", "Player Three", 1.0)
+	server.Store.AddPlayer("p4", "Player Four", 1.0)
+
+	req, err := http.NewRequest("GET", "/fetch", nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler := server.FetchMatchesHandler()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Verify that the correct match was upserted
+	matches, err := server.Store.GetAllMatches(false)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "m1", matches[0].MatchID)
+	assert.Equal(t, playtomic.StatusNew, matches[0].ProcessingStatus)
+}
+
+func TestWeekMatchesHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
+	defer teardown()
+
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+	require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{MatchID: "in-week", OwnerID: "p1", Start: time.Date(2024, 3, 12, 18, 0, 0, 0, time.UTC).Unix()}))
+	require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{MatchID: "next-week", OwnerID: "p1", Start: time.Date(2024, 3, 19, 18, 0, 0, 0, time.UTC).Unix()}))
+
+	rr := httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, httptest.NewRequest("GET", "/matches/week?date=2024-03-17", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var matches []playtomic.PadelMatch
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &matches))
+	require.Len(t, matches, 1)
+	assert.Equal(t, "in-week", matches[0].MatchID)
+
+	rr = httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, httptest.NewRequest("GET", "/matches/week?date=17-03-2024", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestPostDailyDigestHandler(t *testing.T) {
+	mockNotifier := notifier.NewMock()
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), mockNotifier, "")
+	defer teardown()
+	server.Cfg.Notifications.DailyDigest = true
+
+	server.Store.AddPlayer("p1", "Player p1", 1.0)
+	tomorrow := time.Now().Add(24 * time.Hour)
+	for i, id := range []string{"m1", "m2", "m3"} {
+		require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{
+			MatchID: id,
+			OwnerID: "p1",
+			Start:   tomorrow.Add(time.Duration(i) * time.Hour).Unix(),
+			Teams:   []playtomic.Team{{ID: "t1", Players: []playtomic.Player{{UserID: "p1", Name: "Player p1"}}}},
+		}))
+	}
+	require.NoError(t, server.Store.UpdateProcessingStatus("m1", playtomic.StatusBallBoyAssigned))
+	require.NoError(t, server.Store.UpdateProcessingStatus("m2", playtomic.StatusBallBoyAssigned))
+
+	postDigest := func() string {
+		req := httptest.NewRequest("POST", "/digest/daily", nil)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	assert.Equal(t, "Posted a digest of 2 bookings.", postDigest())
+	require.Len(t, mockNotifier.SendBookingDigestCalls, 1, "All new bookings should be posted in one digest")
+	var digested []string
+	for _, match := range mockNotifier.SendBookingDigestCalls[0] {
+		digested = append(digested, match.MatchID)
+	}
+	assert.Equal(t, []string{"m1", "m2"}, digested)
+	assert.Empty(t, mockNotifier.SendBookingNotificationCalls)
+	match, err := server.Store.GetMatch("m1")
+	require.NoError(t, err)
+	assert.Equal(t, playtomic.StatusBookingNotified, match.ProcessingStatus)
+	assert.NotNil(t, match.BookingNotifiedTs)
+
+	assert.Equal(t, "Posted a digest of 0 bookings.", postDigest())
+	assert.Len(t, mockNotifier.SendBookingDigestCalls, 1, "Digested bookings should not be digested again")
+
+	server.Cfg.Notifications.DailyDigest = false
+	assert.Contains(t, postDigest(), "disabled")
+}
+
+func TestPostWeeklyRecapHandler(t *testing.T) {
+	mockNotifier := notifier.NewMock()
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), mockNotifier, "")
+	defer teardown()
+
+	for _, id := range []string{"p1", "p2", "p3", "p4"} {
+		server.Store.AddPlayer(id, "Player "+id, 1.0)
+	}
+	require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{
+		MatchID:    "m1",
+		OwnerID:    "p1",
+		Start:      time.Date(2024, 3, 12, 18, 0, 0, 0, time.UTC).Unix(),
+		GameStatus: playtomic.GameStatusPlayed,
+		Teams: []playtomic.Team{
+			{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p1", Name: "Player p1", Level: 1.0}, {UserID: "p2", Name: "Player p2", Level: 1.0}}},
+			{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p3", Name: "Player p3", Level: 1.0}, {UserID: "p4", Name: "Player p4", Level: 1.0}}},
+		},
+		Results: []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 2}}},
+	}))
+	_, _, err := server.Store.AssignBallBringerAtomically("m1", []string{"p2"})
+	require.NoError(t, err)
+	require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{
+		MatchID:    "m2",
+		OwnerID:    "p1",
+		Start:      time.Date(2024, 3, 14, 18, 0, 0, 0, time.UTC).Unix(),
+		GameStatus: playtomic.GameStatusPlayed,
+		Teams: []playtomic.Team{
+			{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p1", Name: "Player p1", Level: 1.0}, {UserID: "p3", Name: "Player p3", Level: 1.5}}},
+			{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p2", Name: "Player p2", Level: 1.0}, {UserID: "p4", Name: "Player p4", Level: 1.0}}},
+		},
+	}))
+
+	rr := httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, httptest.NewRequest("POST", "/recap/weekly?date=2024-03-13", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, mockNotifier.SendWeeklyRecapCalls, 1)
+	recap := mockNotifier.SendWeeklyRecapCalls[0]
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC).Unix(), recap.WeekStart)
+	require.Len(t, recap.Matches, 2)
+	assert.Equal(t, "m1", recap.Matches[0].MatchID)
+	require.NotNil(t, recap.TopPerformer)
+	assert.Equal(t, "Player p1", recap.TopPerformer.PlayerName)
+	require.NotNil(t, recap.MostImproved)
+	assert.Equal(t, "Player p3", recap.MostImproved.PlayerName)
+	require.NotNil(t, recap.BallBringer)
+	assert.Equal(t, "Player p2", recap.BallBringer.PlayerName)
+
+	rr = httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, httptest.NewRequest("POST", "/recap/weekly?date=13-03-2024", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestFetchMatchesHandler_SkipsIncompleteMatches(t *testing.T) {
+	mockClient := playtomic.NewMockClient()
+	ownerID := "p1"
+	mockClient.GetMatchesFunc = func(params *playtomic.SearchMatchesParams) ([]playtomic.MatchSummary, error) {
+		return []playtomic.MatchSummary{{MatchID: "m1", OwnerID: &ownerID}, {MatchID: "m2", OwnerID: &ownerID}}, nil
+	}
+	mockClient.GetSpecificMatchFunc = func(matchID string) (playtomic.PadelMatch, error) {
+		if matchID == "m2" {
+			return playtomic.PadelMatch{}, fmt.Errorf("%w: missing start time", playtomic.ErrIncompleteMatch)
+		}
+		return playtomic.PadelMatch{
+			MatchID: matchID,
+			OwnerID: ownerID,
+			Teams: []playtomic.Team{
+				{Players: []playtomic.Player{{UserID: "p1"}, {UserID: "p2"}}},
+				{Players: []playtomic.Player{{UserID: "p3"}, {UserID: "p4"}}},
+			},
+		}, nil
+	}
+
+	server, teardown := setupTestServer(t, mockClient, notifier.NewMock(), "")
+	defer teardown()
+	metricsMock := metrics.NewMock()
+	server.Metrics = metricsMock
+	for _, id := range []string{"p1", "p2", "p3", "p4"} {
+		server.Store.AddPlayer(id, "Player "+id, 1.0)
+	}
+
+	req, err := http.NewRequest("GET", "/fetch", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	server.FetchMatchesHandler().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	matches, err := server.Store.GetAllMatches(false)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "m1", matches[0].MatchID)
+	assert.Equal(t, 1, metricsMock.InvalidMatches())
+}
+
+func TestFetchMatchesHandler_CoreMemberOwner(t *testing.T) {
+	mockClient := playtomic.NewMockClient()
+	ownerID := "core"
+	mockClient.GetMatchesFunc = func(params *playtomic.SearchMatchesParams) ([]playtomic.MatchSummary, error) {
+		return []playtomic.MatchSummary{{MatchID: "m1", OwnerID: &ownerID}}, nil
+	}
+	// Only the owner is a known player, which is below the club match threshold.
+	mockClient.GetSpecificMatchFunc = func(matchID string) (playtomic.PadelMatch, error) {
+		return playtomic.PadelMatch{
+			MatchID: matchID,
+			OwnerID: ownerID,
+			Teams: []playtomic.Team{
+				{Players: []playtomic.Player{{UserID: "core"}, {UserID: "guest1"}}},
+				{Players: []playtomic.Player{{UserID: "guest2"}, {UserID: "guest3"}}},
+			},
+		}, nil
+	}
+
+	server, teardown := setupTestServer(t, mockClient, notifier.NewMock(), "")
+	defer teardown()
+	server.Store.AddPlayer("core", "Core Member", 1.0)
+
+	fetch := func() []*playtomic.PadelMatch {
+		rr := httptest.NewRecorder()
+		server.FetchMatchesHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/fetch", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		matches, err := server.Store.GetAllMatches(false)
+		require.NoError(t, err)
+		return matches
+	}
+
+	assert.Empty(t, fetch(), "a sub-threshold match by a regular member is skipped")
+
+	rr := httptest.NewRecorder()
+	server.CorePlayerHandler().ServeHTTP(rr, adminRequest("POST", "/players/core?player=core"))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	matches := fetch()
+	require.Len(t, matches, 1, "a match booked by a core member is always captured")
+	assert.Equal(t, "m1", matches[0].MatchID)
+}
+
+func TestFetchMatchesHandler_DateRange(t *testing.T) {
+	t.Run("passes explicit range to the Playtomic client", func(t *testing.T) {
+		mockClient := playtomic.NewMockClient()
+		server, teardown := setupTestServer(t, mockClient, notifier.NewMock(), "")
+		defer teardown()
+		server.Cfg.TenantID = "tenant1"
+
+		req, err := http.NewRequest("GET", "/fetch?from=2024-03-01&to=2024-03-10", nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		server.FetchMatchesHandler().ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		require.Len(t, mockClient.GetMatchesCalls, 1)
+		params := mockClient.GetMatchesCalls[0]
+		assert.Equal(t, "2024-03-01T00:00:00", params.FromStartDate)
+		assert.Equal(t, "2024-03-10T23:59:59", params.ToStartDate)
+		assert.Equal(t, []string{"tenant1"}, params.TenantIDs)
+	})
+
+	t.Run("keeps days working without an upper bound", func(t *testing.T) {
+		mockClient := playtomic.NewMockClient()
+		server, teardown := setupTestServer(t, mockClient, notifier.NewMock(), "")
+		defer teardown()
+
+		req, err := http.NewRequest("GET", "/fetch?days=3", nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		server.FetchMatchesHandler().ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		require.Len(t, mockClient.GetMatchesCalls, 1)
+		params := mockClient.GetMatchesCalls[0]
+		assert.Equal(t, time.Now().AddDate(0, 0, -3).Format("2006-01-02")+"T00:00:00", params.FromStartDate)
+		assert.Empty(t, params.ToStartDate)
+	})
+
+	t.Run("clamps days to the configured maximum", func(t *testing.T) {
+		mockClient := playtomic.NewMockClient()
+		server, teardown := setupTestServer(t, mockClient, notifier.NewMock(), "")
+		defer teardown()
+		server.Cfg.MaxFetchDays = 90
+
+		req, err := http.NewRequest("GET", "/fetch?days=36500", nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		server.FetchMatchesHandler().ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		require.Len(t, mockClient.GetMatchesCalls, 1)
+		assert.Equal(t, time.Now().AddDate(0, 0, -90).Format("2006-01-02")+"T00:00:00", mockClient.GetMatchesCalls[0].FromStartDate)
+	})
+
+	t.Run("clamps from/to ranges to the configured maximum", func(t *testing.T) {
+		mockClient := playtomic.NewMockClient()
+		server, teardown := setupTestServer(t, mockClient, notifier.NewMock(), "")
+		defer teardown()
+		server.Cfg.MaxFetchDays = 90
+
+		req, err := http.NewRequest("GET", "/fetch?from=1900-01-01&to=2024-03-31", nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		server.FetchMatchesHandler().ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		require.Len(t, mockClient.GetMatchesCalls, 1)
+		params := mockClient.GetMatchesCalls[0]
+		assert.Equal(t, "2024-01-01T00:00:00", params.FromStartDate)
+		assert.Equal(t, "2024-03-31T23:59:59", params.ToStartDate)
+
+		// Without 'to' the range runs up to today.
+		req, err = http.NewRequest("GET", "/fetch?from=1900-01-01", nil)
+		require.NoError(t, err)
+
+		rr = httptest.NewRecorder()
+		server.FetchMatchesHandler().ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		require.Len(t, mockClient.GetMatchesCalls, 2)
+		assert.Equal(t, time.Now().AddDate(0, 0, -90).Format("2006-01-02")+"T00:00:00", mockClient.GetMatchesCalls[1].FromStartDate)
+	})
+
+	t.Run("rejects invalid ranges", func(t *testing.T) {
+		for _, query := range []string{"from=2024-13-01", "to=yesterday", "from=2024-03-10&to=2024-03-01"} {
+			mockClient := playtomic.NewMockClient()
+			server, teardown := setupTestServer(t, mockClient, notifier.NewMock(), "")
+
+			req, err := http.NewRequest("GET", "/fetch?"+query, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			server.FetchMatchesHandler().ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+			assert.Len(t, mockClient.GetMatchesCalls, 0, query)
+			teardown()
+		}
+	})
+}
+
+func TestProcessMatchesHandler(t *testing.T) {
+	t.Run("sends booking notification for new match", func(t *testing.T) {
+		server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
+		defer teardown()
+
+		server.Store.AddPlayer("p1", "Player One", 1.0)
+		match := &playtomic.PadelMatch{
+			MatchID:          "m1",
+			OwnerID:          "p1",
+			ProcessingStatus: playtomic.StatusNew,
+			Start:            time.Now().Unix(),
+		}
+		require.NoError(t, server.Store.UpsertMatch(match))
+
+		req, err := http.NewRequest("GET", "/process", nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		handler := server.ProcessMatchesHandler()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		matches, err := server.Store.GetAllMatches(false)
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, playtomic.StatusAssigningBallBringer, matches[0].ProcessingStatus)
+	})
+
+	t.Run("sends result notification for new but already played match", func(t *testing.T) {
+		server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), "")
+		defer teardown()
+
+		// Add players to the store first to satisfy foreign key constraints.
+		server.Store.AddPlayer("p1", "Player One", 1.0)
+		server.Store.AddPlayer("p2", "Player Two", 1.0)
+		server.Store.AddPlayer("p3", "Player Three", 1.0)
+		server.Store.AddPlayer("p4", "Player Four", 1.0)
+		match := &playtomic.PadelMatch{
+			MatchID:          "m1",
+			OwnerID:          "p1",
+			ProcessingStatus: playtomic.StatusNew,
+			GameStatus:       playtomic.GameStatusPlayed,
+			ResultsStatus:    playtomic.ResultsStatusConfirmed,
+			Start:            time.Now().Unix(),
+			Teams: []playtomic.Team{
+				{Players: []playtomic.Player{{UserID: "p1"}, {UserID: "p2"}}},
+				{Players: []playtomic.Player{{UserID: "p3"}, {UserID: "p4"}}},
+			},
+		}
+		require.NoError(t, server.Store.UpsertMatch(match))
+
+		req, err := http.NewRequest("GET", "/process", nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		handler := server.ProcessMatchesHandler()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		matches, err := server.Store.GetAllMatches(false)
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, playtomic.StatusResultNotified, matches[0].ProcessingStatus)
+	})
+}
+
+func TestNotificationsCommandHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+	defer teardown()
+	server.Store.AddPlayer("p1", "Morten Voss", 1.0)
+	require.NoError(t, server.Store.SetSlackUserID("p1", "U123"))
+
+	notificationsRequest := func(t *testing.T, slackUserID, text string) *httptest.ResponseRecorder {
+		req := createSlackCommandRequest(t, "/slack/command/notifications", url.Values{"user_id": {slackUserID}, "text": {text}}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := notificationsRequest(t, "U123", "off")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "now off")
+	assert.True(t, server.Store.IsDMOptedOut("p1"))
+
+	rr = notificationsRequest(t, "U123", "")
+	assert.Contains(t, rr.Body.String(), "Direct messages are off")
+
+	rr = notificationsRequest(t, "U123", "on")
+	assert.Contains(t, rr.Body.String(), "now on")
+	assert.False(t, server.Store.IsDMOptedOut("p1"))
+
+	rr = notificationsRequest(t, "U123", "maybe")
+	assert.Contains(t, rr.Body.String(), "Usage")
+
+	rr = notificationsRequest(t, "U999", "off")
+	assert.Contains(t, rr.Body.String(), "not linked")
+}
+
+func TestHelpCommandHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+	defer teardown()
+
+	req := createSlackCommandRequest(t, "/slack/command/help", url.Values{"user_id": {"U123"}}, testSlackSigningSecret)
+	rr := httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var msg slack.Message
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &msg))
+	require.Len(t, msg.Blocks.BlockSet, 2)
+	section, ok := msg.Blocks.BlockSet[1].(*slack.SectionBlock)
+	require.True(t, ok)
+
+	commands := server.Commands.Commands()
+	require.NotEmpty(t, commands)
+	for _, cmd := range commands {
+		assert.Contains(t, section.Text.Text, "`/"+cmd.Name, "help should list /%s", cmd.Name)
+	}
+	assert.Contains(t, section.Text.Text, "`/leaderboard [alltime] [competitive] [sort]`")
+}
+
+func TestCommandRegistryRoutes(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+	defer teardown()
+
+	for _, cmd := range server.Commands.Commands() {
+		_, pattern := server.Router.Handler(httptest.NewRequest("POST", "/slack/command/"+cmd.Name, nil))
+		assert.Equal(t, "/slack/command/"+cmd.Name, pattern)
+	}
+}
+
+func TestCommandRegistryAdminGuard(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+	defer teardown()
+	server.Cfg.AdminSlackUserIDs = []string{"UADMIN"}
+
+	var admin []string
+	for _, cmd := range server.Commands.Commands() {
+		req := createSlackCommandRequest(t, "/slack/command/"+cmd.Name, url.Values{"user_id": {"UOTHER"}}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+		if cmd.Admin {
+			admin = append(admin, cmd.Name)
+			assert.Equal(t, http.StatusOK, rr.Code, cmd.Name)
+			assert.Contains(t, rr.Body.String(), "only admins", "/%s should be refused for non-admins", cmd.Name)
+		} else {
+			assert.NotContains(t, rr.Body.String(), "only admins", "/%s should be open to everyone", cmd.Name)
+		}
+	}
+	assert.ElementsMatch(t, []string{"refresh", "process", "map", "merge", "unmapped", "undo-last", "requeue", "new-season", "reset-balls"}, admin)
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+	server := &Server{Cfg: config.Config{Slack: config.SlackConfig{SigningSecret: testSlackSigningSecret}}}
+	var called bool
+	var received string
+	handler := server.VerifySlackSignature(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		require.NoError(t, r.ParseForm())
+		received = r.FormValue("text")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("a badly signed request never reaches the handler", func(t *testing.T) {
+		called = false
+		req := createSlackCommandRequest(t, "/slack/command/test", url.Values{"text": {"hello"}}, "wrong-secret")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.False(t, called)
+	})
+
+	t.Run("a signed request reaches the handler with its body intact", func(t *testing.T) {
+		called = false
+		req := createSlackCommandRequest(t, "/slack/command/test", url.Values{"text": {"hello"}}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.True(t, called)
+		assert.Equal(t, "hello", received)
+	})
+}
+
+func TestAdminEndpoints_RequireToken(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+	defer teardown()
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+	server.Store.AddPlayer("p1-dupe", "Player One", 1.0)
+
+	t.Run("rejects requests without a token", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, httptest.NewRequest("POST", "/players/merge?keep=p1&merge=p1-dupe", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.True(t, server.Store.IsKnownPlayer("p1-dupe"))
+	})
+
+	t.Run("rejects requests with a wrong token", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/players/merge?keep=p1&merge=p1-dupe", nil)
+		req.Header.Set("Authorization", "Bearer wrong-token")
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.True(t, server.Store.IsKnownPlayer("p1-dupe"))
+	})
+
+	t.Run("only accepts POST", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, adminRequest("GET", "/players/merge?keep=p1&merge=p1-dupe"))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+		assert.True(t, server.Store.IsKnownPlayer("p1-dupe"))
+	})
+
+	t.Run("guards the blob re-encoding migration", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, httptest.NewRequest("POST", "/admin/migrations/blobs", nil))
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+		rr = httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, adminRequest("GET", "/admin/migrations/blobs"))
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+
+		rr = httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, adminRequest("POST", "/admin/migrations/blobs"))
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("refuses every request while no token is configured", func(t *testing.T) {
+		server.Cfg.AdminAPIToken = ""
+		defer func() { server.Cfg.AdminAPIToken = testAdminAPIToken }()
+		req := httptest.NewRequest("POST", "/players/merge?keep=p1&merge=p1-dupe", nil)
+		req.Header.Set("Authorization", "Bearer ")
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.True(t, server.Store.IsKnownPlayer("p1-dupe"))
+	})
+}
+
+func TestAdminCommands_RejectForgedRequests(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+	defer teardown()
+	server.Cfg.AdminSlackUserIDs = []string{"UADMIN"}
+	server.Store.AddPlayer("p1", "Anna Berg", 1.0)
+
+	// The admin's user ID is only trusted once the request is known to come from Slack.
+	req := createSlackCommandRequest(t, "/slack/command/map", url.Values{"user_id": {"UADMIN"}, "text": {"<@U111> Anna"}}, "forged-secret")
+	rr := httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	_, found, err := server.Store.GetSlackUserIDByPlayerID("p1")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestRequireAdmin(t *testing.T) {
+	server := &Server{Cfg: config.Config{AdminSlackUserIDs: []string{"UADMIN"}}}
+	assert.True(t, server.requireAdmin("UADMIN"))
+	assert.False(t, server.requireAdmin("UOTHER"))
+	assert.False(t, server.requireAdmin(""))
+
+	server.Cfg.AdminSlackUserIDs = nil
+	assert.False(t, server.requireAdmin("UADMIN"), "Without configured admins nobody is an admin")
+}
+
+func TestAdminOnly(t *testing.T) {
+	server := &Server{Cfg: config.Config{AdminSlackUserIDs: []string{"UADMIN"}}}
+	called := false
+	handler := server.adminOnly(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	request := func(userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/slack/command/test", strings.NewReader(url.Values{"user_id": {userID}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("authorized caller", func(t *testing.T) {
+		called = false
+		rr := request("UADMIN")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.True(t, called)
+	})
+
+	t.Run("unauthorized caller", func(t *testing.T) {
+		called = false
+		rr := request("UOTHER")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.False(t, called)
+		var msg slack.Message
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &msg))
+		assert.Equal(t, slack.ResponseTypeEphemeral, msg.ResponseType)
+		assert.Contains(t, msg.Text, "only admins")
+	})
+}
+
+func TestMatchStatusCommandHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+	defer teardown()
+	server.Store.AddPlayer("p1", "Morten Voss", 1.0)
+	require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m1", OwnerID: "p1", ProcessingStatus: playtomic.StatusNew}))
+	require.NoError(t, server.Store.UpdateProcessingStatus("m1", playtomic.StatusBookingNotified))
+	require.NoError(t, server.Store.UpdateNotificationTimestamp("m1", "booking"))
+
+	matchStatusRequest := func(t *testing.T, text string) *httptest.ResponseRecorder {
+		req := createSlackCommandRequest(t, "/slack/command/match-status", url.Values{"user_id": {"U123"}, "text": {text}}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := matchStatusRequest(t, "m1")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Match m1 is BOOKING_NOTIFIED.")
+	assert.Contains(t, rr.Body.String(), "Booking notification: sent")
+	assert.Contains(t, rr.Body.String(), "Result notification: not sent")
+
+	rr = matchStatusRequest(t, "unknown")
+	assert.Contains(t, rr.Body.String(), "Match unknown was not found.")
+
+	rr = matchStatusRequest(t, "")
+	assert.Contains(t, rr.Body.String(), "Usage")
+}
+
+func TestFormatMatchStatus(t *testing.T) {
+	notifiedAt := time.Date(2024, 3, 15, 18, 30, 0, 0, time.UTC).Unix()
+	tests := []struct {
+		name     string
+		match    playtomic.PadelMatch
+		contains []string
+	}{
+		{
+			name:     "new match",
+			match:    playtomic.PadelMatch{MatchID: "m1", ProcessingStatus: playtomic.StatusNew},
+			contains: []string{"Match m1 is NEW.", "Booking notification: not sent", "Next: a ball bringer will be assigned"},
+		},
+		{
+			name:     "waiting for a result",
+			match:    playtomic.PadelMatch{MatchID: "m1", ProcessingStatus: playtomic.StatusBookingNotified, BookingNotifiedTs: &notifiedAt, GameStatus: playtomic.GameStatusPlayed, ResultsStatus: playtomic.ResultsStatusWaitingFor},
+			contains: []string{"Booking notification: sent 2024-03-15 18:30 UTC", "confirmed in Playtomic (results status WAITING_FOR)"},
+		},
+		{
+			name:     "result posted",
+			match:    playtomic.PadelMatch{MatchID: "m1", ProcessingStatus: playtomic.StatusResultNotified, BookingNotifiedTs: &notifiedAt, ResultNotifiedTs: &notifiedAt},
+			contains: []string{"Result notification: sent 2024-03-15 18:30 UTC", "Next: player stats will be updated"},
+		},
+		{
+			name:     "deleted match",
+			match:    playtomic.PadelMatch{MatchID: "m1", ProcessingStatus: playtomic.StatusBallBoyAssigned, DeletedAt: &notifiedAt},
+			contains: []string{"has been deleted"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := formatMatchStatus(&tt.match)
+			for _, want := range tt.contains {
+				assert.Contains(t, text, want)
+			}
+		})
+	}
+}
+
+func TestNextMatchCommandHandler(t *testing.T) {
+	setup := func(t *testing.T) (*Server, *notifier.Mock, func()) {
+		notif := notifier.NewMock()
+		notif.FormatNextMatchResponseFunc = func(match *playtomic.PadelMatch, playerID string) (any, error) {
+			return slack.Message{Msg: slack.Msg{Text: fmt.Sprintf("next:%s:%s", match.MatchID, playerID)}}, nil
+		}
+		server, teardown := setupTestServer(t, playtomic.NewMockClient(), notif, testSlackSigningSecret)
+		server.Store.AddPlayer("p1", "Morten Voss", 1.0)
+		require.NoError(t, server.Store.SetSlackUserID("p1", "U123"))
+		return server, notif, teardown
+	}
+	nextRequest := func(t *testing.T, server *Server, slackUserID string) *httptest.ResponseRecorder {
+		req := createSlackCommandRequest(t, "/slack/command/next", url.Values{"user_id": {slackUserID}}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("shows the next match for a mapped player", func(t *testing.T) {
+		server, notif, teardown := setup(t)
+		defer teardown()
+		require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{
+			MatchID:    "m1",
+			OwnerID:    "p1",
+			Start:      time.Now().Add(24 * time.Hour).Unix(),
+			GameStatus: playtomic.GameStatusPending,
+			Teams:      []playtomic.Team{{ID: "t1", Players: []playtomic.Player{{UserID: "p1", Name: "Morten Voss"}}}},
+		}))
+
+		rr := nextRequest(t, server, "U123")
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "next:m1:p1")
+		assert.NotNil(t, notif.LastNextMatchResponse)
+	})
+
+	t.Run("says so when there is no upcoming match", func(t *testing.T) {
+		server, notif, teardown := setup(t)
+		defer teardown()
+
+		rr := nextRequest(t, server, "U123")
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "no upcoming matches")
+		assert.Nil(t, notif.LastNextMatchResponse)
+	})
+
+	t.Run("prompts unmapped users to link", func(t *testing.T) {
+		server, _, teardown := setup(t)
+		defer teardown()
+
+		rr := nextRequest(t, server, "U999")
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "not linked")
+	})
+}
+
+func TestMigrationStatusHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+	defer teardown()
+
+	req := httptest.NewRequest("GET", "/admin/migrations", nil)
+	rr := httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var status database.MigrationStatus
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &status))
+	require.NotEmpty(t, status.Applied)
+	assert.Equal(t, "000001_initial_schema.sql", status.Applied[0])
+	assert.Equal(t, int64(len(status.Applied)), status.Version)
+}
+
+func TestAdminSlackCommands(t *testing.T) {
+	newResponseURL := func(t *testing.T) (string, <-chan slack.Message) {
+		received := make(chan slack.Message, 1)
+		responseServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var msg slack.Message
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+			received <- msg
+		}))
+		t.Cleanup(responseServer.Close)
+		return responseServer.URL, received
+	}
+
+	t.Run("rejects non-admins", func(t *testing.T) {
+		server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+		defer teardown()
+		server.Cfg.AdminSlackUserIDs = []string{"UADMIN"}
+		responseURL, received := newResponseURL(t)
+
+		for _, command := range []string{"refresh", "process"} {
+			req := createSlackCommandRequest(t, "/slack/command/"+command, url.Values{
+				"user_id":      {"UOTHER"},
+				"response_url": {responseURL},
+			}, testSlackSigningSecret)
+			rr := httptest.NewRecorder()
+			server.Router.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Contains(t, rr.Body.String(), "only admins")
+		}
+		require.NoError(t, server.Processor.Drain(context.Background()))
+		assert.Empty(t, received, "No work should be started for non-admins")
+	})
+
+	t.Run("acknowledges and posts the summary to the response_url", func(t *testing.T) {
+		playtomicClient := playtomic.NewMockClient()
+		playtomicClient.GetMatchesFunc = func(params *playtomic.SearchMatchesParams) ([]playtomic.MatchSummary, error) {
+			return []playtomic.MatchSummary{{MatchID: "m1"}, {MatchID: "m2"}}, nil
+		}
+		server, teardown := setupTestServer(t, playtomicClient, notifier.NewMock(), testSlackSigningSecret)
+		defer teardown()
+		server.Cfg.AdminSlackUserIDs = []string{"UADMIN"}
+		responseURL, received := newResponseURL(t)
+
+		req := createSlackCommandRequest(t, "/slack/command/refresh", url.Values{
+			"user_id":      {"UADMIN"},
+			"response_url": {responseURL},
+		}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Fetching matches")
+
+		select {
+		case msg := <-received:
+			assert.Equal(t, slack.ResponseTypeEphemeral, msg.ResponseType)
+			assert.Equal(t, "Fetched 2 matches from Playtomic, 0 of them club matches.", msg.Text)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected a delayed response on the response_url")
+		}
+	})
+}
+
+func TestLeaderboardCommandHandler_DelayedResponse(t *testing.T) {
+	notif := notifier.NewMock()
+	notif.FormatLeaderboardResponseFunc = func(stats []club.PlayerStats) (any, error) {
+		return slack.Message{Msg: slack.Msg{ResponseType: slack.ResponseTypeInChannel, Text: "the leaderboard"}}, nil
+	}
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notif, testSlackSigningSecret)
+	defer teardown()
+	server.Cfg.Slack.DelayedResponses = true
+
+	received := make(chan slack.Message, 1)
+	responseServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var msg slack.Message
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		received <- msg
+	}))
+	defer responseServer.Close()
+
+	req := createSlackCommandRequest(t, "/slack/command/leaderboard", url.Values{"response_url": {responseServer.URL}}, testSlackSigningSecret)
+	rr := httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Working on it")
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, slack.ResponseTypeInChannel, msg.ResponseType)
+		assert.Equal(t, "the leaderboard", msg.Text)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the leaderboard to be posted to the response_url")
+	}
+}
+
+func TestResetBallBringerCountsCommandHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+	defer teardown()
+	server.Cfg.AdminSlackUserIDs = []string{"UADMIN"}
+
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+	server.Store.AddPlayer("p2", "Player Two", 1.0)
+	_, _, err := server.Store.AssignBallBringerAtomically("m1", []string{"p1"})
+	require.NoError(t, err)
+	_, _, err = server.Store.AssignBallBringerAtomically("m2", []string{"p2"})
+	require.NoError(t, err)
+
+	resetBalls := func(userID, text string) string {
+		req := createSlackCommandRequest(t, "/slack/command/reset-balls", url.Values{"user_id": {userID}, "text": {text}}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+	counts := func() map[string]int {
+		players, err := server.Store.GetBallBringerCounts()
 		require.NoError(t, err)
-		require.Len(t, matches, 1)
-		assert.Equal(t, playtomic.StatusResultNotified, matches[0].ProcessingStatus)
+		counts := make(map[string]int)
+		for _, p := range players {
+			counts[p.ID] = p.BallBringerCount
+		}
+		return counts
+	}
+
+	assert.Contains(t, resetBalls("UOTHER", ""), "only admins")
+	assert.Equal(t, map[string]int{"p1": 1, "p2": 1}, counts())
+
+	assert.Contains(t, resetBalls("UADMIN", "p1"), "Reset the ball bringer count of player p1")
+	assert.Equal(t, map[string]int{"p1": 0, "p2": 1}, counts())
+
+	assert.Contains(t, resetBalls("UADMIN", "unknown"), "was not found")
+
+	assert.Contains(t, resetBalls("UADMIN", ""), "every player")
+	assert.Equal(t, map[string]int{"p1": 0, "p2": 0}, counts())
+}
+
+func TestNewSeasonCommandHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+	defer teardown()
+	server.Cfg.AdminSlackUserIDs = []string{"UADMIN"}
+
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+	server.Store.AddPlayer("p2", "Player Two", 1.0)
+	match := &playtomic.PadelMatch{
+		MatchID:       "m1",
+		OwnerID:       "p1",
+		GameStatus:    playtomic.GameStatusPlayed,
+		ResultsStatus: playtomic.ResultsStatusConfirmed,
+		Teams: []playtomic.Team{
+			{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p1", Name: "Player One"}}},
+			{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p2", Name: "Player Two"}}},
+		},
+		Results: []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 3}}},
+	}
+	require.NoError(t, server.Store.UpsertMatch(match))
+	server.Store.UpdatePlayerStats(match)
+
+	newSeason := func(userID string) string {
+		req := createSlackCommandRequest(t, "/slack/command/new-season", url.Values{"user_id": {userID}}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	assert.Contains(t, newSeason("UOTHER"), "only admins")
+	stats, err := server.Store.GetPlayerStats(1, club.SortByWins, false, false)
+	require.NoError(t, err)
+	assert.Len(t, stats, 2, "Non-admins should not be able to reset the leaderboard")
+
+	assert.Contains(t, newSeason("UADMIN"), "Season 2 has started")
+	stats, err = server.Store.GetPlayerStats(1, club.SortByWins, false, false)
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+
+	req := httptest.NewRequest("GET", "/leaderboard?all_time=true", nil)
+	rr := httptest.NewRecorder()
+	server.Router.ServeHTTP(rr, req)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &stats))
+	assert.Len(t, stats, 2)
+}
+
+func TestUndoLastCommandHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+	defer teardown()
+	server.Cfg.AdminSlackUserIDs = []string{"UADMIN"}
+
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+	server.Store.AddPlayer("p2", "Player Two", 1.0)
+	match := &playtomic.PadelMatch{
+		MatchID:       "m1",
+		OwnerID:       "p1",
+		GameStatus:    playtomic.GameStatusPlayed,
+		ResultsStatus: playtomic.ResultsStatusConfirmed,
+		Teams: []playtomic.Team{
+			{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{UserID: "p1", Name: "Player One"}}},
+			{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{UserID: "p2", Name: "Player Two"}}},
+		},
+		Results: []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 3}}},
+	}
+	require.NoError(t, server.Store.UpsertMatch(match))
+	server.Store.UpdatePlayerStats(match)
+	require.NoError(t, server.Store.UpdateProcessingStatus("m1", playtomic.StatusCompleted))
+
+	undo := func(userID string) string {
+		req := createSlackCommandRequest(t, "/slack/command/undo-last", url.Values{"user_id": {userID}}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	assert.Contains(t, undo("UOTHER"), "only admins")
+	stats, err := server.Store.GetPlayerStatsByName("Player One", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.MatchesPlayed, "Non-admins should not be able to undo stats")
+
+	assert.Contains(t, undo("UADMIN"), "Undid the stats for match m1")
+	stats, err = server.Store.GetPlayerStatsByName("Player One", false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.MatchesPlayed)
+
+	assert.Contains(t, undo("UADMIN"), "no match stats to undo")
+}
+
+func TestRequeueCommandHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+	defer teardown()
+	server.Cfg.AdminSlackUserIDs = []string{"UADMIN"}
+
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+	require.NoError(t, server.Store.UpsertMatch(&playtomic.PadelMatch{MatchID: "m1", OwnerID: "p1", GameStatus: playtomic.GameStatusPlayed, ResultsStatus: playtomic.ResultsStatusConfirmed}))
+	require.NoError(t, server.Store.UpdateProcessingStatus("m1", playtomic.StatusNeedsReview))
+
+	requeue := func(userID, text string) string {
+		req := createSlackCommandRequest(t, "/slack/command/requeue", url.Values{"user_id": {userID}, "text": {text}}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	assert.Contains(t, requeue("UOTHER", "m1"), "only admins")
+	match, err := server.Store.GetMatch("m1")
+	require.NoError(t, err)
+	assert.Equal(t, playtomic.StatusNeedsReview, match.ProcessingStatus, "Non-admins should not be able to requeue matches")
+
+	assert.Contains(t, requeue("UADMIN", ""), "Usage")
+	assert.Contains(t, requeue("UADMIN", "m1"), "Requeued match m1 as RESULT_AVAILABLE")
+	match, err = server.Store.GetMatch("m1")
+	require.NoError(t, err)
+	assert.Equal(t, playtomic.StatusResultAvailable, match.ProcessingStatus)
+
+	assert.Contains(t, requeue("UADMIN", "m1"), "not marked for review")
+}
+
+func TestMergePlayersCommandHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+	defer teardown()
+	server.Cfg.AdminSlackUserIDs = []string{"UADMIN"}
+	server.Store.AddPlayer("p1", "Player One", 1.0)
+	server.Store.AddPlayer("p1-dupe", "Player One", 1.0)
+
+	merge := func(userID, text string) string {
+		req := createSlackCommandRequest(t, "/slack/command/merge", url.Values{"user_id": {userID}, "text": {text}}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	assert.Contains(t, merge("UOTHER", "p1 p1-dupe"), "only admins")
+	assert.True(t, server.Store.IsKnownPlayer("p1-dupe"), "Non-admins should not be able to merge players")
+
+	assert.Contains(t, merge("UADMIN", "p1"), "Usage")
+	assert.Contains(t, merge("UADMIN", "p1 unknown"), "Could not merge player unknown into p1")
+	assert.Contains(t, merge("UADMIN", "p1 p1-dupe"), "Merged player p1-dupe into p1.")
+	assert.True(t, server.Store.IsKnownPlayer("p1"))
+	assert.False(t, server.Store.IsKnownPlayer("p1-dupe"))
+}
+
+func TestMapPlayerCommandHandler(t *testing.T) {
+	server, teardown := setupTestServer(t, playtomic.NewMockClient(), notifier.NewMock(), testSlackSigningSecret)
+	defer teardown()
+	server.Cfg.AdminSlackUserIDs = []string{"UADMIN"}
+	server.Store.AddPlayer("p1", "Morten Voss", 1.0)
+	server.Store.AddPlayer("p2", "Morten Hansen", 1.0)
+	server.Store.AddPlayer("p3", "Anna Berg", 1.0)
+
+	mapPlayer := func(userID, text string) string {
+		req := createSlackCommandRequest(t, "/slack/command/map", url.Values{"user_id": {userID}, "text": {text}}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var msg slack.Message
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &msg))
+		return msg.Text
+	}
+
+	t.Run("rejects non-admins", func(t *testing.T) {
+		assert.Contains(t, mapPlayer("UOTHER", "<@U111|anna> Anna"), "only admins")
+		_, found, err := server.Store.GetSlackUserIDByPlayerID("p3")
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("dry run leaves the player unmapped", func(t *testing.T) {
+		req := createSlackCommandRequest(t, "/slack/command/map?dry_run=true", url.Values{"user_id": {"UADMIN"}, "text": {"<@U111> Anna"}}, testSlackSigningSecret)
+		rr := httptest.NewRecorder()
+		server.Router.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		var msg slack.Message
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &msg))
+		assert.Equal(t, "[Dry Run] Would have linked <@U111> to Anna Berg (p3).", msg.Text)
+		_, found, err := server.Store.GetSlackUserIDByPlayerID("p3")
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("maps a uniquely matching player", func(t *testing.T) {
+		assert.Contains(t, mapPlayer("UADMIN", "<@U111|anna> anna"), "Linked <@U111> to Anna Berg (p3).")
+		slackUserID, found, err := server.Store.GetSlackUserIDByPlayerID("p3")
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, "U111", slackUserID)
+	})
+
+	t.Run("lists candidates for an ambiguous name", func(t *testing.T) {
+		body := mapPlayer("UADMIN", "<@U222> Morten")
+		assert.Contains(t, body, "matches several players")
+		assert.Contains(t, body, "Morten Hansen (p2)")
+		assert.Contains(t, body, "Morten Voss (p1)")
+		_, found, err := server.Store.GetSlackUserIDByPlayerID("p1")
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("prefers an exact name among several matches", func(t *testing.T) {
+		assert.Contains(t, mapPlayer("UADMIN", "<@U222> Morten Voss"), "Linked <@U222> to Morten Voss (p1).")
+	})
+
+	t.Run("moves an already linked Slack user", func(t *testing.T) {
+		body := mapPlayer("UADMIN", "<@U222> Morten Hansen")
+		assert.Contains(t, body, "Linked <@U222> to Morten Hansen (p2).")
+		assert.Contains(t, body, "It was linked to Morten Voss (p1) before.")
+
+		player, found, err := server.Store.GetPlayerBySlackUserID("U222")
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, "p2", player.ID)
+		_, found, err = server.Store.GetSlackUserIDByPlayerID("p1")
+		require.NoError(t, err)
+		assert.False(t, found)
 	})
 }
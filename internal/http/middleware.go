@@ -1,9 +1,12 @@
 package http
 
 import (
+	"bytes"
 	"context"
+	"crypto/subtle"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/log"
@@ -77,14 +80,61 @@ func (s *Server) VerifySlackSignature(next http.Handler) http.Handler {
 			return
 		}
 
-		r.Body = io.NopCloser(io.TeeReader(r.Body, &verifier))
-
-		next.ServeHTTP(w, r)
-
+		// The signature covers the whole body, so it is checked before the handler sees the request.
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Error reading request body", http.StatusBadRequest)
+			return
+		}
+		if _, err := verifier.Write(body); err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
 		if err = verifier.Ensure(); err != nil {
 			log.Error("Slack signature verification failed", "error", err)
 			http.Error(w, "Unauthorized: Slack signature verification failed", http.StatusUnauthorized)
 			return
 		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// adminOnly is a middleware for admin slash commands. Commands from users who are not admins are answered
+// with an ephemeral refusal instead of being run. It trusts the form's user_id, so it must come after
+// VerifySlackSignature in the chain.
+func (s *Server) adminOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parsing form", http.StatusBadRequest)
+			return
+		}
+		userID := r.FormValue("user_id")
+		if !s.requireAdmin(userID) {
+			log.Warn("Rejected admin command from non-admin", "command", r.FormValue("command"), "user", userID)
+			respondWithSlackText(w, "Sorry, only admins can use this command.")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireAdminToken is a middleware for the admin HTTP endpoints. Requests must carry the configured admin API
+// token as a bearer token; without a configured token every request is refused.
+func (s *Server) requireAdminToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if s.Cfg.AdminAPIToken == "" || !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.Cfg.AdminAPIToken)) != 1 {
+			log.Warn("Rejected admin request without a valid token", "url", r.URL.Path)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
 	})
 }
+
+// requireAdmin reports whether the Slack user is one of the configured admins (see ADMIN_SLACK_USER_IDS).
+func (s *Server) requireAdmin(slackUserID string) bool {
+	return slackUserID != "" && slices.Contains(s.Cfg.AdminSlackUserIDs, slackUserID)
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"database/sql"
 	"net/http"
 
 	"github.com/mauv0809/ideal-tribble/internal/club"
@@ -12,9 +13,10 @@ import (
 	"github.com/mauv0809/ideal-tribble/internal/pubsub"
 )
 
-func NewServer(store club.ClubStore, metricsSvc metrics.Metrics, metricsHandler http.Handler, cfg config.Config, playtomicClient playtomic.PlaytomicClient, notifier notifier.Notifier, processor *processor.Processor, pubsub pubsub.PubSubClient /*inngestClient inngest.InngestClient*/) *Server {
+func NewServer(store club.ClubStore, db *sql.DB, metricsSvc metrics.Metrics, metricsHandler http.Handler, cfg config.Config, playtomicClient playtomic.PlaytomicClient, notifier notifier.Notifier, processor *processor.Processor, pubsub pubsub.PubSubClient /*inngestClient inngest.InngestClient*/) *Server {
 	server := &Server{
 		Store:           store,
+		DB:              db,
 		Metrics:         metricsSvc,
 		MetricsHandler:  metricsHandler,
 		Cfg:             cfg,
@@ -26,6 +28,7 @@ func NewServer(store club.ClubStore, metricsSvc metrics.Metrics, metricsHandler
 		//InngestClient:   inngestClient,
 	}
 
+	server.Commands = server.commandRegistry()
 	server.routes()
 	return server
 }
@@ -36,18 +39,38 @@ func (s *Server) routes() {
 	// e.g. Chain(s.MyHandler(), paramsMiddleware, authMiddleware)
 	s.Router.Handle("/metrics", s.MetricsHandler)
 	s.Router.Handle("/health", Chain(s.HealthCheckHandler(), paramsMiddleware))
-	s.Router.Handle("/clear", Chain(s.ClearStoreHandler(), paramsMiddleware))
 	s.Router.Handle("/members", Chain(s.ListMembersHandler(), paramsMiddleware))
+	s.Router.Handle("POST /players/merge", Chain(s.MergePlayersHandler(), s.requireAdminToken, paramsMiddleware))
+	s.Router.Handle("POST /players/slack-user", Chain(s.SlackUserHandler(), s.requireAdminToken, paramsMiddleware))
+	s.Router.Handle("POST /players/core", Chain(s.CorePlayerHandler(), s.requireAdminToken, paramsMiddleware))
 	s.Router.Handle("/matches", Chain(s.ListMatchesHandler(), paramsMiddleware))
+	s.Router.Handle("POST /matches/undelete", Chain(s.UndeleteMatchHandler(), s.requireAdminToken, paramsMiddleware))
+	s.Router.Handle("/matches/week", Chain(s.WeekMatchesHandler(), paramsMiddleware))
+	s.Router.Handle("/recap/weekly", Chain(s.PostWeeklyRecapHandler(), paramsMiddleware))
+	s.Router.Handle("/digest/daily", Chain(s.PostDailyDigestHandler(), paramsMiddleware))
+	s.Router.Handle("/leaderboard", Chain(s.LeaderboardHandler(), paramsMiddleware))
+	s.Router.Handle("/reports/spend", Chain(s.SpendReportHandler(), paramsMiddleware))
+	s.Router.Handle("/admin/migrations", Chain(s.MigrationStatusHandler(), paramsMiddleware))
+	s.Router.Handle("POST /admin/migrations/blobs", Chain(s.ReencodeBlobsHandler(), s.requireAdminToken, paramsMiddleware))
+	s.Router.Handle("/stats/processing", Chain(s.ProcessingStatusCountsHandler(), paramsMiddleware))
+	s.Router.Handle("/stats/club", Chain(s.ClubStatsHandler(), paramsMiddleware))
 	s.Router.Handle("/fetch", Chain(s.FetchMatchesHandler(), paramsMiddleware))
 	s.Router.Handle("/process", Chain(s.ProcessMatchesHandler(), paramsMiddleware))
+	s.Router.Handle("POST /process/{id}", Chain(s.ProcessMatchHandler(), s.requireAdminToken, paramsMiddleware))
+	s.Router.Handle("POST /refetch/{id}", Chain(s.RefetchMatchHandler(), s.requireAdminToken, paramsMiddleware))
 	s.Router.Handle("/assign-ball-boy", Chain(s.BallBoyHandler(), paramsMiddleware))
 	s.Router.Handle("/update-player-stats", Chain(s.UpdatePlayerStatsHandler(), paramsMiddleware))
 	s.Router.Handle("/notify-booking", Chain(s.NotifyBookingHandler(), paramsMiddleware))
 	s.Router.Handle("/notify-result", Chain(s.NotifyResultHandler(), paramsMiddleware))
-	s.Router.Handle("/slack/command/leaderboard", Chain(s.LeaderboardCommandHandler(), s.VerifySlackSignature, paramsMiddleware))
-	s.Router.Handle("/slack/command/player-stats", Chain(s.PlayerStatsCommandHandler(), s.VerifySlackSignature, paramsMiddleware))
-	s.Router.Handle("/slack/command/level-leaderboard", Chain(s.LevelLeaderboardCommandHandler(), s.VerifySlackSignature, paramsMiddleware))
+	s.registerCommandRoutes()
+	s.Router.Handle("/slack/events", Chain(s.SlackEventsHandler(), s.VerifySlackSignature, paramsMiddleware))
+	// Endpoints that wipe data or inject fake matches are only registered when enabled, so they 404 in production.
+	if s.Cfg.EnableDestructiveEndpoints {
+		s.Router.Handle("/clear", Chain(s.ClearStoreHandler(), paramsMiddleware))
+	}
+	if s.Cfg.EnableTestEndpoints {
+		s.Router.Handle("/matches/seed", Chain(s.SeedMatchHandler(), paramsMiddleware))
+	}
 	//s.Router.Handle("/inngest/send", s.SendInngestEventHandler())
 	//s.Router.Handle("/api/inngest", s.InngestClient.Serve())
 }
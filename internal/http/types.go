@@ -1,6 +1,7 @@
 package http
 
 import (
+	"database/sql"
 	"net/http"
 
 	"github.com/mauv0809/ideal-tribble/internal/club"
@@ -14,6 +15,7 @@ import (
 
 type Server struct {
 	Store           club.ClubStore
+	DB              *sql.DB
 	Metrics         metrics.Metrics
 	MetricsHandler  http.Handler
 	Cfg             config.Config
@@ -21,6 +23,7 @@ type Server struct {
 	Notifier        notifier.Notifier
 	Processor       *processor.Processor
 	Router          *http.ServeMux
+	Commands        *CommandRegistry
 	pubsub          pubsub.PubSubClient
 	//InngestClient   inngest.InngestClient
 }
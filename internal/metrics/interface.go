@@ -9,4 +9,10 @@ type Metrics interface {
 	IncSlackNotifSent()
 	IncSlackNotifFailed()
 	SetStartupTime(duration float64)
+	SetMatchesInStatus(status string, count int)
+	SetMatchesStuck(status string, count int)
+	ObservePlaytomicRequestDuration(endpoint string, duration float64)
+	IncPlaytomicRequestErrors(endpoint, statusClass string)
+	IncIncompleteMatches()
+	IncInvalidMatches()
 }
@@ -12,12 +12,22 @@ type Mock struct {
 	slackNotifSent      int
 	slackNotifFailed    int
 	startupTime         float64
+	matchesInStatus     map[string]int
+	matchesStuck        map[string]int
+	playtomicDurations  map[string][]float64
+	playtomicErrors     map[string]int
+	incompleteMatches   int
+	invalidMatches      int
 }
 
 // NewMock creates a new mock instance.
 func NewMock() *Mock {
 	return &Mock{
 		processingDurations: make([]float64, 0),
+		matchesInStatus:     make(map[string]int),
+		matchesStuck:        make(map[string]int),
+		playtomicDurations:  make(map[string][]float64),
+		playtomicErrors:     make(map[string]int),
 	}
 }
 
@@ -57,6 +67,42 @@ func (m *Mock) SetStartupTime(duration float64) {
 	m.startupTime = duration
 }
 
+func (m *Mock) SetMatchesInStatus(status string, count int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.matchesInStatus[status] = count
+}
+
+func (m *Mock) SetMatchesStuck(status string, count int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.matchesStuck[status] = count
+}
+
+func (m *Mock) ObservePlaytomicRequestDuration(endpoint string, duration float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.playtomicDurations[endpoint] = append(m.playtomicDurations[endpoint], duration)
+}
+
+func (m *Mock) IncPlaytomicRequestErrors(endpoint, statusClass string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.playtomicErrors[endpoint+"/"+statusClass]++
+}
+
+func (m *Mock) IncIncompleteMatches() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.incompleteMatches++
+}
+
+func (m *Mock) IncInvalidMatches() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.invalidMatches++
+}
+
 // FetcherRuns returns the number of times IncFetcherRuns was called.
 func (m *Mock) FetcherRuns() int {
 	m.mu.Lock()
@@ -71,6 +117,13 @@ func (m *Mock) MatchesProcessed() int {
 	return m.matchesProcessed
 }
 
+// ProcessingDurations returns the durations passed to ObserveProcessingDuration.
+func (m *Mock) ProcessingDurations() []float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]float64(nil), m.processingDurations...)
+}
+
 // SlackNotifSent returns the number of times IncSlackNotifSent was called.
 func (m *Mock) SlackNotifSent() int {
 	m.mu.Lock()
@@ -84,3 +137,47 @@ func (m *Mock) SlackNotifFailed() int {
 	defer m.mu.Unlock()
 	return m.slackNotifFailed
 }
+
+// MatchesInStatus returns the last count set for the given processing status.
+func (m *Mock) MatchesInStatus(status string) (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count, ok := m.matchesInStatus[status]
+	return count, ok
+}
+
+// MatchesStuck returns the last stuck match count set for the given processing status.
+func (m *Mock) MatchesStuck(status string) (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count, ok := m.matchesStuck[status]
+	return count, ok
+}
+
+// PlaytomicRequestDurations returns the durations observed for the given Playtomic endpoint.
+func (m *Mock) PlaytomicRequestDurations(endpoint string) []float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]float64(nil), m.playtomicDurations[endpoint]...)
+}
+
+// PlaytomicRequestErrors returns the number of errors counted for the given Playtomic endpoint and status class.
+func (m *Mock) PlaytomicRequestErrors(endpoint, statusClass string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.playtomicErrors[endpoint+"/"+statusClass]
+}
+
+// IncompleteMatches returns the number of times IncIncompleteMatches was called.
+func (m *Mock) IncompleteMatches() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.incompleteMatches
+}
+
+// InvalidMatches returns the number of times IncInvalidMatches was called.
+func (m *Mock) InvalidMatches() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.invalidMatches
+}
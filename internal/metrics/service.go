@@ -53,6 +53,31 @@ func NewService(registerer ...prometheus.Registerer) *Service {
 			Name: "padel_startup_duration_seconds",
 			Help: "The duration of the application startup in seconds.",
 		}),
+		MatchesByStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "padel_matches_by_processing_status",
+			Help: "The number of matches currently in each processing status.",
+		}, []string{"status"}),
+		MatchesStuck: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "padel_matches_stuck",
+			Help: "The number of matches that have waited longer than expected in a processing status, e.g. because a Pub/Sub message was lost.",
+		}, []string{"status"}),
+		PlaytomicRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "padel_playtomic_request_duration_seconds",
+			Help:    "The duration of requests to the Playtomic API.",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"endpoint"}),
+		PlaytomicRequestErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "padel_playtomic_request_errors_total",
+			Help: "The total number of failed requests to the Playtomic API, by status class.",
+		}, []string{"endpoint", "status_class"}),
+		IncompleteMatches: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "padel_incomplete_matches_total",
+			Help: "The total number of matches sent to review because they had fewer players than their format requires.",
+		}),
+		InvalidMatches: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "padel_invalid_matches_total",
+			Help: "The total number of fetched matches skipped because Playtomic left out essential fields.",
+		}),
 	}
 
 	reg.MustRegister(
@@ -62,6 +87,12 @@ func NewService(registerer ...prometheus.Registerer) *Service {
 		s.SlackNotifSent,
 		s.SlackNotifFailed,
 		s.StartupTimeSeconds,
+		s.MatchesByStatus,
+		s.MatchesStuck,
+		s.PlaytomicRequestDuration,
+		s.PlaytomicRequestErrors,
+		s.IncompleteMatches,
+		s.InvalidMatches,
 	)
 
 	return s
@@ -90,3 +121,27 @@ func (s *Service) IncSlackNotifFailed() {
 func (s *Service) SetStartupTime(duration float64) {
 	s.StartupTimeSeconds.Set(duration)
 }
+
+func (s *Service) SetMatchesInStatus(status string, count int) {
+	s.MatchesByStatus.WithLabelValues(status).Set(float64(count))
+}
+
+func (s *Service) SetMatchesStuck(status string, count int) {
+	s.MatchesStuck.WithLabelValues(status).Set(float64(count))
+}
+
+func (s *Service) ObservePlaytomicRequestDuration(endpoint string, duration float64) {
+	s.PlaytomicRequestDuration.WithLabelValues(endpoint).Observe(duration)
+}
+
+func (s *Service) IncPlaytomicRequestErrors(endpoint, statusClass string) {
+	s.PlaytomicRequestErrors.WithLabelValues(endpoint, statusClass).Inc()
+}
+
+func (s *Service) IncIncompleteMatches() {
+	s.IncompleteMatches.Inc()
+}
+
+func (s *Service) IncInvalidMatches() {
+	s.InvalidMatches.Inc()
+}
@@ -11,4 +11,12 @@ type Service struct {
 	SlackNotifSent     prometheus.Counter
 	SlackNotifFailed   prometheus.Counter
 	StartupTimeSeconds prometheus.Gauge
+	MatchesByStatus    *prometheus.GaugeVec
+	MatchesStuck       *prometheus.GaugeVec
+
+	PlaytomicRequestDuration *prometheus.HistogramVec
+	PlaytomicRequestErrors   *prometheus.CounterVec
+
+	IncompleteMatches prometheus.Counter
+	InvalidMatches    prometheus.Counter
 }
@@ -13,27 +13,71 @@ type Mock struct {
 	mu sync.Mutex
 
 	// Call records
-	SendBookingNotificationCalls []struct{ Match *playtomic.PadelMatch }
-	SendResultNotificationCalls  []struct{ Match *playtomic.PadelMatch }
-	SendLeaderboardCalls         [][]club.PlayerStats
-	SendLevelLeaderboardCalls    [][]club.PlayerInfo
-	SendPlayerStatsCalls         []struct {
+	SendBookingNotificationCalls []struct {
+		Match        *playtomic.PadelMatch
+		SlackUserIDs map[string]string
+		GuestIDs     map[string]bool
+	}
+	SendResultNotificationCalls []struct {
+		Match    *playtomic.PadelMatch
+		ThreadTS string
+	}
+	SendResultCorrectionCalls []struct {
+		Match    *playtomic.PadelMatch
+		ThreadTS string
+	}
+	SendLeaderboardCalls      [][]club.PlayerStats
+	SendLevelLeaderboardCalls [][]club.PlayerInfo
+	SendPlayerStatsCalls      []struct {
 		Stats *club.PlayerStats
 		Query string
 	}
 	SendPlayerNotFoundCalls []string
+	SendNewPlayersCalls     [][]club.PlayerInfo
+	SendWeeklyRecapCalls    []club.WeeklyRecap
+	SendBookingDigestCalls  [][]*playtomic.PadelMatch
+	SendDirectMessageCalls  []struct {
+		SlackUserID string
+		Text        string
+	}
+	SendBallBringerReminderCalls []struct {
+		SlackUserID string
+		Match       *playtomic.PadelMatch
+	}
+	SendIncompleteLineupReminderCalls []struct {
+		SlackUserID string
+		Match       *playtomic.PadelMatch
+		Players     int
+		Required    int
+	}
+	PublishHomeViewCalls []struct {
+		SlackUserID string
+		View        HomeView
+	}
 
 	// Spies for format functions
 	FormatLeaderboardResponseFunc      func(stats []club.PlayerStats) (any, error)
 	FormatLevelLeaderboardResponseFunc func(players []club.PlayerInfo) (any, error)
 	FormatPlayerStatsResponseFunc      func(stats *club.PlayerStats, query string) (any, error)
 	FormatPlayerNotFoundResponseFunc   func(query string) (any, error)
+	FormatBallBringerResponseFunc      func(players []club.PlayerInfo) (any, error)
+	FormatRosterResponseFunc           func(players []club.PlayerInfo, page int) (any, error)
+	FormatFormResponseFunc             func(playerName string, form []bool) (any, error)
+	FormatNextMatchResponseFunc        func(match *playtomic.PadelMatch, playerID string) (any, error)
+	FormatLeaderboardDiffResponseFunc  func(before, after string, deltas []club.PlayerStatsDelta) (any, error)
+	FormatUnmappedPlayersResponseFunc  func(players []club.PlayerInfo) (any, error)
 
 	// Call records for format functions
 	LastLeaderboardResponse      any
 	LastLevelLeaderboardResponse any
 	LastPlayerStatsResponse      any
 	LastPlayerNotFoundResponse   any
+	LastBallBringerResponse      any
+	LastRosterResponse           any
+	LastFormResponse             any
+	LastNextMatchResponse        any
+	LastLeaderboardDiffResponse  any
+	LastUnmappedPlayersResponse  any
 }
 
 // NewMock creates a new mock instance.
@@ -47,27 +91,117 @@ func (m *Mock) Reset() {
 	defer m.mu.Unlock()
 	m.SendBookingNotificationCalls = nil
 	m.SendResultNotificationCalls = nil
+	m.SendResultCorrectionCalls = nil
 	m.SendLeaderboardCalls = nil
 	m.SendLevelLeaderboardCalls = nil
 	m.SendPlayerStatsCalls = nil
 	m.SendPlayerNotFoundCalls = nil
+	m.SendDirectMessageCalls = nil
+	m.SendBallBringerReminderCalls = nil
+	m.SendIncompleteLineupReminderCalls = nil
+	m.SendNewPlayersCalls = nil
+	m.SendWeeklyRecapCalls = nil
+	m.SendBookingDigestCalls = nil
+	m.PublishHomeViewCalls = nil
 	m.LastLeaderboardResponse = nil
 	m.LastLevelLeaderboardResponse = nil
 	m.LastPlayerStatsResponse = nil
 	m.LastPlayerNotFoundResponse = nil
+	m.LastBallBringerResponse = nil
+	m.LastNextMatchResponse = nil
+}
+
+func (m *Mock) SendBookingNotification(match *playtomic.PadelMatch, slackUserIDs map[string]string, guestIDs map[string]bool, dryRun bool) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.SendBookingNotificationCalls = append(m.SendBookingNotificationCalls, struct {
+		Match        *playtomic.PadelMatch
+		SlackUserIDs map[string]string
+		GuestIDs     map[string]bool
+	}{match, slackUserIDs, guestIDs})
+	return "booking-ts", nil
+}
+
+func (m *Mock) SendResultNotification(match *playtomic.PadelMatch, threadTS string, dryRun bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.SendResultNotificationCalls = append(m.SendResultNotificationCalls, struct {
+		Match    *playtomic.PadelMatch
+		ThreadTS string
+	}{match, threadTS})
+	return nil
+}
+
+func (m *Mock) SendResultCorrection(match *playtomic.PadelMatch, threadTS string, dryRun bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.SendResultCorrectionCalls = append(m.SendResultCorrectionCalls, struct {
+		Match    *playtomic.PadelMatch
+		ThreadTS string
+	}{match, threadTS})
+	return nil
+}
+
+func (m *Mock) SendDirectMessage(slackUserID, text string, dryRun bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.SendDirectMessageCalls = append(m.SendDirectMessageCalls, struct {
+		SlackUserID string
+		Text        string
+	}{slackUserID, text})
+	return nil
+}
+
+func (m *Mock) SendBallBringerReminder(slackUserID string, match *playtomic.PadelMatch, dryRun bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.SendBallBringerReminderCalls = append(m.SendBallBringerReminderCalls, struct {
+		SlackUserID string
+		Match       *playtomic.PadelMatch
+	}{slackUserID, match})
+	return nil
+}
+
+func (m *Mock) SendIncompleteLineupReminder(slackUserID string, match *playtomic.PadelMatch, players, required int, dryRun bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.SendIncompleteLineupReminderCalls = append(m.SendIncompleteLineupReminderCalls, struct {
+		SlackUserID string
+		Match       *playtomic.PadelMatch
+		Players     int
+		Required    int
+	}{slackUserID, match, players, required})
+	return nil
+}
+
+func (m *Mock) PublishHomeView(slackUserID string, view HomeView, dryRun bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.PublishHomeViewCalls = append(m.PublishHomeViewCalls, struct {
+		SlackUserID string
+		View        HomeView
+	}{slackUserID, view})
+	return nil
 }
 
-func (m *Mock) SendBookingNotification(match *playtomic.PadelMatch, dryRun bool) error {
+func (m *Mock) SendBookingDigest(matches []*playtomic.PadelMatch, dryRun bool) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.SendBookingNotificationCalls = append(m.SendBookingNotificationCalls, struct{ Match *playtomic.PadelMatch }{match})
+	m.SendBookingDigestCalls = append(m.SendBookingDigestCalls, matches)
 	return nil
 }
 
-func (m *Mock) SendResultNotification(match *playtomic.PadelMatch, dryRun bool) error {
+func (m *Mock) SendWeeklyRecap(recap club.WeeklyRecap, dryRun bool) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.SendResultNotificationCalls = append(m.SendResultNotificationCalls, struct{ Match *playtomic.PadelMatch }{match})
+	m.SendWeeklyRecapCalls = append(m.SendWeeklyRecapCalls, recap)
+	return nil
+}
+
+func (m *Mock) SendNewPlayersNotification(players []club.PlayerInfo, dryRun bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.SendNewPlayersCalls = append(m.SendNewPlayersCalls, players)
 	return nil
 }
 
@@ -145,3 +279,69 @@ func (m *Mock) FormatPlayerNotFoundResponse(query string) (any, error) {
 	}
 	return "formatted_player_not_found", nil
 }
+
+func (m *Mock) FormatBallBringerResponse(players []club.PlayerInfo) (any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.FormatBallBringerResponseFunc != nil {
+		resp, err := m.FormatBallBringerResponseFunc(players)
+		m.LastBallBringerResponse = resp
+		return resp, err
+	}
+	return "formatted_ball_bringer", nil
+}
+
+func (m *Mock) FormatRosterResponse(players []club.PlayerInfo, page int) (any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.FormatRosterResponseFunc != nil {
+		resp, err := m.FormatRosterResponseFunc(players, page)
+		m.LastRosterResponse = resp
+		return resp, err
+	}
+	return "formatted_roster", nil
+}
+
+func (m *Mock) FormatFormResponse(playerName string, form []bool) (any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.FormatFormResponseFunc != nil {
+		resp, err := m.FormatFormResponseFunc(playerName, form)
+		m.LastFormResponse = resp
+		return resp, err
+	}
+	return "formatted_form", nil
+}
+
+func (m *Mock) FormatNextMatchResponse(match *playtomic.PadelMatch, playerID string) (any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.FormatNextMatchResponseFunc != nil {
+		resp, err := m.FormatNextMatchResponseFunc(match, playerID)
+		m.LastNextMatchResponse = resp
+		return resp, err
+	}
+	return "formatted_next_match", nil
+}
+
+func (m *Mock) FormatLeaderboardDiffResponse(before, after string, deltas []club.PlayerStatsDelta) (any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.FormatLeaderboardDiffResponseFunc != nil {
+		resp, err := m.FormatLeaderboardDiffResponseFunc(before, after, deltas)
+		m.LastLeaderboardDiffResponse = resp
+		return resp, err
+	}
+	return "formatted_leaderboard_diff", nil
+}
+
+func (m *Mock) FormatUnmappedPlayersResponse(players []club.PlayerInfo) (any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.FormatUnmappedPlayersResponseFunc != nil {
+		resp, err := m.FormatUnmappedPlayersResponseFunc(players)
+		m.LastUnmappedPlayersResponse = resp
+		return resp, err
+	}
+	return "formatted_unmapped_players", nil
+}
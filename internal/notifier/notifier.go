@@ -5,22 +5,65 @@ import (
 	"github.com/mauv0809/ideal-tribble/internal/playtomic"
 )
 
+// Notification types, used to route notifications to their own channel.
+const (
+	NotificationBooking          = "booking"
+	NotificationResult           = "result"
+	NotificationLeaderboard      = "leaderboard"
+	NotificationLevelLeaderboard = "level_leaderboard"
+	NotificationPlayerStats      = "player_stats"
+	NotificationNewPlayers       = "new_players"
+	NotificationWeeklyRecap      = "weekly_recap"
+)
+
+// HomeView is what a Slack user's personal home tab shows. Player is nil when the user is not linked to a player;
+// NextMatch is nil when the player has no upcoming match.
+type HomeView struct {
+	Player    *club.PlayerInfo
+	Stats     *club.PlayerStats
+	NextMatch *playtomic.PadelMatch
+	Form      []bool
+}
+
 // Notifier defines a high-level interface for sending notifications about business events.
 // This decouples the rest of the application from the specific notification provider (e.g., Slack).
 type Notifier interface {
-	// For upcoming matches
-	SendBookingNotification(match *playtomic.PadelMatch, dryRun bool) error
-	// For completed matches
-	SendResultNotification(match *playtomic.PadelMatch, dryRun bool) error
+	// For upcoming matches. slackUserIDs maps player IDs to Slack users, so mapped players can be mentioned.
+	// guestIDs holds the players who are not club members. It returns the timestamp of the sent message.
+	SendBookingNotification(match *playtomic.PadelMatch, slackUserIDs map[string]string, guestIDs map[string]bool, dryRun bool) (string, error)
+	// For the daily digest of new bookings, which replaces per-match booking notifications when enabled.
+	SendBookingDigest(matches []*playtomic.PadelMatch, dryRun bool) error
+	// For completed matches. A non-empty threadTS posts the result as a reply to that message.
+	SendResultNotification(match *playtomic.PadelMatch, threadTS string, dryRun bool) error
+	// For results Playtomic corrected after they were notified. threadTS works as for SendResultNotification.
+	SendResultCorrection(match *playtomic.PadelMatch, threadTS string, dryRun bool) error
+	// For messaging a single player
+	SendDirectMessage(slackUserID, text string, dryRun bool) error
+	// For telling the assigned ball bringer, in a direct message, to bring balls to the match
+	SendBallBringerReminder(slackUserID string, match *playtomic.PadelMatch, dryRun bool) error
+	// For asking the owner of a match, in a direct message, to add the players its lineup is missing
+	SendIncompleteLineupReminder(slackUserID string, match *playtomic.PadelMatch, players, required int, dryRun bool) error
+	// For players seen for the first time, so admins can link them to their Slack users
+	SendNewPlayersNotification(players []club.PlayerInfo, dryRun bool) error
+	// For the weekly summary of results and highlights
+	SendWeeklyRecap(recap club.WeeklyRecap, dryRun bool) error
 	// For slash commands
 	SendLeaderboard(stats []club.PlayerStats, dryRun bool) error
 	SendLevelLeaderboard(players []club.PlayerInfo, dryRun bool) error
 	SendPlayerStats(stats *club.PlayerStats, query string, dryRun bool) error
 	SendPlayerNotFound(query string, dryRun bool) error
+	// For a Slack user's personal home tab
+	PublishHomeView(slackUserID string, view HomeView, dryRun bool) error
 
 	// For formatting responses for slash commands
 	FormatLeaderboardResponse(stats []club.PlayerStats) (any, error)
 	FormatLevelLeaderboardResponse(players []club.PlayerInfo) (any, error)
 	FormatPlayerStatsResponse(stats *club.PlayerStats, query string) (any, error)
 	FormatPlayerNotFoundResponse(query string) (any, error)
+	FormatBallBringerResponse(players []club.PlayerInfo) (any, error)
+	FormatRosterResponse(players []club.PlayerInfo, page int) (any, error)
+	FormatFormResponse(playerName string, form []bool) (any, error)
+	FormatNextMatchResponse(match *playtomic.PadelMatch, playerID string) (any, error)
+	FormatLeaderboardDiffResponse(before, after string, deltas []club.PlayerStatsDelta) (any, error)
+	FormatUnmappedPlayersResponse(players []club.PlayerInfo) (any, error)
 }
@@ -0,0 +1,228 @@
+package slack
+
+import (
+	"strings"
+
+	"github.com/mauv0809/ideal-tribble/internal/config"
+)
+
+// Keys of the translated notification copy. Values containing verbs are fmt format strings.
+const (
+	msgBookingHeader          = "booking_header"
+	msgResultHeader           = "result_header"
+	msgResultCorrectedHeader  = "result_corrected_header"
+	msgFriendlyResultHeader   = "friendly_result_header"
+	msgLeaderboardHeader      = "leaderboard_header"
+	msgLevelLeaderboardHeader = "level_leaderboard_header"
+	msgBallBringersHeader     = "ball_bringers_header"
+	msgNextMatchHeader        = "next_match_header"
+	msgMatchDetails           = "match_details"
+	msgMatchAt                = "match_at"
+	msgPlayers                = "players"
+	msgGuest                  = "guest"
+	msgUnnamedGuest           = "unnamed_guest"
+	msgTeammates              = "teammates"
+	msgOpponents              = "opponents"
+	msgBringingBalls          = "bringing_balls"
+	msgYouAreBringingBalls    = "you_are_bringing_balls"
+	msgNoBallBringer          = "no_ball_bringer"
+	msgBroughtBalls           = "brought_balls"
+	msgResult                 = "result"
+	msgResultWon              = "result_won"
+	msgFriendlyResult         = "friendly_result"
+	msgFriendlyResultWon      = "friendly_result_won"
+	msgNoScores               = "no_scores"
+	msgNoStats                = "no_stats"
+	msgNoPlayers              = "no_players"
+	msgLeaderboardEntry       = "leaderboard_entry"
+	msgLevelEntry             = "level_entry"
+	msgStatsHeader            = "stats_header"
+	msgStatsBody              = "stats_body"
+	msgPlayerNotFound         = "player_not_found"
+	msgNewPlayer              = "new_player"
+	msgRosterHeader           = "roster_header"
+	msgRosterEntry            = "roster_entry"
+	msgRosterPage             = "roster_page"
+	msgLowSample              = "low_sample"
+	msgForm                   = "form"
+	msgNoForm                 = "no_form"
+	msgHomeUnmapped           = "home_unmapped"
+	msgHomeNoNextMatch        = "home_no_next_match"
+	msgRecapHeader            = "recap_header"
+	msgRecapSummary           = "recap_summary"
+	msgRecapNoMatches         = "recap_no_matches"
+	msgRecapResult            = "recap_result"
+	msgRecapTopPerformer      = "recap_top_performer"
+	msgRecapMostImproved      = "recap_most_improved"
+	msgRecapBallBringer       = "recap_ball_bringer"
+	msgLeaderboardDiffHeader  = "leaderboard_diff_header"
+	msgLeaderboardDiffEntry   = "leaderboard_diff_entry"
+	msgNoLeaderboardDiff      = "no_leaderboard_diff"
+	msgAndMore                = "and_more"
+	msgDigestHeader           = "digest_header"
+	msgDigestMatch            = "digest_match"
+	msgUnmappedHeader         = "unmapped_header"
+	msgUnmappedEntry          = "unmapped_entry"
+	msgUnmappedHint           = "unmapped_hint"
+	msgNoUnmapped             = "no_unmapped"
+	msgBallBringerReminder    = "ball_bringer_reminder"
+	msgLineupReminder         = "lineup_reminder"
+)
+
+// catalogs holds the notification copy per locale. English is complete and is used for any key a locale lacks.
+var catalogs = map[string]map[string]string{
+	"en": {
+		msgBookingHeader:          "🎾 New match booked! 🎾",
+		msgResultHeader:           "🎾 Match finished! 🎾",
+		msgResultCorrectedHeader:  "✏️ Updated result ✏️",
+		msgFriendlyResultHeader:   "🎾 Friendly finished! 🎾",
+		msgLeaderboardHeader:      "🏆 Player Leaderboard 🏆",
+		msgLevelLeaderboardHeader: "🏆 Player Leaderboard (by Level) 🏆",
+		msgBallBringersHeader:     "🎾 Ball Bringer Standings 🎾",
+		msgNextMatchHeader:        "🎾 Your next match 🎾",
+		msgMatchDetails:           "Court: %s\nTime: %s",
+		msgMatchAt:                "%s at %s",
+		msgPlayers:                "Players:",
+		msgGuest:                  "%s (guest)",
+		msgUnnamedGuest:           "Guest",
+		msgTeammates:              "Teammates: %s",
+		msgOpponents:              "Opponents: %s",
+		msgBringingBalls:          "%s %s is bringing balls!",
+		msgYouAreBringingBalls:    "%s You are bringing balls!",
+		msgNoBallBringer:          "%s No ball bringer assigned yet.",
+		msgBroughtBalls:           "%s %s brought the balls!",
+		msgResult:                 "Result:",
+		msgResultWon:              "Result: %s won! %s",
+		msgFriendlyResult:         "Friendly score:",
+		msgFriendlyResultWon:      "Friendly score: %s took this one %s",
+		msgNoScores:               "Result: No scores reported.",
+		msgNoStats:                "No stats available yet. Go play some matches!",
+		msgNoPlayers:              "No players found.",
+		msgLeaderboardEntry:       "%d. %s %s\n> Match Win %%: %.2f%% (%d/%d) | Sets Won: %d | Games Won: %d",
+		msgLevelEntry:             "%d. %s %s\n> *Level*: %.2f",
+		msgStatsHeader:            "%s Stats for %s %s",
+		msgStatsBody:              "> *Match Win %%*: %.2f%% (%d/%d)\n> *Sets Won*: %d\n> *Games Won*: %d",
+		msgPlayerNotFound:         "Sorry, I couldn't find a player matching *%s*. Try a different name.",
+		msgNewPlayer:              "New player discovered: *%s* — link them with `/map`",
+		msgRosterHeader:           "👥 Club Roster 👥",
+		msgRosterEntry:            "• *%s* — Level %.2f",
+		msgRosterPage:             "Page %d of %d (%d players). Use `/roster <page>` to see another page.",
+		msgLowSample:              "(only %d matches)",
+		msgForm:                   "Form for *%s* (last %d, oldest first): %s",
+		msgNoForm:                 "*%s* has no finished matches yet.",
+		msgHomeUnmapped:           "👋 Your Slack user is not linked to a Playtomic player yet. Ask an admin to link you with `/map` to see your stats and matches here.",
+		msgHomeNoNextMatch:        "You have no upcoming matches.",
+		msgRecapHeader:            "📅 Weekly Recap 📅",
+		msgRecapSummary:           "*Week of %s* — %d matches played",
+		msgRecapNoMatches:         "*Week of %s* — no matches were played this week.",
+		msgRecapResult:            "• %s beat %s",
+		msgRecapTopPerformer:      "%s Top performer: *%s* with %d wins",
+		msgRecapMostImproved:      "📈 Most improved: *%s* (+%.2f level)",
+		msgRecapBallBringer:       "%s Ball bringer of the week: *%s* (%d times)",
+		msgLeaderboardDiffHeader:  "📊 %s vs %s 📊",
+		msgLeaderboardDiffEntry:   "%d. *%s*: %+d wins (%+d matches, %+d games won)",
+		msgNoLeaderboardDiff:      "No matches were played in either period.",
+		msgAndMore:                "…and %d more",
+		msgDigestHeader:           "🎾 Today's new bookings 🎾",
+		msgDigestMatch:            "• *%s* — %s: %s",
+		msgUnmappedHeader:         "🔗 Players without Slack 🔗",
+		msgUnmappedEntry:          "• *%s* — `%s`",
+		msgUnmappedHint:           "%d players are not linked to a Slack user. Link one with `/map @slackuser <player name>`.",
+		msgNoUnmapped:             "Every player is linked to a Slack user.",
+		msgBallBringerReminder:    "%s You're bringing balls to the match on %s at %s.",
+		msgLineupReminder:         "🎾 Your match on %s at %s only has %d of %d players. Please add the missing players in Playtomic so it can be tracked.",
+	},
+	"da": {
+		msgBookingHeader:          "🎾 Ny kamp booket! 🎾",
+		msgResultHeader:           "🎾 Kampen er slut! 🎾",
+		msgResultCorrectedHeader:  "✏️ Rettet resultat ✏️",
+		msgFriendlyResultHeader:   "🎾 Venskabskampen er slut! 🎾",
+		msgLeaderboardHeader:      "🏆 Rangliste 🏆",
+		msgLevelLeaderboardHeader: "🏆 Rangliste (efter niveau) 🏆",
+		msgBallBringersHeader:     "🎾 Boldbringere 🎾",
+		msgNextMatchHeader:        "🎾 Din næste kamp 🎾",
+		msgMatchDetails:           "Bane: %s\nTid: %s",
+		msgMatchAt:                "%s, %s",
+		msgPlayers:                "Spillere:",
+		msgGuest:                  "%s (gæst)",
+		msgUnnamedGuest:           "Gæst",
+		msgTeammates:              "Makker: %s",
+		msgOpponents:              "Modstandere: %s",
+		msgBringingBalls:          "%s %s tager bolde med!",
+		msgYouAreBringingBalls:    "%s Du tager bolde med!",
+		msgNoBallBringer:          "%s Der er endnu ikke fundet en boldbringer.",
+		msgBroughtBalls:           "%s %s havde bolde med!",
+		msgResult:                 "Resultat:",
+		msgResultWon:              "Resultat: %s vandt! %s",
+		msgFriendlyResult:         "Venskabskamp:",
+		msgFriendlyResultWon:      "Venskabskamp: %s tog den her %s",
+		msgNoScores:               "Resultat: Ingen score registreret.",
+		msgNoStats:                "Ingen statistik endnu. Kom ud og spil nogle kampe!",
+		msgNoPlayers:              "Ingen spillere fundet.",
+		msgLeaderboardEntry:       "%d. %s %s\n> Sejre %%: %.2f%% (%d/%d) | Vundne sæt: %d | Vundne partier: %d",
+		msgLevelEntry:             "%d. %s %s\n> *Niveau*: %.2f",
+		msgStatsHeader:            "%s Statistik for %s %s",
+		msgStatsBody:              "> *Sejre %%*: %.2f%% (%d/%d)\n> *Vundne sæt*: %d\n> *Vundne partier*: %d",
+		msgPlayerNotFound:         "Beklager, jeg kunne ikke finde en spiller, der matcher *%s*. Prøv et andet navn.",
+		msgNewPlayer:              "Ny spiller fundet: *%s* — forbind dem med `/map`",
+		msgRosterHeader:           "👥 Medlemmer 👥",
+		msgRosterEntry:            "• *%s* — Niveau %.2f",
+		msgRosterPage:             "Side %d af %d (%d spillere). Brug `/roster <side>` for at se en anden side.",
+		msgLowSample:              "(kun %d kampe)",
+		msgForm:                   "Form for *%s* (seneste %d, ældste først): %s",
+		msgNoForm:                 "*%s* har endnu ingen færdigspillede kampe.",
+		msgHomeUnmapped:           "👋 Din Slack-bruger er endnu ikke forbundet til en Playtomic-spiller. Bed en admin om at forbinde dig med `/map` for at se din statistik og dine kampe her.",
+		msgHomeNoNextMatch:        "Du har ingen kommende kampe.",
+		msgRecapHeader:            "📅 Ugens overblik 📅",
+		msgRecapSummary:           "*Ugen fra %s* — %d kampe spillet",
+		msgRecapNoMatches:         "*Ugen fra %s* — der blev ikke spillet nogen kampe.",
+		msgRecapResult:            "• %s slog %s",
+		msgRecapTopPerformer:      "%s Ugens spiller: *%s* med %d sejre",
+		msgRecapMostImproved:      "📈 Største fremgang: *%s* (+%.2f niveau)",
+		msgRecapBallBringer:       "%s Ugens boldbringer: *%s* (%d gange)",
+		msgLeaderboardDiffHeader:  "📊 %s mod %s 📊",
+		msgLeaderboardDiffEntry:   "%d. *%s*: %+d sejre (%+d kampe, %+d vundne partier)",
+		msgNoLeaderboardDiff:      "Der blev ikke spillet nogen kampe i nogen af perioderne.",
+		msgAndMore:                "…og %d mere",
+		msgDigestHeader:           "🎾 Dagens nye bookinger 🎾",
+		msgDigestMatch:            "• *%s* — %s: %s",
+		msgUnmappedHeader:         "🔗 Spillere uden Slack 🔗",
+		msgUnmappedEntry:          "• *%s* — `%s`",
+		msgUnmappedHint:           "%d spillere er ikke forbundet til en Slack-bruger. Forbind en med `/map @slackbruger <spillernavn>`.",
+		msgNoUnmapped:             "Alle spillere er forbundet til en Slack-bruger.",
+		msgBallBringerReminder:    "%s Du tager bolde med til kampen %s på %s.",
+		msgLineupReminder:         "🎾 Din kamp %s på %s har kun %d af %d spillere. Tilføj de manglende spillere i Playtomic, så kampen kan registreres.",
+	},
+}
+
+// catalogFor returns the catalog for a locale such as "da" or "da-DK", falling back to English.
+func catalogFor(locale string) map[string]string {
+	lang, _, _ := strings.Cut(strings.ToLower(locale), "-")
+	if catalog, ok := catalogs[lang]; ok {
+		return catalog
+	}
+	return catalogs["en"]
+}
+
+// text returns the notifier's copy for a key.
+func (s *Notifier) text(key string) string {
+	if value, ok := s.catalog[key]; ok {
+		return value
+	}
+	return catalogs["en"][key]
+}
+
+// localizedTheme returns the default theme with its headers in the given locale.
+func localizedTheme(locale string) config.MessageTheme {
+	catalog := catalogFor(locale)
+	theme := config.DefaultMessageTheme()
+	theme.BookingHeader = catalog[msgBookingHeader]
+	theme.ResultHeader = catalog[msgResultHeader]
+	theme.PracticeResultHeader = catalog[msgResultHeader]
+	theme.FriendlyResultHeader = catalog[msgFriendlyResultHeader]
+	theme.LeaderboardHeader = catalog[msgLeaderboardHeader]
+	theme.LevelLeaderboardHeader = catalog[msgLevelLeaderboardHeader]
+	theme.BallBringersHeader = catalog[msgBallBringersHeader]
+	theme.NextMatchHeader = catalog[msgNextMatchHeader]
+	return theme
+}
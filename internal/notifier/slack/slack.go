@@ -7,9 +7,11 @@ import (
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/log"
 	"github.com/mauv0809/ideal-tribble/internal/club"
+	"github.com/mauv0809/ideal-tribble/internal/config"
 	"github.com/mauv0809/ideal-tribble/internal/metrics"
 	"github.com/mauv0809/ideal-tribble/internal/notifier"
 	"github.com/mauv0809/ideal-tribble/internal/playtomic"
@@ -20,53 +22,90 @@ import (
 // This allows for easy mocking in tests.
 type slackClient interface {
 	PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error)
+	PublishViewContext(ctx context.Context, req slack.PublishViewContextRequest) (*slack.ViewResponse, error)
 }
 
 var _ notifier.Notifier = &Notifier{}
 
+const (
+	// maxMessageBlocks is the most blocks Slack accepts in a single message.
+	maxMessageBlocks = 50
+	// linesPerSection keeps each section of a list well below Slack's 3000 character section limit.
+	linesPerSection = 20
+	// rosterPageSize leaves room for the header and page context blocks on every roster page.
+	rosterPageSize = (maxMessageBlocks - 2) * linesPerSection
+	// lowSampleMatches is the number of matches below which a win percentage is flagged as based on few matches.
+	lowSampleMatches = 10
+	// topTenRank is the last rank the theme's top ten marker is shown for.
+	topTenRank = 10
+)
+
 // Notifier handles sending notifications to Slack.
 type Notifier struct {
 	api       slackClient
 	channelID string
-	metrics   metrics.Metrics
+	channels  map[string]string // notification type -> channel ID, overriding channelID
+	// compactLeaderboardMax is the largest leaderboard rendered as a single table. Zero disables the table.
+	compactLeaderboardMax int
+	theme                 config.MessageTheme
+	catalog               map[string]string
+	hideGuests            bool
+	metrics               metrics.Metrics
 }
 
-// NewNotifier creates a new Notifier.
-func NewNotifier(token, channelID string, metrics metrics.Metrics) *Notifier {
-	api := slack.New(token)
-	return &Notifier{
-		api:       api,
-		channelID: channelID,
-		metrics:   metrics,
-	}
+// NewNotifier creates a new Notifier from the Slack configuration.
+func NewNotifier(cfg config.SlackConfig, metrics metrics.Metrics) *Notifier {
+	return NewNotifierWithAPI(slack.New(cfg.Token), cfg, metrics)
 }
 
 // NewNotifierWithAPI creates a new Notifier with a specific slack.Client instance.
 // Useful for tests that need to intercept API calls.
-func NewNotifierWithAPI(api slackClient, channelID string, metrics metrics.Metrics) *Notifier {
+func NewNotifierWithAPI(api slackClient, cfg config.SlackConfig, metrics metrics.Metrics) *Notifier {
 	return &Notifier{
-		api:       api,
-		channelID: channelID,
-		metrics:   metrics,
+		api:                   api,
+		channelID:             cfg.ChannelID,
+		channels:              cfg.Channels,
+		compactLeaderboardMax: cfg.CompactLeaderboardMax,
+		theme:                 cfg.Theme.WithDefaultsFrom(localizedTheme(cfg.Locale)),
+		catalog:               catalogFor(cfg.Locale),
+		hideGuests:            cfg.HideGuests,
+		metrics:               metrics,
 	}
 }
 
-func (s *Notifier) sendMessage(message slack.Message, dryRun bool) (string, string, error) {
+// channelFor returns the channel a notification type is sent to, defaulting to the main channel.
+func (s *Notifier) channelFor(notificationType string) string {
+	if channelID := s.channels[notificationType]; channelID != "" {
+		return channelID
+	}
+	return s.channelID
+}
+
+func (s *Notifier) sendMessage(notificationType string, message slack.Message, dryRun bool) (string, string, error) {
+	return s.sendMessageTo(s.channelFor(notificationType), message, dryRun)
+}
+
+// sendMessageToThread posts a message as a reply to the message with the given timestamp.
+func (s *Notifier) sendMessageToThread(notificationType string, message slack.Message, threadTS string, dryRun bool) (string, string, error) {
+	return s.sendMessageTo(s.channelFor(notificationType), message, dryRun, slack.MsgOptionTS(threadTS))
+}
+
+// sendMessageTo posts a message to the given channel. Passing a Slack user ID sends a direct message.
+func (s *Notifier) sendMessageTo(channelID string, message slack.Message, dryRun bool, options ...slack.MsgOption) (string, string, error) {
 	if dryRun {
 		jsonMsg, _ := json.MarshalIndent(message, "", "  ")
-		log.Info("[Dry Run] Would send Slack message", "channel", s.channelID, "message", string(jsonMsg))
+		log.Info("[Dry Run] Would send Slack message", "channel", channelID, "message", string(jsonMsg))
 		return "dry-run-ts", "dry-run-thread-ts", nil
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	channelID, timestamp, err := s.api.PostMessageContext(
-		ctx,
-		s.channelID,
+	options = append([]slack.MsgOption{
 		slack.MsgOptionBlocks(message.Blocks.BlockSet...),
 		slack.MsgOptionAsUser(true),
-	)
+	}, options...)
+	channelID, timestamp, err := s.api.PostMessageContext(ctx, channelID, options...)
 
 	if err != nil {
 		s.metrics.IncSlackNotifFailed()
@@ -80,45 +119,129 @@ func (s *Notifier) sendMessage(message slack.Message, dryRun bool) (string, stri
 }
 
 // Implement the Notifier interface
-func (s *Notifier) SendBookingNotification(match *playtomic.PadelMatch, dryRun bool) error {
-	msg := s.formatBookingNotification(match)
-	_, _, err := s.sendMessage(msg, dryRun)
+func (s *Notifier) SendBookingNotification(match *playtomic.PadelMatch, slackUserIDs map[string]string, guestIDs map[string]bool, dryRun bool) (string, error) {
+	msg := s.formatBookingNotification(match, slackUserIDs, guestIDs)
+	_, timestamp, err := s.sendMessage(notifier.NotificationBooking, msg, dryRun)
+	return timestamp, err
+}
+
+// SendBookingDigest posts the day's new bookings as one message, to the booking channel.
+func (s *Notifier) SendBookingDigest(matches []*playtomic.PadelMatch, dryRun bool) error {
+	msg := s.formatBookingDigest(matches)
+	_, _, err := s.sendMessage(notifier.NotificationBooking, msg, dryRun)
 	return err
 }
 
-func (s *Notifier) SendResultNotification(match *playtomic.PadelMatch, dryRun bool) error {
+func (s *Notifier) SendResultNotification(match *playtomic.PadelMatch, threadTS string, dryRun bool) error {
 	msg := s.formatResultNotification(match)
-	_, _, err := s.sendMessage(msg, dryRun)
+	// A thread only exists in the channel the booking was posted to.
+	if threadTS != "" && s.channelFor(notifier.NotificationResult) == s.channelFor(notifier.NotificationBooking) {
+		_, _, err := s.sendMessageToThread(notifier.NotificationResult, msg, threadTS, dryRun)
+		return err
+	}
+	_, _, err := s.sendMessage(notifier.NotificationResult, msg, dryRun)
+	return err
+}
+
+// SendResultCorrection posts the corrected result of a match, threaded like its result notification.
+func (s *Notifier) SendResultCorrection(match *playtomic.PadelMatch, threadTS string, dryRun bool) error {
+	msg := s.formatResultCorrection(match)
+	if threadTS != "" && s.channelFor(notifier.NotificationResult) == s.channelFor(notifier.NotificationBooking) {
+		_, _, err := s.sendMessageToThread(notifier.NotificationResult, msg, threadTS, dryRun)
+		return err
+	}
+	_, _, err := s.sendMessage(notifier.NotificationResult, msg, dryRun)
+	return err
+}
+
+// SendDirectMessage sends a plain text direct message to a Slack user.
+func (s *Notifier) SendDirectMessage(slackUserID, text string, dryRun bool) error {
+	msg := slack.NewBlockMessage(slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil))
+	_, _, err := s.sendMessageTo(slackUserID, msg, dryRun)
+	return err
+}
+
+// SendBallBringerReminder tells the ball bringer of a match in a direct message that they are bringing balls.
+func (s *Notifier) SendBallBringerReminder(slackUserID string, match *playtomic.PadelMatch, dryRun bool) error {
+	text := fmt.Sprintf(s.text(msgBallBringerReminder), s.theme.BallEmoji, localMatchTime(match), match.ResourceName)
+	return s.SendDirectMessage(slackUserID, text, dryRun)
+}
+
+// SendIncompleteLineupReminder asks the owner of a match in a direct message to add the players its lineup is missing.
+func (s *Notifier) SendIncompleteLineupReminder(slackUserID string, match *playtomic.PadelMatch, players, required int, dryRun bool) error {
+	text := fmt.Sprintf(s.text(msgLineupReminder), localMatchTime(match), match.ResourceName, players, required)
+	return s.SendDirectMessage(slackUserID, text, dryRun)
+}
+
+// localMatchTime formats the start of a match in club local time for direct messages.
+func localMatchTime(match *playtomic.PadelMatch) string {
+	startTime := time.Unix(match.Start, 0)
+	if loc, err := time.LoadLocation("Europe/Copenhagen"); err == nil {
+		startTime = startTime.In(loc)
+	}
+	return startTime.Format("Monday 02 Jan, 15:04")
+}
+
+// PublishHomeView publishes the user's personal App Home tab.
+func (s *Notifier) PublishHomeView(slackUserID string, view notifier.HomeView, dryRun bool) error {
+	homeView := s.formatHomeView(view)
+	if dryRun {
+		jsonView, _ := json.MarshalIndent(homeView, "", "  ")
+		log.Info("[Dry Run] Would publish Slack home view", "user", slackUserID, "view", string(jsonView))
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := s.api.PublishViewContext(ctx, slack.PublishViewContextRequest{UserID: slackUserID, View: homeView}); err != nil {
+		s.metrics.IncSlackNotifFailed()
+		log.Error("Failed to publish Slack home view", "error", err, "user", slackUserID)
+		return fmt.Errorf("failed to publish home view: %w", err)
+	}
+	s.metrics.IncSlackNotifSent()
+	return nil
+}
+
+func (s *Notifier) SendWeeklyRecap(recap club.WeeklyRecap, dryRun bool) error {
+	msg := s.formatWeeklyRecap(recap)
+	_, _, err := s.sendMessage(notifier.NotificationWeeklyRecap, msg, dryRun)
+	return err
+}
+
+func (s *Notifier) SendNewPlayersNotification(players []club.PlayerInfo, dryRun bool) error {
+	msg := s.formatNewPlayers(players)
+	_, _, err := s.sendMessage(notifier.NotificationNewPlayers, msg, dryRun)
 	return err
 }
 
 func (s *Notifier) SendLeaderboard(stats []club.PlayerStats, dryRun bool) error {
-	msg := s.formatLeaderboard(stats)
-	_, _, err := s.sendMessage(msg, dryRun)
+	msg := s.formatLeaderboard(stats, s.compactLeaderboard(stats))
+	_, _, err := s.sendMessage(notifier.NotificationLeaderboard, msg, dryRun)
 	return err
 }
 
 func (s *Notifier) SendLevelLeaderboard(players []club.PlayerInfo, dryRun bool) error {
 	msg := s.formatLevelLeaderboard(players)
-	_, _, err := s.sendMessage(msg, dryRun)
+	_, _, err := s.sendMessage(notifier.NotificationLevelLeaderboard, msg, dryRun)
 	return err
 }
 
 func (s *Notifier) SendPlayerStats(stats *club.PlayerStats, query string, dryRun bool) error {
 	msg := s.formatPlayerStats(stats, query)
-	_, _, err := s.sendMessage(msg, dryRun)
+	_, _, err := s.sendMessage(notifier.NotificationPlayerStats, msg, dryRun)
 	return err
 }
 
 func (s *Notifier) SendPlayerNotFound(query string, dryRun bool) error {
 	msg := s.formatPlayerNotFound(query)
-	_, _, err := s.sendMessage(msg, dryRun)
+	_, _, err := s.sendMessage(notifier.NotificationPlayerStats, msg, dryRun)
 	return err
 }
 
 // FormatLeaderboardResponse formats a leaderboard message for a slash command response.
 func (s *Notifier) FormatLeaderboardResponse(stats []club.PlayerStats) (any, error) {
-	return s.formatLeaderboard(stats), nil
+	return s.formatLeaderboard(stats, s.compactLeaderboard(stats)), nil
 }
 
 // FormatLevelLeaderboardResponse formats a level leaderboard message for a slash command response.
@@ -136,13 +259,44 @@ func (s *Notifier) FormatPlayerNotFoundResponse(query string) (any, error) {
 	return s.formatPlayerNotFound(query), nil
 }
 
+// FormatBallBringerResponse formats the ball bringer standings for a slash command response.
+func (s *Notifier) FormatBallBringerResponse(players []club.PlayerInfo) (any, error) {
+	return s.formatBallBringers(players), nil
+}
+
+// FormatRosterResponse formats one page of the club roster for a slash command response.
+func (s *Notifier) FormatRosterResponse(players []club.PlayerInfo, page int) (any, error) {
+	return s.formatRoster(players, page), nil
+}
+
+// FormatUnmappedPlayersResponse formats the players that still need a Slack user for a slash command response.
+func (s *Notifier) FormatUnmappedPlayersResponse(players []club.PlayerInfo) (any, error) {
+	return s.formatUnmappedPlayers(players), nil
+}
+
+// FormatFormResponse formats a player's recent results for a slash command response.
+func (s *Notifier) FormatFormResponse(playerName string, form []bool) (any, error) {
+	return s.formatForm(playerName, form), nil
+}
+
+// FormatNextMatchResponse formats a player's next match for a slash command response.
+func (s *Notifier) FormatNextMatchResponse(match *playtomic.PadelMatch, playerID string) (any, error) {
+	return s.formatNextMatch(match, playerID), nil
+}
+
+// FormatLeaderboardDiffResponse formats the change in player stats between two periods for a slash command response.
+func (s *Notifier) FormatLeaderboardDiffResponse(before, after string, deltas []club.PlayerStatsDelta) (any, error) {
+	return s.formatLeaderboardDiff(before, after, deltas), nil
+}
+
 // formatBookingNotification creates the Slack message for a new match booking using Block Kit.
-func (s *Notifier) formatBookingNotification(match *playtomic.PadelMatch) slack.Message {
+// Players with a Slack user in slackUserIDs are mentioned so they get pinged; everyone else is listed by name.
+func (s *Notifier) formatBookingNotification(match *playtomic.PadelMatch, slackUserIDs map[string]string, guestIDs map[string]bool) slack.Message {
 
 	blocks := make([]slack.Block, 0)
 
 	// Header - The Header block itself provides bolding. No asterisks needed.
-	headerText := slack.NewTextBlockObject("plain_text", "🎾 New match booked! 🎾", true, false)
+	headerText := slack.NewTextBlockObject("plain_text", s.theme.BookingHeader, true, false)
 	blocks = append(blocks, slack.NewHeaderBlock(headerText))
 
 	// Details - Use newlines for clear separation.
@@ -153,27 +307,39 @@ func (s *Notifier) formatBookingNotification(match *playtomic.PadelMatch) slack.
 	} else {
 		timeStr = time.Unix(match.Start, 0).Format("Monday 02 Jan, 15:04")
 	}
-	detailsText := fmt.Sprintf("Court: %s\nTime: %s", match.ResourceName, timeStr)
+	detailsText := fmt.Sprintf(s.text(msgMatchDetails), match.ResourceName, timeStr)
 	blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", detailsText, true, false), nil, nil))
 
-	// Players
+	// Players - mrkdwn so that mentions are rendered. Guests are labeled, or left out when hidden.
 	var playerNames []string
 	for _, team := range match.Teams {
 		for _, player := range team.Players {
-			if player.Name != "" {
-				playerNames = append(playerNames, fmt.Sprintf("• %s", player.Name))
+			isGuest := guestIDs[player.UserID]
+			switch {
+			case isGuest && s.hideGuests:
+				continue
+			case isGuest && player.Name != "":
+				playerNames = append(playerNames, "• "+fmt.Sprintf(s.text(msgGuest), escapeMrkdwn(player.Name)))
+			case isGuest:
+				playerNames = append(playerNames, "• "+s.text(msgUnnamedGuest))
+			case slackUserIDs[player.UserID] != "":
+				playerNames = append(playerNames, fmt.Sprintf("• <@%s>", slackUserIDs[player.UserID]))
+			case player.Name != "":
+				playerNames = append(playerNames, fmt.Sprintf("• %s", escapeMrkdwn(player.Name)))
 			}
 		}
 	}
 	if len(playerNames) > 0 {
-		playersText := "Players:\n" + strings.Join(playerNames, "\n")
-		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", playersText, true, false), nil, nil))
+		playersText := s.text(msgPlayers) + "\n" + strings.Join(playerNames, "\n")
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", playersText, false, false), nil, nil))
 	}
 
 	// Context - For simpler, single-line info.
 	var contextElements []slack.MixedElement
-	if match.BallBringerName != "" {
-		contextElements = append(contextElements, slack.NewTextBlockObject("plain_text", fmt.Sprintf("🎾 %s is bringing balls!", match.BallBringerName), true, false))
+	if slackUserID := slackUserIDs[match.BallBringerID]; match.BallBringerID != "" && slackUserID != "" {
+		contextElements = append(contextElements, slack.NewTextBlockObject("mrkdwn", fmt.Sprintf(s.text(msgBringingBalls), s.theme.BallEmoji, "<@"+slackUserID+">"), false, false))
+	} else if match.BallBringerName != "" {
+		contextElements = append(contextElements, slack.NewTextBlockObject("plain_text", fmt.Sprintf(s.text(msgBringingBalls), s.theme.BallEmoji, match.BallBringerName), true, false))
 	}
 	if len(contextElements) > 0 {
 		blocks = append(blocks, slack.NewContextBlock("", contextElements...))
@@ -182,12 +348,31 @@ func (s *Notifier) formatBookingNotification(match *playtomic.PadelMatch) slack.
 	return slack.NewBlockMessage(blocks...)
 }
 
+// mrkdwnEscaper escapes the characters Slack treats as control characters in mrkdwn text.
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// escapeMrkdwn escapes text from outside the club, such as Playtomic player names, so it can't add mentions or links.
+func escapeMrkdwn(text string) string {
+	return mrkdwnEscaper.Replace(text)
+}
+
 // formatResultNotification creates the Slack message for a finished match using Block Kit.
 func (s *Notifier) formatResultNotification(match *playtomic.PadelMatch) slack.Message {
 	blocks := make([]slack.Block, 0)
+	match.FillTeamResults()
+
+	header, mode := s.theme.ResultHeader, config.ResultModeScored
+	if match.MatchType != playtomic.MatchTypeCompetition {
+		header, mode = s.theme.PracticeResultHeader, s.theme.PracticeResultMode
+	}
+	resultLabel, winnerLabel, winnerEmoji := msgResult, msgResultWon, s.theme.TrophyEmoji
+	if mode == config.ResultModeFriendly {
+		header = s.theme.FriendlyResultHeader
+		resultLabel, winnerLabel, winnerEmoji = msgFriendlyResult, msgFriendlyResultWon, s.theme.FriendlyEmoji
+	}
 
 	// Header
-	headerText := slack.NewTextBlockObject("plain_text", "🎾 Match finished! 🎾", true, false)
+	headerText := slack.NewTextBlockObject("plain_text", header, true, false)
 	blocks = append(blocks, slack.NewHeaderBlock(headerText))
 
 	// Details
@@ -198,14 +383,14 @@ func (s *Notifier) formatResultNotification(match *playtomic.PadelMatch) slack.M
 	} else {
 		timeStr = time.Unix(match.Start, 0).Format("Monday 02 Jan, 15:04")
 	}
-	detailsText := fmt.Sprintf("%s at %s", match.ResourceName, timeStr)
+	detailsText := fmt.Sprintf(s.text(msgMatchAt), match.ResourceName, timeStr)
 	blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", detailsText, false, false), nil, nil))
 
-	if match.MatchType == playtomic.MatchTypeCompetition {
+	if mode == config.ResultModeScored || mode == config.ResultModeFriendly {
 		// Results
 		if len(match.Results) > 0 {
 			teamNames := make(map[string]string)
-			var winningTeamName string
+			var winningTeamID, winningTeamName string
 			for _, team := range match.Teams {
 				var playerNames []string
 				for _, player := range team.Players {
@@ -215,6 +400,7 @@ func (s *Notifier) formatResultNotification(match *playtomic.PadelMatch) slack.M
 				teamNames[team.ID] = currentTeamName
 
 				if team.TeamResult == "WON" {
+					winningTeamID = team.ID
 					winningTeamName = currentTeamName
 				}
 			}
@@ -234,18 +420,22 @@ func (s *Notifier) formatResultNotification(match *playtomic.PadelMatch) slack.M
 				}
 			}
 
-			resultHeaderText := "Result:"
+			resultHeaderText := s.text(resultLabel)
 			if winningTeamName != "" {
-				resultHeaderText = fmt.Sprintf("Result: %s won! 🏆", winningTeamName)
+				resultHeaderText = fmt.Sprintf(s.text(winnerLabel), winningTeamName, winnerEmoji)
 			}
 
 			if len(resultsFields) > 0 {
 				blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", resultHeaderText, true, false), resultsFields, nil))
+				if summary := setScoresSummary(match.Results, winningTeamID); summary != "" {
+					summaryText := fmt.Sprintf("%s (%s)", summary, winningTeamName)
+					blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject("plain_text", summaryText, true, false)))
+				}
 			} else {
-				blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", "Result: No scores reported.", true, false), nil, nil))
+				blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", s.text(msgNoScores), true, false), nil, nil))
 			}
 		} else {
-			blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", "Result: No scores reported.", true, false), nil, nil))
+			blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", s.text(msgNoScores), true, false), nil, nil))
 		}
 	} else {
 		// Players
@@ -258,7 +448,7 @@ func (s *Notifier) formatResultNotification(match *playtomic.PadelMatch) slack.M
 			}
 		}
 		if len(playerNames) > 0 {
-			playersText := "Players:\n" + strings.Join(playerNames, "\n")
+			playersText := s.text(msgPlayers) + "\n" + strings.Join(playerNames, "\n")
 			blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", playersText, true, false), nil, nil))
 		}
 
@@ -266,40 +456,71 @@ func (s *Notifier) formatResultNotification(match *playtomic.PadelMatch) slack.M
 
 	// Context (Ball Bringer)
 	if match.BallBringerName != "" {
-		ballBringerText := fmt.Sprintf("🎾 %s brought the balls!", match.BallBringerName)
+		ballBringerText := fmt.Sprintf(s.text(msgBroughtBalls), s.theme.BallEmoji, match.BallBringerName)
 		blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject("plain_text", ballBringerText, true, false)))
 	}
 
 	return slack.NewBlockMessage(blocks...)
 }
 
+// formatResultCorrection creates the Slack message for a corrected result: the result notification under a header
+// saying the result was updated.
+func (s *Notifier) formatResultCorrection(match *playtomic.PadelMatch) slack.Message {
+	msg := s.formatResultNotification(match)
+	headerText := slack.NewTextBlockObject("plain_text", s.text(msgResultCorrectedHeader), true, false)
+	msg.Blocks.BlockSet[0] = slack.NewHeaderBlock(headerText)
+	return msg
+}
+
+// setScoresSummary collapses the set scores into a single line from the winning team's perspective, e.g. "6-4, 7-5".
+// It returns an empty string when the winner is unknown.
+func setScoresSummary(results []playtomic.SetResult, winningTeamID string) string {
+	if winningTeamID == "" {
+		return ""
+	}
+	var sets []string
+	for _, result := range results {
+		winnerScore, ok := result.Scores[winningTeamID]
+		if !ok {
+			continue
+		}
+		for teamID, score := range result.Scores {
+			if teamID != winningTeamID {
+				sets = append(sets, fmt.Sprintf("%d-%d", winnerScore, score))
+				break
+			}
+		}
+	}
+	return strings.Join(sets, ", ")
+}
+
 // formatLeaderboard creates a Slack message to display the player leaderboard.
-func (s *Notifier) formatLeaderboard(stats []club.PlayerStats) slack.Message {
+func (s *Notifier) formatLeaderboard(stats []club.PlayerStats, compact bool) slack.Message {
 	blocks := make([]slack.Block, 0)
 
 	// Header
-	headerText := slack.NewTextBlockObject("plain_text", "🏆 Player Leaderboard 🏆", true, false)
+	headerText := slack.NewTextBlockObject("plain_text", s.theme.LeaderboardHeader, true, false)
 	blocks = append(blocks, slack.NewHeaderBlock(headerText))
 
 	if len(stats) == 0 {
-		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", "No stats available yet. Go play some matches!", true, false), nil, nil))
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", s.text(msgNoStats), true, false), nil, nil))
+		return slack.NewBlockMessage(blocks...)
+	}
+
+	if compact {
+		table := formatLeaderboardTable(stats)
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", table, false, false), nil, nil))
 		return slack.NewBlockMessage(blocks...)
 	}
 
 	// Player Ranks
+	var lines []string
+	ranks := leaderboardRanks(stats)
 	for i, stat := range stats {
-		rank := i + 1
-		var medal string
-		switch rank {
-		case 1:
-			medal = "🥇"
-		case 2:
-			medal = "🥈"
-		case 3:
-			medal = "🥉"
-		}
+		rank := ranks[i]
+		medal := s.medal(rank)
 
-		playerText := fmt.Sprintf("%d. %s %s\n> Match Win %%: %.2f%% (%d/%d) | Sets Won: %d | Games Won: %d",
+		playerText := fmt.Sprintf(s.text(msgLeaderboardEntry),
 			rank,
 			medal,
 			stat.PlayerName,
@@ -309,45 +530,412 @@ func (s *Notifier) formatLeaderboard(stats []club.PlayerStats) slack.Message {
 			stat.SetsWon,
 			stat.GamesWon,
 		)
-		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", playerText, true, false), nil, nil))
+		if stat.MatchesPlayed < lowSampleMatches {
+			playerText += " " + fmt.Sprintf(s.text(msgLowSample), stat.MatchesPlayed)
+		}
+		lines = append(lines, playerText)
 	}
+	blocks = append(blocks, s.chunkBlocks(lines, "plain_text", 1, len(blocks))...)
 
 	return slack.NewBlockMessage(blocks...)
 }
 
+// chunkBlocks renders lines as sections of at most perSection lines each, for a message that has reserved blocks
+// besides them. If the sections would take the message past Slack's block limit, the lines that don't fit are
+// dropped and summarized in a trailing "…and N more" context block instead.
+func (s *Notifier) chunkBlocks(lines []string, textType string, perSection, reserved int) []slack.Block {
+	available := maxMessageBlocks - reserved
+	var more int
+	if sections := (len(lines) + perSection - 1) / perSection; sections > available {
+		kept := (available - 1) * perSection
+		more = len(lines) - kept
+		lines = lines[:kept]
+	}
+
+	blocks := make([]slack.Block, 0, available)
+	for i := 0; i < len(lines); i += perSection {
+		text := strings.Join(lines[i:min(i+perSection, len(lines))], "\n")
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject(textType, text, textType == "plain_text", false), nil, nil))
+	}
+	if more > 0 {
+		blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject("mrkdwn", fmt.Sprintf(s.text(msgAndMore), more), false, false)))
+	}
+	return blocks
+}
+
+// leaderboardRanks returns the rank of each player on the leaderboard. Stats ranked by the store keep their rank;
+// otherwise players with the same record share a rank, and the places they share are skipped, e.g. 1, 2, 2, 4.
+func leaderboardRanks(stats []club.PlayerStats) []int {
+	ranks := make([]int, len(stats))
+	for i, stat := range stats {
+		switch {
+		case stat.Rank > 0:
+			ranks[i] = stat.Rank
+		case i > 0 && stat.SameRecord(stats[i-1]):
+			ranks[i] = ranks[i-1]
+		default:
+			ranks[i] = i + 1
+		}
+	}
+	return ranks
+}
+
+// medal returns the theme's medal for a rank, its top ten marker for the places after the medals up to tenth,
+// or an empty string beyond those.
+func (s *Notifier) medal(rank int) string {
+	if rank > len(s.theme.Medals) && rank <= topTenRank {
+		return s.theme.TopTenMarker
+	}
+	if rank < 1 || rank > len(s.theme.Medals) {
+		return ""
+	}
+	return s.theme.Medals[rank-1]
+}
+
+// compactLeaderboard reports whether the leaderboard is small enough to be rendered as a single table.
+func (s *Notifier) compactLeaderboard(stats []club.PlayerStats) bool {
+	return len(stats) > 0 && len(stats) <= s.compactLeaderboardMax
+}
+
+// formatLeaderboardTable renders the leaderboard as a monospaced table in a mrkdwn code block.
+func formatLeaderboardTable(stats []club.PlayerStats) string {
+	nameWidth := len("Player")
+	for _, stat := range stats {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(stat.PlayerName))
+	}
+
+	var b strings.Builder
+	b.WriteString("```\n")
+	fmt.Fprintf(&b, "%3s  %-*s  %7s  %7s  %4s  %5s\n", "#", nameWidth, "Player", "Win %", "W/P", "Sets", "Games")
+	ranks := leaderboardRanks(stats)
+	for i, stat := range stats {
+		fmt.Fprintf(&b, "%3d  %-*s  %6.2f%%  %7s  %4d  %5d\n",
+			ranks[i],
+			nameWidth,
+			stat.PlayerName,
+			stat.WinPercentage,
+			fmt.Sprintf("%d/%d", stat.MatchesWon, stat.MatchesPlayed),
+			stat.SetsWon,
+			stat.GamesWon,
+		)
+	}
+	b.WriteString("```")
+	return b.String()
+}
+
 // formatLevelLeaderboard creates a Slack message to display the player leaderboard by level.
 func (s *Notifier) formatLevelLeaderboard(players []club.PlayerInfo) slack.Message {
 	blocks := make([]slack.Block, 0)
 
 	// Header
-	headerText := slack.NewTextBlockObject("plain_text", "🏆 Player Leaderboard (by Level) 🏆", true, false)
+	headerText := slack.NewTextBlockObject("plain_text", s.theme.LevelLeaderboardHeader, true, false)
 	blocks = append(blocks, slack.NewHeaderBlock(headerText))
 
 	if len(players) == 0 {
-		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", "No players found.", true, false), nil, nil))
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", s.text(msgNoPlayers), true, false), nil, nil))
 		return slack.NewBlockMessage(blocks...)
 	}
 
 	// Player Ranks
+	var lines []string
 	for i, player := range players {
 		rank := i + 1
-		var medal string
-		switch rank {
-		case 1:
-			medal = "🥇"
-		case 2:
-			medal = "🥈"
-		case 3:
-			medal = "🥉"
-		}
+		medal := s.medal(rank)
 
-		playerText := fmt.Sprintf("%d. %s %s\n> *Level*: %.2f",
+		lines = append(lines, fmt.Sprintf(s.text(msgLevelEntry),
 			rank,
 			medal,
 			player.Name,
 			player.Level,
-		)
-		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", playerText, false, false), nil, nil))
+		))
+	}
+	blocks = append(blocks, s.chunkBlocks(lines, "mrkdwn", 1, len(blocks))...)
+
+	return slack.NewBlockMessage(blocks...)
+}
+
+// formatNextMatch creates a Slack message describing a player's next match from their point of view.
+func (s *Notifier) formatNextMatch(match *playtomic.PadelMatch, playerID string) slack.Message {
+	blocks := make([]slack.Block, 0)
+
+	headerText := slack.NewTextBlockObject("plain_text", s.theme.NextMatchHeader, true, false)
+	blocks = append(blocks, slack.NewHeaderBlock(headerText))
+
+	loc, err := time.LoadLocation("Europe/Copenhagen")
+	var timeStr string
+	if err == nil {
+		timeStr = time.Unix(match.Start, 0).In(loc).Format("Monday 02 Jan, 15:04")
+	} else {
+		timeStr = time.Unix(match.Start, 0).Format("Monday 02 Jan, 15:04")
+	}
+	detailsText := fmt.Sprintf(s.text(msgMatchDetails), match.ResourceName, timeStr)
+
+	var teammates, opponents []string
+	for _, team := range match.Teams {
+		var names []string
+		onTeam := false
+		for _, player := range team.Players {
+			if player.UserID == playerID {
+				onTeam = true
+				continue
+			}
+			names = append(names, player.Name)
+		}
+		if onTeam {
+			teammates = append(teammates, names...)
+		} else {
+			opponents = append(opponents, names...)
+		}
+	}
+	if len(teammates) > 0 {
+		detailsText += "\n" + fmt.Sprintf(s.text(msgTeammates), strings.Join(teammates, ", "))
+	}
+	if len(opponents) > 0 {
+		detailsText += "\n" + fmt.Sprintf(s.text(msgOpponents), strings.Join(opponents, ", "))
+	}
+	blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", detailsText, true, false), nil, nil))
+
+	var ballBringerText string
+	switch {
+	case match.BallBringerID != "" && match.BallBringerID == playerID:
+		ballBringerText = fmt.Sprintf(s.text(msgYouAreBringingBalls), s.theme.BallEmoji)
+	case match.BallBringerName != "":
+		ballBringerText = fmt.Sprintf(s.text(msgBringingBalls), s.theme.BallEmoji, match.BallBringerName)
+	default:
+		ballBringerText = fmt.Sprintf(s.text(msgNoBallBringer), s.theme.BallEmoji)
+	}
+	blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject("plain_text", ballBringerText, true, false)))
+
+	return slack.NewBlockMessage(blocks...)
+}
+
+// formatBallBringers creates a Slack message showing how many times each player has brought balls.
+func (s *Notifier) formatBallBringers(players []club.PlayerInfo) slack.Message {
+	blocks := make([]slack.Block, 0)
+
+	// Header
+	headerText := slack.NewTextBlockObject("plain_text", s.theme.BallBringersHeader, true, false)
+	blocks = append(blocks, slack.NewHeaderBlock(headerText))
+
+	if len(players) == 0 {
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", s.text(msgNoPlayers), true, false), nil, nil))
+		return slack.NewBlockMessage(blocks...)
+	}
+
+	var lines []string
+	for i, player := range players {
+		lines = append(lines, fmt.Sprintf("%d. %s: %d", i+1, player.Name, player.BallBringerCount))
+	}
+	blocks = append(blocks, s.chunkBlocks(lines, "mrkdwn", linesPerSection, len(blocks))...)
+
+	return slack.NewBlockMessage(blocks...)
+}
+
+// formatRoster creates a Slack message listing the players on the given page (1-based) of the roster.
+// Players are grouped into sections so that a page stays within Slack's block limit.
+func (s *Notifier) formatRoster(players []club.PlayerInfo, page int) slack.Message {
+	blocks := make([]slack.Block, 0)
+
+	headerText := slack.NewTextBlockObject("plain_text", s.text(msgRosterHeader), true, false)
+	blocks = append(blocks, slack.NewHeaderBlock(headerText))
+
+	if len(players) == 0 {
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", s.text(msgNoPlayers), true, false), nil, nil))
+		return slack.NewBlockMessage(blocks...)
+	}
+
+	pages := (len(players) + rosterPageSize - 1) / rosterPageSize
+	page = max(1, min(page, pages))
+	start := (page - 1) * rosterPageSize
+	end := min(start+rosterPageSize, len(players))
+
+	var lines []string
+	for _, player := range players[start:end] {
+		lines = append(lines, fmt.Sprintf(s.text(msgRosterEntry), player.Name, player.Level))
+	}
+	// A page always fits, so the helper only groups the players into sections here.
+	blocks = append(blocks, s.chunkBlocks(lines, "mrkdwn", linesPerSection, len(blocks)+1)...)
+
+	if pages > 1 {
+		pageText := fmt.Sprintf(s.text(msgRosterPage), page, pages, len(players))
+		blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject("mrkdwn", pageText, false, false)))
+	}
+
+	return slack.NewBlockMessage(blocks...)
+}
+
+// formatUnmappedPlayers creates a Slack message listing the players that are not linked to a Slack user, with
+// their IDs, as a worklist for /map.
+func (s *Notifier) formatUnmappedPlayers(players []club.PlayerInfo) slack.Message {
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject("plain_text", s.text(msgUnmappedHeader), true, false)),
+	}
+
+	if len(players) == 0 {
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("plain_text", s.text(msgNoUnmapped), true, false), nil, nil))
+		return slack.NewBlockMessage(blocks...)
+	}
+
+	lines := make([]string, 0, len(players))
+	for _, player := range players {
+		lines = append(lines, fmt.Sprintf(s.text(msgUnmappedEntry), player.Name, player.ID))
+	}
+	// Reserve a block for the hint below the list.
+	blocks = append(blocks, s.chunkBlocks(lines, "mrkdwn", linesPerSection, len(blocks)+1)...)
+	hintText := fmt.Sprintf(s.text(msgUnmappedHint), len(players))
+	blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject("mrkdwn", hintText, false, false)))
+
+	return slack.NewBlockMessage(blocks...)
+}
+
+// formatForm creates a Slack message showing a player's recent results as a strip of win/loss emoji, oldest first.
+func (s *Notifier) formatForm(playerName string, form []bool) slack.Message {
+	if len(form) == 0 {
+		text := fmt.Sprintf(s.text(msgNoForm), playerName)
+		return slack.NewBlockMessage(slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil))
+	}
+
+	var strip strings.Builder
+	for _, won := range form {
+		if won {
+			strip.WriteString("✅")
+		} else {
+			strip.WriteString("❌")
+		}
+	}
+	text := fmt.Sprintf(s.text(msgForm), playerName, len(form), strip.String())
+	return slack.NewBlockMessage(slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil))
+}
+
+// formatLeaderboardDiff creates a Slack message ranking players by the wins they gained from the before period
+// to the after period.
+func (s *Notifier) formatLeaderboardDiff(before, after string, deltas []club.PlayerStatsDelta) slack.Message {
+	headerText := slack.NewTextBlockObject("plain_text", fmt.Sprintf(s.text(msgLeaderboardDiffHeader), after, before), true, false)
+	blocks := []slack.Block{slack.NewHeaderBlock(headerText)}
+	if len(deltas) == 0 {
+		text := slack.NewTextBlockObject("mrkdwn", s.text(msgNoLeaderboardDiff), false, false)
+		return slack.NewBlockMessage(append(blocks, slack.NewSectionBlock(text, nil, nil))...)
+	}
+
+	lines := make([]string, 0, len(deltas))
+	for i, delta := range deltas {
+		lines = append(lines, fmt.Sprintf(s.text(msgLeaderboardDiffEntry), i+1, delta.PlayerName, delta.MatchesWon, delta.MatchesPlayed, delta.GamesWon))
+	}
+	return slack.NewBlockMessage(append(blocks, s.chunkBlocks(lines, "mrkdwn", linesPerSection, len(blocks))...)...)
+}
+
+// formatHomeView builds the home tab from the player's stats, next match and form.
+// Users not linked to a player are asked to get linked instead.
+func (s *Notifier) formatHomeView(view notifier.HomeView) slack.HomeTabViewRequest {
+	home := slack.HomeTabViewRequest{Type: slack.VTHomeTab}
+	if view.Player == nil {
+		home.Blocks.BlockSet = append(home.Blocks.BlockSet,
+			slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", s.text(msgHomeUnmapped), false, false), nil, nil))
+		return home
+	}
+
+	stats := view.Stats
+	if stats == nil {
+		stats = &club.PlayerStats{PlayerID: view.Player.ID, PlayerName: view.Player.Name}
+	}
+	home.Blocks.BlockSet = append(home.Blocks.BlockSet, s.formatPlayerStats(stats, view.Player.Name).Blocks.BlockSet...)
+	home.Blocks.BlockSet = append(home.Blocks.BlockSet, s.formatForm(view.Player.Name, view.Form).Blocks.BlockSet...)
+	home.Blocks.BlockSet = append(home.Blocks.BlockSet, slack.NewDividerBlock())
+	if view.NextMatch != nil {
+		home.Blocks.BlockSet = append(home.Blocks.BlockSet, s.formatNextMatch(view.NextMatch, view.Player.ID).Blocks.BlockSet...)
+	} else {
+		home.Blocks.BlockSet = append(home.Blocks.BlockSet,
+			slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", s.text(msgHomeNoNextMatch), false, false), nil, nil))
+	}
+	return home
+}
+
+// formatBookingDigest creates a Slack message listing several bookings, one line per match.
+func (s *Notifier) formatBookingDigest(matches []*playtomic.PadelMatch) slack.Message {
+	blocks := make([]slack.Block, 0)
+
+	headerText := slack.NewTextBlockObject("plain_text", s.text(msgDigestHeader), true, false)
+	blocks = append(blocks, slack.NewHeaderBlock(headerText))
+
+	loc, err := time.LoadLocation("Europe/Copenhagen")
+	if err != nil {
+		loc = time.Local
+	}
+	lines := make([]string, 0, len(matches))
+	for _, match := range matches {
+		var names []string
+		for _, team := range match.Teams {
+			for _, player := range team.Players {
+				if player.Name != "" {
+					names = append(names, player.Name)
+				}
+			}
+		}
+		timeStr := time.Unix(match.Start, 0).In(loc).Format("Monday 02 Jan, 15:04")
+		line := fmt.Sprintf(s.text(msgDigestMatch), timeStr, match.ResourceName, strings.Join(names, ", "))
+		if match.BallBringerName != "" {
+			line += " " + fmt.Sprintf(s.text(msgBringingBalls), s.theme.BallEmoji, match.BallBringerName)
+		}
+		lines = append(lines, line)
+	}
+	blocks = append(blocks, s.chunkBlocks(lines, "mrkdwn", linesPerSection, len(blocks))...)
+
+	return slack.NewBlockMessage(blocks...)
+}
+
+// formatWeeklyRecap creates a Slack message summarizing the week's results and highlights.
+func (s *Notifier) formatWeeklyRecap(recap club.WeeklyRecap) slack.Message {
+	blocks := make([]slack.Block, 0)
+
+	headerText := slack.NewTextBlockObject("plain_text", s.text(msgRecapHeader), true, false)
+	blocks = append(blocks, slack.NewHeaderBlock(headerText))
+
+	weekOf := time.Unix(recap.WeekStart, 0).UTC().Format("02 Jan 2006")
+	if len(recap.Matches) == 0 {
+		text := fmt.Sprintf(s.text(msgRecapNoMatches), weekOf)
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil))
+		return slack.NewBlockMessage(blocks...)
+	}
+
+	lines := []string{fmt.Sprintf(s.text(msgRecapSummary), weekOf, len(recap.Matches))}
+	for _, match := range recap.Matches {
+		var winnerID, winner, loser string
+		for _, team := range match.Teams {
+			var names []string
+			for _, player := range team.Players {
+				names = append(names, player.Name)
+			}
+			if team.TeamResult == "WON" {
+				winnerID, winner = team.ID, strings.Join(names, " & ")
+			} else {
+				loser = strings.Join(names, " & ")
+			}
+		}
+		if winner == "" {
+			continue
+		}
+		line := fmt.Sprintf(s.text(msgRecapResult), winner, loser)
+		if summary := setScoresSummary(match.Results, winnerID); summary != "" {
+			line += " (" + summary + ")"
+		}
+		lines = append(lines, line)
+	}
+	// Reserve room for the divider and highlights that follow the results.
+	blocks = append(blocks, s.chunkBlocks(lines, "mrkdwn", linesPerSection, len(blocks)+2)...)
+
+	var highlights []string
+	if h := recap.TopPerformer; h != nil {
+		highlights = append(highlights, fmt.Sprintf(s.text(msgRecapTopPerformer), s.theme.TrophyEmoji, h.PlayerName, int(h.Value)))
+	}
+	if h := recap.MostImproved; h != nil {
+		highlights = append(highlights, fmt.Sprintf(s.text(msgRecapMostImproved), h.PlayerName, h.Value))
+	}
+	if h := recap.BallBringer; h != nil {
+		highlights = append(highlights, fmt.Sprintf(s.text(msgRecapBallBringer), s.theme.BallEmoji, h.PlayerName, int(h.Value)))
+	}
+	if len(highlights) > 0 {
+		blocks = append(blocks, slack.NewDividerBlock())
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", strings.Join(highlights, "\n"), false, false), nil, nil))
 	}
 
 	return slack.NewBlockMessage(blocks...)
@@ -358,11 +946,11 @@ func (s *Notifier) formatPlayerStats(stat *club.PlayerStats, query string) slack
 	blocks := make([]slack.Block, 0)
 
 	// Header
-	headerText := fmt.Sprintf("🏆 Stats for %s 🏆", stat.PlayerName)
+	headerText := fmt.Sprintf(s.text(msgStatsHeader), s.theme.TrophyEmoji, stat.PlayerName, s.theme.TrophyEmoji)
 	blocks = append(blocks, slack.NewHeaderBlock(slack.NewTextBlockObject("plain_text", headerText, true, false)))
 
 	// Player Ranks
-	playerText := fmt.Sprintf("> *Match Win %%*: %.2f%% (%d/%d)\n> *Sets Won*: %d\n> *Games Won*: %d",
+	playerText := fmt.Sprintf(s.text(msgStatsBody),
 		stat.WinPercentage,
 		stat.MatchesWon,
 		stat.MatchesPlayed,
@@ -374,9 +962,20 @@ func (s *Notifier) formatPlayerStats(stat *club.PlayerStats, query string) slack
 	return slack.NewBlockMessage(blocks...)
 }
 
+// formatNewPlayers creates a Slack message announcing newly discovered players.
+func (s *Notifier) formatNewPlayers(players []club.PlayerInfo) slack.Message {
+	lines := make([]string, 0, len(players))
+	for _, player := range players {
+		lines = append(lines, fmt.Sprintf(s.text(msgNewPlayer), player.Name))
+	}
+	return slack.NewBlockMessage(
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", strings.Join(lines, "\n"), false, false), nil, nil),
+	)
+}
+
 // formatPlayerNotFound creates a Slack message for when a player's stats are not found.
 func (s *Notifier) formatPlayerNotFound(query string) slack.Message {
-	text := fmt.Sprintf("Sorry, I couldn't find a player matching *%s*. Try a different name.", query)
+	text := fmt.Sprintf(s.text(msgPlayerNotFound), query)
 	return slack.NewBlockMessage(
 		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil),
 	)
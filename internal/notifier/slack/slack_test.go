@@ -2,12 +2,17 @@ package slack
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/mauv0809/ideal-tribble/internal/club"
+	"github.com/mauv0809/ideal-tribble/internal/config"
 	"github.com/mauv0809/ideal-tribble/internal/metrics"
+	notifierPkg "github.com/mauv0809/ideal-tribble/internal/notifier"
 	"github.com/mauv0809/ideal-tribble/internal/playtomic"
 	slackapi "github.com/slack-go/slack"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +22,7 @@ import (
 // mockSlackAPI is a mock implementation of the parts of the slack.Client that we use.
 type mockSlackAPI struct {
 	postMessageContextFunc func(ctx context.Context, channelID string, options ...slackapi.MsgOption) (string, string, error)
+	publishViewContextFunc func(ctx context.Context, req slackapi.PublishViewContextRequest) (*slackapi.ViewResponse, error)
 }
 
 func (m *mockSlackAPI) PostMessageContext(ctx context.Context, channelID string, options ...slackapi.MsgOption) (string, string, error) {
@@ -26,13 +32,20 @@ func (m *mockSlackAPI) PostMessageContext(ctx context.Context, channelID string,
 	return "C12345", "123456789.12345", nil
 }
 
+func (m *mockSlackAPI) PublishViewContext(ctx context.Context, req slackapi.PublishViewContextRequest) (*slackapi.ViewResponse, error) {
+	if m.publishViewContextFunc != nil {
+		return m.publishViewContextFunc(ctx, req)
+	}
+	return &slackapi.ViewResponse{}, nil
+}
+
 func TestSendMessage_DryRun(t *testing.T) {
 	metrics := metrics.NewMock()
 	// Pass nil for the api, as it shouldn't be called in dry-run mode.
-	notifier := NewNotifierWithAPI(nil, "C123", metrics)
+	notifier := NewNotifierWithAPI(nil, config.SlackConfig{ChannelID: "C123"}, metrics)
 
 	message := slackapi.NewBlockMessage()
-	_, _, err := notifier.sendMessage(message, true)
+	_, _, err := notifier.sendMessage(notifierPkg.NotificationBooking, message, true)
 	require.NoError(t, err)
 }
 
@@ -47,10 +60,10 @@ func TestSendMessage_Success(t *testing.T) {
 	}
 
 	metrics := metrics.NewMock()
-	notifier := NewNotifierWithAPI(api, "C123", metrics)
+	notifier := NewNotifierWithAPI(api, config.SlackConfig{ChannelID: "C123"}, metrics)
 
 	message := slackapi.NewBlockMessage(slackapi.NewSectionBlock(slackapi.NewTextBlockObject("plain_text", "hello", false, false), nil, nil))
-	_, _, err := notifier.sendMessage(message, false)
+	_, _, err := notifier.sendMessage(notifierPkg.NotificationBooking, message, false)
 
 	require.NoError(t, err)
 	assert.True(t, postMessageCalled, "PostMessageContext should have been called")
@@ -69,10 +82,10 @@ func TestSendMessage_Failure(t *testing.T) {
 	}
 
 	metrics := metrics.NewMock()
-	notifier := NewNotifierWithAPI(api, "C123", metrics)
+	notifier := NewNotifierWithAPI(api, config.SlackConfig{ChannelID: "C123"}, metrics)
 
 	message := slackapi.NewBlockMessage()
-	_, _, err := notifier.sendMessage(message, false)
+	_, _, err := notifier.sendMessage(notifierPkg.NotificationBooking, message, false)
 
 	require.Error(t, err)
 	assert.ErrorIs(t, err, expectedErr)
@@ -92,17 +105,129 @@ func TestSendBookingNotification_CallsSender(t *testing.T) {
 	}
 
 	metrics := metrics.NewMock()
-	notifier := NewNotifierWithAPI(api, "C123", metrics)
+	notifier := NewNotifierWithAPI(api, config.SlackConfig{ChannelID: "C123"}, metrics)
 
 	match := &playtomic.PadelMatch{
 		ResourceName: "Court 1",
 		Start:        time.Now().Unix(),
 	}
 
-	err := notifier.SendBookingNotification(match, false)
+	_, err := notifier.SendBookingNotification(match, nil, nil, false)
 	require.NoError(t, err)
 	assert.True(t, postMessageCalled, "PostMessageContext should have been called via SendBookingNotification")
 }
+func TestSendDirectMessage_PostsToUser(t *testing.T) {
+	var postedTo string
+	api := &mockSlackAPI{
+		postMessageContextFunc: func(ctx context.Context, channelID string, options ...slackapi.MsgOption) (string, string, error) {
+			postedTo = channelID
+			return "D123", "ts123", nil
+		},
+	}
+
+	notifier := NewNotifierWithAPI(api, config.SlackConfig{ChannelID: "C123"}, metrics.NewMock())
+
+	err := notifier.SendDirectMessage("U123", "You're bringing balls!", false)
+	require.NoError(t, err)
+	assert.Equal(t, "U123", postedTo, "Direct messages should be posted to the user rather than the channel")
+}
+
+func TestSendReminders_Localized(t *testing.T) {
+	match := &playtomic.PadelMatch{
+		ResourceName: "Court 1",
+		Start:        time.Date(2024, 6, 1, 16, 0, 0, 0, time.UTC).Unix(),
+	}
+	send := func(t *testing.T, locale string, reminder func(n *Notifier) error) (string, string) {
+		t.Helper()
+		var postedTo, blocks string
+		api := &mockSlackAPI{
+			postMessageContextFunc: func(ctx context.Context, channelID string, options ...slackapi.MsgOption) (string, string, error) {
+				_, values, err := slackapi.UnsafeApplyMsgOptions("", channelID, "", options...)
+				require.NoError(t, err)
+				postedTo, blocks = channelID, values.Get("blocks")
+				return "D123", "ts123", nil
+			},
+		}
+		n := NewNotifierWithAPI(api, config.SlackConfig{ChannelID: "C123", Locale: locale}, metrics.NewMock())
+		require.NoError(t, reminder(n))
+		return postedTo, blocks
+	}
+	ballBringer := func(n *Notifier) error { return n.SendBallBringerReminder("U123", match, false) }
+	lineup := func(n *Notifier) error { return n.SendIncompleteLineupReminder("U123", match, 3, 4, false) }
+
+	postedTo, text := send(t, "en", ballBringer)
+	assert.Equal(t, "U123", postedTo)
+	assert.Contains(t, text, "You're bringing balls to the match on Saturday 01 Jun, 18:00 at Court 1.")
+	_, text = send(t, "da", ballBringer)
+	assert.Contains(t, text, "Du tager bolde med til kampen Saturday 01 Jun, 18:00 på Court 1.")
+
+	postedTo, text = send(t, "en", lineup)
+	assert.Equal(t, "U123", postedTo)
+	assert.Contains(t, text, "only has 3 of 4 players")
+	_, text = send(t, "da", lineup)
+	assert.Contains(t, text, "har kun 3 af 4 spillere")
+}
+
+func TestSendResultNotification_Threading(t *testing.T) {
+	var threadTS string
+	api := &mockSlackAPI{
+		postMessageContextFunc: func(ctx context.Context, channelID string, options ...slackapi.MsgOption) (string, string, error) {
+			_, values, err := slackapi.UnsafeApplyMsgOptions("", channelID, "", options...)
+			require.NoError(t, err)
+			threadTS = values.Get("thread_ts")
+			return "C123", "ts456", nil
+		},
+	}
+	notifier := NewNotifierWithAPI(api, config.SlackConfig{ChannelID: "C123"}, metrics.NewMock())
+	match := &playtomic.PadelMatch{ResourceName: "Court 1"}
+
+	require.NoError(t, notifier.SendResultNotification(match, "ts123", false))
+	assert.Equal(t, "ts123", threadTS, "Result should be posted in the booking thread")
+
+	require.NoError(t, notifier.SendResultNotification(match, "", false))
+	assert.Empty(t, threadTS, "Result should be posted top-level without a booking timestamp")
+}
+
+func TestNotificationChannelRouting(t *testing.T) {
+	var postedTo []string
+	api := &mockSlackAPI{
+		postMessageContextFunc: func(ctx context.Context, channelID string, options ...slackapi.MsgOption) (string, string, error) {
+			postedTo = append(postedTo, channelID)
+			return channelID, "ts123", nil
+		},
+	}
+	channels := map[string]string{
+		notifierPkg.NotificationResult:      "CRESULTS",
+		notifierPkg.NotificationLeaderboard: "CSTATS",
+	}
+	notifier := NewNotifierWithAPI(api, config.SlackConfig{ChannelID: "CMAIN", Channels: channels}, metrics.NewMock())
+	match := &playtomic.PadelMatch{ResourceName: "Court 1"}
+
+	_, err := notifier.SendBookingNotification(match, nil, nil, false)
+	require.NoError(t, err)
+	require.NoError(t, notifier.SendResultNotification(match, "", false))
+	require.NoError(t, notifier.SendLeaderboard(nil, false))
+	require.NoError(t, notifier.SendLevelLeaderboard(nil, false))
+
+	assert.Equal(t, []string{"CMAIN", "CRESULTS", "CSTATS", "CMAIN"}, postedTo, "Unmapped types should use the main channel")
+}
+
+func TestSendResultNotification_NoThreadAcrossChannels(t *testing.T) {
+	var threadTS string
+	api := &mockSlackAPI{
+		postMessageContextFunc: func(ctx context.Context, channelID string, options ...slackapi.MsgOption) (string, string, error) {
+			_, values, err := slackapi.UnsafeApplyMsgOptions("", channelID, "", options...)
+			require.NoError(t, err)
+			threadTS = values.Get("thread_ts")
+			return channelID, "ts456", nil
+		},
+	}
+	notifier := NewNotifierWithAPI(api, config.SlackConfig{ChannelID: "CMAIN", Channels: map[string]string{notifierPkg.NotificationResult: "CRESULTS"}}, metrics.NewMock())
+
+	require.NoError(t, notifier.SendResultNotification(&playtomic.PadelMatch{ResourceName: "Court 1"}, "ts123", false))
+	assert.Empty(t, threadTS, "Results in another channel than the booking cannot be threaded")
+}
+
 func TestFormatBookingNotification(t *testing.T) {
 	loc, _ := time.LoadLocation("Europe/Copenhagen")
 	match := &playtomic.PadelMatch{
@@ -113,8 +238,8 @@ func TestFormatBookingNotification(t *testing.T) {
 		},
 		BallBringerName: "Player A",
 	}
-	client := &Notifier{channelID: "C123"}
-	msg := client.formatBookingNotification(match)
+	client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
+	msg := client.formatBookingNotification(match, nil, nil)
 	require.Len(t, msg.Blocks.BlockSet, 4, "Expected 4 blocks")
 
 	// 1. Header Block
@@ -145,6 +270,101 @@ func TestFormatBookingNotification(t *testing.T) {
 	assert.Equal(t, "🎾 Player A is bringing balls!", ballBringerElement.Text)
 }
 
+func TestFormatBookingNotification_MentionsMappedPlayers(t *testing.T) {
+	match := &playtomic.PadelMatch{
+		ResourceName: "Court 1",
+		Teams: []playtomic.Team{
+			{Players: []playtomic.Player{{UserID: "p1", Name: "Player A"}, {UserID: "p2", Name: "Player B"}}},
+		},
+		BallBringerID:   "p1",
+		BallBringerName: "Player A",
+	}
+	client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
+	msg := client.formatBookingNotification(match, map[string]string{"p1": "U123"}, nil)
+	require.Len(t, msg.Blocks.BlockSet, 4, "Expected 4 blocks")
+
+	players, ok := msg.Blocks.BlockSet[2].(*slackapi.SectionBlock)
+	require.True(t, ok, "Third block should be a SectionBlock")
+	assert.Equal(t, "mrkdwn", players.Text.Type)
+	assert.Equal(t, "Players:\n• <@U123>\n• Player B", players.Text.Text)
+
+	contextBlock, ok := msg.Blocks.BlockSet[3].(*slackapi.ContextBlock)
+	require.True(t, ok, "Fourth block should be a ContextBlock")
+	ballBringerElement, ok := contextBlock.ContextElements.Elements[0].(*slackapi.TextBlockObject)
+	require.True(t, ok)
+	assert.Equal(t, "🎾 <@U123> is bringing balls!", ballBringerElement.Text)
+}
+
+func TestFormatBookingNotification_Guests(t *testing.T) {
+	match := &playtomic.PadelMatch{
+		ResourceName: "Court 1",
+		Teams: []playtomic.Team{
+			{Players: []playtomic.Player{{UserID: "p1", Name: "Player A"}, {UserID: "g1", Name: "Visitor"}}},
+			{Players: []playtomic.Player{{UserID: "p2", Name: "Player B"}, {UserID: "g2"}}},
+		},
+	}
+	guestIDs := map[string]bool{"g1": true, "g2": true}
+
+	t.Run("labeled", func(t *testing.T) {
+		client := NewNotifierWithAPI(nil, config.SlackConfig{ChannelID: "C123"}, metrics.NewMock())
+		msg := client.formatBookingNotification(match, map[string]string{"p1": "U123"}, guestIDs)
+		players := msg.Blocks.BlockSet[2].(*slackapi.SectionBlock)
+		assert.Equal(t, "Players:\n• <@U123>\n• Visitor (guest)\n• Player B\n• Guest", players.Text.Text)
+	})
+
+	t.Run("hidden", func(t *testing.T) {
+		client := NewNotifierWithAPI(nil, config.SlackConfig{ChannelID: "C123", HideGuests: true}, metrics.NewMock())
+		msg := client.formatBookingNotification(match, nil, guestIDs)
+		players := msg.Blocks.BlockSet[2].(*slackapi.SectionBlock)
+		assert.Equal(t, "Players:\n• Player A\n• Player B", players.Text.Text)
+	})
+
+	t.Run("danish", func(t *testing.T) {
+		client := NewNotifierWithAPI(nil, config.SlackConfig{ChannelID: "C123", Locale: "da"}, metrics.NewMock())
+		msg := client.formatBookingNotification(match, nil, guestIDs)
+		players := msg.Blocks.BlockSet[2].(*slackapi.SectionBlock)
+		assert.Equal(t, "Spillere:\n• Player A\n• Visitor (gæst)\n• Player B\n• Gæst", players.Text.Text)
+	})
+}
+
+func TestFormatBookingNotification_EscapesNames(t *testing.T) {
+	match := &playtomic.PadelMatch{
+		ResourceName: "Court 1",
+		Teams: []playtomic.Team{
+			{Players: []playtomic.Player{{UserID: "p1", Name: "Player A"}, {UserID: "p2", Name: "<!channel> & co"}}},
+			{Players: []playtomic.Player{{UserID: "g1", Name: "<@U999>"}}},
+		},
+	}
+	client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
+	msg := client.formatBookingNotification(match, map[string]string{"p1": "U123"}, map[string]bool{"g1": true})
+
+	players := msg.Blocks.BlockSet[2].(*slackapi.SectionBlock)
+	assert.Equal(t, "Players:\n• <@U123>\n• &lt;!channel&gt; &amp; co\n• &lt;@U999&gt; (guest)", players.Text.Text)
+}
+
+func TestFormatBookingNotification_Danish(t *testing.T) {
+	match := &playtomic.PadelMatch{
+		ResourceName: "Court 1",
+		Teams: []playtomic.Team{
+			{Players: []playtomic.Player{{Name: "Player A"}, {Name: "Player B"}}},
+		},
+		BallBringerName: "Player A",
+	}
+	client := NewNotifierWithAPI(nil, config.SlackConfig{ChannelID: "C123", Locale: "da"}, metrics.NewMock())
+	msg := client.formatBookingNotification(match, nil, nil)
+	require.Len(t, msg.Blocks.BlockSet, 4)
+
+	header := msg.Blocks.BlockSet[0].(*slackapi.HeaderBlock)
+	assert.Equal(t, "🎾 Ny kamp booket! 🎾", header.Text.Text)
+	details := msg.Blocks.BlockSet[1].(*slackapi.SectionBlock)
+	assert.True(t, strings.HasPrefix(details.Text.Text, "Bane: Court 1\nTid: "))
+	players := msg.Blocks.BlockSet[2].(*slackapi.SectionBlock)
+	assert.Equal(t, "Spillere:\n• Player A\n• Player B", players.Text.Text)
+	contextBlock := msg.Blocks.BlockSet[3].(*slackapi.ContextBlock)
+	ballBringer := contextBlock.ContextElements.Elements[0].(*slackapi.TextBlockObject)
+	assert.Equal(t, "🎾 Player A tager bolde med!", ballBringer.Text)
+}
+
 func TestFormatResultNotification(t *testing.T) {
 	loc, _ := time.LoadLocation("Europe/Copenhagen")
 	match := &playtomic.PadelMatch{
@@ -161,10 +381,10 @@ func TestFormatResultNotification(t *testing.T) {
 			{Name: "Set 2", Scores: map[string]int{"t1": 7, "t2": 5}},
 		},
 	}
-	client := &Notifier{channelID: "C123"}
+	client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
 	msg := client.formatResultNotification(match)
 
-	require.Len(t, msg.Blocks.BlockSet, 4, "Expected 4 blocks")
+	require.Len(t, msg.Blocks.BlockSet, 5, "Expected 5 blocks")
 
 	// Check header and details
 	header, ok := msg.Blocks.BlockSet[0].(*slackapi.HeaderBlock)
@@ -187,8 +407,16 @@ func TestFormatResultNotification(t *testing.T) {
 	assert.Equal(t, expectedSet1, resultsSection.Fields[0].Text)
 	assert.Equal(t, expectedSet2, resultsSection.Fields[1].Text)
 
+	// Check the one-line summary of the set scores
+	summaryBlock, ok := msg.Blocks.BlockSet[3].(*slackapi.ContextBlock)
+	require.True(t, ok)
+	require.Len(t, summaryBlock.ContextElements.Elements, 1)
+	summaryElement, ok := summaryBlock.ContextElements.Elements[0].(*slackapi.TextBlockObject)
+	require.True(t, ok)
+	assert.Equal(t, "6-2, 7-5 (Player A & Player B)", summaryElement.Text)
+
 	// Check context block
-	contextBlock, ok := msg.Blocks.BlockSet[3].(*slackapi.ContextBlock)
+	contextBlock, ok := msg.Blocks.BlockSet[4].(*slackapi.ContextBlock)
 	require.True(t, ok)
 	require.Len(t, contextBlock.ContextElements.Elements, 1)
 
@@ -197,6 +425,154 @@ func TestFormatResultNotification(t *testing.T) {
 	assert.Equal(t, "🎾 Player C brought the balls!", ballBringerElement.Text)
 }
 
+func TestFormatResultNotification_DerivesMissingTeamResult(t *testing.T) {
+	match := &playtomic.PadelMatch{
+		MatchType: playtomic.MatchTypeCompetition,
+		Teams: []playtomic.Team{
+			{ID: "t1", Players: []playtomic.Player{{Name: "Player A"}, {Name: "Player B"}}},
+			{ID: "t2", Players: []playtomic.Player{{Name: "Player C"}, {Name: "Player D"}}},
+		},
+		Results: []playtomic.SetResult{
+			{Name: "Set 1", Scores: map[string]int{"t1": 2, "t2": 6}},
+			{Name: "Set 2", Scores: map[string]int{"t1": 6, "t2": 7}},
+		},
+	}
+	client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
+	msg := client.formatResultNotification(match)
+
+	resultsSection, ok := msg.Blocks.BlockSet[2].(*slackapi.SectionBlock)
+	require.True(t, ok)
+	assert.Equal(t, "Result: Player C & Player D won! 🏆", resultsSection.Text.Text)
+	assert.Equal(t, "WON", match.Teams[1].TeamResult, "the derived result is kept on the match")
+}
+
+func TestFormatResultCorrection(t *testing.T) {
+	match := &playtomic.PadelMatch{
+		MatchType: playtomic.MatchTypeCompetition,
+		Teams: []playtomic.Team{
+			{ID: "t1", Players: []playtomic.Player{{Name: "Player A"}}},
+			{ID: "t2", Players: []playtomic.Player{{Name: "Player C"}}},
+		},
+		Results: []playtomic.SetResult{{Name: "Set 1", Scores: map[string]int{"t1": 4, "t2": 6}}},
+	}
+	client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
+	msg := client.formatResultCorrection(match)
+
+	header, ok := msg.Blocks.BlockSet[0].(*slackapi.HeaderBlock)
+	require.True(t, ok)
+	assert.Equal(t, "✏️ Updated result ✏️", header.Text.Text)
+	resultsSection, ok := msg.Blocks.BlockSet[2].(*slackapi.SectionBlock)
+	require.True(t, ok)
+	assert.Equal(t, "Result: Player C won! 🏆", resultsSection.Text.Text)
+}
+
+func TestFormatResultNotification_Modes(t *testing.T) {
+	newMatch := func(matchType playtomic.MatchType) *playtomic.PadelMatch {
+		return &playtomic.PadelMatch{
+			ResourceName: "Court 1",
+			MatchType:    matchType,
+			Teams: []playtomic.Team{
+				{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{Name: "Player A"}, {Name: "Player B"}}},
+				{ID: "t2", Players: []playtomic.Player{{Name: "Player C"}, {Name: "Player D"}}},
+			},
+			Results: []playtomic.SetResult{{Name: "Set 1", Scores: map[string]int{"t1": 6, "t2": 2}}},
+		}
+	}
+	render := func(t *testing.T, theme config.MessageTheme, match *playtomic.PadelMatch) (string, string) {
+		t.Helper()
+		client := NewNotifierWithAPI(&mockSlackAPI{}, config.SlackConfig{Theme: theme}, metrics.NewMock())
+		msg := client.formatResultNotification(match)
+		header, ok := msg.Blocks.BlockSet[0].(*slackapi.HeaderBlock)
+		require.True(t, ok)
+		var body strings.Builder
+		for _, block := range msg.Blocks.BlockSet[1:] {
+			if section, ok := block.(*slackapi.SectionBlock); ok {
+				body.WriteString(section.Text.Text + "\n")
+			}
+		}
+		return header.Text.Text, body.String()
+	}
+
+	t.Run("competition results show scores", func(t *testing.T) {
+		header, body := render(t, config.MessageTheme{PracticeResultHeader: "Friendly done"}, newMatch(playtomic.MatchTypeCompetition))
+		assert.Equal(t, "🎾 Match finished! 🎾", header)
+		assert.Contains(t, body, "Player A & Player B won!")
+		assert.NotContains(t, body, "Players:")
+	})
+
+	t.Run("practice results list the players by default", func(t *testing.T) {
+		header, body := render(t, config.MessageTheme{PracticeResultHeader: "Friendly done"}, newMatch(playtomic.MatchTypePractice))
+		assert.Equal(t, "Friendly done", header)
+		assert.Contains(t, body, "Players:")
+		assert.NotContains(t, body, "won!")
+	})
+
+	t.Run("scored mode shows practice scores like a competition", func(t *testing.T) {
+		theme := config.MessageTheme{PracticeResultHeader: "Friendly done", PracticeResultMode: config.ResultModeScored}
+		header, body := render(t, theme, newMatch(playtomic.MatchTypePractice))
+		assert.Equal(t, "Friendly done", header)
+		assert.Contains(t, body, "Result: Player A & Player B won! 🏆")
+		assert.NotContains(t, body, "Players:")
+	})
+
+	t.Run("friendly scored mode shows practice scores with the friendly labels", func(t *testing.T) {
+		theme := config.MessageTheme{PracticeResultHeader: "Practice done", PracticeResultMode: config.ResultModeFriendly}
+		header, body := render(t, theme, newMatch(playtomic.MatchTypePractice))
+		assert.Equal(t, "🎾 Friendly finished! 🎾", header)
+		assert.Contains(t, body, "Friendly score: Player A & Player B took this one 🤝")
+		assert.NotContains(t, body, "won!")
+		assert.NotContains(t, body, "Players:")
+	})
+
+	t.Run("friendly scored mode takes its header and emoji from the theme", func(t *testing.T) {
+		theme := config.MessageTheme{PracticeResultMode: config.ResultModeFriendly, FriendlyResultHeader: "Good game!", FriendlyEmoji: "🍻"}
+		header, body := render(t, theme, newMatch(playtomic.MatchTypePractice))
+		assert.Equal(t, "Good game!", header)
+		assert.Contains(t, body, "took this one 🍻")
+	})
+
+	t.Run("competition results ignore the friendly mode", func(t *testing.T) {
+		header, body := render(t, config.MessageTheme{PracticeResultMode: config.ResultModeFriendly}, newMatch(playtomic.MatchTypeCompetition))
+		assert.Equal(t, "🎾 Match finished! 🎾", header)
+		assert.Contains(t, body, "Result: Player A & Player B won! 🏆")
+	})
+
+	t.Run("practice results follow a custom result header", func(t *testing.T) {
+		header, _ := render(t, config.MessageTheme{ResultHeader: "Game over"}, newMatch(playtomic.MatchTypePractice))
+		assert.Equal(t, "Game over", header)
+	})
+}
+
+func TestFormatResultNotification_PlainTextNames(t *testing.T) {
+	match := &playtomic.PadelMatch{
+		ResourceName: "Court 1",
+		MatchType:    playtomic.MatchTypePractice,
+		Teams: []playtomic.Team{
+			{ID: "t1", Players: []playtomic.Player{{Name: "Tom & Jerry"}}},
+			{ID: "t2", Players: []playtomic.Player{{Name: "<Ana>"}}},
+		},
+	}
+	client := NewNotifierWithAPI(&mockSlackAPI{}, config.SlackConfig{}, metrics.NewMock())
+	msg := client.formatResultNotification(match)
+
+	players, ok := msg.Blocks.BlockSet[2].(*slackapi.SectionBlock)
+	require.True(t, ok)
+	assert.Equal(t, "plain_text", players.Text.Type)
+	assert.Equal(t, "Players:\n• Tom & Jerry\n• <Ana>", players.Text.Text, "plain_text is shown as is, so names are not escaped")
+}
+
+func TestSetScoresSummary(t *testing.T) {
+	results := []playtomic.SetResult{
+		{Name: "Set 1", Scores: map[string]int{"t1": 4, "t2": 6}},
+		{Name: "Set 2", Scores: map[string]int{"t1": 7, "t2": 5}},
+		{Name: "Set 3", Scores: map[string]int{"t1": 3, "t2": 6}},
+	}
+
+	assert.Equal(t, "6-4, 5-7, 6-3", setScoresSummary(results, "t2"), "Scores should be read from the winner's side")
+	assert.Equal(t, "4-6, 7-5, 3-6", setScoresSummary(results, "t1"))
+	assert.Empty(t, setScoresSummary(results, ""), "No summary without a known winner")
+}
+
 func TestFormatLeaderboard(t *testing.T) {
 	t.Run("displays leaderboard with stats", func(t *testing.T) {
 		stats := []club.PlayerStats{
@@ -205,8 +581,8 @@ func TestFormatLeaderboard(t *testing.T) {
 			{PlayerName: "Player C", MatchesPlayed: 10, MatchesWon: 4, WinPercentage: 40.0, SetsWon: 8, GamesWon: 64},
 		}
 
-		client := &Notifier{channelID: "C123"}
-		msg := client.formatLeaderboard(stats)
+		client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
+		msg := client.formatLeaderboard(stats, false)
 
 		require.Len(t, msg.Blocks.BlockSet, 4, "Expected 4 blocks (header + 3 players)")
 
@@ -230,13 +606,83 @@ func TestFormatLeaderboard(t *testing.T) {
 		player3, ok := msg.Blocks.BlockSet[3].(*slackapi.SectionBlock)
 		require.True(t, ok)
 		assert.Contains(t, player3.Text.Text, "3. 🥉 Player C")
+		assert.NotContains(t, player3.Text.Text, "matches)")
+	})
+
+	t.Run("tied players share a rank", func(t *testing.T) {
+		stats := []club.PlayerStats{
+			{PlayerName: "Player A", MatchesPlayed: 10, MatchesWon: 8, SetsWon: 16, SetsLost: 4, GamesWon: 96, GamesLost: 60},
+			{PlayerName: "Player B", MatchesPlayed: 10, MatchesWon: 6, SetsWon: 12, SetsLost: 8, GamesWon: 80, GamesLost: 70},
+			{PlayerName: "Player C", MatchesPlayed: 10, MatchesWon: 6, SetsWon: 12, SetsLost: 8, GamesWon: 80, GamesLost: 70},
+			{PlayerName: "Player D", MatchesPlayed: 10, MatchesWon: 4, SetsWon: 8, SetsLost: 12, GamesWon: 64, GamesLost: 80},
+		}
+
+		client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
+		msg := client.formatLeaderboard(stats, false)
+
+		require.Len(t, msg.Blocks.BlockSet, 5)
+		var lines []string
+		for _, block := range msg.Blocks.BlockSet[1:] {
+			section, ok := block.(*slackapi.SectionBlock)
+			require.True(t, ok)
+			lines = append(lines, strings.SplitN(section.Text.Text, "\n", 2)[0])
+		}
+		assert.Equal(t, []string{"1. 🥇 Player A", "2. 🥈 Player B", "2. 🥈 Player C", "4.  Player D"}, lines)
+
+		table := formatLeaderboardTable(stats)
+		assert.Contains(t, table, "  2  Player C")
+		assert.Contains(t, table, "  4  Player D")
+	})
+
+	t.Run("keeps the ranks of ranked stats", func(t *testing.T) {
+		stats := []club.PlayerStats{
+			{Rank: 1, PlayerName: "Player A", MatchesPlayed: 10, MatchesWon: 8},
+			{Rank: 1, PlayerName: "Player B", MatchesPlayed: 10, MatchesWon: 8},
+			{Rank: 3, PlayerName: "Player C", MatchesPlayed: 10, MatchesWon: 4},
+		}
+		assert.Equal(t, []int{1, 1, 3}, leaderboardRanks(stats))
+	})
+
+	t.Run("marks the places after the medals up to tenth", func(t *testing.T) {
+		var stats []club.PlayerStats
+		for i := range 11 {
+			stats = append(stats, club.PlayerStats{PlayerName: fmt.Sprintf("Player %d", i+1), MatchesPlayed: 20, MatchesWon: 20 - i})
+		}
+		theme := config.DefaultMessageTheme()
+		theme.TopTenMarker = "🎖️"
+		client := &Notifier{channelID: "C123", theme: theme}
+		msg := client.formatLeaderboard(stats, false)
+
+		require.Len(t, msg.Blocks.BlockSet, 12)
+		text := func(i int) string {
+			section, ok := msg.Blocks.BlockSet[i].(*slackapi.SectionBlock)
+			require.True(t, ok)
+			return section.Text.Text
+		}
+		assert.Contains(t, text(3), "3. 🥉 Player 3")
+		assert.Contains(t, text(4), "4. 🎖️ Player 4")
+		assert.Contains(t, text(10), "10. 🎖️ Player 10")
+		assert.Contains(t, text(11), "11.  Player 11")
+	})
+
+	t.Run("flags win percentages based on few matches", func(t *testing.T) {
+		stats := []club.PlayerStats{
+			{PlayerName: "Newcomer", MatchesPlayed: 3, MatchesWon: 3, WinPercentage: 100.0},
+		}
+
+		client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
+		msg := client.formatLeaderboard(stats, false)
+
+		player, ok := msg.Blocks.BlockSet[1].(*slackapi.SectionBlock)
+		require.True(t, ok)
+		assert.Contains(t, player.Text.Text, "(only 3 matches)")
 	})
 
 	t.Run("displays message when no stats are available", func(t *testing.T) {
 		stats := []club.PlayerStats{}
 
-		client := &Notifier{channelID: "C123"}
-		msg := client.formatLeaderboard(stats)
+		client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
+		msg := client.formatLeaderboard(stats, false)
 
 		require.Len(t, msg.Blocks.BlockSet, 2, "Expected 2 blocks (header + message)")
 
@@ -247,8 +693,42 @@ func TestFormatLeaderboard(t *testing.T) {
 	})
 }
 
+func TestFormatLeaderboard_Compact(t *testing.T) {
+	stats := []club.PlayerStats{
+		{PlayerName: "Player A", MatchesPlayed: 10, MatchesWon: 8, WinPercentage: 80.0, SetsWon: 16, GamesWon: 96},
+		{PlayerName: "Søren", MatchesPlayed: 4, MatchesWon: 1, WinPercentage: 25.0, SetsWon: 3, GamesWon: 20},
+	}
+
+	t.Run("renders an aligned table", func(t *testing.T) {
+		client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
+		msg := client.formatLeaderboard(stats, true)
+		require.Len(t, msg.Blocks.BlockSet, 2, "Expected 2 blocks (header + table)")
+
+		table, ok := msg.Blocks.BlockSet[1].(*slackapi.SectionBlock)
+		require.True(t, ok)
+		assert.Equal(t, "mrkdwn", table.Text.Type)
+		expected := "```\n" +
+			"  #  Player      Win %      W/P  Sets  Games\n" +
+			"  1  Player A   80.00%     8/10    16     96\n" +
+			"  2  Søren      25.00%      1/4     3     20\n" +
+			"```"
+		assert.Equal(t, expected, table.Text.Text)
+	})
+
+	t.Run("uses the table up to the configured size", func(t *testing.T) {
+		client := &Notifier{channelID: "C123", compactLeaderboardMax: 2, theme: config.DefaultMessageTheme()}
+		assert.True(t, client.compactLeaderboard(stats))
+
+		client.compactLeaderboardMax = 1
+		assert.False(t, client.compactLeaderboard(stats), "Larger leaderboards fall back to one block per player")
+
+		client.compactLeaderboardMax = 0
+		assert.False(t, client.compactLeaderboard(stats), "Zero disables the table")
+	})
+}
+
 func TestFormatPlayerStats(t *testing.T) {
-	client := &Notifier{channelID: "C123"}
+	client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
 
 	t.Run("formats stats for a found player", func(t *testing.T) {
 		stat := &club.PlayerStats{
@@ -285,7 +765,7 @@ func TestFormatPlayerStats(t *testing.T) {
 }
 
 func TestFormatLevelLeaderboard(t *testing.T) {
-	client := &Notifier{channelID: "C123"}
+	client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
 
 	t.Run("formats leaderboard with players", func(t *testing.T) {
 		players := []club.PlayerInfo{
@@ -324,3 +804,393 @@ func TestFormatLevelLeaderboard(t *testing.T) {
 		assert.Equal(t, "No players found.", message.Text.Text)
 	})
 }
+
+func TestFormatBallBringers(t *testing.T) {
+	client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
+
+	t.Run("formats standings with players", func(t *testing.T) {
+		players := []club.PlayerInfo{
+			{Name: "Player A", BallBringerCount: 5},
+			{Name: "Player B", BallBringerCount: 3},
+			{Name: "Player C", BallBringerCount: 0},
+		}
+
+		msg := client.formatBallBringers(players)
+		require.Len(t, msg.Blocks.BlockSet, 2) // Header + standings
+
+		header, ok := msg.Blocks.BlockSet[0].(*slackapi.HeaderBlock)
+		require.True(t, ok)
+		assert.Equal(t, "🎾 Ball Bringer Standings 🎾", header.Text.Text)
+
+		section, ok := msg.Blocks.BlockSet[1].(*slackapi.SectionBlock)
+		require.True(t, ok)
+		assert.Equal(t, "1. Player A: 5\n2. Player B: 3\n3. Player C: 0", section.Text.Text)
+	})
+
+	t.Run("formats message for no players", func(t *testing.T) {
+		msg := client.formatBallBringers([]club.PlayerInfo{})
+		require.Len(t, msg.Blocks.BlockSet, 2) // Header + message
+
+		message, ok := msg.Blocks.BlockSet[1].(*slackapi.SectionBlock)
+		require.True(t, ok)
+		assert.Equal(t, "No players found.", message.Text.Text)
+	})
+}
+
+func TestFormatForm(t *testing.T) {
+	client := &Notifier{theme: config.DefaultMessageTheme()}
+
+	msg := client.formatForm("Morten Voss", []bool{true, true, false, true})
+	require.Len(t, msg.Blocks.BlockSet, 1)
+	section, ok := msg.Blocks.BlockSet[0].(*slackapi.SectionBlock)
+	require.True(t, ok)
+	assert.Equal(t, "Form for *Morten Voss* (last 4, oldest first): ✅✅❌✅", section.Text.Text)
+
+	msg = client.formatForm("Newcomer", nil)
+	section, ok = msg.Blocks.BlockSet[0].(*slackapi.SectionBlock)
+	require.True(t, ok)
+	assert.Equal(t, "*Newcomer* has no finished matches yet.", section.Text.Text)
+}
+
+func TestFormatBookingDigest(t *testing.T) {
+	client := &Notifier{theme: config.DefaultMessageTheme()}
+	start := time.Date(2024, 3, 15, 17, 0, 0, 0, time.UTC).Unix()
+
+	msg := client.formatBookingDigest([]*playtomic.PadelMatch{
+		{
+			MatchID:         "m1",
+			Start:           start,
+			ResourceName:    "Court 1",
+			BallBringerName: "Anna",
+			Teams:           []playtomic.Team{{ID: "t1", Players: []playtomic.Player{{Name: "Anna"}, {Name: "Bo"}}}},
+		},
+		{
+			MatchID:      "m2",
+			Start:        start + 3600,
+			ResourceName: "Court 2",
+			Teams:        []playtomic.Team{{ID: "t1", Players: []playtomic.Player{{Name: "Carl"}}}},
+		},
+	})
+	require.Len(t, msg.Blocks.BlockSet, 2)
+	header, ok := msg.Blocks.BlockSet[0].(*slackapi.HeaderBlock)
+	require.True(t, ok)
+	assert.Equal(t, "🎾 Today's new bookings 🎾", header.Text.Text)
+	section, ok := msg.Blocks.BlockSet[1].(*slackapi.SectionBlock)
+	require.True(t, ok)
+	assert.Equal(t, "• *Friday 15 Mar, 18:00* — Court 1: Anna, Bo 🎾 Anna is bringing balls!\n• *Friday 15 Mar, 19:00* — Court 2: Carl", section.Text.Text)
+}
+
+func TestFormatWeeklyRecap(t *testing.T) {
+	client := &Notifier{theme: config.DefaultMessageTheme()}
+	weekStart := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC).Unix()
+
+	msg := client.formatWeeklyRecap(club.WeeklyRecap{
+		WeekStart: weekStart,
+		Matches: []*playtomic.PadelMatch{{
+			MatchID: "m1",
+			Teams: []playtomic.Team{
+				{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{Name: "Anna"}, {Name: "Bo"}}},
+				{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{Name: "Carl"}, {Name: "Dan"}}},
+			},
+			Results: []playtomic.SetResult{
+				{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 4}},
+				{Name: "Set-2", Scores: map[string]int{"t1": 6, "t2": 3}},
+			},
+		}},
+		TopPerformer: &club.RecapHighlight{PlayerID: "p1", PlayerName: "Anna", Value: 1},
+		MostImproved: &club.RecapHighlight{PlayerID: "p3", PlayerName: "Carl", Value: 0.25},
+		BallBringer:  &club.RecapHighlight{PlayerID: "p2", PlayerName: "Bo", Value: 1},
+	})
+	require.Len(t, msg.Blocks.BlockSet, 4)
+	results, ok := msg.Blocks.BlockSet[1].(*slackapi.SectionBlock)
+	require.True(t, ok)
+	assert.Equal(t, "*Week of 11 Mar 2024* — 1 matches played\n• Anna & Bo beat Carl & Dan (6-4, 6-3)", results.Text.Text)
+	highlights, ok := msg.Blocks.BlockSet[3].(*slackapi.SectionBlock)
+	require.True(t, ok)
+	assert.Contains(t, highlights.Text.Text, "Top performer: *Anna* with 1 wins")
+	assert.Contains(t, highlights.Text.Text, "Most improved: *Carl* (+0.25 level)")
+	assert.Contains(t, highlights.Text.Text, "Ball bringer of the week: *Bo* (1 times)")
+
+	msg = client.formatWeeklyRecap(club.WeeklyRecap{WeekStart: weekStart})
+	require.Len(t, msg.Blocks.BlockSet, 2)
+	section, ok := msg.Blocks.BlockSet[1].(*slackapi.SectionBlock)
+	require.True(t, ok)
+	assert.Equal(t, "*Week of 11 Mar 2024* — no matches were played this week.", section.Text.Text)
+}
+
+func TestFormatLeaderboardDiff(t *testing.T) {
+	client := &Notifier{theme: config.DefaultMessageTheme()}
+
+	msg := client.formatLeaderboardDiff("last-week", "this-week", []club.PlayerStatsDelta{
+		{PlayerID: "p1", PlayerName: "Anna", MatchesPlayed: 1, MatchesWon: 2, GamesWon: 9},
+		{PlayerID: "p2", PlayerName: "Bo", MatchesPlayed: -1, MatchesWon: -1, GamesWon: -4},
+	})
+	require.Len(t, msg.Blocks.BlockSet, 2)
+	header, ok := msg.Blocks.BlockSet[0].(*slackapi.HeaderBlock)
+	require.True(t, ok)
+	assert.Equal(t, "📊 this-week vs last-week 📊", header.Text.Text)
+	section, ok := msg.Blocks.BlockSet[1].(*slackapi.SectionBlock)
+	require.True(t, ok)
+	assert.Equal(t, "1. *Anna*: +2 wins (+1 matches, +9 games won)\n2. *Bo*: -1 wins (-1 matches, -4 games won)", section.Text.Text)
+
+	msg = client.formatLeaderboardDiff("last-week", "this-week", nil)
+	section, ok = msg.Blocks.BlockSet[1].(*slackapi.SectionBlock)
+	require.True(t, ok)
+	assert.Equal(t, "No matches were played in either period.", section.Text.Text)
+}
+
+func TestFormatHomeView(t *testing.T) {
+	client := &Notifier{theme: config.DefaultMessageTheme()}
+
+	t.Run("shows stats, form and next match of a linked player", func(t *testing.T) {
+		view := client.formatHomeView(notifierPkg.HomeView{
+			Player: &club.PlayerInfo{ID: "p1", Name: "Morten Voss"},
+			Stats:  &club.PlayerStats{PlayerID: "p1", PlayerName: "Morten Voss", MatchesPlayed: 4, MatchesWon: 3, WinPercentage: 75},
+			NextMatch: &playtomic.PadelMatch{
+				MatchID:      "m1",
+				ResourceName: "Court 3",
+				Teams: []playtomic.Team{
+					{ID: "t1", Players: []playtomic.Player{{UserID: "p1", Name: "Morten Voss"}, {UserID: "p2", Name: "Player Two"}}},
+				},
+			},
+			Form: []bool{true, false, true},
+		})
+
+		assert.Equal(t, slackapi.VTHomeTab, view.Type)
+		payload, err := json.Marshal(view)
+		require.NoError(t, err)
+		assert.Contains(t, string(payload), "Stats for Morten Voss")
+		assert.Contains(t, string(payload), "75.00% (3/4)")
+		assert.Contains(t, string(payload), "✅❌✅")
+		assert.Contains(t, string(payload), "Court 3")
+	})
+
+	t.Run("tells a player without upcoming matches", func(t *testing.T) {
+		view := client.formatHomeView(notifierPkg.HomeView{Player: &club.PlayerInfo{ID: "p1", Name: "Morten Voss"}})
+		payload, err := json.Marshal(view)
+		require.NoError(t, err)
+		assert.Contains(t, string(payload), "You have no upcoming matches.")
+		assert.Contains(t, string(payload), "has no finished matches yet")
+	})
+
+	t.Run("prompts unlinked users to get linked", func(t *testing.T) {
+		view := client.formatHomeView(notifierPkg.HomeView{})
+		require.Len(t, view.Blocks.BlockSet, 1)
+		section, ok := view.Blocks.BlockSet[0].(*slackapi.SectionBlock)
+		require.True(t, ok)
+		assert.Contains(t, section.Text.Text, "not linked to a Playtomic player")
+	})
+}
+
+func TestPublishHomeView(t *testing.T) {
+	var published slackapi.PublishViewContextRequest
+	api := &mockSlackAPI{
+		publishViewContextFunc: func(ctx context.Context, req slackapi.PublishViewContextRequest) (*slackapi.ViewResponse, error) {
+			published = req
+			return &slackapi.ViewResponse{}, nil
+		},
+	}
+	metricsMock := metrics.NewMock()
+	client := NewNotifierWithAPI(api, config.SlackConfig{}, metricsMock)
+
+	require.NoError(t, client.PublishHomeView("U123", notifierPkg.HomeView{}, false))
+	assert.Equal(t, "U123", published.UserID)
+	assert.Equal(t, slackapi.VTHomeTab, published.View.Type)
+	assert.Equal(t, 1, metricsMock.SlackNotifSent())
+}
+
+func TestFormatRoster(t *testing.T) {
+	client := &Notifier{channelID: "C123", theme: config.DefaultMessageTheme()}
+
+	t.Run("lists players with their level", func(t *testing.T) {
+		players := []club.PlayerInfo{
+			{Name: "Anna", Level: 2.5},
+			{Name: "Bo", Level: 3},
+		}
+
+		msg := client.formatRoster(players, 1)
+		require.Len(t, msg.Blocks.BlockSet, 2) // Header + one section
+
+		header, ok := msg.Blocks.BlockSet[0].(*slackapi.HeaderBlock)
+		require.True(t, ok)
+		assert.Equal(t, "👥 Club Roster 👥", header.Text.Text)
+
+		section, ok := msg.Blocks.BlockSet[1].(*slackapi.SectionBlock)
+		require.True(t, ok)
+		assert.Equal(t, "• *Anna* — Level 2.50\n• *Bo* — Level 3.00", section.Text.Text)
+	})
+
+	t.Run("pages a large club within the block limit", func(t *testing.T) {
+		players := make([]club.PlayerInfo, rosterPageSize+5)
+		for i := range players {
+			players[i] = club.PlayerInfo{Name: fmt.Sprintf("Player %04d", i)}
+		}
+
+		first := client.formatRoster(players, 1)
+		assert.LessOrEqual(t, len(first.Blocks.BlockSet), maxMessageBlocks)
+		assert.Contains(t, first.Blocks.BlockSet[1].(*slackapi.SectionBlock).Text.Text, "Player 0000")
+		pageInfo, ok := first.Blocks.BlockSet[len(first.Blocks.BlockSet)-1].(*slackapi.ContextBlock)
+		require.True(t, ok)
+		assert.Contains(t, pageInfo.ContextElements.Elements[0].(*slackapi.TextBlockObject).Text, "Page 1 of 2")
+
+		second := client.formatRoster(players, 2)
+		require.Len(t, second.Blocks.BlockSet, 3) // Header + the last five players + page context
+		assert.Equal(t, 5, strings.Count(second.Blocks.BlockSet[1].(*slackapi.SectionBlock).Text.Text, "•"))
+
+		beyond := client.formatRoster(players, 9)
+		assert.Equal(t, second.Blocks.BlockSet[1], beyond.Blocks.BlockSet[1], "pages past the end show the last page")
+	})
+
+	t.Run("formats message for no players", func(t *testing.T) {
+		msg := client.formatRoster(nil, 1)
+		require.Len(t, msg.Blocks.BlockSet, 2)
+		assert.Equal(t, "No players found.", msg.Blocks.BlockSet[1].(*slackapi.SectionBlock).Text.Text)
+	})
+}
+
+func TestFormatUnmappedPlayers(t *testing.T) {
+	client := &Notifier{theme: config.DefaultMessageTheme()}
+
+	t.Run("lists players with their IDs and how to map them", func(t *testing.T) {
+		players := []club.PlayerInfo{
+			{ID: "p1", Name: "Anna"},
+			{ID: "p2", Name: "Bo"},
+		}
+
+		msg := client.formatUnmappedPlayers(players)
+		require.Len(t, msg.Blocks.BlockSet, 3) // Header + one section + hint
+
+		header, ok := msg.Blocks.BlockSet[0].(*slackapi.HeaderBlock)
+		require.True(t, ok)
+		assert.Equal(t, "🔗 Players without Slack 🔗", header.Text.Text)
+
+		section, ok := msg.Blocks.BlockSet[1].(*slackapi.SectionBlock)
+		require.True(t, ok)
+		assert.Equal(t, "• *Anna* — `p1`\n• *Bo* — `p2`", section.Text.Text)
+
+		hint, ok := msg.Blocks.BlockSet[2].(*slackapi.ContextBlock)
+		require.True(t, ok)
+		assert.Equal(t, "2 players are not linked to a Slack user. Link one with `/map @slackuser <player name>`.", hint.ContextElements.Elements[0].(*slackapi.TextBlockObject).Text)
+	})
+
+	t.Run("keeps the hint when a long list is cut short", func(t *testing.T) {
+		players := make([]club.PlayerInfo, 1200)
+		for i := range players {
+			players[i] = club.PlayerInfo{ID: fmt.Sprint(i), Name: fmt.Sprintf("Player %d", i)}
+		}
+
+		blocks := client.formatUnmappedPlayers(players).Blocks.BlockSet
+		require.Len(t, blocks, maxMessageBlocks)
+		more, ok := blocks[len(blocks)-2].(*slackapi.ContextBlock)
+		require.True(t, ok)
+		assert.Equal(t, fmt.Sprintf("…and %d more", 1200-(maxMessageBlocks-3)*linesPerSection), more.ContextElements.Elements[0].(*slackapi.TextBlockObject).Text)
+		hint, ok := blocks[len(blocks)-1].(*slackapi.ContextBlock)
+		require.True(t, ok)
+		assert.Contains(t, hint.ContextElements.Elements[0].(*slackapi.TextBlockObject).Text, "1200 players")
+	})
+
+	t.Run("formats message when every player is mapped", func(t *testing.T) {
+		msg := client.formatUnmappedPlayers(nil)
+		require.Len(t, msg.Blocks.BlockSet, 2)
+		assert.Equal(t, "Every player is linked to a Slack user.", msg.Blocks.BlockSet[1].(*slackapi.SectionBlock).Text.Text)
+	})
+}
+
+func TestFormatters_StayWithinBlockLimit(t *testing.T) {
+	client := &Notifier{theme: config.DefaultMessageTheme()}
+
+	const count = 1200
+	stats := make([]club.PlayerStats, count)
+	players := make([]club.PlayerInfo, count)
+	deltas := make([]club.PlayerStatsDelta, count)
+	matches := make([]*playtomic.PadelMatch, count)
+	for i := range count {
+		name := fmt.Sprintf("Player %d", i)
+		stats[i] = club.PlayerStats{PlayerID: fmt.Sprint(i), PlayerName: name, MatchesPlayed: 20, MatchesWon: 10}
+		players[i] = club.PlayerInfo{ID: fmt.Sprint(i), Name: name, Level: 2.5, BallBringerCount: 1}
+		deltas[i] = club.PlayerStatsDelta{PlayerID: fmt.Sprint(i), PlayerName: name, MatchesWon: 1}
+		matches[i] = &playtomic.PadelMatch{Teams: []playtomic.Team{
+			{ID: "t1", TeamResult: "WON", Players: []playtomic.Player{{Name: name}}},
+			{ID: "t2", TeamResult: "LOST", Players: []playtomic.Player{{Name: "Opponent"}}},
+		}}
+	}
+
+	tests := map[string]struct {
+		msg   slackapi.Message
+		shown int // lines shown before the "…and N more" block
+	}{
+		"leaderboard":       {client.formatLeaderboard(stats, false), maxMessageBlocks - 2},
+		"level leaderboard": {client.formatLevelLeaderboard(players), maxMessageBlocks - 2},
+		"ball bringers":     {client.formatBallBringers(players), (maxMessageBlocks - 2) * linesPerSection},
+		"leaderboard diff":  {client.formatLeaderboardDiff("last-week", "this-week", deltas), (maxMessageBlocks - 2) * linesPerSection},
+		// The recap's first line is the summary, and it keeps room for its highlights.
+		"weekly recap": {client.formatWeeklyRecap(club.WeeklyRecap{Matches: matches, TopPerformer: &club.RecapHighlight{PlayerName: "Player 0", Value: 1}}), (maxMessageBlocks-4)*linesPerSection - 1},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			blocks := tc.msg.Blocks.BlockSet
+			assert.LessOrEqual(t, len(blocks), maxMessageBlocks)
+
+			var more *slackapi.ContextBlock
+			for _, block := range blocks {
+				if context, ok := block.(*slackapi.ContextBlock); ok {
+					more = context
+				}
+			}
+			require.NotNil(t, more, "truncated output ends with a context block")
+			text, ok := more.ContextElements.Elements[0].(*slackapi.TextBlockObject)
+			require.True(t, ok)
+			assert.Equal(t, fmt.Sprintf("…and %d more", count-tc.shown), text.Text)
+		})
+	}
+}
+
+func TestChunkBlocks(t *testing.T) {
+	client := &Notifier{theme: config.DefaultMessageTheme()}
+	lines := make([]string, 45)
+	for i := range lines {
+		lines[i] = fmt.Sprint(i)
+	}
+
+	blocks := client.chunkBlocks(lines, "mrkdwn", 20, 1)
+	require.Len(t, blocks, 3)
+	last, ok := blocks[2].(*slackapi.SectionBlock)
+	require.True(t, ok)
+	assert.Equal(t, "40\n41\n42\n43\n44", last.Text.Text)
+
+	blocks = client.chunkBlocks(lines, "mrkdwn", 1, 10)
+	require.Len(t, blocks, maxMessageBlocks-10)
+	_, ok = blocks[len(blocks)-1].(*slackapi.ContextBlock)
+	assert.True(t, ok)
+}
+
+func TestMessageTheme(t *testing.T) {
+	theme := config.MessageTheme{
+		BookingHeader: "🏓 Court booked 🏓",
+		BallEmoji:     "🟡",
+		Medals:        []string{"👑"},
+	}
+	client := NewNotifierWithAPI(nil, config.SlackConfig{ChannelID: "C123", Theme: theme}, metrics.NewMock())
+
+	msg := client.formatBookingNotification(&playtomic.PadelMatch{ResourceName: "Court 1", BallBringerName: "Player A"}, nil, nil)
+	header, ok := msg.Blocks.BlockSet[0].(*slackapi.HeaderBlock)
+	require.True(t, ok)
+	assert.Equal(t, "🏓 Court booked 🏓", header.Text.Text)
+
+	contextBlock, ok := msg.Blocks.BlockSet[len(msg.Blocks.BlockSet)-1].(*slackapi.ContextBlock)
+	require.True(t, ok)
+	ballBringer, ok := contextBlock.ContextElements.Elements[0].(*slackapi.TextBlockObject)
+	require.True(t, ok)
+	assert.Equal(t, "🟡 Player A is bringing balls!", ballBringer.Text)
+
+	leaderboard := client.formatLeaderboard([]club.PlayerStats{{PlayerName: "Player A", MatchesPlayed: 1, MatchesWon: 1}, {PlayerName: "Player B", MatchesPlayed: 1}}, false)
+	header, ok = leaderboard.Blocks.BlockSet[0].(*slackapi.HeaderBlock)
+	require.True(t, ok)
+	assert.Equal(t, "🏆 Player Leaderboard 🏆", header.Text.Text, "Unset fields should keep the default")
+	first, ok := leaderboard.Blocks.BlockSet[1].(*slackapi.SectionBlock)
+	require.True(t, ok)
+	assert.Contains(t, first.Text.Text, "1. 👑 Player A")
+	second, ok := leaderboard.Blocks.BlockSet[2].(*slackapi.SectionBlock)
+	require.True(t, ok)
+	assert.NotContains(t, second.Text.Text, "👑")
+}
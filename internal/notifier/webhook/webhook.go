@@ -0,0 +1,209 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/mauv0809/ideal-tribble/internal/club"
+	"github.com/mauv0809/ideal-tribble/internal/notifier"
+	"github.com/mauv0809/ideal-tribble/internal/playtomic"
+)
+
+var _ notifier.Notifier = &Notifier{}
+
+// Notification types that only exist for the webhook, on top of the ones shared with Slack.
+const (
+	notificationDirectMessage   = "direct_message"
+	notificationPlayerNotFound  = "player_not_found"
+	notificationBallBringers    = "ball_bringers"
+	notificationNextMatch       = "next_match"
+	notificationRoster          = "roster"
+	notificationForm            = "form"
+	notificationHomeView        = "home_view"
+	notificationLeaderboardDiff = "leaderboard_diff"
+	notificationBookingDigest   = "booking_digest"
+	notificationResultCorrected = "result_corrected"
+	notificationUnmapped        = "unmapped_players"
+	notificationBallBringerDM   = "ball_bringer_reminder"
+	notificationLineupDM        = "incomplete_lineup_reminder"
+)
+
+// Payload is the JSON body posted to the webhook for every notification.
+// Only the fields relevant to the notification type are set.
+type Payload struct {
+	Type         string                  `json:"type"`
+	Match        *playtomic.PadelMatch   `json:"match,omitempty"`
+	Matches      []*playtomic.PadelMatch `json:"matches,omitempty"`
+	SlackUserIDs map[string]string       `json:"slack_user_ids,omitempty"`
+	GuestIDs     []string                `json:"guest_ids,omitempty"`
+	SlackUserID  string                  `json:"slack_user_id,omitempty"`
+	Text         string                  `json:"text,omitempty"`
+	Stats        []club.PlayerStats      `json:"stats,omitempty"`
+	PlayerStats  *club.PlayerStats       `json:"player_stats,omitempty"`
+	Players      []club.PlayerInfo       `json:"players,omitempty"`
+	Query        string                  `json:"query,omitempty"`
+	PlayerID     string                  `json:"player_id,omitempty"`
+	Page         int                     `json:"page,omitempty"`
+	Form         []bool                  `json:"form,omitempty"`
+	Recap        *club.WeeklyRecap       `json:"recap,omitempty"`
+	Periods      []string                `json:"periods,omitempty"`
+	Deltas       []club.PlayerStatsDelta `json:"deltas,omitempty"`
+	LineupSize   int                     `json:"lineup_size,omitempty"`
+	LineupNeeded int                     `json:"lineup_needed,omitempty"`
+}
+
+// Notifier posts notifications as JSON to a generic webhook, for clubs that don't use Slack.
+type Notifier struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewNotifier creates a new Notifier posting to the given URL.
+func NewNotifier(url string) *Notifier {
+	return &Notifier{
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (w *Notifier) send(payload Payload, dryRun bool) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+	if dryRun {
+		log.Info("[Dry Run] Would post webhook notification", "type", payload.Type, "payload", string(body))
+		return nil
+	}
+
+	resp, err := w.httpClient.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Error("Failed to post webhook notification", "error", err, "type", payload.Type)
+		return fmt.Errorf("failed to post webhook notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Error("Webhook rejected notification", "status", resp.StatusCode, "type", payload.Type)
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	log.Info("Successfully posted webhook notification", "type", payload.Type)
+	return nil
+}
+
+// SendBookingNotification posts the booking. Webhooks have no message timestamp, so results are never threaded.
+func (w *Notifier) SendBookingNotification(match *playtomic.PadelMatch, slackUserIDs map[string]string, guestIDs map[string]bool, dryRun bool) (string, error) {
+	var guests []string
+	for playerID, isGuest := range guestIDs {
+		if isGuest {
+			guests = append(guests, playerID)
+		}
+	}
+	sort.Strings(guests)
+	return "", w.send(Payload{Type: notifier.NotificationBooking, Match: match, SlackUserIDs: slackUserIDs, GuestIDs: guests}, dryRun)
+}
+
+func (w *Notifier) SendResultNotification(match *playtomic.PadelMatch, threadTS string, dryRun bool) error {
+	return w.send(Payload{Type: notifier.NotificationResult, Match: match}, dryRun)
+}
+
+func (w *Notifier) SendResultCorrection(match *playtomic.PadelMatch, threadTS string, dryRun bool) error {
+	return w.send(Payload{Type: notificationResultCorrected, Match: match}, dryRun)
+}
+
+func (w *Notifier) SendDirectMessage(slackUserID, text string, dryRun bool) error {
+	return w.send(Payload{Type: notificationDirectMessage, SlackUserID: slackUserID, Text: text}, dryRun)
+}
+
+func (w *Notifier) SendBallBringerReminder(slackUserID string, match *playtomic.PadelMatch, dryRun bool) error {
+	return w.send(Payload{Type: notificationBallBringerDM, SlackUserID: slackUserID, Match: match}, dryRun)
+}
+
+func (w *Notifier) SendIncompleteLineupReminder(slackUserID string, match *playtomic.PadelMatch, players, required int, dryRun bool) error {
+	return w.send(Payload{Type: notificationLineupDM, SlackUserID: slackUserID, Match: match, LineupSize: players, LineupNeeded: required}, dryRun)
+}
+
+// PublishHomeView posts the home tab contents; rendering them is up to the receiver.
+func (w *Notifier) PublishHomeView(slackUserID string, view notifier.HomeView, dryRun bool) error {
+	payload := Payload{Type: notificationHomeView, SlackUserID: slackUserID, PlayerStats: view.Stats, Match: view.NextMatch, Form: view.Form}
+	if view.Player != nil {
+		payload.PlayerID = view.Player.ID
+	}
+	return w.send(payload, dryRun)
+}
+
+func (w *Notifier) SendNewPlayersNotification(players []club.PlayerInfo, dryRun bool) error {
+	return w.send(Payload{Type: notifier.NotificationNewPlayers, Players: players}, dryRun)
+}
+
+func (w *Notifier) SendBookingDigest(matches []*playtomic.PadelMatch, dryRun bool) error {
+	return w.send(Payload{Type: notificationBookingDigest, Matches: matches}, dryRun)
+}
+
+func (w *Notifier) SendWeeklyRecap(recap club.WeeklyRecap, dryRun bool) error {
+	return w.send(Payload{Type: notifier.NotificationWeeklyRecap, Recap: &recap}, dryRun)
+}
+
+func (w *Notifier) SendLeaderboard(stats []club.PlayerStats, dryRun bool) error {
+	return w.send(Payload{Type: notifier.NotificationLeaderboard, Stats: stats}, dryRun)
+}
+
+func (w *Notifier) SendLevelLeaderboard(players []club.PlayerInfo, dryRun bool) error {
+	return w.send(Payload{Type: notifier.NotificationLevelLeaderboard, Players: players}, dryRun)
+}
+
+func (w *Notifier) SendPlayerStats(stats *club.PlayerStats, query string, dryRun bool) error {
+	return w.send(Payload{Type: notifier.NotificationPlayerStats, PlayerStats: stats, Query: query}, dryRun)
+}
+
+func (w *Notifier) SendPlayerNotFound(query string, dryRun bool) error {
+	return w.send(Payload{Type: notificationPlayerNotFound, Query: query}, dryRun)
+}
+
+// The format functions return the webhook payload. Slack slash commands need a Slack notifier,
+// so these are only useful to callers that serialize the response themselves.
+
+func (w *Notifier) FormatLeaderboardResponse(stats []club.PlayerStats) (any, error) {
+	return Payload{Type: notifier.NotificationLeaderboard, Stats: stats}, nil
+}
+
+func (w *Notifier) FormatLevelLeaderboardResponse(players []club.PlayerInfo) (any, error) {
+	return Payload{Type: notifier.NotificationLevelLeaderboard, Players: players}, nil
+}
+
+func (w *Notifier) FormatPlayerStatsResponse(stats *club.PlayerStats, query string) (any, error) {
+	return Payload{Type: notifier.NotificationPlayerStats, PlayerStats: stats, Query: query}, nil
+}
+
+func (w *Notifier) FormatPlayerNotFoundResponse(query string) (any, error) {
+	return Payload{Type: notificationPlayerNotFound, Query: query}, nil
+}
+
+func (w *Notifier) FormatBallBringerResponse(players []club.PlayerInfo) (any, error) {
+	return Payload{Type: notificationBallBringers, Players: players}, nil
+}
+
+func (w *Notifier) FormatRosterResponse(players []club.PlayerInfo, page int) (any, error) {
+	return Payload{Type: notificationRoster, Players: players, Page: page}, nil
+}
+
+func (w *Notifier) FormatUnmappedPlayersResponse(players []club.PlayerInfo) (any, error) {
+	return Payload{Type: notificationUnmapped, Players: players}, nil
+}
+
+func (w *Notifier) FormatFormResponse(playerName string, form []bool) (any, error) {
+	return Payload{Type: notificationForm, Query: playerName, Form: form}, nil
+}
+
+func (w *Notifier) FormatNextMatchResponse(match *playtomic.PadelMatch, playerID string) (any, error) {
+	return Payload{Type: notificationNextMatch, Match: match, PlayerID: playerID}, nil
+}
+
+func (w *Notifier) FormatLeaderboardDiffResponse(before, after string, deltas []club.PlayerStatsDelta) (any, error) {
+	return Payload{Type: notificationLeaderboardDiff, Periods: []string{before, after}, Deltas: deltas}, nil
+}
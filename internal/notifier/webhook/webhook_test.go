@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mauv0809/ideal-tribble/internal/playtomic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, status int) (*httptest.Server, *[]Payload) {
+	t.Helper()
+	var received []Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var payload Payload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		received = append(received, payload)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func TestSendBookingNotification(t *testing.T) {
+	server, received := newTestServer(t, http.StatusOK)
+	notifier := NewNotifier(server.URL)
+	match := &playtomic.PadelMatch{MatchID: "m1", ResourceName: "Court 1", BallBringerName: "Player A"}
+
+	ts, err := notifier.SendBookingNotification(match, map[string]string{"p1": "U123"}, map[string]bool{"g1": true}, false)
+	require.NoError(t, err)
+	assert.Empty(t, ts)
+
+	require.Len(t, *received, 1)
+	payload := (*received)[0]
+	assert.Equal(t, "booking", payload.Type)
+	require.NotNil(t, payload.Match)
+	assert.Equal(t, "m1", payload.Match.MatchID)
+	assert.Equal(t, "Court 1", payload.Match.ResourceName)
+	assert.Equal(t, "Player A", payload.Match.BallBringerName)
+	assert.Equal(t, map[string]string{"p1": "U123"}, payload.SlackUserIDs)
+	assert.Equal(t, []string{"g1"}, payload.GuestIDs)
+}
+
+func TestSendResultNotification(t *testing.T) {
+	server, received := newTestServer(t, http.StatusNoContent)
+	notifier := NewNotifier(server.URL)
+	match := &playtomic.PadelMatch{
+		MatchID: "m1",
+		Teams:   []playtomic.Team{{ID: "t1", TeamResult: "WON"}, {ID: "t2", TeamResult: "LOST"}},
+		Results: []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 4}}},
+	}
+
+	require.NoError(t, notifier.SendResultNotification(match, "ts123", false))
+
+	require.Len(t, *received, 1)
+	payload := (*received)[0]
+	assert.Equal(t, "result", payload.Type)
+	require.NotNil(t, payload.Match)
+	assert.Equal(t, "WON", payload.Match.Teams[0].TeamResult)
+	assert.Equal(t, 6, payload.Match.Results[0].Scores["t1"])
+}
+
+func TestSend_Errors(t *testing.T) {
+	t.Run("non-2xx status", func(t *testing.T) {
+		server, _ := newTestServer(t, http.StatusInternalServerError)
+		err := NewNotifier(server.URL).SendResultNotification(&playtomic.PadelMatch{MatchID: "m1"}, "", false)
+		assert.Error(t, err)
+	})
+
+	t.Run("dry run does not post", func(t *testing.T) {
+		server, received := newTestServer(t, http.StatusOK)
+		_, err := NewNotifier(server.URL).SendBookingNotification(&playtomic.PadelMatch{MatchID: "m1"}, nil, nil, true)
+		require.NoError(t, err)
+		assert.Empty(t, *received)
+	})
+}
@@ -0,0 +1,109 @@
+package playtomic
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mauv0809/ideal-tribble/internal/clock"
+)
+
+// matchCacheSize bounds the number of match details kept in memory.
+const matchCacheSize = 500
+
+type matchCacheEntry struct {
+	match   PadelMatch
+	expires time.Time
+}
+
+// matchCache is a small in-memory cache of match details, so a match seen in overlapping fetch windows
+// is only requested from Playtomic once per TTL. It is safe for concurrent use.
+type matchCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	clock   clock.Clock
+	entries map[string]matchCacheEntry
+}
+
+func newMatchCache(ttl time.Duration, maxSize int, clk clock.Clock) *matchCache {
+	return &matchCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		clock:   clk,
+		entries: make(map[string]matchCacheEntry),
+	}
+}
+
+func (c *matchCache) get(matchID string) (PadelMatch, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[matchID]
+	if !ok {
+		return PadelMatch{}, false
+	}
+	if !c.clock.Now().Before(entry.expires) {
+		delete(c.entries, matchID)
+		return PadelMatch{}, false
+	}
+	return cloneMatch(entry.match), true
+}
+
+func (c *matchCache) put(matchID string, match PadelMatch) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	if _, ok := c.entries[matchID]; !ok && len(c.entries) >= c.maxSize {
+		c.evictLocked(now)
+	}
+	c.entries[matchID] = matchCacheEntry{match: cloneMatch(match), expires: now.Add(c.ttl)}
+}
+
+// cloneMatch copies the teams and results of a match, so callers changing a match they got from or gave to the
+// cache don't change the cached copy.
+func cloneMatch(match PadelMatch) PadelMatch {
+	if match.Teams != nil {
+		teams := make([]Team, len(match.Teams))
+		for i, team := range match.Teams {
+			if team.Players != nil {
+				team.Players = append([]Player(nil), team.Players...)
+			}
+			teams[i] = team
+		}
+		match.Teams = teams
+	}
+	if match.Results != nil {
+		results := make([]SetResult, len(match.Results))
+		for i, result := range match.Results {
+			if result.Scores != nil {
+				scores := make(map[string]int, len(result.Scores))
+				for teamID, score := range result.Scores {
+					scores[teamID] = score
+				}
+				result.Scores = scores
+			}
+			results[i] = result
+		}
+		match.Results = results
+	}
+	return match
+}
+
+// evictLocked drops expired entries, or the entry closest to expiry if none have expired.
+func (c *matchCache) evictLocked(now time.Time) {
+	var oldestID string
+	var oldest time.Time
+	for id, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, id)
+			continue
+		}
+		if oldestID == "" || entry.expires.Before(oldest) {
+			oldestID, oldest = id, entry.expires
+		}
+	}
+	if len(c.entries) >= c.maxSize && oldestID != "" {
+		delete(c.entries, oldestID)
+	}
+}
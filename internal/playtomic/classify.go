@@ -0,0 +1,30 @@
+package playtomic
+
+// ClassifyMatch determines whether a match is singles or doubles, and whether it is competitive or
+// a practice match. The format is taken from the largest team, so a doubles match where one team is
+// still missing players is classified as doubles. rawType is Playtomic's competition_mode.
+// An empty value is returned for anything that cannot be classified.
+func ClassifyMatch(teams []Team, rawType string) (MatchTypeEnum, MatchType) {
+	var matchTypeEnum MatchTypeEnum
+	largestTeam := 0
+	for _, team := range teams {
+		if len(team.Players) > largestTeam {
+			largestTeam = len(team.Players)
+		}
+	}
+	switch largestTeam {
+	case 1:
+		matchTypeEnum = MatchTypeEnumSingles
+	case 2:
+		matchTypeEnum = MatchTypeEnumDoubles
+	}
+
+	var matchType MatchType
+	switch rawType {
+	case string(MatchTypeCompetition):
+		matchType = MatchTypeCompetition
+	case string(MatchTypePractice):
+		matchType = MatchTypePractice
+	}
+	return matchTypeEnum, matchType
+}
@@ -0,0 +1,44 @@
+package playtomic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func teamOf(ids ...string) Team {
+	team := Team{ID: "team-" + ids[0]}
+	for _, id := range ids {
+		team.Players = append(team.Players, Player{UserID: id})
+	}
+	return team
+}
+
+func TestClassifyMatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		teams        []Team
+		rawType      string
+		expectedEnum MatchTypeEnum
+		expectedType MatchType
+	}{
+		{"two players", []Team{teamOf("p1"), teamOf("p2")}, "COMPETITIVE", MatchTypeEnumSingles, MatchTypeCompetition},
+		{"four players", []Team{teamOf("p1", "p2"), teamOf("p3", "p4")}, "FRIENDLY", MatchTypeEnumDoubles, MatchTypePractice},
+		{"three players", []Team{teamOf("p1", "p2"), teamOf("p3")}, "FRIENDLY", MatchTypeEnumDoubles, MatchTypePractice},
+		{"doubles with an empty team", []Team{teamOf("p1", "p2"), {ID: "team-2"}}, "COMPETITIVE", MatchTypeEnumDoubles, MatchTypeCompetition},
+		{"single player", []Team{teamOf("p1"), {ID: "team-2"}}, "FRIENDLY", MatchTypeEnumSingles, MatchTypePractice},
+		{"no players", []Team{{ID: "team-1"}, {ID: "team-2"}}, "FRIENDLY", "", MatchTypePractice},
+		{"no teams", nil, "COMPETITIVE", "", MatchTypeCompetition},
+		{"oversized team", []Team{teamOf("p1", "p2", "p3"), teamOf("p4")}, "FRIENDLY", "", MatchTypePractice},
+		{"unknown raw type", []Team{teamOf("p1"), teamOf("p2")}, "TOURNAMENT", MatchTypeEnumSingles, ""},
+		{"empty raw type", []Team{teamOf("p1", "p2"), teamOf("p3", "p4")}, "", MatchTypeEnumDoubles, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchTypeEnum, matchType := ClassifyMatch(tt.teams, tt.rawType)
+			assert.Equal(t, tt.expectedEnum, matchTypeEnum)
+			assert.Equal(t, tt.expectedType, matchType)
+		})
+	}
+}
@@ -3,38 +3,64 @@ package playtomic
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
-	"github.com/rafa-garcia/go-playtomic-api/client"
-	"github.com/rafa-garcia/go-playtomic-api/models"
+	"github.com/mauv0809/ideal-tribble/internal/clock"
+	"github.com/mauv0809/ideal-tribble/internal/metrics"
 )
 
 // APIClient is a custom Playtomic API client that implements the PlaytomicClient interface.
 type APIClient struct {
-	httpClient *http.Client
-	apiClient  *client.Client
-	BaseURL    string
+	httpClient   *http.Client
+	BaseURL      string
+	cache        *matchCache     // nil when caching is disabled
+	metrics      metrics.Metrics // nil disables request metrics
+	retries      int             // how often a failed request is retried
+	retryBackoff time.Duration   // wait before the first retry, doubled for every further retry
 }
 
-// NewClient creates a new custom Playtomic client.
-func NewClient() PlaytomicClient {
-	return &APIClient{
-		httpClient: &http.Client{Timeout: 10 * time.Second},
-		apiClient: client.NewClient(
-			client.WithTimeout(10*time.Second),
-			client.WithRetries(3),
-		),
-		BaseURL: "https://api.playtomic.io",
+const (
+	// requestRetries is how often NewClient's client retries a request that failed with a network error, a 5xx or
+	// a 429, as the go-playtomic-api client it replaced did.
+	requestRetries = 3
+	// requestRetryBackoff is the wait before the first retry.
+	requestRetryBackoff = 500 * time.Millisecond
+)
+
+// NewClient creates a new custom Playtomic client. Match details are cached for matchCacheTTL;
+// a zero TTL disables the cache.
+func NewClient(matchCacheTTL time.Duration, metrics metrics.Metrics) PlaytomicClient {
+	c := &APIClient{
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		BaseURL:      "https://api.playtomic.io",
+		metrics:      metrics,
+		retries:      requestRetries,
+		retryBackoff: requestRetryBackoff,
+	}
+	if matchCacheTTL > 0 {
+		c.cache = newMatchCache(matchCacheTTL, matchCacheSize, clock.New())
 	}
+	return c
 }
 
+// ErrIncompleteMatch is returned when Playtomic omits fields a match cannot be stored without.
+var ErrIncompleteMatch = errors.New("incomplete match")
+
+// defaultMatchDuration is assumed when Playtomic leaves out a match's end time.
+const defaultMatchDuration = 90 * time.Minute
+
 // Ensure APIClient implements the PlaytomicClient interface.
 var _ PlaytomicClient = (*APIClient)(nil)
 
+// GetMatches searches for matches page by page until a short page signals the end of the results.
 func (c *APIClient) GetMatches(params *SearchMatchesParams) ([]MatchSummary, error) {
 	const pageSize = 300
 	var (
@@ -43,18 +69,7 @@ func (c *APIClient) GetMatches(params *SearchMatchesParams) ([]MatchSummary, err
 	)
 
 	for {
-		externalParams := &models.SearchMatchesParams{
-			SportID:       params.SportID,
-			HasPlayers:    params.HasPlayers,
-			Sort:          params.Sort,
-			TenantIDs:     params.TenantIDs,
-			FromStartDate: params.FromStartDate,
-			Size:          pageSize,
-			Page:          page,
-		}
-
-		log.Debug("Fetching matches from Playtomic API", "params", externalParams)
-		matches, err := c.apiClient.GetMatches(context.Background(), externalParams)
+		matches, err := c.searchMatches(params, pageSize, page)
 		if err != nil {
 			return nil, fmt.Errorf("error fetching matches from playtomic api: %w", err)
 		}
@@ -78,25 +93,157 @@ func (c *APIClient) GetMatches(params *SearchMatchesParams) ([]MatchSummary, err
 	return allMatches, nil
 }
 
-// GetSpecificMatch fetches a specific match by its ID.
-func (c *APIClient) GetSpecificMatch(matchID string) (PadelMatch, error) {
-	url := fmt.Sprintf("%s/v1/matches/%s", c.BaseURL, matchID)
+// searchMatchesQuery builds the query string for a single page of a match search.
+// Optional bounds are left out entirely when empty so the API applies its defaults.
+func searchMatchesQuery(params *SearchMatchesParams, size, page int) url.Values {
+	query := url.Values{}
+	query.Set("sport_id", params.SportID)
+	query.Set("has_players", strconv.FormatBool(params.HasPlayers))
+	if params.Sort != "" {
+		query.Set("sort", params.Sort)
+	}
+	if len(params.TenantIDs) > 0 {
+		query.Set("tenant_id", strings.Join(params.TenantIDs, ","))
+	}
+	if params.FromStartDate != "" {
+		query.Set("from_start_date", params.FromStartDate)
+	}
+	if params.ToStartDate != "" {
+		query.Set("to_start_date", params.ToStartDate)
+	}
+	query.Set("size", strconv.Itoa(size))
+	query.Set("page", strconv.Itoa(page))
+	return query
+}
+
+func (c *APIClient) searchMatches(params *SearchMatchesParams, size, page int) ([]playtomicMatchSummaryResponse, error) {
+	url := fmt.Sprintf("%s/v1/matches?%s", c.BaseURL, searchMatchesQuery(params, size, page).Encode())
 
 	req, err := http.NewRequestWithContext(context.Background(), "GET", url, nil)
 	if err != nil {
-		return PadelMatch{}, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	setDefaultHeaders(req)
+	log.Debug("Fetching matches from Playtomic API", "url", url)
+
+	resp, err := c.do(req, "get_matches")
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Error("Received non-OK HTTP status from Playtomic API", "status", resp.StatusCode, "body", string(body))
+		return nil, fmt.Errorf("received non-OK HTTP status: %d", resp.StatusCode)
+	}
+
+	var matches []playtomicMatchSummaryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&matches); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return matches, nil
+}
+
+// do executes a request against the Playtomic API. Requests failing with a network error, a 5xx or a 429 are
+// retried with backoff. Only requests without a body may be passed, so they can be sent again.
+func (c *APIClient) do(req *http.Request, endpoint string) (*http.Response, error) {
+	backoff := c.retryBackoff
+	for attempt := 1; ; attempt++ {
+		resp, err := c.doOnce(req, endpoint)
+		if attempt > c.retries || !retryable(resp, err) {
+			return resp, err
+		}
+		if err == nil {
+			resp.Body.Close()
+			err = fmt.Errorf("received HTTP status %d", resp.StatusCode)
+		}
+		log.Warn("Playtomic request failed, retrying", "endpoint", endpoint, "attempt", attempt, "backoff", backoff, "error", err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// retryable reports whether a request that ended with resp and err is worth sending again.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// doOnce executes a request once, recording its latency and any error under the given endpoint name.
+func (c *APIClient) doOnce(req *http.Request, endpoint string) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if c.metrics == nil {
+		return resp, err
+	}
+	c.metrics.ObservePlaytomicRequestDuration(endpoint, time.Since(start).Seconds())
+	switch {
+	case err != nil:
+		c.metrics.IncPlaytomicRequestErrors(endpoint, "network")
+	case resp.StatusCode >= 500:
+		c.metrics.IncPlaytomicRequestErrors(endpoint, "5xx")
+	case resp.StatusCode >= 400:
+		c.metrics.IncPlaytomicRequestErrors(endpoint, "4xx")
 	}
+	return resp, err
+}
 
+// setDefaultHeaders sets the headers the Playtomic API expects on every request.
+func setDefaultHeaders(req *http.Request) {
 	req.Header.Set("Accept", "*/*")
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Language", "en-AU,en;q=0.9")
 	req.Header.Set("User-Agent", "PlaytomicGoClient/1.0")
+}
+
+// GetSpecificMatch fetches a specific match by its ID, serving it from the cache when possible.
+func (c *APIClient) GetSpecificMatch(matchID string) (PadelMatch, error) {
+	if c.cache != nil {
+		if match, ok := c.cache.get(matchID); ok {
+			log.Debug("Serving match from cache", "matchID", matchID)
+			return match, nil
+		}
+	}
+	match, err := c.fetchSpecificMatch(matchID)
+	if err != nil {
+		return PadelMatch{}, err
+	}
+	if c.cache != nil {
+		c.cache.put(matchID, match)
+	}
+	return match, nil
+}
+
+// RefreshSpecificMatch fetches a specific match by its ID from Playtomic, bypassing the cache, and caches the fresh copy.
+func (c *APIClient) RefreshSpecificMatch(matchID string) (PadelMatch, error) {
+	match, err := c.fetchSpecificMatch(matchID)
+	if err != nil {
+		return PadelMatch{}, err
+	}
+	if c.cache != nil {
+		c.cache.put(matchID, match)
+	}
+	return match, nil
+}
+
+func (c *APIClient) fetchSpecificMatch(matchID string) (PadelMatch, error) {
+	url := fmt.Sprintf("%s/v1/matches/%s", c.BaseURL, matchID)
+
+	req, err := http.NewRequestWithContext(context.Background(), "GET", url, nil)
+	if err != nil {
+		return PadelMatch{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	setDefaultHeaders(req)
 	log.Debug("Requesting specific match from Playtomic API", "url", url)
 	log.Debug(fmt.Sprintf(
 		`curl -X GET '%s' -H 'Accept: */*' -H 'Content-Type: application/json' -H 'Accept-Language: en-AU,en;q=0.9' -H 'User-Agent: PlaytomicGoClient/1.0'`,
 		url,
 	))
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req, "get_specific_match")
 	if err != nil {
 		return PadelMatch{}, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -120,20 +267,30 @@ func (c *APIClient) GetSpecificMatch(matchID string) (PadelMatch, error) {
 
 	const layout = "2006-01-02T15:04:05"
 
+	if matchResponse.StartDate == "" {
+		return PadelMatch{}, fmt.Errorf("%w: missing start time", ErrIncompleteMatch)
+	}
+	if matchResponse.OwnerID == "" {
+		return PadelMatch{}, fmt.Errorf("%w: missing owner", ErrIncompleteMatch)
+	}
 	startTime, err := time.Parse(layout, matchResponse.StartDate)
 	if err != nil {
 		return PadelMatch{}, fmt.Errorf("failed to parse start time: %w", err)
 	}
 	endTime, err := time.Parse(layout, matchResponse.EndDate)
 	if err != nil {
-		return PadelMatch{}, fmt.Errorf("failed to parse end time: %w", err)
+		log.Warn("Missing or invalid end time from Playtomic API, assuming default duration", "end", matchResponse.EndDate, "matchID", matchID)
+		endTime = startTime.Add(defaultMatchDuration)
 	}
-	createdAtTime, err := time.Parse(layout, matchResponse.CreatedAt)
-	if err != nil {
-		return PadelMatch{}, fmt.Errorf("failed to parse created at time: %w", err)
+	var createdAt int64
+	if createdAtTime, err := time.Parse(layout, matchResponse.CreatedAt); err == nil {
+		createdAt = createdAtTime.Local().Unix()
+	} else {
+		log.Warn("Missing or invalid created at time from Playtomic API", "createdAt", matchResponse.CreatedAt, "matchID", matchID)
 	}
 
 	var teams []Team
+	playerCount := 0
 	for _, responseTeam := range matchResponse.Teams {
 		t := Team{
 			ID: responseTeam.TeamID,
@@ -142,6 +299,11 @@ func (c *APIClient) GetSpecificMatch(matchID string) (PadelMatch, error) {
 			t.TeamResult = *responseTeam.TeamResult
 		}
 		for _, responsePlayer := range responseTeam.Players {
+			if responsePlayer.UserID == "" {
+				log.Warn("Dropping player without user ID from Playtomic API", "name", responsePlayer.Name, "matchID", matchID)
+				continue
+			}
+			playerCount++
 			t.Players = append(t.Players, Player{
 				UserID: responsePlayer.UserID,
 				Name:   responsePlayer.Name,
@@ -157,6 +319,9 @@ func (c *APIClient) GetSpecificMatch(matchID string) (PadelMatch, error) {
 		}
 		teams = append(teams, t)
 	}
+	if playerCount == 0 {
+		return PadelMatch{}, fmt.Errorf("%w: no players in teams", ErrIncompleteMatch)
+	}
 	ownerName := ""
 	for _, team := range teams {
 		for _, player := range team.Players {
@@ -179,6 +344,7 @@ func (c *APIClient) GetSpecificMatch(matchID string) (PadelMatch, error) {
 		for _, score := range responseResult.Scores {
 			set.Scores[score.TeamID] = score.Score
 		}
+		set.Tiebreak = isTiebreak(set)
 		results = append(results, set)
 	}
 
@@ -222,22 +388,20 @@ func (c *APIClient) GetSpecificMatch(matchID string) (PadelMatch, error) {
 		log.Warn("Unknown results status received from Playtomic API", "status", matchResponse.ResultsStatus, "matchID", matchID)
 	}
 
-	var matchType MatchType
-	switch matchResponse.MatchType {
-	case string(MatchTypeCompetition):
-		matchType = MatchTypeCompetition
-	case string(MatchTypePractice):
-		matchType = MatchTypePractice
-	default:
+	matchTypeEnum, matchType := ClassifyMatch(teams, matchResponse.MatchType)
+	if matchType == "" {
 		log.Warn("Unknown match type received from Playtomic API", "type", matchResponse.MatchType, "matchID", matchID)
 	}
+	if matchTypeEnum == "" {
+		log.Warn("Could not classify match as singles or doubles", "teams", len(teams), "matchID", matchID)
+	}
 	padelMatch := PadelMatch{
 		MatchID:       matchID,
 		OwnerID:       matchResponse.OwnerID,
 		OwnerName:     ownerName,
 		Start:         startTime.Local().Unix(),
 		End:           endTime.Local().Unix(),
-		CreatedAt:     createdAtTime.Local().Unix(),
+		CreatedAt:     createdAt,
 		Teams:         teams,
 		GameStatus:    gameStatus,
 		Status:        matchResponse.Status,
@@ -249,12 +413,32 @@ func (c *APIClient) GetSpecificMatch(matchID string) (PadelMatch, error) {
 			ID:   matchResponse.Tenant.ID,
 			Name: matchResponse.Tenant.Name,
 		},
-		MatchType: matchType,
+		MatchType:     matchType,
+		MatchTypeEnum: matchTypeEnum,
 	}
 
 	if matchResponse.MerchantAccessCode != nil {
 		padelMatch.AccessCode = matchResponse.MerchantAccessCode.Code
 	}
+	padelMatch.FillTeamResults()
 	log.Debug("Match", "match", padelMatch)
 	return padelMatch, nil
 }
+
+// maxSetGames is the most games a team can win in a regular set (7-5 or 7-6).
+const maxSetGames = 7
+
+// isTiebreak reports whether a set is a match tiebreak. Playtomic does not flag these, so it is detected from
+// the set name or from a winning score no regular set can reach.
+func isTiebreak(set SetResult) bool {
+	name := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(set.Name))
+	if strings.Contains(name, "tiebreak") {
+		return true
+	}
+	for _, score := range set.Scores {
+		if score > maxSetGames {
+			return true
+		}
+	}
+	return false
+}
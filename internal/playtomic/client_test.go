@@ -2,11 +2,16 @@ package playtomic
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/rafa-garcia/go-playtomic-api/client"
+	"github.com/mauv0809/ideal-tribble/internal/clock"
+	"github.com/mauv0809/ideal-tribble/internal/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -52,7 +57,6 @@ func TestGetSpecificMatch(t *testing.T) {
 	// Create our APIClient and point it to the mock server
 	client := APIClient{
 		httpClient: server.Client(),
-		apiClient:  client.NewClient(), // Dummy client, not used in this specific test
 		BaseURL:    server.URL,
 	}
 
@@ -71,3 +75,333 @@ func TestGetSpecificMatch(t *testing.T) {
 	assert.Len(t, match.Teams[0].Players, 2)
 	assert.Equal(t, "Player A", match.Teams[0].Players[0].Name)
 }
+
+func TestGetMatches(t *testing.T) {
+	t.Run("includes to_start_date when set", func(t *testing.T) {
+		var query url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/matches", r.URL.Path)
+			query = r.URL.Query()
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintln(w, `[{"match_id": "match-1", "owner_id": "user-1"}, {"match_id": "match-2"}]`)
+		}))
+		defer server.Close()
+
+		client := APIClient{httpClient: server.Client(), BaseURL: server.URL}
+		matches, err := client.GetMatches(&SearchMatchesParams{
+			SportID:       "PADEL",
+			HasPlayers:    true,
+			Sort:          "start_date,ASC",
+			TenantIDs:     []string{"tenant-abc"},
+			FromStartDate: "2024-03-01T00:00:00",
+			ToStartDate:   "2024-03-10T23:59:59",
+		})
+
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+		assert.Equal(t, "match-1", matches[0].MatchID)
+		require.NotNil(t, matches[0].OwnerID)
+		assert.Equal(t, "user-1", *matches[0].OwnerID)
+		assert.Nil(t, matches[1].OwnerID)
+
+		assert.Equal(t, "PADEL", query.Get("sport_id"))
+		assert.Equal(t, "true", query.Get("has_players"))
+		assert.Equal(t, "tenant-abc", query.Get("tenant_id"))
+		assert.Equal(t, "2024-03-01T00:00:00", query.Get("from_start_date"))
+		assert.Equal(t, "2024-03-10T23:59:59", query.Get("to_start_date"))
+		assert.Equal(t, "0", query.Get("page"))
+	})
+
+	t.Run("omits to_start_date when empty", func(t *testing.T) {
+		var query url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query()
+			fmt.Fprintln(w, `[]`)
+		}))
+		defer server.Close()
+
+		client := APIClient{httpClient: server.Client(), BaseURL: server.URL}
+		matches, err := client.GetMatches(&SearchMatchesParams{
+			SportID:       "PADEL",
+			FromStartDate: "2024-03-01T00:00:00",
+		})
+
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+		assert.Equal(t, "2024-03-01T00:00:00", query.Get("from_start_date"))
+		assert.False(t, query.Has("to_start_date"))
+	})
+}
+
+func TestGetSpecificMatch_Cache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintln(w, `{
+			"owner_id": "user-123",
+			"start_date": "2025-07-09T18:00:00",
+			"end_date": "2025-07-09T19:30:00",
+			"created_at": "2025-07-08T10:00:00",
+			"resource_name": "Court 1",
+			"teams": [{ "team_id": "0", "players": [{ "user_id": "user-123" }] }]
+		}`)
+	}))
+	defer server.Close()
+
+	clk := clock.NewMock(time.Date(2025, 7, 9, 12, 0, 0, 0, time.UTC))
+	client := APIClient{
+		httpClient: server.Client(),
+		BaseURL:    server.URL,
+		cache:      newMatchCache(time.Minute, 10, clk),
+	}
+
+	first, err := client.GetSpecificMatch("match-abc")
+	require.NoError(t, err)
+	second, err := client.GetSpecificMatch("match-abc")
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests, "Second call within the TTL should be served from the cache")
+	assert.Equal(t, first.ResourceName, second.ResourceName)
+
+	clk.Advance(time.Minute)
+	_, err = client.GetSpecificMatch("match-abc")
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests, "Expired entries should be refetched")
+
+	t.Run("disabled cache always fetches", func(t *testing.T) {
+		requests = 0
+		uncached := APIClient{httpClient: server.Client(), BaseURL: server.URL}
+		for i := 0; i < 2; i++ {
+			_, err := uncached.GetSpecificMatch("match-abc")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, requests)
+	})
+}
+
+func TestRefreshSpecificMatch(t *testing.T) {
+	resourceName := "Court 1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{
+			"owner_id": "user-123",
+			"start_date": "2025-07-09T18:00:00",
+			"end_date": "2025-07-09T19:30:00",
+			"created_at": "2025-07-08T10:00:00",
+			"resource_name": %q,
+			"teams": [{ "team_id": "0", "players": [{ "user_id": "user-123" }] }]
+		}`, resourceName)
+	}))
+	defer server.Close()
+
+	client := APIClient{
+		httpClient: server.Client(),
+		BaseURL:    server.URL,
+		cache:      newMatchCache(time.Hour, 10, clock.NewMock(time.Date(2025, 7, 9, 12, 0, 0, 0, time.UTC))),
+	}
+	_, err := client.GetSpecificMatch("match-abc")
+	require.NoError(t, err)
+
+	resourceName = "Court 2"
+	refreshed, err := client.RefreshSpecificMatch("match-abc")
+	require.NoError(t, err)
+	assert.Equal(t, "Court 2", refreshed.ResourceName, "A refresh should not be served from the cache")
+
+	cached, err := client.GetSpecificMatch("match-abc")
+	require.NoError(t, err)
+	assert.Equal(t, "Court 2", cached.ResourceName, "The refreshed match should replace the cached copy")
+}
+
+// roundTripFunc lets a plain function stand in for the HTTP transport.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestGetSpecificMatch_PartialFields(t *testing.T) {
+	serve := func(body string) *APIClient {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, body)
+		}))
+		t.Cleanup(server.Close)
+		return &APIClient{httpClient: server.Client(), BaseURL: server.URL}
+	}
+
+	t.Run("sanitizes optional fields", func(t *testing.T) {
+		client := serve(`{
+			"owner_id": "user-123",
+			"start_date": "2025-07-09T18:00:00",
+			"end_date": null,
+			"tenant": null,
+			"teams": [
+				{ "team_id": "0", "players": [{ "user_id": "user-123", "name": "Player A", "level_value": null }, { "user_id": null, "name": "Ghost" }] },
+				{ "team_id": "1", "players": null }
+			],
+			"results": null
+		}`)
+
+		match, err := client.GetSpecificMatch("match-abc")
+		require.NoError(t, err)
+		assert.Equal(t, match.Start+int64(defaultMatchDuration.Seconds()), match.End)
+		assert.Zero(t, match.CreatedAt)
+		require.Len(t, match.Teams, 2)
+		require.Len(t, match.Teams[0].Players, 1, "players without a user ID are dropped")
+		assert.Equal(t, "user-123", match.Teams[0].Players[0].UserID)
+		assert.Empty(t, match.Teams[1].Players)
+		assert.Equal(t, "Player A", match.OwnerName)
+		assert.Empty(t, match.Tenant.ID)
+	})
+
+	for name, body := range map[string]string{
+		"missing start time": `{"owner_id": "user-123", "teams": [{"team_id": "0", "players": [{"user_id": "user-123"}]}]}`,
+		"missing owner":      `{"start_date": "2025-07-09T18:00:00", "teams": [{"team_id": "0", "players": [{"user_id": "user-123"}]}]}`,
+		"missing teams":      `{"owner_id": "user-123", "start_date": "2025-07-09T18:00:00", "teams": null}`,
+		"no usable players":  `{"owner_id": "user-123", "start_date": "2025-07-09T18:00:00", "teams": [{"team_id": "0", "players": [{"name": "Ghost"}]}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := serve(body).GetSpecificMatch("match-abc")
+			assert.ErrorIs(t, err, ErrIncompleteMatch)
+		})
+	}
+}
+
+func TestRequestMetrics(t *testing.T) {
+	status := http.StatusOK
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Body:       io.NopCloser(strings.NewReader(`{"owner_id": "user-123", "start_date": "2025-07-09T18:00:00", "end_date": "2025-07-09T19:30:00", "created_at": "2025-07-08T10:00:00", "teams": [{"team_id": "1", "players": [{"user_id": "user-123"}]}]}`)),
+			Header:     make(http.Header),
+			Request:    r,
+		}, nil
+	})
+	metricsMock := metrics.NewMock()
+	client := APIClient{
+		httpClient: &http.Client{Transport: transport},
+		BaseURL:    "https://playtomic.test",
+		metrics:    metricsMock,
+	}
+
+	_, err := client.GetSpecificMatch("match-abc")
+	require.NoError(t, err)
+	assert.Len(t, metricsMock.PlaytomicRequestDurations("get_specific_match"), 1)
+	assert.Equal(t, 0, metricsMock.PlaytomicRequestErrors("get_specific_match", "5xx"))
+
+	status = http.StatusInternalServerError
+	_, err = client.GetSpecificMatch("match-abc")
+	require.Error(t, err)
+	assert.Len(t, metricsMock.PlaytomicRequestDurations("get_specific_match"), 2)
+	assert.Equal(t, 1, metricsMock.PlaytomicRequestErrors("get_specific_match", "5xx"))
+
+	_, err = client.GetMatches(&SearchMatchesParams{})
+	require.Error(t, err)
+	assert.Len(t, metricsMock.PlaytomicRequestDurations("get_matches"), 1)
+	assert.Equal(t, 1, metricsMock.PlaytomicRequestErrors("get_matches", "5xx"))
+}
+
+func TestRequestRetries(t *testing.T) {
+	var statuses []int
+	requests := 0
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		status := statuses[requests]
+		requests++
+		return &http.Response{
+			StatusCode: status,
+			Body:       io.NopCloser(strings.NewReader(`{"owner_id": "user-123", "start_date": "2025-07-09T18:00:00", "end_date": "2025-07-09T19:30:00", "created_at": "2025-07-08T10:00:00", "teams": [{"team_id": "1", "players": [{"user_id": "user-123"}]}]}`)),
+			Header:     make(http.Header),
+			Request:    r,
+		}, nil
+	})
+	client := APIClient{
+		httpClient: &http.Client{Transport: transport},
+		BaseURL:    "https://playtomic.test",
+		retries:    2,
+	}
+
+	t.Run("retries server errors and rate limits", func(t *testing.T) {
+		statuses, requests = []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK}, 0
+		match, err := client.GetSpecificMatch("match-abc")
+		require.NoError(t, err)
+		assert.Equal(t, "user-123", match.OwnerID)
+		assert.Equal(t, 3, requests)
+	})
+
+	t.Run("gives up after the configured retries", func(t *testing.T) {
+		statuses, requests = []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError}, 0
+		_, err := client.GetSpecificMatch("match-abc")
+		require.Error(t, err)
+		assert.Equal(t, 3, requests)
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		statuses, requests = []int{http.StatusNotFound}, 0
+		_, err := client.GetSpecificMatch("match-abc")
+		require.Error(t, err)
+		assert.Equal(t, 1, requests)
+	})
+}
+
+func TestMatchCache_SizeBound(t *testing.T) {
+	clk := clock.NewMock(time.Date(2025, 7, 9, 12, 0, 0, 0, time.UTC))
+	cache := newMatchCache(time.Hour, 2, clk)
+
+	cache.put("m1", PadelMatch{MatchID: "m1"})
+	clk.Advance(time.Minute)
+	cache.put("m2", PadelMatch{MatchID: "m2"})
+	clk.Advance(time.Minute)
+	cache.put("m3", PadelMatch{MatchID: "m3"})
+
+	_, ok := cache.get("m1")
+	assert.False(t, ok, "The entry closest to expiry should be evicted")
+	_, ok = cache.get("m2")
+	assert.True(t, ok)
+	_, ok = cache.get("m3")
+	assert.True(t, ok)
+}
+
+func TestMatchCache_Copies(t *testing.T) {
+	cache := newMatchCache(time.Hour, 10, clock.NewMock(time.Date(2025, 7, 9, 12, 0, 0, 0, time.UTC)))
+
+	match := PadelMatch{
+		MatchID: "m1",
+		Teams:   []Team{{ID: "t1", TeamResult: "WON", Players: []Player{{UserID: "p1", Name: "Player One"}}}},
+		Results: []SetResult{{Name: "Set 1", Scores: map[string]int{"t1": 6, "t2": 4}}},
+	}
+	cache.put("m1", match)
+
+	// Changing the match after it was cached does not change the cached copy...
+	match.Teams[0].TeamResult = "LOST"
+	match.Teams[0].Players[0].Name = "Changed"
+	match.Results[0].Scores["t1"] = 0
+
+	cached, ok := cache.get("m1")
+	require.True(t, ok)
+	assert.Equal(t, "WON", cached.Teams[0].TeamResult)
+	assert.Equal(t, "Player One", cached.Teams[0].Players[0].Name)
+	assert.Equal(t, 6, cached.Results[0].Scores["t1"])
+
+	// ...and neither does changing a match served from the cache.
+	cached.Teams[0].Players[0].Name = "Changed"
+	cached.Results[0].Scores["t2"] = 0
+	cached.Results = append(cached.Results[:0], SetResult{Name: "Set 2"})
+
+	again, ok := cache.get("m1")
+	require.True(t, ok)
+	assert.Equal(t, "Player One", again.Teams[0].Players[0].Name)
+	assert.Equal(t, []SetResult{{Name: "Set 1", Scores: map[string]int{"t1": 6, "t2": 4}}}, again.Results)
+}
+
+func TestIsTiebreak(t *testing.T) {
+	tests := map[string]struct {
+		set  SetResult
+		want bool
+	}{
+		"regular set":            {SetResult{Name: "Set 1", Scores: map[string]int{"0": 6, "1": 4}}, false},
+		"set won 7-6":            {SetResult{Name: "Set 2", Scores: map[string]int{"0": 7, "1": 6}}, false},
+		"match tiebreak score":   {SetResult{Name: "Set 3", Scores: map[string]int{"0": 10, "1": 8}}, true},
+		"named tiebreak":         {SetResult{Name: "Super Tie-Break", Scores: map[string]int{"0": 7, "1": 5}}, true},
+		"extended tiebreak loss": {SetResult{Name: "Set 3", Scores: map[string]int{"0": 12, "1": 10}}, true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, isTiebreak(tc.set))
+		})
+	}
+}
@@ -5,4 +5,5 @@ package playtomic
 type PlaytomicClient interface {
 	GetMatches(params *SearchMatchesParams) ([]MatchSummary, error)
 	GetSpecificMatch(matchID string) (PadelMatch, error)
+	RefreshSpecificMatch(matchID string) (PadelMatch, error)
 }
@@ -8,12 +8,14 @@ type MockClient struct {
 	mu sync.Mutex
 
 	// Spies for method calls
-	GetMatchesFunc       func(params *SearchMatchesParams) ([]MatchSummary, error)
-	GetSpecificMatchFunc func(matchID string) (PadelMatch, error)
+	GetMatchesFunc           func(params *SearchMatchesParams) ([]MatchSummary, error)
+	GetSpecificMatchFunc     func(matchID string) (PadelMatch, error)
+	RefreshSpecificMatchFunc func(matchID string) (PadelMatch, error)
 
 	// Call records
-	GetMatchesCalls       []*SearchMatchesParams
-	GetSpecificMatchCalls []string
+	GetMatchesCalls           []*SearchMatchesParams
+	GetSpecificMatchCalls     []string
+	RefreshSpecificMatchCalls []string
 }
 
 // NewMockClient creates a new mock instance.
@@ -27,6 +29,7 @@ func (m *MockClient) Reset() {
 	defer m.mu.Unlock()
 	m.GetMatchesCalls = nil
 	m.GetSpecificMatchCalls = nil
+	m.RefreshSpecificMatchCalls = nil
 }
 
 func (m *MockClient) GetMatches(params *SearchMatchesParams) ([]MatchSummary, error) {
@@ -48,3 +51,13 @@ func (m *MockClient) GetSpecificMatch(matchID string) (PadelMatch, error) {
 	}
 	return PadelMatch{}, nil
 }
+
+func (m *MockClient) RefreshSpecificMatch(matchID string) (PadelMatch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.RefreshSpecificMatchCalls = append(m.RefreshSpecificMatchCalls, matchID)
+	if m.RefreshSpecificMatchFunc != nil {
+		return m.RefreshSpecificMatchFunc(matchID)
+	}
+	return PadelMatch{}, nil
+}
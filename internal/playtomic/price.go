@@ -0,0 +1,47 @@
+package playtomic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParsePrice parses a Playtomic price such as "10 EUR" or "10.50 DKK" into an amount in cents and a currency code.
+// An empty price yields a zero amount and an empty currency.
+func ParsePrice(s string) (amount int64, currency string, err error) {
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 0:
+		return 0, "", nil
+	case 2:
+	default:
+		return 0, "", fmt.Errorf("invalid price %q, expected format like 10.50 EUR", s)
+	}
+
+	currency = strings.ToUpper(fields[1])
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return 0, "", fmt.Errorf("invalid currency in price %q", s)
+		}
+	}
+
+	whole, fraction, hasFraction := strings.Cut(strings.Replace(fields[0], ",", ".", 1), ".")
+	if whole == "" || (hasFraction && (fraction == "" || len(fraction) > 2)) {
+		return 0, "", fmt.Errorf("invalid amount in price %q", s)
+	}
+	units, err := strconv.ParseUint(whole, 10, 63)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid amount in price %q", s)
+	}
+	var cents uint64
+	if hasFraction {
+		cents, err = strconv.ParseUint(fraction, 10, 8)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid amount in price %q", s)
+		}
+		if len(fraction) == 1 {
+			cents *= 10
+		}
+	}
+	return int64(units*100 + cents), currency, nil
+}
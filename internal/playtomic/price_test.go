@@ -0,0 +1,40 @@
+package playtomic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		amount   int64
+		currency string
+	}{
+		{"whole amount", "10 EUR", 1000, "EUR"},
+		{"amount with cents", "10.50 DKK", 1050, "DKK"},
+		{"single decimal", "7.5 EUR", 750, "EUR"},
+		{"decimal comma", "10,25 DKK", 1025, "DKK"},
+		{"lowercase currency", "12 eur", 1200, "EUR"},
+		{"empty", "", 0, ""},
+		{"whitespace only", "  ", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, currency, err := ParsePrice(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.amount, amount)
+			assert.Equal(t, tt.currency, currency)
+		})
+	}
+
+	for _, input := range []string{"10", "EUR", "ten EUR", "10.505 EUR", "10. EUR", "-5 EUR", "10 E1R", "10 EUR extra"} {
+		t.Run("rejects "+input, func(t *testing.T) {
+			_, _, err := ParsePrice(input)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package playtomic
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+)
+
+// maxSets is the most sets a single padel match can have.
+const maxSets = 3
+
+// SetScore is the number of games won in a set, seen from the reporting team.
+type SetScore struct {
+	Own      int
+	Opponent int
+}
+
+// ParseSetScores parses comma separated set scores such as "6-4,6-3".
+// Each score is read from the reporting team's perspective.
+func ParseSetScores(text string) ([]SetScore, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("no set scores given")
+	}
+
+	var sets []SetScore
+	for _, part := range strings.Split(text, ",") {
+		games := strings.Split(strings.TrimSpace(part), "-")
+		if len(games) != 2 {
+			return nil, fmt.Errorf("invalid set score %q, expected format like 6-4", part)
+		}
+		own, err := strconv.Atoi(strings.TrimSpace(games[0]))
+		if err != nil || own < 0 {
+			return nil, fmt.Errorf("invalid games in set score %q", part)
+		}
+		opponent, err := strconv.Atoi(strings.TrimSpace(games[1]))
+		if err != nil || opponent < 0 {
+			return nil, fmt.Errorf("invalid games in set score %q", part)
+		}
+		if own == opponent {
+			return nil, fmt.Errorf("set score %q has no winner", part)
+		}
+		sets = append(sets, SetScore{Own: own, Opponent: opponent})
+	}
+	if len(sets) > maxSets {
+		return nil, fmt.Errorf("too many sets: got %d, at most %d allowed", len(sets), maxSets)
+	}
+	return sets, nil
+}
+
+// ApplyManualResult records the given set scores, reported by the team with teamID, on the match.
+// It fills in Results and TeamResult and marks the match as played with manually confirmed results.
+func (m *PadelMatch) ApplyManualResult(teamID string, sets []SetScore) error {
+	if len(m.Teams) != 2 {
+		return fmt.Errorf("match %s must have exactly two teams, has %d", m.MatchID, len(m.Teams))
+	}
+	var opponentID string
+	switch teamID {
+	case m.Teams[0].ID:
+		opponentID = m.Teams[1].ID
+	case m.Teams[1].ID:
+		opponentID = m.Teams[0].ID
+	default:
+		return fmt.Errorf("team %s is not part of match %s", teamID, m.MatchID)
+	}
+
+	var results []SetResult
+	setsWon := 0
+	for i, set := range sets {
+		results = append(results, SetResult{
+			Name:   fmt.Sprintf("Set %d", i+1),
+			Scores: map[string]int{teamID: set.Own, opponentID: set.Opponent},
+		})
+		if set.Own > set.Opponent {
+			setsWon++
+		}
+	}
+	setsLost := len(sets) - setsWon
+	if setsWon == setsLost {
+		return errors.New("set scores do not decide a winner")
+	}
+
+	for i := range m.Teams {
+		won := (m.Teams[i].ID == teamID) == (setsWon > setsLost)
+		if won {
+			m.Teams[i].TeamResult = "WON"
+		} else {
+			m.Teams[i].TeamResult = "LOST"
+		}
+	}
+	m.Results = results
+	m.GameStatus = GameStatusPlayed
+	m.ResultsStatus = ResultsStatusManuallyConfirmed
+	return nil
+}
+
+// PlayerTeam returns the team the player plays on in the match.
+func (m *PadelMatch) PlayerTeam(playerID string) (Team, bool) {
+	for _, team := range m.Teams {
+		for _, player := range team.Players {
+			if player.UserID == playerID {
+				return team, true
+			}
+		}
+	}
+	return Team{}, false
+}
+
+// FillTeamResults derives TeamResult from the set results when Playtomic omitted it for every team, so stats
+// and notifications agree on the winner. The team that won the most sets wins; without a clear winner, or when
+// any team already has a result, the teams are left as they are.
+func (m *PadelMatch) FillTeamResults() {
+	for _, team := range m.Teams {
+		if team.TeamResult != "" {
+			return
+		}
+	}
+
+	setsWon := make(map[string]int)
+	for _, set := range m.Results {
+		var setWinnerID string
+		best, tied := -1, false
+		for teamID, score := range set.Scores {
+			switch {
+			case score > best:
+				setWinnerID, best, tied = teamID, score, false
+			case score == best:
+				tied = true
+			}
+		}
+		if setWinnerID != "" && !tied {
+			setsWon[setWinnerID]++
+		}
+	}
+
+	var winnerID string
+	most, tied := 0, false
+	for _, team := range m.Teams {
+		switch won := setsWon[team.ID]; {
+		case won > most:
+			winnerID, most, tied = team.ID, won, false
+		case won == most:
+			tied = true
+		}
+	}
+	if winnerID == "" || tied {
+		return
+	}
+
+	for i := range m.Teams {
+		if m.Teams[i].ID == winnerID {
+			m.Teams[i].TeamResult = "WON"
+		} else {
+			m.Teams[i].TeamResult = "LOST"
+		}
+	}
+}
+
+// SameResults reports whether two sets of results have the same sets with the same scores.
+func SameResults(a, b []SetResult) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Tiebreak != b[i].Tiebreak || !maps.Equal(a[i].Scores, b[i].Scores) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package playtomic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSetScores(t *testing.T) {
+	t.Run("parses comma separated sets", func(t *testing.T) {
+		sets, err := ParseSetScores("6-4, 3-6,7-6")
+		require.NoError(t, err)
+		assert.Equal(t, []SetScore{{Own: 6, Opponent: 4}, {Own: 3, Opponent: 6}, {Own: 7, Opponent: 6}}, sets)
+	})
+
+	for _, input := range []string{"", "6", "6-4-2", "six-four", "6--4", "6-6", "6-4,6-3,6-2,6-1"} {
+		t.Run("rejects "+input, func(t *testing.T) {
+			_, err := ParseSetScores(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestApplyManualResult(t *testing.T) {
+	newMatch := func() *PadelMatch {
+		return &PadelMatch{
+			MatchID: "m1",
+			Teams: []Team{
+				{ID: "t1", Players: []Player{{UserID: "p1"}, {UserID: "p2"}}},
+				{ID: "t2", Players: []Player{{UserID: "p3"}, {UserID: "p4"}}},
+			},
+		}
+	}
+
+	t.Run("reporting team wins", func(t *testing.T) {
+		match := newMatch()
+		require.NoError(t, match.ApplyManualResult("t2", []SetScore{{Own: 6, Opponent: 4}, {Own: 6, Opponent: 3}}))
+
+		assert.Equal(t, "LOST", match.Teams[0].TeamResult)
+		assert.Equal(t, "WON", match.Teams[1].TeamResult)
+		require.Len(t, match.Results, 2)
+		assert.Equal(t, "Set 1", match.Results[0].Name)
+		assert.Equal(t, map[string]int{"t2": 6, "t1": 4}, match.Results[0].Scores)
+		assert.Equal(t, GameStatusPlayed, match.GameStatus)
+		assert.Equal(t, ResultsStatusManuallyConfirmed, match.ResultsStatus)
+	})
+
+	t.Run("reporting team loses", func(t *testing.T) {
+		match := newMatch()
+		require.NoError(t, match.ApplyManualResult("t1", []SetScore{{Own: 4, Opponent: 6}, {Own: 6, Opponent: 3}, {Own: 2, Opponent: 6}}))
+
+		assert.Equal(t, "LOST", match.Teams[0].TeamResult)
+		assert.Equal(t, "WON", match.Teams[1].TeamResult)
+	})
+
+	t.Run("rejects undecided results and unknown teams", func(t *testing.T) {
+		assert.Error(t, newMatch().ApplyManualResult("t1", []SetScore{{Own: 6, Opponent: 4}, {Own: 4, Opponent: 6}}))
+		assert.Error(t, newMatch().ApplyManualResult("t3", []SetScore{{Own: 6, Opponent: 4}}))
+	})
+}
+
+func TestFillTeamResults(t *testing.T) {
+	newMatch := func(results ...SetResult) *PadelMatch {
+		return &PadelMatch{Teams: []Team{{ID: "t1"}, {ID: "t2"}}, Results: results}
+	}
+
+	t.Run("derives the winner from the most sets won", func(t *testing.T) {
+		match := newMatch(
+			SetResult{Scores: map[string]int{"t1": 4, "t2": 6}},
+			SetResult{Scores: map[string]int{"t1": 6, "t2": 3}},
+			SetResult{Scores: map[string]int{"t1": 8, "t2": 10}, Tiebreak: true},
+		)
+		match.FillTeamResults()
+		assert.Equal(t, "LOST", match.Teams[0].TeamResult)
+		assert.Equal(t, "WON", match.Teams[1].TeamResult)
+	})
+
+	t.Run("keeps results reported by Playtomic", func(t *testing.T) {
+		match := newMatch(SetResult{Scores: map[string]int{"t1": 6, "t2": 3}})
+		match.Teams[1].TeamResult = "WON"
+		match.FillTeamResults()
+		assert.Equal(t, "", match.Teams[0].TeamResult)
+		assert.Equal(t, "WON", match.Teams[1].TeamResult)
+	})
+
+	t.Run("leaves teams alone without a clear winner", func(t *testing.T) {
+		for name, match := range map[string]*PadelMatch{
+			"no results": newMatch(),
+			"sets split": newMatch(SetResult{Scores: map[string]int{"t1": 6, "t2": 3}}, SetResult{Scores: map[string]int{"t1": 3, "t2": 6}}),
+			"tied set":   newMatch(SetResult{Scores: map[string]int{"t1": 3, "t2": 3}}),
+		} {
+			match.FillTeamResults()
+			assert.Equal(t, "", match.Teams[0].TeamResult, name)
+			assert.Equal(t, "", match.Teams[1].TeamResult, name)
+		}
+	})
+}
@@ -7,6 +7,7 @@ type SearchMatchesParams struct {
 	Sort          string
 	TenantIDs     []string
 	FromStartDate string
+	ToStartDate   string // Optional upper bound on the match start date; empty means no upper bound.
 }
 
 // MatchSummary contains the essential details of a match from a search result.
@@ -36,7 +37,10 @@ type PadelMatch struct {
 	BallBringerName   string
 	BookingNotifiedTs *int64 // Unix timestamp when booking notification was sent
 	ResultNotifiedTs  *int64 // Unix timestamp when result notification was sent
+	BookingMessageTs  string // Slack timestamp of the booking notification, empty if unknown
+	DeletedAt         *int64 // Unix timestamp when the match was soft-deleted, nil if it is not deleted
 	MatchType         MatchType
+	MatchTypeEnum     MatchTypeEnum
 	ProcessingStatus  ProcessingStatus
 }
 
@@ -52,8 +56,22 @@ const (
 	StatusResultNotified       ProcessingStatus = "RESULT_NOTIFIED"
 	StatusStatsUpdated         ProcessingStatus = "STATS_UPDATED"
 	StatusCompleted            ProcessingStatus = "COMPLETED"
+	StatusNeedsReview          ProcessingStatus = "NEEDS_REVIEW" // Stats undone by an admin; not processed further until an admin requeues it.
 )
 
+// ProcessingStatuses lists every processing status in state machine order.
+var ProcessingStatuses = []ProcessingStatus{
+	StatusNew,
+	StatusAssigningBallBringer,
+	StatusBallBoyAssigned,
+	StatusBookingNotified,
+	StatusResultAvailable,
+	StatusResultNotified,
+	StatusStatsUpdated,
+	StatusCompleted,
+	StatusNeedsReview,
+}
+
 // MatchType defines the type of match.
 type MatchType string
 
@@ -62,6 +80,14 @@ const (
 	MatchTypePractice    MatchType = "FRIENDLY"
 )
 
+// MatchTypeEnum defines whether a match is played as singles or doubles.
+type MatchTypeEnum string
+
+const (
+	MatchTypeEnumSingles MatchTypeEnum = "SINGLES"
+	MatchTypeEnumDoubles MatchTypeEnum = "DOUBLES"
+)
+
 // GameStatus defines the status of a game.
 type GameStatus string
 
@@ -87,6 +113,8 @@ const (
 	ResultsStatusCanceled   ResultsStatus = "CANCELED"
 	ResultsStatusWaitingFor ResultsStatus = "WAITING_FOR"
 	ResultsStatusValidating ResultsStatus = "VALIDATING"
+	// ResultsStatusManuallyConfirmed marks results entered by a player through Slack rather than confirmed in Playtomic.
+	ResultsStatusManuallyConfirmed ResultsStatus = "MANUALLY_CONFIRMED"
 )
 
 // Team represents a team in a match.
@@ -104,10 +132,12 @@ type Player struct {
 	Paid   bool
 }
 
-// SetResult represents the result of a single set.
+// SetResult represents the result of a single set. Tiebreak marks a match tiebreak played instead of a
+// deciding set, whose scores are points rather than games.
 type SetResult struct {
-	Name   string
-	Scores map[string]int
+	Name     string
+	Scores   map[string]int
+	Tiebreak bool
 }
 
 // Tenant represents a Playtomic tenant (club).
@@ -116,6 +146,12 @@ type Tenant struct {
 	Name string
 }
 
+// playtomicMatchSummaryResponse defines the fields we read from each entry of a match search response.
+type playtomicMatchSummaryResponse struct {
+	MatchID string  `json:"match_id"`
+	OwnerID *string `json:"owner_id"`
+}
+
 // playtomicMatchResponse defines the structure for the JSON response from the Playtomic API for a single match.
 type playtomicMatchResponse struct {
 	OwnerID            string                       `json:"owner_id"`
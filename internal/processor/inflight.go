@@ -0,0 +1,44 @@
+package processor
+
+import (
+	"context"
+	"sync"
+)
+
+// inFlight keeps track of processing that is still running, so shutdown can wait for it to finish
+// instead of closing the database underneath it.
+type inFlight struct {
+	wg sync.WaitGroup
+}
+
+// start registers a task and returns the function that marks it as finished.
+func (f *inFlight) start() func() {
+	f.wg.Add(1)
+	return f.wg.Done
+}
+
+// wait blocks until all registered tasks have finished or the context is done.
+func (f *inFlight) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		f.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Track registers a unit of in-flight processing. The returned function must be called once the work is done.
+func (p *Processor) Track() func() {
+	return p.inFlight.start()
+}
+
+// Drain waits for all in-flight processing to finish, giving up when the context is done.
+// It should only be called once no new work can arrive, i.e. after the HTTP server has shut down.
+func (p *Processor) Drain(ctx context.Context) error {
+	return p.inFlight.wait(ctx)
+}
@@ -9,11 +9,22 @@ import (
 // Store defines the database operations required by the processor.
 type Store interface {
 	GetMatchesForProcessing() ([]*playtomic.PadelMatch, error)
+	GetProcessingStatusCounts() (map[playtomic.ProcessingStatus]int, error)
+	GetMatchesStuckInStatus(status playtomic.ProcessingStatus, before int64) ([]*playtomic.PadelMatch, error)
+	GetUndigestedBookings() ([]*playtomic.PadelMatch, error)
+	MarkBookingsDigested(matchIDs []string) error
 	UpdateProcessingStatus(matchID string, status playtomic.ProcessingStatus) error
-	UpsertPlayers(players []club.PlayerInfo) error
+	UpsertPlayers(players []club.PlayerInfo) ([]club.PlayerInfo, error)
 	AssignBallBringerAtomically(matchID string, playerIDs []string) (string, string, error)
 	UpdateNotificationTimestamp(matchID string, notificationType string) error
+	SetBookingMessageTS(matchID, ts string) error
+	MarkLineupReminded(matchID string) (bool, error)
 	UpdatePlayerStats(match *playtomic.PadelMatch)
+	GetResultCorrections() ([]club.ResultCorrection, error)
+	CorrectMatchStats(matchID string) error
+	GetSlackUserIDByPlayerID(playerID string) (string, bool, error)
+	IsCorePlayer(playerID string) bool
+	IsDMOptedOut(playerID string) bool
 }
 
 // Notifier defines the notification operations required by the processor.
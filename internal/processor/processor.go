@@ -1,29 +1,37 @@
 package processor
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/mauv0809/ideal-tribble/internal/clock"
 	"github.com/mauv0809/ideal-tribble/internal/club"
+	"github.com/mauv0809/ideal-tribble/internal/config"
 	"github.com/mauv0809/ideal-tribble/internal/metrics"
 	"github.com/mauv0809/ideal-tribble/internal/playtomic"
 	"github.com/mauv0809/ideal-tribble/internal/pubsub"
 )
 
 // New creates a new Processor.
-func New(store Store, notifier Notifier, metrics metrics.Metrics, pubsub pubsub.PubSubClient) *Processor {
+func New(store Store, notifier Notifier, metrics metrics.Metrics, pubsub pubsub.PubSubClient, cfg config.Config) *Processor {
 	return &Processor{
 		store:    store,
 		pubsub:   pubsub,
 		notifier: notifier,
 		metrics:  metrics,
+		cfg:      cfg,
+		clock:    clock.New(),
 	}
 }
 
 // ProcessMatches fetches matches that need processing and advances them through the state machine.
 func (p *Processor) ProcessMatches(dryRun bool) {
 	log.Info("Starting match processing...")
+	defer p.recordProcessingStatusCounts()
+	defer p.recordStuckMatches()
+	p.correctResults(dryRun)
 	matches, err := p.store.GetMatchesForProcessing()
 	if err != nil {
 		log.Error("Failed to get matches for processing", "error", err)
@@ -35,23 +43,111 @@ func (p *Processor) ProcessMatches(dryRun bool) {
 		return
 	}
 
-	log.Info("Found matches to process", "count", len(matches))
+	workers := p.cfg.ProcessConcurrency
+	if workers <= 0 {
+		workers = 1
+	}
+	workers = min(workers, len(matches))
+	log.Info("Found matches to process", "count", len(matches), "workers", workers)
+
+	queue := make(chan *playtomic.PadelMatch)
 	var wg sync.WaitGroup
-	for _, match := range matches {
+	for range workers {
 		wg.Add(1)
-		go func(m *playtomic.PadelMatch) {
+		go func() {
 			defer wg.Done()
-			startTime := time.Now()
-			p.ProcessMatch(m, dryRun)
-			duration := time.Since(startTime).Milliseconds()
-			p.metrics.ObserveProcessingDuration(float64(duration))
-		}(match)
+			for m := range queue {
+				startTime := time.Now()
+				p.ProcessMatch(m, dryRun)
+				duration := time.Since(startTime).Milliseconds()
+				p.metrics.ObserveProcessingDuration(float64(duration))
+			}
+		}()
 	}
+	for _, match := range matches {
+		queue <- match
+	}
+	close(queue)
 	wg.Wait()
 	log.Info("Match processing finished.")
 }
 
+// correctResults finds matches whose results Playtomic changed after their stats were counted. Their stats are
+// corrected and, if the original result was posted, the updated result is posted too.
+func (p *Processor) correctResults(dryRun bool) {
+	corrections, err := p.store.GetResultCorrections()
+	if err != nil {
+		log.Error("Failed to get result corrections", "error", err)
+		return
+	}
+	for _, correction := range corrections {
+		match := correction.Match
+		log.Info("Match result changed after its stats were counted. Correcting stats.", "matchID", match.MatchID)
+		if dryRun {
+			log.Info("[Dry Run] Would have corrected player stats and posted the updated result", "matchID", match.MatchID)
+			continue
+		}
+		if err := p.store.CorrectMatchStats(match.MatchID); err != nil {
+			log.Error("Failed to correct player stats", "error", err, "matchID", match.MatchID)
+			continue
+		}
+		if match.ResultNotifiedTs == nil {
+			log.Debug("Result was never notified. Skipping updated result notification.", "matchID", match.MatchID)
+			continue
+		}
+		var threadTS string
+		if p.cfg.Notifications.ThreadResults {
+			threadTS = match.BookingMessageTs
+		}
+		if err := p.notifier.SendResultCorrection(match, threadTS, dryRun); err != nil {
+			log.Error("Failed to send updated result notification", "error", err, "matchID", match.MatchID)
+		}
+	}
+}
+
+// recordProcessingStatusCounts publishes the number of matches in each processing status as metrics.
+func (p *Processor) recordProcessingStatusCounts() {
+	counts, err := p.store.GetProcessingStatusCounts()
+	if err != nil {
+		log.Error("Failed to get processing status counts", "error", err)
+		return
+	}
+	for _, status := range playtomic.ProcessingStatuses {
+		p.metrics.SetMatchesInStatus(string(status), counts[status])
+	}
+}
+
+// stuckStatuses are the processing statuses in which a match waits for a Pub/Sub handler to move it on.
+// A match that stays in one of them for long most likely had its message lost. BALL_BOY_ASSIGNED is left out,
+// as booking notifications may be held back on purpose, e.g. for the daily digest.
+var stuckStatuses = []playtomic.ProcessingStatus{
+	playtomic.StatusAssigningBallBringer,
+	playtomic.StatusResultAvailable,
+	playtomic.StatusResultNotified,
+}
+
+// recordStuckMatches publishes the number of matches stuck in each status as metrics, so they can be alerted on,
+// and logs the stuck matches.
+func (p *Processor) recordStuckMatches() {
+	if p.cfg.StuckMatchAfter <= 0 {
+		return
+	}
+	before := p.clock.Now().Add(-p.cfg.StuckMatchAfter).Unix()
+	for _, status := range stuckStatuses {
+		matches, err := p.store.GetMatchesStuckInStatus(status, before)
+		if err != nil {
+			log.Error("Failed to get stuck matches", "error", err, "status", status)
+			continue
+		}
+		for _, match := range matches {
+			log.Warn("Match is stuck in processing status", "matchID", match.MatchID, "status", status, "after", p.cfg.StuckMatchAfter)
+		}
+		p.metrics.SetMatchesStuck(string(status), len(matches))
+	}
+}
+
 func (p *Processor) ProcessMatch(match *playtomic.PadelMatch, dryRun bool) {
+	defer p.Track()()
 	log.Info("Processing match", "matchID", match.MatchID, "initial_status", match.ProcessingStatus, "game_status", match.GameStatus)
 	for {
 		currentState := match.ProcessingStatus
@@ -71,14 +167,27 @@ func (p *Processor) ProcessMatch(match *playtomic.PadelMatch, dryRun bool) {
 				}
 			}
 			if len(playersToUpsert) > 0 {
-				if err := p.store.UpsertPlayers(playersToUpsert); err != nil {
+				newPlayers, err := p.store.UpsertPlayers(playersToUpsert)
+				if err != nil {
 					log.Error("Failed to upsert players for match", "error", err, "matchID", match.MatchID)
+				} else if len(newPlayers) > 0 && p.cfg.Notifications.NewPlayers {
+					if err := p.notifier.SendNewPlayersNotification(newPlayers, dryRun); err != nil {
+						log.Error("Failed to send new player notification", "error", err, "matchID", match.MatchID)
+					}
 				}
 			}
 
 			// If a match is already played, we never want to send a booking notification.
 			switch match.GameStatus {
 			case playtomic.GameStatusPlayed:
+				// The lineup of a played match is final and a missing player would skew the stats, so it needs a human to look at it.
+				if players, required := lineupSize(match); players < required && match.ResultsStatus != playtomic.ResultsStatusExpired {
+					log.Warn("Played match has fewer players than its format requires. Sending it to review.", "matchID", match.MatchID, "players", players, "required", required)
+					p.metrics.IncIncompleteMatches()
+					p.notifyIncompleteLineup(match, players, required, dryRun)
+					p.updateStatus(match, playtomic.StatusNeedsReview, dryRun)
+					return
+				}
 				// If results are also confirmed, we can jump straight to processing the result.
 				switch match.ResultsStatus {
 				case playtomic.ResultsStatusConfirmed:
@@ -96,6 +205,16 @@ func (p *Processor) ProcessMatch(match *playtomic.PadelMatch, dryRun bool) {
 				log.Info("Match is canceled. Setting match to completed.", "matchID", match.MatchID)
 				p.updateStatus(match, playtomic.StatusCompleted, dryRun)
 			default:
+				if p.assignmentTooEarly(match, p.clock.Now()) {
+					log.Info("Match is too far ahead. Deferring ball bringer assignment to a later run.", "matchID", match.MatchID)
+					return
+				}
+				// Players may still join an upcoming match, so an incomplete one stays new and is checked again on the next run.
+				if players, required := lineupSize(match); players < required {
+					log.Info("Match has fewer players than its format requires. Waiting for the lineup to fill.", "matchID", match.MatchID, "players", players, "required", required)
+					p.remindIncompleteLineup(match, players, required, dryRun)
+					return
+				}
 				// This is a normal, upcoming match. Trigger ball bringer assignment and advance state.
 				log.Info("Match is new. Triggering ball bringer assignment asynchronously and advancing state.", "matchID", match.MatchID)
 				if !dryRun {
@@ -114,6 +233,23 @@ func (p *Processor) ProcessMatch(match *playtomic.PadelMatch, dryRun bool) {
 			return // Exit processMatch for now, will be re-processed on BallBringerAssigned event.
 
 		case playtomic.StatusBallBoyAssigned:
+			if p.bookingStale(match, p.clock.Now()) {
+				log.Info("Match has already started. Skipping booking notification.", "matchID", match.MatchID)
+				p.updateStatus(match, playtomic.StatusBookingNotified, dryRun)
+				break
+			}
+			if p.cfg.Notifications.DailyDigest {
+				log.Debug("Booking notifications are batched. Leaving the booking for the daily digest.", "matchID", match.MatchID)
+				return
+			}
+			if p.inQuietHours(p.clock.Now()) {
+				log.Info("Within quiet hours. Holding back booking notification until the next run.", "matchID", match.MatchID)
+				return
+			}
+			if p.bookingTooEarly(match, p.clock.Now()) {
+				log.Info("Match is too far ahead. Deferring booking notification to a later run.", "matchID", match.MatchID)
+				return
+			}
 			log.Info("Ball boy assigned. Sending booking notification.", "matchID", match.MatchID)
 			if !dryRun {
 				err := p.pubsub.SendMessage(pubsub.EventNotifyBooking, match)
@@ -126,7 +262,8 @@ func (p *Processor) ProcessMatch(match *playtomic.PadelMatch, dryRun bool) {
 			return
 
 		case playtomic.StatusBookingNotified:
-			if match.GameStatus == playtomic.GameStatusPlayed && match.ResultsStatus == playtomic.ResultsStatusConfirmed {
+			resultsConfirmed := match.ResultsStatus == playtomic.ResultsStatusConfirmed || match.ResultsStatus == playtomic.ResultsStatusManuallyConfirmed
+			if match.GameStatus == playtomic.GameStatusPlayed && resultsConfirmed {
 				log.Info("Match has been played. Marking as result available.", "matchID", match.MatchID)
 				p.updateStatus(match, playtomic.StatusResultAvailable, dryRun)
 			} else if match.GameStatus == playtomic.GameStatusCanceled || match.GameStatus == playtomic.GameStatusExpired {
@@ -137,7 +274,7 @@ func (p *Processor) ProcessMatch(match *playtomic.PadelMatch, dryRun bool) {
 		case playtomic.StatusResultAvailable:
 			log.Info("Match result is available. Notifying result.", "matchID", match.MatchID)
 			timeEnded := time.Unix(match.End, 0)
-			timeSinceEnd := time.Since(timeEnded)
+			timeSinceEnd := p.clock.Now().Sub(timeEnded)
 			//If game is ended more than 2 days ago we should not send results and just set update stats. This way we can fetch historic data without sending notifications.
 			if timeSinceEnd < 48*time.Hour {
 				if !dryRun {
@@ -174,6 +311,10 @@ func (p *Processor) ProcessMatch(match *playtomic.PadelMatch, dryRun bool) {
 			log.Debug("Match is complete. No further processing needed.", "matchID", match.MatchID)
 			return // End of the line for this match
 
+		case playtomic.StatusNeedsReview:
+			log.Debug("Match is awaiting review. An admin can requeue it with /requeue.", "matchID", match.MatchID)
+			return
+
 		default:
 			log.Warn("Unknown processing status", "status", currentState, "matchID", match.MatchID)
 			return // Exit if status is unknown
@@ -188,6 +329,7 @@ func (p *Processor) ProcessMatch(match *playtomic.PadelMatch, dryRun bool) {
 	log.Info("Finished processing match", "matchID", match.MatchID, "final_status", match.ProcessingStatus)
 }
 func (p *Processor) NotifyResult(match *playtomic.PadelMatch, dryRun bool) error {
+	defer p.Track()()
 	if match.ResultNotifiedTs != nil {
 		log.Debug("Result notification already sent for match. Skipping.", "matchID", match.MatchID)
 		p.updateStatus(match, playtomic.StatusResultNotified, dryRun) // Ensure in-memory status is updated
@@ -195,7 +337,11 @@ func (p *Processor) NotifyResult(match *playtomic.PadelMatch, dryRun bool) error
 	}
 
 	log.Debug("Notifying result for match", "matchID", match.MatchID)
-	err := p.notifier.SendResultNotification(match, dryRun)
+	var threadTS string
+	if p.cfg.Notifications.ThreadResults {
+		threadTS = match.BookingMessageTs
+	}
+	err := p.notifier.SendResultNotification(match, threadTS, dryRun)
 	if err != nil {
 		log.Error("Failed to send result notification", "error", err, "matchID", match.MatchID)
 		return err
@@ -213,6 +359,7 @@ func (p *Processor) NotifyResult(match *playtomic.PadelMatch, dryRun bool) error
 	return nil
 }
 func (p *Processor) NotifyBooking(match *playtomic.PadelMatch, dryRun bool) error {
+	defer p.Track()()
 	if match.BookingNotifiedTs != nil {
 		log.Debug("Booking notification already sent for match. Skipping.", "matchID", match.MatchID)
 		// Ensure the in-memory status is updated if it somehow wasn't (should be by ProcessMatch calling updateStatus already).
@@ -221,13 +368,21 @@ func (p *Processor) NotifyBooking(match *playtomic.PadelMatch, dryRun bool) erro
 	}
 
 	log.Debug("Notifying booking for match", "matchID", match.MatchID)
-	err := p.notifier.SendBookingNotification(match, dryRun)
+	slackUserIDs := p.slackUserIDs(match)
+	messageTS, err := p.notifier.SendBookingNotification(match, slackUserIDs, p.guestIDs(match, slackUserIDs), dryRun)
 	if err != nil {
 		log.Error("Failed to send booking notification", "error", err, "matchID", match.MatchID)
 		return err
 	}
 
 	if !dryRun {
+		// Failing to store the timestamp only means the result will not be threaded, so it is not fatal.
+		if err := p.store.SetBookingMessageTS(match.MatchID, messageTS); err != nil {
+			log.Warn("Failed to store booking message timestamp", "error", err, "matchID", match.MatchID)
+		} else {
+			match.BookingMessageTs = messageTS
+		}
+
 		err = p.store.UpdateNotificationTimestamp(match.MatchID, "booking")
 		if err != nil {
 			log.Error("Failed to update booking notification timestamp", "error", err, "matchID", match.MatchID)
@@ -239,12 +394,52 @@ func (p *Processor) NotifyBooking(match *playtomic.PadelMatch, dryRun bool) erro
 	return nil
 }
 
+// PostBookingDigest posts the bookings waiting for the daily digest in one message and moves them on to
+// BOOKING_NOTIFIED, so they are not digested again. Bookings that have already started or are beyond the booking
+// lead time are left for the regular processing runs. It returns the number of bookings in the digest.
+func (p *Processor) PostBookingDigest(dryRun bool) (int, error) {
+	defer p.Track()()
+	bookings, err := p.store.GetUndigestedBookings()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get undigested bookings: %w", err)
+	}
+	now := p.clock.Now()
+	var matches []*playtomic.PadelMatch
+	var matchIDs []string
+	for _, match := range bookings {
+		if p.bookingStale(match, now) || p.bookingTooEarly(match, now) {
+			continue
+		}
+		matches = append(matches, match)
+		matchIDs = append(matchIDs, match.MatchID)
+	}
+	if len(matches) == 0 {
+		log.Info("No new bookings for the daily digest")
+		return 0, nil
+	}
+
+	if err := p.notifier.SendBookingDigest(matches, dryRun); err != nil {
+		return 0, fmt.Errorf("failed to send booking digest: %w", err)
+	}
+	if dryRun {
+		log.Info("[Dry Run] Would have marked bookings as digested", "matches", matchIDs)
+		return len(matches), nil
+	}
+	if err := p.store.MarkBookingsDigested(matchIDs); err != nil {
+		return 0, fmt.Errorf("failed to mark bookings as digested: %w", err)
+	}
+	log.Info("Posted daily booking digest", "matches", len(matches))
+	return len(matches), nil
+}
+
 func (p *Processor) UpdatePlayerStats(match *playtomic.PadelMatch, dryRun bool) {
+	defer p.Track()()
 	log.Debug("Updating player stats for match", "matchID", match.MatchID)
 	p.store.UpdatePlayerStats(match)
 	p.updateStatus(match, playtomic.StatusStatsUpdated, dryRun)
 }
 func (p *Processor) AssignBallBringer(match *playtomic.PadelMatch, dryRun bool) {
+	defer p.Track()()
 	var playerIDs []string
 	for _, team := range match.Teams {
 		for _, player := range team.Players {
@@ -266,6 +461,7 @@ func (p *Processor) AssignBallBringer(match *playtomic.PadelMatch, dryRun bool)
 		// Update the in-memory match object so the notifier has the correct data
 		match.BallBringerID = assignedBallBringerID
 		match.BallBringerName = assignedBallBringerName
+		p.notifyBallBringer(match, dryRun)
 	} else {
 		log.Info("[Dry Run] Would have assigned ball bringer (atomically)", "matchID", match.MatchID, "playerIDs", playerIDs)
 	}
@@ -273,6 +469,131 @@ func (p *Processor) AssignBallBringer(match *playtomic.PadelMatch, dryRun bool)
 	p.updateStatus(match, playtomic.StatusBallBoyAssigned, dryRun)
 }
 
+// slackUserIDs returns the Slack users of the players in the match that have one mapped, keyed by player ID.
+func (p *Processor) slackUserIDs(match *playtomic.PadelMatch) map[string]string {
+	slackUserIDs := make(map[string]string)
+	for _, team := range match.Teams {
+		for _, player := range team.Players {
+			if player.UserID == "" {
+				continue
+			}
+			slackUserID, ok, err := p.store.GetSlackUserIDByPlayerID(player.UserID)
+			if err != nil {
+				log.Warn("Failed to look up slack user of player. Falling back to their name.", "error", err, "playerID", player.UserID)
+				continue
+			}
+			if ok {
+				slackUserIDs[player.UserID] = slackUserID
+			}
+		}
+	}
+	return slackUserIDs
+}
+
+// guestIDs returns the players of the match who are not club members. Every player of a processed match is in the
+// club store, so members are the core players and the players linked to a Slack user.
+func (p *Processor) guestIDs(match *playtomic.PadelMatch, slackUserIDs map[string]string) map[string]bool {
+	guestIDs := make(map[string]bool)
+	for _, team := range match.Teams {
+		for _, player := range team.Players {
+			if player.UserID == "" {
+				continue
+			}
+			if _, mapped := slackUserIDs[player.UserID]; !mapped && !p.store.IsCorePlayer(player.UserID) {
+				guestIDs[player.UserID] = true
+			}
+		}
+	}
+	return guestIDs
+}
+
+// notifyBallBringer sends the assigned ball bringer a direct message, if enabled and their Slack user is known.
+// Failures are only logged, as the booking notification in the channel still tells them.
+func (p *Processor) notifyBallBringer(match *playtomic.PadelMatch, dryRun bool) {
+	if !p.cfg.Notifications.BallBringerDM || match.BallBringerID == "" {
+		return
+	}
+	if p.store.IsDMOptedOut(match.BallBringerID) {
+		log.Debug("Ball bringer opted out of direct messages. Skipping direct message.", "matchID", match.MatchID, "playerID", match.BallBringerID)
+		return
+	}
+	slackUserID, ok, err := p.store.GetSlackUserIDByPlayerID(match.BallBringerID)
+	if err != nil {
+		log.Error("Failed to look up slack user of ball bringer", "error", err, "matchID", match.MatchID, "playerID", match.BallBringerID)
+		return
+	}
+	if !ok {
+		log.Debug("Ball bringer has no slack user mapped. Skipping direct message.", "matchID", match.MatchID, "playerID", match.BallBringerID)
+		return
+	}
+
+	if err := p.notifier.SendBallBringerReminder(slackUserID, match, dryRun); err != nil {
+		log.Error("Failed to send direct message to ball bringer", "error", err, "matchID", match.MatchID, "playerID", match.BallBringerID)
+	}
+}
+
+// lineupSize returns how many players the match has and how many its format requires.
+// A match whose format is unknown requires none.
+func lineupSize(match *playtomic.PadelMatch) (players, required int) {
+	for _, team := range match.Teams {
+		for _, player := range team.Players {
+			if player.UserID != "" {
+				players++
+			}
+		}
+	}
+	// The format is derived from the team sizes, which are stored with the match, unlike the classified format.
+	format, _ := playtomic.ClassifyMatch(match.Teams, "")
+	switch format {
+	case playtomic.MatchTypeEnumSingles:
+		required = 2
+	case playtomic.MatchTypeEnumDoubles:
+		required = 4
+	}
+	return players, required
+}
+
+// remindIncompleteLineup counts an incomplete upcoming match and asks its owner to complete the lineup. As the match
+// is checked on every run until its lineup fills, the reminder is recorded on the match so it happens once per match.
+func (p *Processor) remindIncompleteLineup(match *playtomic.PadelMatch, players, required int, dryRun bool) {
+	if !dryRun {
+		first, err := p.store.MarkLineupReminded(match.MatchID)
+		if err != nil {
+			log.Error("Failed to record lineup reminder", "error", err, "matchID", match.MatchID)
+			return
+		}
+		if !first {
+			return
+		}
+	}
+	p.metrics.IncIncompleteMatches()
+	p.notifyIncompleteLineup(match, players, required, dryRun)
+}
+
+// notifyIncompleteLineup asks the match owner to add the missing players, if enabled and their Slack user is known.
+func (p *Processor) notifyIncompleteLineup(match *playtomic.PadelMatch, players, required int, dryRun bool) {
+	if !p.cfg.Notifications.IncompleteMatchDM || match.OwnerID == "" {
+		return
+	}
+	if p.store.IsDMOptedOut(match.OwnerID) {
+		log.Debug("Match owner opted out of direct messages. Skipping direct message.", "matchID", match.MatchID, "playerID", match.OwnerID)
+		return
+	}
+	slackUserID, ok, err := p.store.GetSlackUserIDByPlayerID(match.OwnerID)
+	if err != nil {
+		log.Error("Failed to look up slack user of match owner", "error", err, "matchID", match.MatchID, "playerID", match.OwnerID)
+		return
+	}
+	if !ok {
+		log.Debug("Match owner has no slack user mapped. Skipping direct message.", "matchID", match.MatchID, "playerID", match.OwnerID)
+		return
+	}
+
+	if err := p.notifier.SendIncompleteLineupReminder(slackUserID, match, players, required, dryRun); err != nil {
+		log.Error("Failed to send direct message to match owner", "error", err, "matchID", match.MatchID, "playerID", match.OwnerID)
+	}
+}
+
 func (p *Processor) updateStatus(match *playtomic.PadelMatch, newStatus playtomic.ProcessingStatus, dryRun bool) {
 	if dryRun {
 		log.Info("[Dry Run] Would update match status", "matchID", match.MatchID, "from", match.ProcessingStatus, "to", newStatus)
@@ -289,3 +610,48 @@ func (p *Processor) updateStatus(match *playtomic.PadelMatch, newStatus playtomi
 		match.ProcessingStatus = newStatus
 	}
 }
+
+// bookingStale reports whether the match started longer ago than the booking grace period,
+// making a booking notification pointless. Matches without a known start time are never stale.
+func (p *Processor) bookingStale(match *playtomic.PadelMatch, now time.Time) bool {
+	if match.Start == 0 {
+		return false
+	}
+	return now.After(time.Unix(match.Start, 0).Add(p.cfg.Notifications.BookingGrace))
+}
+
+// assignmentTooEarly reports whether the match starts later than the ball bringer assignment window from now.
+func (p *Processor) assignmentTooEarly(match *playtomic.PadelMatch, now time.Time) bool {
+	window := p.cfg.BallBringerAssignWindow
+	if window <= 0 || match.Start == 0 {
+		return false
+	}
+	return time.Unix(match.Start, 0).Sub(now) > window
+}
+
+// bookingTooEarly reports whether the match starts later than the booking notification lead time from now.
+func (p *Processor) bookingTooEarly(match *playtomic.PadelMatch, now time.Time) bool {
+	maxLead := p.cfg.Notifications.BookingNotifyMaxLeadTime
+	if maxLead <= 0 || match.Start == 0 {
+		return false
+	}
+	return time.Unix(match.Start, 0).Sub(now) > maxLead
+}
+
+// inQuietHours reports whether t falls inside the configured quiet hours, evaluated in club local time.
+func (p *Processor) inQuietHours(t time.Time) bool {
+	start, end := p.cfg.Notifications.QuietHoursStart, p.cfg.Notifications.QuietHoursEnd
+	if start == end {
+		return false
+	}
+	loc, err := time.LoadLocation("Europe/Copenhagen")
+	if err == nil {
+		t = t.In(loc)
+	}
+	hour := t.Hour()
+	if start < end {
+		return hour >= start && hour < end
+	}
+	// The window wraps midnight, e.g. 22-07.
+	return hour >= start || hour < end
+}
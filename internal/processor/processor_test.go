@@ -1,11 +1,17 @@
 package processor
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/mauv0809/ideal-tribble/internal/clock"
 	"github.com/mauv0809/ideal-tribble/internal/club"
+	"github.com/mauv0809/ideal-tribble/internal/config"
+	"github.com/mauv0809/ideal-tribble/internal/database"
 	"github.com/mauv0809/ideal-tribble/internal/metrics"
 	"github.com/mauv0809/ideal-tribble/internal/notifier"
 	"github.com/mauv0809/ideal-tribble/internal/playtomic"
@@ -14,6 +20,18 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// setupTestStore returns a club store backed by an in-memory database with the schema migrated.
+func setupTestStore(t *testing.T) club.ClubStore {
+	t.Helper()
+	db, teardown, err := database.InitDB(":memory:", "", "", "../../migrations", config.DBPoolConfig{})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		teardown()
+		db.Close()
+	})
+	return club.New(db)
+}
+
 func TestProcessor_ProcessMatches(t *testing.T) {
 	t.Run("new upcoming match sends assign ball boy event and changes status", func(t *testing.T) {
 		// Setup
@@ -21,13 +39,14 @@ func TestProcessor_ProcessMatches(t *testing.T) {
 		notif := notifier.NewMock()
 		metr := metrics.NewMock()
 		psClient := pubsubPkg.NewMock("TEST")
-		p := New(store, notif, metr, psClient)
+		p := New(store, notif, metr, psClient, config.Config{})
 
 		match := &playtomic.PadelMatch{
 			MatchID:          "m1",
 			ProcessingStatus: playtomic.StatusNew,
 			Teams: []playtomic.Team{
 				{Players: []playtomic.Player{{UserID: "p1", Name: "Player 1"}, {UserID: "p2", Name: "Player 2"}}},
+				{Players: []playtomic.Player{{UserID: "p3", Name: "Player 3"}, {UserID: "p4", Name: "Player 4"}}},
 			},
 		}
 		store.GetMatchesForProcessingFunc = func() ([]*playtomic.PadelMatch, error) {
@@ -67,7 +86,7 @@ func TestProcessor_ProcessMatches(t *testing.T) {
 		notif := notifier.NewMock()
 		metr := metrics.NewMock()
 		psClient := pubsubPkg.NewMock("TEST")
-		p := New(store, notif, metr, psClient)
+		p := New(store, notif, metr, psClient, config.Config{})
 
 		match := &playtomic.PadelMatch{
 			MatchID:          "m1",
@@ -123,7 +142,7 @@ func TestProcessor_ProcessMatches(t *testing.T) {
 		notif := notifier.NewMock()
 		metr := metrics.NewMock()
 		psClient := pubsubPkg.NewMock("TEST")
-		p := New(store, notif, metr, psClient)
+		p := New(store, notif, metr, psClient, config.Config{})
 
 		match := &playtomic.PadelMatch{
 			MatchID:          "m1",
@@ -179,7 +198,7 @@ func TestProcessor_ProcessMatches(t *testing.T) {
 		notif := notifier.NewMock()
 		metr := metrics.NewMock()
 		psClient := pubsubPkg.NewMock("TEST")
-		p := New(store, notif, metr, psClient)
+		p := New(store, notif, metr, psClient, config.Config{})
 
 		match := &playtomic.PadelMatch{
 			MatchID:          "m1",
@@ -201,3 +220,809 @@ func TestProcessor_ProcessMatches(t *testing.T) {
 		assert.Equal(t, playtomic.StatusBookingNotified, store.UpdateProcessingStatusCalls[0].Status)
 	})
 }
+
+func TestProcessor_RecordsProcessingStatusCounts(t *testing.T) {
+	store := club.NewMock()
+	metr := metrics.NewMock()
+	p := New(store, notifier.NewMock(), metr, pubsubPkg.NewMock("TEST"), config.Config{})
+
+	store.GetProcessingStatusCountsFunc = func() (map[playtomic.ProcessingStatus]int, error) {
+		return map[playtomic.ProcessingStatus]int{
+			playtomic.StatusNew:       2,
+			playtomic.StatusCompleted: 5,
+		}, nil
+	}
+
+	p.ProcessMatches(false)
+
+	count, ok := metr.MatchesInStatus(string(playtomic.StatusNew))
+	require.True(t, ok)
+	assert.Equal(t, 2, count)
+	count, ok = metr.MatchesInStatus(string(playtomic.StatusCompleted))
+	require.True(t, ok)
+	assert.Equal(t, 5, count)
+	count, ok = metr.MatchesInStatus(string(playtomic.StatusBookingNotified))
+	require.True(t, ok, "Statuses without matches should be reset to zero")
+	assert.Equal(t, 0, count)
+}
+
+func TestProcessor_RecordsStuckMatches(t *testing.T) {
+	store := club.NewMock()
+	metr := metrics.NewMock()
+	p := New(store, notifier.NewMock(), metr, pubsubPkg.NewMock("TEST"), config.Config{StuckMatchAfter: 12 * time.Hour})
+	now := time.Date(2024, 3, 15, 18, 0, 0, 0, time.UTC)
+	p.clock = clock.NewMock(now)
+
+	var cutoffs []int64
+	store.GetMatchesStuckInStatusFunc = func(status playtomic.ProcessingStatus, before int64) ([]*playtomic.PadelMatch, error) {
+		cutoffs = append(cutoffs, before)
+		if status == playtomic.StatusAssigningBallBringer {
+			return []*playtomic.PadelMatch{{MatchID: "stuck", ProcessingStatus: status}}, nil
+		}
+		return nil, nil
+	}
+
+	p.ProcessMatches(false)
+
+	count, ok := metr.MatchesStuck(string(playtomic.StatusAssigningBallBringer))
+	require.True(t, ok)
+	assert.Equal(t, 1, count)
+	count, ok = metr.MatchesStuck(string(playtomic.StatusResultAvailable))
+	require.True(t, ok, "Statuses without stuck matches should be reset to zero")
+	assert.Equal(t, 0, count)
+	require.NotEmpty(t, cutoffs)
+	assert.Equal(t, now.Add(-12*time.Hour).Unix(), cutoffs[0])
+}
+
+// concurrencyStore records how many matches are being processed at once. UpsertPlayers is overridden
+// so it runs outside the mock's lock, which would otherwise serialise the workers.
+type concurrencyStore struct {
+	*club.MockStore
+	active, peak atomic.Int32
+}
+
+func (s *concurrencyStore) UpsertPlayers(players []club.PlayerInfo) ([]club.PlayerInfo, error) {
+	n := s.active.Add(1)
+	defer s.active.Add(-1)
+	for {
+		peak := s.peak.Load()
+		if n <= peak || s.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return nil, nil
+}
+
+func TestProcessor_ProcessMatchesBoundedConcurrency(t *testing.T) {
+	store := &concurrencyStore{MockStore: club.NewMock()}
+	metr := metrics.NewMock()
+	p := New(store, notifier.NewMock(), metr, pubsubPkg.NewMock("TEST"), config.Config{ProcessConcurrency: 3})
+
+	var matches []*playtomic.PadelMatch
+	for i := range 10 {
+		matches = append(matches, &playtomic.PadelMatch{
+			MatchID:          fmt.Sprintf("m%d", i),
+			ProcessingStatus: playtomic.StatusNew,
+			GameStatus:       playtomic.GameStatusCanceled,
+			Teams:            []playtomic.Team{{Players: []playtomic.Player{{UserID: "p1", Name: "Player 1"}}}},
+		})
+	}
+	store.GetMatchesForProcessingFunc = func() ([]*playtomic.PadelMatch, error) {
+		return matches, nil
+	}
+
+	p.ProcessMatches(false)
+
+	assert.LessOrEqual(t, store.peak.Load(), int32(3), "No more than ProcessConcurrency matches should be processed at once")
+	assert.Greater(t, store.peak.Load(), int32(1), "Matches should still be processed concurrently")
+	assert.Len(t, metr.ProcessingDurations(), 10, "Processing duration should be observed for every match")
+	assert.Len(t, store.UpdateProcessingStatusCalls, 10)
+}
+
+func TestProcessor_QuietHours(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Copenhagen")
+	require.NoError(t, err)
+	now := time.Date(2024, 6, 1, 23, 30, 0, 0, loc)
+
+	newBallBoyAssignedMatch := func() *playtomic.PadelMatch {
+		return &playtomic.PadelMatch{
+			MatchID:          "m1",
+			ProcessingStatus: playtomic.StatusBallBoyAssigned,
+			BallBringerName:  "Player 1",
+		}
+	}
+
+	t.Run("holds back booking notification during quiet hours", func(t *testing.T) {
+		store := club.NewMock()
+		psClient := pubsubPkg.NewMock("TEST")
+		cfg := config.Config{Notifications: config.NotificationConfig{QuietHoursStart: 22, QuietHoursEnd: 7}}
+		p := New(store, notifier.NewMock(), metrics.NewMock(), psClient, cfg)
+		p.clock = clock.NewMock(now)
+
+		match := newBallBoyAssignedMatch()
+		p.ProcessMatch(match, false)
+
+		assert.Len(t, psClient.SendMessageCalls, 0, "Booking notification should be held back during quiet hours")
+		assert.Equal(t, playtomic.StatusBallBoyAssigned, match.ProcessingStatus)
+	})
+
+	t.Run("sends booking notification outside quiet hours", func(t *testing.T) {
+		store := club.NewMock()
+		psClient := pubsubPkg.NewMock("TEST")
+		cfg := config.Config{Notifications: config.NotificationConfig{QuietHoursStart: 22, QuietHoursEnd: 7}}
+		p := New(store, notifier.NewMock(), metrics.NewMock(), psClient, cfg)
+		p.clock = clock.NewMock(now.Add(8 * time.Hour))
+
+		match := newBallBoyAssignedMatch()
+		p.ProcessMatch(match, false)
+
+		require.Len(t, psClient.SendMessageCalls, 1)
+		assert.Equal(t, string(pubsubPkg.EventNotifyBooking), string(psClient.SendMessageCalls[0].Topic))
+	})
+
+	t.Run("evaluates windows that wrap midnight", func(t *testing.T) {
+		p := New(club.NewMock(), notifier.NewMock(), metrics.NewMock(), pubsubPkg.NewMock("TEST"), config.Config{
+			Notifications: config.NotificationConfig{QuietHoursStart: 22, QuietHoursEnd: 7},
+		})
+
+		assert.True(t, p.inQuietHours(time.Date(2024, 6, 1, 23, 30, 0, 0, loc)))
+		assert.True(t, p.inQuietHours(time.Date(2024, 6, 1, 6, 59, 0, 0, loc)))
+		assert.False(t, p.inQuietHours(time.Date(2024, 6, 1, 7, 0, 0, 0, loc)))
+		assert.False(t, p.inQuietHours(time.Date(2024, 6, 1, 12, 0, 0, 0, loc)))
+	})
+
+	t.Run("is disabled when start equals end", func(t *testing.T) {
+		p := New(club.NewMock(), notifier.NewMock(), metrics.NewMock(), pubsubPkg.NewMock("TEST"), config.Config{})
+
+		assert.False(t, p.inQuietHours(time.Date(2024, 6, 1, 3, 0, 0, 0, loc)))
+	})
+}
+
+func TestProcessor_DefersBallBringerAssignment(t *testing.T) {
+	now := time.Date(2024, 6, 1, 18, 0, 0, 0, time.UTC)
+	cfg := config.Config{BallBringerAssignWindow: 48 * time.Hour}
+	newMatch := func(start time.Time) *playtomic.PadelMatch {
+		return &playtomic.PadelMatch{
+			MatchID:          "m1",
+			ProcessingStatus: playtomic.StatusNew,
+			GameStatus:       playtomic.GameStatusPending,
+			Start:            start.Unix(),
+		}
+	}
+
+	t.Run("defers assignment for a far-future match", func(t *testing.T) {
+		store := club.NewMock()
+		psClient := pubsubPkg.NewMock("TEST")
+		p := New(store, notifier.NewMock(), metrics.NewMock(), psClient, cfg)
+		p.clock = clock.NewMock(now)
+
+		match := newMatch(now.Add(7 * 24 * time.Hour))
+		p.ProcessMatch(match, false)
+
+		assert.Empty(t, psClient.SendMessageCalls, "No assignment should be triggered yet")
+		assert.Empty(t, store.UpdateProcessingStatusCalls)
+		assert.Equal(t, playtomic.StatusNew, match.ProcessingStatus, "The match is picked up again on a later run")
+	})
+
+	t.Run("assigns once the match is within the window", func(t *testing.T) {
+		psClient := pubsubPkg.NewMock("TEST")
+		p := New(club.NewMock(), notifier.NewMock(), metrics.NewMock(), psClient, cfg)
+		p.clock = clock.NewMock(now)
+
+		match := newMatch(now.Add(24 * time.Hour))
+		p.ProcessMatch(match, false)
+
+		require.Len(t, psClient.SendMessageCalls, 1)
+		assert.Equal(t, string(pubsubPkg.EventAssignBallBoy), psClient.SendMessageCalls[0].Topic)
+		assert.Equal(t, playtomic.StatusAssigningBallBringer, match.ProcessingStatus)
+	})
+}
+
+func TestProcessor_DefersFarFutureBookingNotification(t *testing.T) {
+	now := time.Date(2024, 6, 1, 18, 0, 0, 0, time.UTC)
+	cfg := config.Config{Notifications: config.NotificationConfig{BookingNotifyMaxLeadTime: 14 * 24 * time.Hour}}
+	newMatch := func(start time.Time) *playtomic.PadelMatch {
+		return &playtomic.PadelMatch{
+			MatchID:          "m1",
+			ProcessingStatus: playtomic.StatusBallBoyAssigned,
+			Start:            start.Unix(),
+		}
+	}
+
+	t.Run("defers the notification for a far-future match", func(t *testing.T) {
+		store := club.NewMock()
+		psClient := pubsubPkg.NewMock("TEST")
+		p := New(store, notifier.NewMock(), metrics.NewMock(), psClient, cfg)
+		p.clock = clock.NewMock(now)
+
+		match := newMatch(now.Add(60 * 24 * time.Hour))
+		p.ProcessMatch(match, false)
+
+		assert.Empty(t, psClient.SendMessageCalls, "No booking notification should be sent yet")
+		assert.Empty(t, store.UpdateProcessingStatusCalls)
+		assert.Equal(t, playtomic.StatusBallBoyAssigned, match.ProcessingStatus, "The match is picked up again on a later run")
+	})
+
+	t.Run("notifies once the match is within the lead time", func(t *testing.T) {
+		psClient := pubsubPkg.NewMock("TEST")
+		p := New(club.NewMock(), notifier.NewMock(), metrics.NewMock(), psClient, cfg)
+		p.clock = clock.NewMock(now.Add(50 * 24 * time.Hour))
+
+		match := newMatch(now.Add(60 * 24 * time.Hour))
+		p.ProcessMatch(match, false)
+
+		require.Len(t, psClient.SendMessageCalls, 1)
+		assert.Equal(t, string(pubsubPkg.EventNotifyBooking), psClient.SendMessageCalls[0].Topic)
+	})
+}
+
+func TestProcessor_DailyDigestHoldsBackBookingNotification(t *testing.T) {
+	now := time.Date(2024, 6, 1, 18, 0, 0, 0, time.UTC)
+	store := club.NewMock()
+	psClient := pubsubPkg.NewMock("TEST")
+	p := New(store, notifier.NewMock(), metrics.NewMock(), psClient, config.Config{Notifications: config.NotificationConfig{DailyDigest: true}})
+	p.clock = clock.NewMock(now)
+
+	match := &playtomic.PadelMatch{MatchID: "m1", ProcessingStatus: playtomic.StatusBallBoyAssigned, Start: now.Add(24 * time.Hour).Unix()}
+	p.ProcessMatch(match, false)
+
+	assert.Empty(t, psClient.SendMessageCalls, "The booking should be left for the daily digest")
+	assert.Equal(t, playtomic.StatusBallBoyAssigned, match.ProcessingStatus)
+}
+
+func TestProcessor_PostBookingDigest(t *testing.T) {
+	now := time.Date(2024, 6, 1, 18, 0, 0, 0, time.UTC)
+	store := club.NewMock()
+	notif := notifier.NewMock()
+	cfg := config.Config{Notifications: config.NotificationConfig{DailyDigest: true, BookingGrace: 15 * time.Minute, BookingNotifyMaxLeadTime: 14 * 24 * time.Hour}}
+	p := New(store, notif, metrics.NewMock(), pubsubPkg.NewMock("TEST"), cfg)
+	p.clock = clock.NewMock(now)
+
+	store.GetUndigestedBookingsFunc = func() ([]*playtomic.PadelMatch, error) {
+		return []*playtomic.PadelMatch{
+			{MatchID: "started", Start: now.Add(-time.Hour).Unix()},
+			{MatchID: "tomorrow", Start: now.Add(24 * time.Hour).Unix()},
+			{MatchID: "next-week", Start: now.Add(7 * 24 * time.Hour).Unix()},
+			{MatchID: "far-ahead", Start: now.Add(60 * 24 * time.Hour).Unix()},
+		}, nil
+	}
+	var marked []string
+	store.MarkBookingsDigestedFunc = func(matchIDs []string) error {
+		marked = matchIDs
+		return nil
+	}
+
+	count, err := p.PostBookingDigest(false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	require.Len(t, notif.SendBookingDigestCalls, 1)
+	assert.Len(t, notif.SendBookingDigestCalls[0], 2)
+	assert.Equal(t, []string{"tomorrow", "next-week"}, marked)
+
+	t.Run("sends nothing without new bookings", func(t *testing.T) {
+		notif.Reset()
+		store.GetUndigestedBookingsFunc = nil
+
+		count, err := p.PostBookingDigest(false)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		assert.Empty(t, notif.SendBookingDigestCalls)
+	})
+}
+
+func TestProcessor_CorrectsChangedResults(t *testing.T) {
+	notifiedAt := int64(1717257600)
+	counted := []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 6, "t2": 4}}}
+	changed := []playtomic.SetResult{{Name: "Set-1", Scores: map[string]int{"t1": 4, "t2": 6}}}
+	store := club.NewMock()
+	notif := notifier.NewMock()
+	cfg := config.Config{Notifications: config.NotificationConfig{ThreadResults: true}}
+	p := New(store, notif, metrics.NewMock(), pubsubPkg.NewMock("TEST"), cfg)
+
+	store.GetResultCorrectionsFunc = func() ([]club.ResultCorrection, error) {
+		return []club.ResultCorrection{
+			{Match: &playtomic.PadelMatch{MatchID: "notified", Results: changed, ResultNotifiedTs: &notifiedAt, BookingMessageTs: "booking-ts"}, CountedResults: counted},
+			{Match: &playtomic.PadelMatch{MatchID: "historic", Results: changed}, CountedResults: counted},
+		}, nil
+	}
+	var corrected []string
+	store.CorrectMatchStatsFunc = func(matchID string) error {
+		corrected = append(corrected, matchID)
+		return nil
+	}
+
+	p.ProcessMatches(false)
+
+	assert.Equal(t, []string{"notified", "historic"}, corrected, "Stats are corrected for every changed result")
+	require.Len(t, notif.SendResultCorrectionCalls, 1, "Only results that were posted get an updated result")
+	assert.Equal(t, "notified", notif.SendResultCorrectionCalls[0].Match.MatchID)
+	assert.Equal(t, "booking-ts", notif.SendResultCorrectionCalls[0].ThreadTS)
+
+	t.Run("dry run corrects nothing", func(t *testing.T) {
+		corrected = nil
+		notif.Reset()
+
+		p.ProcessMatches(true)
+
+		assert.Empty(t, corrected)
+		assert.Empty(t, notif.SendResultCorrectionCalls)
+	})
+
+	t.Run("does not post the updated result when the correction fails", func(t *testing.T) {
+		notif.Reset()
+		store.CorrectMatchStatsFunc = func(matchID string) error {
+			return club.ErrStatsArchived
+		}
+
+		p.ProcessMatches(false)
+
+		assert.Empty(t, notif.SendResultCorrectionCalls)
+	})
+}
+
+func TestProcessor_SkipsStaleBookingNotification(t *testing.T) {
+	now := time.Date(2024, 6, 1, 18, 0, 0, 0, time.UTC)
+	cfg := config.Config{Notifications: config.NotificationConfig{BookingGrace: 15 * time.Minute}}
+	newMatch := func(start time.Time) *playtomic.PadelMatch {
+		return &playtomic.PadelMatch{
+			MatchID:          "m1",
+			ProcessingStatus: playtomic.StatusBallBoyAssigned,
+			Start:            start.Unix(),
+		}
+	}
+
+	t.Run("skips the notification for a match that started before the grace period", func(t *testing.T) {
+		store := club.NewMock()
+		psClient := pubsubPkg.NewMock("TEST")
+		p := New(store, notifier.NewMock(), metrics.NewMock(), psClient, cfg)
+		p.clock = clock.NewMock(now)
+
+		match := newMatch(now.Add(-time.Hour))
+		p.ProcessMatch(match, false)
+
+		assert.Empty(t, psClient.SendMessageCalls, "No booking notification should be sent for a match that has started")
+		require.Len(t, store.UpdateProcessingStatusCalls, 1)
+		assert.Equal(t, playtomic.StatusBookingNotified, store.UpdateProcessingStatusCalls[0].Status)
+	})
+
+	t.Run("still notifies within the grace period", func(t *testing.T) {
+		psClient := pubsubPkg.NewMock("TEST")
+		p := New(club.NewMock(), notifier.NewMock(), metrics.NewMock(), psClient, cfg)
+		p.clock = clock.NewMock(now)
+
+		p.ProcessMatch(newMatch(now.Add(-10*time.Minute)), false)
+
+		require.Len(t, psClient.SendMessageCalls, 1)
+		assert.Equal(t, string(pubsubPkg.EventNotifyBooking), string(psClient.SendMessageCalls[0].Topic))
+	})
+}
+
+func TestProcessor_ResultNotificationCutoff(t *testing.T) {
+	end := time.Date(2024, 6, 1, 20, 0, 0, 0, time.UTC)
+	newResultAvailableMatch := func() *playtomic.PadelMatch {
+		return &playtomic.PadelMatch{
+			MatchID:          "m1",
+			ProcessingStatus: playtomic.StatusResultAvailable,
+			End:              end.Unix(),
+		}
+	}
+
+	t.Run("notifies results just inside the cutoff", func(t *testing.T) {
+		store := club.NewMock()
+		psClient := pubsubPkg.NewMock("TEST")
+		p := New(store, notifier.NewMock(), metrics.NewMock(), psClient, config.Config{})
+		p.clock = clock.NewMock(end.Add(48*time.Hour - time.Minute))
+
+		match := newResultAvailableMatch()
+		p.ProcessMatch(match, false)
+
+		require.Len(t, psClient.SendMessageCalls, 1)
+		assert.Equal(t, string(pubsubPkg.EventNotifyResult), string(psClient.SendMessageCalls[0].Topic))
+		assert.Equal(t, playtomic.StatusResultAvailable, match.ProcessingStatus)
+	})
+
+	t.Run("skips notification once the cutoff has passed", func(t *testing.T) {
+		store := club.NewMock()
+		psClient := pubsubPkg.NewMock("TEST")
+		p := New(store, notifier.NewMock(), metrics.NewMock(), psClient, config.Config{})
+		clk := clock.NewMock(end.Add(48*time.Hour - time.Minute))
+		p.clock = clk
+		clk.Advance(2 * time.Minute)
+
+		match := newResultAvailableMatch()
+		p.ProcessMatch(match, false)
+
+		for _, call := range psClient.SendMessageCalls {
+			assert.NotEqual(t, string(pubsubPkg.EventNotifyResult), string(call.Topic))
+		}
+		require.NotEmpty(t, store.UpdateProcessingStatusCalls)
+		assert.Equal(t, playtomic.StatusResultNotified, store.UpdateProcessingStatusCalls[0].Status)
+	})
+}
+
+func TestProcessor_BallBringerDirectMessage(t *testing.T) {
+	newMatch := func() *playtomic.PadelMatch {
+		return &playtomic.PadelMatch{
+			MatchID:          "m1",
+			ProcessingStatus: playtomic.StatusAssigningBallBringer,
+			ResourceName:     "Court 1",
+			Start:            time.Date(2024, 6, 1, 18, 0, 0, 0, time.UTC).Unix(),
+			Teams: []playtomic.Team{
+				{Players: []playtomic.Player{{UserID: "p1", Name: "Player 1"}, {UserID: "p2", Name: "Player 2"}}},
+			},
+		}
+	}
+	newStore := func() *club.MockStore {
+		store := club.NewMock()
+		store.AssignBallBringerAtomicallyFunc = func(matchID string, playerIDs []string) (string, string, error) {
+			return "p1", "Player 1", nil
+		}
+		return store
+	}
+	enabled := config.Config{Notifications: config.NotificationConfig{BallBringerDM: true}}
+
+	t.Run("sends a direct message to a mapped ball bringer", func(t *testing.T) {
+		store := newStore()
+		store.GetSlackUserIDByPlayerIDFunc = func(playerID string) (string, bool, error) {
+			return "U123", true, nil
+		}
+		notif := notifier.NewMock()
+		p := New(store, notif, metrics.NewMock(), pubsubPkg.NewMock("TEST"), enabled)
+
+		p.AssignBallBringer(newMatch(), false)
+
+		assert.Equal(t, []string{"p1"}, store.GetSlackUserIDByPlayerIDCalls)
+		require.Len(t, notif.SendBallBringerReminderCalls, 1)
+		assert.Equal(t, "U123", notif.SendBallBringerReminderCalls[0].SlackUserID)
+		assert.Equal(t, "m1", notif.SendBallBringerReminderCalls[0].Match.MatchID)
+	})
+
+	t.Run("skips an unmapped ball bringer", func(t *testing.T) {
+		store := newStore()
+		notif := notifier.NewMock()
+		p := New(store, notif, metrics.NewMock(), pubsubPkg.NewMock("TEST"), enabled)
+
+		p.AssignBallBringer(newMatch(), false)
+
+		assert.Equal(t, []string{"p1"}, store.GetSlackUserIDByPlayerIDCalls)
+		assert.Empty(t, notif.SendBallBringerReminderCalls)
+	})
+
+	t.Run("skips a ball bringer who opted out", func(t *testing.T) {
+		store := newStore()
+		store.GetSlackUserIDByPlayerIDFunc = func(playerID string) (string, bool, error) {
+			return "U123", true, nil
+		}
+		store.IsDMOptedOutFunc = func(playerID string) bool {
+			return playerID == "p1"
+		}
+		notif := notifier.NewMock()
+		p := New(store, notif, metrics.NewMock(), pubsubPkg.NewMock("TEST"), enabled)
+
+		p.AssignBallBringer(newMatch(), false)
+
+		assert.Empty(t, store.GetSlackUserIDByPlayerIDCalls)
+		assert.Empty(t, notif.SendBallBringerReminderCalls)
+	})
+
+	t.Run("does nothing when disabled", func(t *testing.T) {
+		store := newStore()
+		store.GetSlackUserIDByPlayerIDFunc = func(playerID string) (string, bool, error) {
+			return "U123", true, nil
+		}
+		notif := notifier.NewMock()
+		p := New(store, notif, metrics.NewMock(), pubsubPkg.NewMock("TEST"), config.Config{})
+
+		p.AssignBallBringer(newMatch(), false)
+
+		assert.Empty(t, store.GetSlackUserIDByPlayerIDCalls)
+		assert.Empty(t, notif.SendBallBringerReminderCalls)
+	})
+}
+
+func TestProcessor_NewPlayerNotification(t *testing.T) {
+	newMatch := func() *playtomic.PadelMatch {
+		return &playtomic.PadelMatch{
+			MatchID:          "m1",
+			ProcessingStatus: playtomic.StatusNew,
+			GameStatus:       playtomic.GameStatusCanceled,
+			Teams: []playtomic.Team{
+				{Players: []playtomic.Player{{UserID: "p1", Name: "Player 1"}, {UserID: "p2", Name: "Player 2"}}},
+			},
+		}
+	}
+	enabled := config.Config{Notifications: config.NotificationConfig{NewPlayers: true}}
+
+	t.Run("announces only newly inserted players", func(t *testing.T) {
+		store := club.NewMock()
+		store.UpsertPlayersFunc = func(players []club.PlayerInfo) ([]club.PlayerInfo, error) {
+			return []club.PlayerInfo{players[1]}, nil
+		}
+		notif := notifier.NewMock()
+		p := New(store, notif, metrics.NewMock(), pubsubPkg.NewMock("TEST"), enabled)
+
+		p.ProcessMatch(newMatch(), false)
+
+		require.Len(t, notif.SendNewPlayersCalls, 1)
+		assert.Equal(t, []club.PlayerInfo{{ID: "p2", Name: "Player 2"}}, notif.SendNewPlayersCalls[0])
+	})
+
+	t.Run("stays quiet when every player is known", func(t *testing.T) {
+		store := club.NewMock()
+		store.UpsertPlayersFunc = func(players []club.PlayerInfo) ([]club.PlayerInfo, error) {
+			return nil, nil
+		}
+		notif := notifier.NewMock()
+		p := New(store, notif, metrics.NewMock(), pubsubPkg.NewMock("TEST"), enabled)
+
+		p.ProcessMatch(newMatch(), false)
+
+		assert.Empty(t, notif.SendNewPlayersCalls)
+	})
+
+	t.Run("does nothing when disabled", func(t *testing.T) {
+		store := club.NewMock()
+		store.UpsertPlayersFunc = func(players []club.PlayerInfo) ([]club.PlayerInfo, error) {
+			return players, nil
+		}
+		notif := notifier.NewMock()
+		p := New(store, notif, metrics.NewMock(), pubsubPkg.NewMock("TEST"), config.Config{})
+
+		p.ProcessMatch(newMatch(), false)
+
+		assert.Empty(t, notif.SendNewPlayersCalls)
+	})
+}
+
+func TestProcessor_NotifyBookingPassesSlackUsers(t *testing.T) {
+	store := club.NewMock()
+	store.GetSlackUserIDByPlayerIDFunc = func(playerID string) (string, bool, error) {
+		if playerID == "p1" {
+			return "U123", true, nil
+		}
+		return "", false, nil
+	}
+	notif := notifier.NewMock()
+	p := New(store, notif, metrics.NewMock(), pubsubPkg.NewMock("TEST"), config.Config{})
+
+	match := &playtomic.PadelMatch{
+		MatchID:          "m1",
+		ProcessingStatus: playtomic.StatusBallBoyAssigned,
+		Teams: []playtomic.Team{
+			{Players: []playtomic.Player{{UserID: "p1", Name: "Player 1"}, {UserID: "p2", Name: "Player 2"}}},
+		},
+	}
+	require.NoError(t, p.NotifyBooking(match, false))
+
+	require.Len(t, notif.SendBookingNotificationCalls, 1)
+	assert.Equal(t, map[string]string{"p1": "U123"}, notif.SendBookingNotificationCalls[0].SlackUserIDs)
+}
+
+func TestProcessor_ResultThreading(t *testing.T) {
+	newMatch := func() *playtomic.PadelMatch {
+		return &playtomic.PadelMatch{
+			MatchID:          "m1",
+			ProcessingStatus: playtomic.StatusResultAvailable,
+			BookingMessageTs: "ts123",
+		}
+	}
+
+	t.Run("threads the result under the booking message when enabled", func(t *testing.T) {
+		notif := notifier.NewMock()
+		cfg := config.Config{Notifications: config.NotificationConfig{ThreadResults: true}}
+		p := New(club.NewMock(), notif, metrics.NewMock(), pubsubPkg.NewMock("TEST"), cfg)
+
+		require.NoError(t, p.NotifyResult(newMatch(), false))
+
+		require.Len(t, notif.SendResultNotificationCalls, 1)
+		assert.Equal(t, "ts123", notif.SendResultNotificationCalls[0].ThreadTS)
+	})
+
+	t.Run("posts top-level when disabled", func(t *testing.T) {
+		notif := notifier.NewMock()
+		p := New(club.NewMock(), notif, metrics.NewMock(), pubsubPkg.NewMock("TEST"), config.Config{})
+
+		require.NoError(t, p.NotifyResult(newMatch(), false))
+
+		require.Len(t, notif.SendResultNotificationCalls, 1)
+		assert.Empty(t, notif.SendResultNotificationCalls[0].ThreadTS)
+	})
+
+	t.Run("stores the booking message timestamp", func(t *testing.T) {
+		store := club.NewMock()
+		var storedTS string
+		store.SetBookingMessageTSFunc = func(matchID, ts string) error {
+			storedTS = ts
+			return nil
+		}
+		p := New(store, notifier.NewMock(), metrics.NewMock(), pubsubPkg.NewMock("TEST"), config.Config{})
+
+		match := &playtomic.PadelMatch{MatchID: "m1", ProcessingStatus: playtomic.StatusBallBoyAssigned}
+		require.NoError(t, p.NotifyBooking(match, false))
+
+		assert.Equal(t, "booking-ts", storedTS)
+		assert.Equal(t, "booking-ts", match.BookingMessageTs)
+	})
+}
+
+func TestProcessor_DrainWaitsForInFlightProcessing(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var completed atomic.Bool
+	store := club.NewMock()
+	store.UpdatePlayerStatsFunc = func(match *playtomic.PadelMatch) {
+		close(started)
+		<-release
+		completed.Store(true)
+	}
+	p := New(store, notifier.NewMock(), metrics.NewMock(), pubsubPkg.NewMock("TEST"), config.Config{})
+
+	go p.UpdatePlayerStats(&playtomic.PadelMatch{MatchID: "m1"}, false)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, p.Drain(ctx), context.DeadlineExceeded, "Drain should not return while processing is in flight")
+
+	close(release)
+	require.NoError(t, p.Drain(context.Background()))
+	assert.True(t, completed.Load(), "Drain returned before the in-flight processing finished")
+}
+
+func TestProcessor_IncompleteLineup(t *testing.T) {
+	newMatch := func() *playtomic.PadelMatch {
+		return &playtomic.PadelMatch{
+			MatchID:          "m1",
+			OwnerID:          "p1",
+			ResourceName:     "Court 1",
+			ProcessingStatus: playtomic.StatusNew,
+			GameStatus:       playtomic.GameStatusPending,
+			Teams: []playtomic.Team{
+				{Players: []playtomic.Player{{UserID: "p1", Name: "Player 1"}, {UserID: "p2", Name: "Player 2"}}},
+				{Players: []playtomic.Player{{UserID: "p3", Name: "Player 3"}}},
+			},
+		}
+	}
+
+	t.Run("routes a played three player doubles match to review", func(t *testing.T) {
+		store := club.NewMock()
+		pubsub := pubsubPkg.NewMock("TEST")
+		metricsMock := metrics.NewMock()
+		notif := notifier.NewMock()
+		p := New(store, notif, metricsMock, pubsub, config.Config{})
+
+		match := newMatch()
+		match.GameStatus = playtomic.GameStatusPlayed
+		match.ResultsStatus = playtomic.ResultsStatusConfirmed
+		p.ProcessMatch(match, false)
+
+		require.Len(t, store.UpdateProcessingStatusCalls, 1)
+		assert.Equal(t, playtomic.StatusNeedsReview, store.UpdateProcessingStatusCalls[0].Status)
+		assert.Equal(t, playtomic.StatusNeedsReview, match.ProcessingStatus)
+		assert.Equal(t, 1, metricsMock.IncompleteMatches())
+		assert.Empty(t, notif.SendIncompleteLineupReminderCalls, "the owner is only messaged when enabled")
+	})
+
+	t.Run("leaves an upcoming three player doubles match new", func(t *testing.T) {
+		store := club.NewMock()
+		pubsub := pubsubPkg.NewMock("TEST")
+		metricsMock := metrics.NewMock()
+		p := New(store, notifier.NewMock(), metricsMock, pubsub, config.Config{})
+
+		match := newMatch()
+		p.ProcessMatch(match, false)
+
+		assert.Empty(t, store.UpdateProcessingStatusCalls)
+		assert.Equal(t, playtomic.StatusNew, match.ProcessingStatus)
+		assert.Empty(t, pubsub.SendMessageCalls, "no ball bringer should be assigned")
+		assert.Equal(t, 1, metricsMock.IncompleteMatches())
+	})
+
+	t.Run("does not check the lineup before the assignment window", func(t *testing.T) {
+		now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+		metricsMock := metrics.NewMock()
+		p := New(club.NewMock(), notifier.NewMock(), metricsMock, pubsubPkg.NewMock("TEST"), config.Config{BallBringerAssignWindow: 24 * time.Hour})
+		p.clock = clock.NewMock(now)
+
+		match := newMatch()
+		match.Start = now.Add(72 * time.Hour).Unix()
+		p.ProcessMatch(match, false)
+
+		assert.Equal(t, playtomic.StatusNew, match.ProcessingStatus)
+		assert.Zero(t, metricsMock.IncompleteMatches(), "players may still join a match this far ahead")
+	})
+
+	t.Run("asks a mapped owner once to complete the lineup", func(t *testing.T) {
+		store := setupTestStore(t)
+		store.AddPlayer("p1", "Player 1", 1.0)
+		require.NoError(t, store.SetSlackUserID("p1", "U123"))
+		require.NoError(t, store.UpsertMatch(newMatch()))
+		notif := notifier.NewMock()
+		cfg := config.Config{Notifications: config.NotificationConfig{IncompleteMatchDM: true}}
+
+		// The reminder is recorded on the match, so another instance of the service doesn't ask again.
+		for range 2 {
+			p := New(store, notif, metrics.NewMock(), pubsubPkg.NewMock("TEST"), cfg)
+			p.ProcessMatch(newMatch(), false)
+		}
+
+		require.Len(t, notif.SendIncompleteLineupReminderCalls, 1)
+		call := notif.SendIncompleteLineupReminderCalls[0]
+		assert.Equal(t, "U123", call.SlackUserID)
+		assert.Equal(t, 3, call.Players)
+		assert.Equal(t, 4, call.Required)
+	})
+
+	t.Run("a full lineup is processed as usual", func(t *testing.T) {
+		store := club.NewMock()
+		metricsMock := metrics.NewMock()
+		p := New(store, notifier.NewMock(), metricsMock, pubsubPkg.NewMock("TEST"), config.Config{})
+
+		match := newMatch()
+		match.Teams[1].Players = append(match.Teams[1].Players, playtomic.Player{UserID: "p4", Name: "Player 4"})
+		p.ProcessMatch(match, false)
+
+		assert.Equal(t, playtomic.StatusAssigningBallBringer, match.ProcessingStatus)
+		assert.Zero(t, metricsMock.IncompleteMatches())
+	})
+
+	t.Run("detects the format of a match read back from the store", func(t *testing.T) {
+		store := setupTestStore(t)
+		metricsMock := metrics.NewMock()
+		p := New(store, notifier.NewMock(), metricsMock, pubsubPkg.NewMock("TEST"), config.Config{})
+
+		played := newMatch()
+		played.MatchID = "played"
+		played.GameStatus = playtomic.GameStatusPlayed
+		played.ResultsStatus = playtomic.ResultsStatusConfirmed
+		upcoming := newMatch()
+		upcoming.MatchID = "upcoming"
+		store.AddPlayer("p1", "Player 1", 1.0)
+		require.NoError(t, store.UpsertMatches([]*playtomic.PadelMatch{played, upcoming}))
+
+		for _, id := range []string{"played", "upcoming"} {
+			stored, err := store.GetMatch(id)
+			require.NoError(t, err)
+			p.ProcessMatch(stored, false)
+		}
+
+		stored, err := store.GetMatch("played")
+		require.NoError(t, err)
+		assert.Equal(t, playtomic.StatusNeedsReview, stored.ProcessingStatus)
+		stored, err = store.GetMatch("upcoming")
+		require.NoError(t, err)
+		assert.Equal(t, playtomic.StatusNew, stored.ProcessingStatus)
+		assert.Equal(t, 2, metricsMock.IncompleteMatches())
+	})
+}
+
+func TestProcessor_NotifyBookingPassesGuests(t *testing.T) {
+	store := setupTestStore(t)
+	store.AddPlayer("p1", "Core Member", 1.0)
+	require.NoError(t, store.SetCorePlayer("p1", true))
+	store.AddPlayer("p2", "Slack Member", 1.0)
+	require.NoError(t, store.SetSlackUserID("p2", "U2"))
+	notif := notifier.NewMock()
+	p := New(store, notif, metrics.NewMock(), pubsubPkg.NewMock("TEST"), config.Config{})
+
+	match := &playtomic.PadelMatch{
+		MatchID:          "m1",
+		OwnerID:          "p1",
+		ProcessingStatus: playtomic.StatusNew,
+		GameStatus:       playtomic.GameStatusPending,
+		Teams: []playtomic.Team{
+			{Players: []playtomic.Player{{UserID: "p1", Name: "Core Member"}, {UserID: "p2", Name: "Slack Member"}}},
+			{Players: []playtomic.Player{{UserID: "g1", Name: "Visitor"}, {UserID: "g2", Name: "Other Visitor"}}},
+		},
+	}
+	require.NoError(t, store.UpsertMatch(match))
+
+	// Processing adds the visitors to the store, which must not make them members.
+	p.ProcessMatch(match, false)
+	require.True(t, store.IsKnownPlayer("g1"))
+	require.NoError(t, p.NotifyBooking(match, false))
+
+	require.Len(t, notif.SendBookingNotificationCalls, 1)
+	assert.Equal(t, map[string]bool{"g1": true, "g2": true}, notif.SendBookingNotificationCalls[0].GuestIDs)
+}
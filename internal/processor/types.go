@@ -1,6 +1,8 @@
 package processor
 
 import (
+	"github.com/mauv0809/ideal-tribble/internal/clock"
+	"github.com/mauv0809/ideal-tribble/internal/config"
 	"github.com/mauv0809/ideal-tribble/internal/metrics"
 	"github.com/mauv0809/ideal-tribble/internal/pubsub"
 )
@@ -11,4 +13,7 @@ type Processor struct {
 	pubsub   pubsub.PubSubClient
 	notifier Notifier
 	metrics  metrics.Metrics
+	cfg      config.Config
+	clock    clock.Clock
+	inFlight inFlight
 }
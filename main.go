@@ -14,7 +14,9 @@ import (
 	"github.com/mauv0809/ideal-tribble/internal/database"
 	server "github.com/mauv0809/ideal-tribble/internal/http"
 	"github.com/mauv0809/ideal-tribble/internal/metrics"
+	"github.com/mauv0809/ideal-tribble/internal/notifier"
 	"github.com/mauv0809/ideal-tribble/internal/notifier/slack"
+	"github.com/mauv0809/ideal-tribble/internal/notifier/webhook"
 	"github.com/mauv0809/ideal-tribble/internal/playtomic"
 	"github.com/mauv0809/ideal-tribble/internal/processor"
 	"github.com/mauv0809/ideal-tribble/internal/pubsub"
@@ -25,7 +27,7 @@ func main() {
 	startTime := time.Now()
 	log.SetFormatter(log.JSONFormatter)
 	cfg := config.Load()
-	db, dbTeardown, err := database.InitDB(cfg.DBName, cfg.Turso.PrimaryURL, cfg.Turso.AuthToken, cfg.MigrationsDir)
+	db, dbTeardown, err := database.InitDB(cfg.DBName, cfg.Turso.PrimaryURL, cfg.Turso.AuthToken, cfg.MigrationsDir, cfg.DBPool)
 	dbInitDuration := time.Since(startTime)
 	log.Info("Database initialization time recorded", "duration_ms", dbInitDuration.Milliseconds())
 	if err != nil {
@@ -51,13 +53,14 @@ func main() {
 	clubStore := club.New(db)
 	metricsSvc := metrics.NewService()
 	metricsHandler := metrics.NewMetricsHandler()
-	playtomicClient := playtomic.NewClient()
-	notifier := slack.NewNotifier(cfg.Slack.Token, cfg.Slack.ChannelID, metricsSvc)
+	playtomicClient := playtomic.NewClient(cfg.MatchCacheTTL, metricsSvc)
+	notifier := newNotifier(cfg, metricsSvc)
 	pubsub := pubsub.New(cfg.ProjectID)
-	processor := processor.New(clubStore, notifier, metricsSvc, pubsub)
+	processor := processor.New(clubStore, notifier, metricsSvc, pubsub, cfg)
 
 	s := server.NewServer(
 		clubStore,
+		db,
 		metricsSvc,
 		metricsHandler,
 		cfg,
@@ -112,7 +115,30 @@ func main() {
 		} else {
 			log.Info("Server gracefully stopped")
 		}
+
+		// Wait for in-flight processing before the deferred database teardown runs.
+		if err := processor.Drain(ctx); err != nil {
+			log.Error("Timed out waiting for in-flight processing", "error", err)
+		} else {
+			log.Info("In-flight processing drained")
+		}
 	}
 
 	log.Info("Server process shutting down")
 }
+
+// newNotifier creates the notifier selected by the NOTIFIER setting.
+func newNotifier(cfg config.Config, metricsSvc metrics.Metrics) notifier.Notifier {
+	switch cfg.Notifier {
+	case "slack":
+		return slack.NewNotifier(cfg.Slack, metricsSvc)
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			log.Fatalf("WEBHOOK_URL must be set when NOTIFIER is webhook")
+		}
+		return webhook.NewNotifier(cfg.WebhookURL)
+	default:
+		log.Fatalf("Unknown notifier %q, expected slack or webhook", cfg.Notifier)
+		return nil
+	}
+}
@@ -0,0 +1,8 @@
+-- +goose Up
+-- slack_user_id maps a Playtomic player to their Slack user, so they can be messaged and mentioned directly.
+ALTER TABLE players ADD COLUMN slack_user_id TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_players_slack_user_id ON players (slack_user_id) WHERE slack_user_id IS NOT NULL AND slack_user_id != '';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_players_slack_user_id;
+-- SQLite does not support ALTER TABLE DROP COLUMN directly, so the column itself is left in place.
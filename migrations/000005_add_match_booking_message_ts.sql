@@ -0,0 +1,6 @@
+-- +goose Up
+-- booking_message_ts is the Slack timestamp of the booking notification, used to thread the result notification under it.
+ALTER TABLE matches ADD COLUMN booking_message_ts TEXT;
+
+-- +goose Down
+-- SQLite does not support ALTER TABLE DROP COLUMN directly, so no down migration is provided for this column.
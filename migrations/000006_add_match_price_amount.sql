@@ -0,0 +1,7 @@
+-- +goose Up
+-- price_amount (in cents) and price_currency are parsed from the free-text price, which is kept as is.
+ALTER TABLE matches ADD COLUMN price_amount INTEGER;
+ALTER TABLE matches ADD COLUMN price_currency TEXT;
+
+-- +goose Down
+-- SQLite does not support ALTER TABLE DROP COLUMN directly, so no down migration is provided for these columns.
@@ -0,0 +1,6 @@
+-- +goose Up
+-- is_core marks core club members. Matches they book are always treated as club matches, however few known players join.
+ALTER TABLE players ADD COLUMN is_core INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+-- SQLite does not support ALTER TABLE DROP COLUMN directly, so no down migration is provided for this column.
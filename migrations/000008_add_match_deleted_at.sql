@@ -0,0 +1,6 @@
+-- +goose Up
+-- deleted_at soft-deletes a match: the row is kept for audit and can be restored, but reads ignore it while set.
+ALTER TABLE matches ADD COLUMN deleted_at INTEGER;
+
+-- +goose Down
+-- SQLite does not support ALTER TABLE DROP COLUMN directly, so no down migration is provided for this column.
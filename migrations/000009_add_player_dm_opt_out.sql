@@ -0,0 +1,6 @@
+-- +goose Up
+-- dm_opt_out is set by players who don't want direct messages from the bot, such as the ball bringer reminder.
+ALTER TABLE players ADD COLUMN dm_opt_out INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+-- SQLite does not support ALTER TABLE DROP COLUMN directly, so no down migration is provided for this column.
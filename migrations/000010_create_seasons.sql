@@ -0,0 +1,32 @@
+-- +goose Up
+-- seasons are the periods the leaderboard is counted over. player_stats holds the live totals of the current season,
+-- the one without ended_at. Starting a new season archives those totals in season_player_stats and resets them.
+CREATE TABLE IF NOT EXISTS seasons (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at INTEGER NOT NULL,
+    ended_at INTEGER
+);
+
+-- Everything recorded before seasons existed belongs to the first season.
+INSERT INTO seasons (started_at) VALUES (0);
+
+CREATE TABLE IF NOT EXISTS season_player_stats (
+    season_id INTEGER NOT NULL,
+    player_id TEXT NOT NULL,
+
+    matches_played INTEGER NOT NULL DEFAULT 0,
+    matches_won INTEGER NOT NULL DEFAULT 0,
+    matches_lost INTEGER NOT NULL DEFAULT 0,
+    sets_won INTEGER NOT NULL DEFAULT 0,
+    sets_lost INTEGER NOT NULL DEFAULT 0,
+    games_won INTEGER NOT NULL DEFAULT 0,
+    games_lost INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (season_id, player_id),
+    FOREIGN KEY (season_id) REFERENCES seasons(id) ON DELETE CASCADE,
+    FOREIGN KEY (player_id) REFERENCES players(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS season_player_stats;
+DROP TABLE IF EXISTS seasons;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- status_updated_at is when processing_status last changed, so matches waiting too long in one status can be found.
+-- It stays NULL for matches that have not changed status since this column was added.
+ALTER TABLE matches ADD COLUMN status_updated_at INTEGER;
+
+-- +goose Down
+-- SQLite does not support ALTER TABLE DROP COLUMN directly, so no down migration is provided for this column.
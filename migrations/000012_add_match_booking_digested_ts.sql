@@ -0,0 +1,6 @@
+-- +goose Up
+-- booking_digested_ts is when a booking was included in the daily booking digest, so it is not digested twice.
+ALTER TABLE matches ADD COLUMN booking_digested_ts INTEGER;
+
+-- +goose Down
+-- SQLite does not support ALTER TABLE DROP COLUMN directly, so no down migration is provided for this column.
@@ -0,0 +1,8 @@
+-- +goose Up
+-- counted_results_blob holds the results a match's stats were counted with. results_changed_ts is when Playtomic
+-- changed the results after that, so the stats still have to be corrected.
+ALTER TABLE matches ADD COLUMN counted_results_blob BLOB;
+ALTER TABLE matches ADD COLUMN results_changed_ts INTEGER;
+
+-- +goose Down
+-- SQLite does not support ALTER TABLE DROP COLUMN directly, so no down migration is provided for these columns.
@@ -0,0 +1,7 @@
+-- +goose Up
+-- lineup_reminded_ts is when the owner of an upcoming match with an incomplete lineup was asked to complete it,
+-- so every instance of the service reminds them only once.
+ALTER TABLE matches ADD COLUMN lineup_reminded_ts INTEGER;
+
+-- +goose Down
+-- SQLite does not support ALTER TABLE DROP COLUMN directly, so no down migration is provided for this column.
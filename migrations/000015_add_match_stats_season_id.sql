@@ -0,0 +1,12 @@
+-- +goose Up
+-- stats_season_id is the season whose player_stats a match's stats were counted in. It decides whether the stats can
+-- still be undone or corrected, and which season's competitive leaderboard the match counts towards.
+ALTER TABLE matches ADD COLUMN stats_season_id INTEGER;
+
+-- Matches counted before the column existed are taken to have been counted in the season they started in.
+UPDATE matches SET stats_season_id = (
+    SELECT id FROM seasons WHERE started_at <= matches.start_time ORDER BY started_at DESC LIMIT 1
+) WHERE processing_status IN ('STATS_UPDATED', 'COMPLETED');
+
+-- +goose Down
+-- SQLite does not support ALTER TABLE DROP COLUMN directly, so no down migration is provided for this column.
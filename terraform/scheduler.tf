@@ -53,3 +53,53 @@ resource "google_cloud_scheduler_job" "process_job" {
     google_service_account.scheduler_invoker
   ]
 }
+
+resource "google_cloud_scheduler_job" "weekly_recap_job" {
+  project          = var.gcp_project_id
+  name             = "${var.service_name}-weekly-recap"
+  description      = "Triggers the ${var.weekly_recap_path} endpoint to post the recap of the previous week."
+  schedule         = var.weekly_recap_cron_schedule
+  time_zone        = "Europe/Copenhagen"
+  attempt_deadline = "320s"
+  paused           = false
+
+  http_target {
+    http_method = "POST"
+    uri         = "${google_cloud_run_v2_service.main.uri}${var.weekly_recap_path}"
+
+    oidc_token {
+      service_account_email = google_service_account.scheduler_invoker.email
+    }
+  }
+
+  depends_on = [
+    google_project_service.scheduler_api,
+    google_cloud_run_v2_service.main,
+    google_service_account.scheduler_invoker
+  ]
+}
+
+resource "google_cloud_scheduler_job" "daily_digest_job" {
+  project          = var.gcp_project_id
+  name             = "${var.service_name}-daily-digest"
+  description      = "Triggers the ${var.daily_digest_path} endpoint to post the digest of new bookings. It does nothing unless DAILY_BOOKING_DIGEST is enabled."
+  schedule         = var.daily_digest_cron_schedule
+  time_zone        = "Europe/Copenhagen"
+  attempt_deadline = "320s"
+  paused           = false
+
+  http_target {
+    http_method = "POST"
+    uri         = "${google_cloud_run_v2_service.main.uri}${var.daily_digest_path}"
+
+    oidc_token {
+      service_account_email = google_service_account.scheduler_invoker.email
+    }
+  }
+
+  depends_on = [
+    google_project_service.scheduler_api,
+    google_cloud_run_v2_service.main,
+    google_service_account.scheduler_invoker
+  ]
+}
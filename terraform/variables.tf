@@ -53,6 +53,30 @@ variable "fetch_path" {
   default     = "/fetch"
 }
 
+variable "weekly_recap_cron_schedule" {
+  description = "The cron schedule for the weekly recap job."
+  type        = string
+  default     = "0 9 * * 1" # Mondays at 09:00
+}
+
+variable "weekly_recap_path" {
+  description = "Path on the service to trigger the weekly recap."
+  type        = string
+  default     = "/recap/weekly"
+}
+
+variable "daily_digest_cron_schedule" {
+  description = "The cron schedule for the daily booking digest job."
+  type        = string
+  default     = "0 8 * * *" # Every day at 08:00
+}
+
+variable "daily_digest_path" {
+  description = "Path on the service to trigger the daily booking digest."
+  type        = string
+  default     = "/digest/daily"
+}
+
 variable "process_path" {
   description = "Path on the service to trigger process."
   type        = string